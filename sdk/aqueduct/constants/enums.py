@@ -112,6 +112,7 @@ class ExecutionStatus(str, Enum, metaclass=MetaEnum):
     REGISTERED = "registered"
     CANCELED = "canceled"
     DELETED = "deleted"
+    SKIPPED = "skipped"
 
 
 class NotificationLogLevel(str, Enum, metaclass=MetaEnum):
@@ -8,7 +8,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/repos"
-	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/dropbox/godropbox/errors"
 )
@@ -34,7 +34,7 @@ func NewBaseExecutor(conf *job.ExecutorConfiguration) (*BaseExecutor, error) {
 		}
 	}()
 
-	schemaVersionRepo := sqlite.NewSchemaVersionRepo()
+	schemaVersionRepo := sql.NewSchemaVersionRepo()
 
 	if err := requireSchemaVersion(
 		context.Background(),
@@ -3,7 +3,7 @@ package executor
 import (
 	"github.com/aqueducthq/aqueduct/lib/engine"
 	"github.com/aqueducthq/aqueduct/lib/repos"
-	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
 )
 
 type Repos struct {
@@ -17,24 +17,26 @@ type Repos struct {
 	NotificationRepo         repos.Notification
 	OperatorRepo             repos.Operator
 	OperatorResultRepo       repos.OperatorResult
+	RunRequestRepo           repos.RunRequest
 	WatcherRepo              repos.Watcher
 	WorkflowRepo             repos.Workflow
 }
 
 func createRepos() *Repos {
 	return &Repos{
-		ArtifactRepo:             sqlite.NewArtifactRepo(),
-		ArtifactResultRepo:       sqlite.NewArtifactResultRepo(),
-		DAGRepo:                  sqlite.NewDAGRepo(),
-		DAGEdgeRepo:              sqlite.NewDAGEdgeRepo(),
-		DAGResultRepo:            sqlite.NewDAGResultRepo(),
-		ExecutionEnvironmentRepo: sqlite.NewExecutionEnvironmentRepo(),
-		ResourceRepo:             sqlite.NewResourceRepo(),
-		NotificationRepo:         sqlite.NewNotificationRepo(),
-		OperatorRepo:             sqlite.NewOperatorRepo(),
-		OperatorResultRepo:       sqlite.NewOperatorResultRepo(),
-		WatcherRepo:              sqlite.NewWatcherRepo(),
-		WorkflowRepo:             sqlite.NewWorklowRepo(),
+		ArtifactRepo:             sql.NewArtifactRepo(),
+		ArtifactResultRepo:       sql.NewArtifactResultRepo(),
+		DAGRepo:                  sql.NewDAGRepo(),
+		DAGEdgeRepo:              sql.NewDAGEdgeRepo(),
+		DAGResultRepo:            sql.NewDAGResultRepo(),
+		ExecutionEnvironmentRepo: sql.NewExecutionEnvironmentRepo(),
+		ResourceRepo:             sql.NewResourceRepo(),
+		NotificationRepo:         sql.NewNotificationRepo(),
+		OperatorRepo:             sql.NewOperatorRepo(),
+		OperatorResultRepo:       sql.NewOperatorResultRepo(),
+		RunRequestRepo:           sql.NewRunRequestRepo(),
+		WatcherRepo:              sql.NewWatcherRepo(),
+		WorkflowRepo:             sql.NewWorklowRepo(),
 	}
 }
 
@@ -50,6 +52,7 @@ func getEngineRepos(repos *Repos) *engine.Repos {
 		NotificationRepo:         repos.NotificationRepo,
 		OperatorRepo:             repos.OperatorRepo,
 		OperatorResultRepo:       repos.OperatorResultRepo,
+		RunRequestRepo:           repos.RunRequestRepo,
 		WatcherRepo:              repos.WatcherRepo,
 		WorkflowRepo:             repos.WorkflowRepo,
 	}
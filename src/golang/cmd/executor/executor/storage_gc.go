@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/storage_gc"
+	log "github.com/sirupsen/logrus"
+)
+
+type StorageGCExecutor struct {
+	*BaseExecutor
+	spec *job.StorageGCSpec
+}
+
+func NewStorageGCExecutor(spec *job.StorageGCSpec, base *BaseExecutor) *StorageGCExecutor {
+	return &StorageGCExecutor{BaseExecutor: base, spec: spec}
+}
+
+// Run deletes the objects in the server's storage that the metadata database no longer refers to.
+// In dry-run mode, it only reports them.
+func (ex *StorageGCExecutor) Run(ctx context.Context) error {
+	log.Info("Starting storage garbage collection.")
+
+	storageConfig := config.Storage()
+	report, err := storage_gc.Collect(
+		ctx,
+		storage.NewStorage(&storageConfig),
+		ex.spec.GracePeriod,
+		ex.spec.DryRun,
+		ex.ArtifactResultRepo,
+		ex.OperatorRepo,
+		ex.Database,
+	)
+	if err != nil {
+		return err
+	}
+
+	if report.DryRun {
+		for _, object := range report.Orphans {
+			log.Infof("Found orphaned storage object %s (%d bytes).", object.Key, object.Size)
+		}
+		log.Infof(
+			"Dry run of storage garbage collection found %d orphaned objects, %d bytes are reclaimable.",
+			len(report.Orphans),
+			report.ReclaimableBytes,
+		)
+		return nil
+	}
+
+	log.Infof(
+		"Executed storage garbage collection. Deleted %d of %d orphaned objects, %d bytes were reclaimable.",
+		len(report.Orphans)-len(report.FailedKeys),
+		len(report.Orphans),
+		report.ReclaimableBytes,
+	)
+	return nil
+}
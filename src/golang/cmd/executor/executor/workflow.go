@@ -4,10 +4,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/cronjob"
 	"github.com/aqueducthq/aqueduct/lib/engine"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
+	"github.com/aqueducthq/aqueduct/lib/workflow"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
 	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
 	"github.com/google/uuid"
@@ -26,6 +29,12 @@ type WorkflowExecutor struct {
 	// The parameters to execute this workflow job with. If nil, then only default parameters
 	// will be used. These values not persisted to the db.
 	Parameters map[string]param.Param
+
+	// If set, this workflow job resumes the failed workflow run with this ID.
+	ResumedDAGResultID uuid.UUID
+
+	// If set, the run request that launched this workflow job.
+	RunRequestID uuid.UUID
 }
 
 func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowExecutor, error) {
@@ -34,6 +43,22 @@ func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowE
 		return nil, err
 	}
 
+	resumedDAGResultID := uuid.Nil
+	if spec.ResumedDAGResultId != "" {
+		resumedDAGResultID, err = uuid.Parse(spec.ResumedDAGResultId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	runRequestID := uuid.Nil
+	if spec.RunRequestId != "" {
+		runRequestID, err = uuid.Parse(spec.RunRequestId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	githubManager, err := github.NewManager(spec.GithubManager)
 	if err != nil {
 		return nil, err
@@ -45,6 +70,8 @@ func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowE
 		base.Database,
 		githubManager,
 		nil, /* PreviewCacheManager */
+		cronjob.NewProcessCronjobManager(),
+		nil, /* SchedulerLeader */
 		spec.AqPath,
 		spec.DisplayIP,
 		engineRepos,
@@ -54,11 +81,13 @@ func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowE
 	}
 
 	return &WorkflowExecutor{
-		BaseExecutor:  base,
-		WorkflowID:    workflowID,
-		GithubManager: githubManager,
-		Engine:        eng,
-		Parameters:    spec.Parameters,
+		BaseExecutor:       base,
+		WorkflowID:         workflowID,
+		GithubManager:      githubManager,
+		Engine:             eng,
+		Parameters:         spec.Parameters,
+		ResumedDAGResultID: resumedDAGResultID,
+		RunRequestID:       runRequestID,
 	}, nil
 }
 
@@ -76,25 +105,34 @@ func (ex *WorkflowExecutor) Run(ctx context.Context) error {
 		}
 	}()
 
-	status, err := ex.Engine.ExecuteWorkflow(
-		ctx,
-		ex.WorkflowID,
-		&engine.AqueductTimeConfig{
-			OperatorPollInterval: pollingIntervalMS,
-			ExecTimeout:          engine.DefaultExecutionTimeout,
-			CleanupTimeout:       engine.DefaultCleanupTimeout,
-		},
-		ex.Parameters,
-	)
+	timeConfig := &engine.AqueductTimeConfig{
+		OperatorPollInterval: pollingIntervalMS,
+		ExecTimeout:          engine.DefaultExecutionTimeout,
+		CleanupTimeout:       engine.DefaultCleanupTimeout,
+	}
+
+	var status shared.ExecutionStatus
+	var err error
+	if ex.ResumedDAGResultID != uuid.Nil {
+		status, err = ex.Engine.ResumeWorkflow(ctx, ex.WorkflowID, ex.ResumedDAGResultID, timeConfig, ex.RunRequestID)
+	} else {
+		status, err = ex.Engine.ExecuteWorkflow(ctx, ex.WorkflowID, timeConfig, ex.Parameters, ex.RunRequestID)
+	}
 	if err != nil {
 		return err
 	}
 
 	log.WithFields(log.Fields{
-		"WorkflowId": ex.WorkflowID,
-		"Parameters": ex.Parameters,
+		"WorkflowId":         ex.WorkflowID,
+		"Parameters":         ex.Parameters,
+		"ResumedDAGResultId": ex.ResumedDAGResultID,
 	}).Infof("Workflow run completed with status: %v", status)
 
+	if status != shared.SucceededExecutionStatus {
+		// Cascading Workflows are only triggered by successful runs.
+		return nil
+	}
+
 	if err := ex.TriggerCascadingFlows(ctx); err != nil {
 		log.WithFields(log.Fields{
 			"WorkflowId": ex.WorkflowID,
@@ -106,9 +144,10 @@ func (ex *WorkflowExecutor) Run(ctx context.Context) error {
 }
 
 // TriggerCascadingFlows triggers a new Workflow run for all Workflows (if any)
-// that are scheduled to run after this Workflow.
+// that are scheduled to run after this Workflow, and whose other sources have
+// succeeded if they require all of their sources to succeed.
 func (ex *WorkflowExecutor) TriggerCascadingFlows(ctx context.Context) error {
-	targetIDs, err := ex.WorkflowRepo.GetTargets(ctx, ex.WorkflowID, ex.Database)
+	targetIDs, err := workflow.CascadeTargets(ctx, ex.WorkflowID, ex.WorkflowRepo, ex.DAGResultRepo, ex.Database)
 	if err != nil {
 		return err
 	}
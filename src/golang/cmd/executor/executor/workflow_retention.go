@@ -2,13 +2,22 @@ package executor
 
 import (
 	"context"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/workflow"
+	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 )
 
+// doneRunRequestRetention is how long the run requests that are done are kept. They are only needed
+// while the run is queued or executing, since the run itself is recorded as a DAG result.
+const doneRunRequestRetention = 24 * time.Hour
+
 type WorkflowRetentionExecutor struct {
 	*BaseExecutor
 }
@@ -19,59 +28,70 @@ func NewWorkflowRetentionExecutor(base *BaseExecutor) *WorkflowRetentionExecutor
 
 func (ex *WorkflowRetentionExecutor) Run(ctx context.Context) error {
 	log.Info("Starting workflow retention.")
-	txn, err := ex.Database.BeginTx(ctx)
-	if err != nil {
-		return errors.Wrap(err, "Unable to start transaction.")
-	}
-	defer database.TxnRollbackIgnoreErr(ctx, txn)
 
 	// We first retrieve all relevant records from the database.
-	workflows, err := ex.WorkflowRepo.List(ctx, txn)
+	workflows, err := ex.WorkflowRepo.List(ctx, ex.Database)
 	if err != nil {
 		return errors.Wrap(err, "Unexpected error occurred while retrieving workflow.")
 	}
 
-	for _, workflow := range workflows {
-		err = ex.cleanupOldWorkflows(ctx, txn, workflow.ID, workflow.RetentionPolicy.KLatestRuns)
+	for _, workflowObj := range workflows {
+		err = ex.cleanupOldWorkflows(ctx, workflowObj.ID, &workflowObj.RetentionPolicy)
 		if err != nil {
 			return err
 		}
+	}
 
+	if err := ex.RunRequestRepo.DeleteDoneBefore(ctx, time.Now().Add(-doneRunRequestRetention), ex.Database); err != nil {
+		return errors.Wrap(err, "Unexpected error occurred while deleting run requests that are done.")
 	}
 	log.Info("Executed workflow retention.")
 
 	return nil
 }
 
+// cleanupOldWorkflows deletes the runs of the workflow workflowObjectID that are expired
+// according to retentionPolicy, along with the artifact content they stored.
 func (ex *WorkflowRetentionExecutor) cleanupOldWorkflows(
 	ctx context.Context,
-	txn database.Transaction,
 	workflowObjectID uuid.UUID,
-	kLatestRuns int,
+	retentionPolicy *shared.RetentionPolicy,
 ) error {
-	// If kLatestRuns set to -1, we keep all runs.
-	if kLatestRuns == -1 {
+	// If no option is set, we keep all runs.
+	if retentionPolicy.KLatestRuns == -1 && retentionPolicy.MaxAgeMillisec == 0 {
 		return nil
 	}
 
-	dagResults, err := ex.DAGResultRepo.GetKOffsetByWorkflow(
+	txn, err := ex.Database.BeginTx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Unable to start transaction.")
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	dagResults, err := ex.DAGResultRepo.GetByWorkflow(
 		ctx,
 		workflowObjectID,
-		kLatestRuns,
+		models.DAGResultCreatedAt,
+		-1,   /* limit */
+		true, /* orderDescending */
 		txn,
 	)
 	if err != nil {
 		return errors.Wrap(err, "Unexpected error occurred while retrieving workflow dags.")
 	}
-	dagResultIDs := make([]uuid.UUID, 0, len(dagResults))
-	for _, dagResult := range dagResults {
-		dagResultIDs = append(dagResultIDs, dagResult.ID)
-	}
 
-	if len(dagResultIDs) == 0 {
+	expiredDAGResults := workflow.ExpiredDAGResults(retentionPolicy, dagResults, time.Now())
+	if len(expiredDAGResults) == 0 {
 		return nil
 	}
 
+	dagResultIDs := make([]uuid.UUID, 0, len(expiredDAGResults))
+	dagResultToDAG := make(map[uuid.UUID]uuid.UUID, len(expiredDAGResults))
+	for _, dagResult := range expiredDAGResults {
+		dagResultIDs = append(dagResultIDs, dagResult.ID)
+		dagResultToDAG[dagResult.ID] = dagResult.DagID
+	}
+
 	operatorResultsToDelete, err := ex.OperatorResultRepo.GetByDAGResultBatch(
 		ctx,
 		dagResultIDs,
@@ -96,8 +116,13 @@ func (ex *WorkflowRetentionExecutor) cleanupOldWorkflows(
 	}
 
 	artifactResultIDs := make([]uuid.UUID, 0, len(artifactResultsToDelete))
+	// Maps the content path of each deleted artifact result to the DAG it was stored with.
+	contentPathToDAG := make(map[string]uuid.UUID, len(artifactResultsToDelete))
 	for _, artifactResult := range artifactResultsToDelete {
 		artifactResultIDs = append(artifactResultIDs, artifactResult.ID)
+		if artifactResult.ContentPath != "" {
+			contentPathToDAG[artifactResult.ContentPath] = dagResultToDAG[artifactResult.DAGResultID]
+		}
 	}
 
 	// Do the deleting
@@ -116,9 +141,58 @@ func (ex *WorkflowRetentionExecutor) cleanupOldWorkflows(
 		return errors.Wrap(err, "Unexpected error occurred while deleting workflow dag results.")
 	}
 
+	// The content of an artifact result can be shared with the runs that reused it, e.g. from the
+	// operator result cache, so it is only deleted if none of the remaining runs refer to it.
+	contentPaths := make([]string, 0, len(contentPathToDAG))
+	for contentPath := range contentPathToDAG {
+		contentPaths = append(contentPaths, contentPath)
+	}
+
+	referencedArtifactResults, err := ex.ArtifactResultRepo.GetByContentPaths(ctx, contentPaths, txn)
+	if err != nil {
+		return errors.Wrap(err, "Unexpected error occurred while retrieving remaining artifact results.")
+	}
+
+	for _, artifactResult := range referencedArtifactResults {
+		delete(contentPathToDAG, artifactResult.ContentPath)
+	}
+
+	dagIDs := make([]uuid.UUID, 0, len(expiredDAGResults))
+	seenDAGIDs := make(map[uuid.UUID]bool, len(expiredDAGResults))
+	for _, dagID := range dagResultToDAG {
+		if !seenDAGIDs[dagID] {
+			seenDAGIDs[dagID] = true
+			dagIDs = append(dagIDs, dagID)
+		}
+	}
+
+	dags, err := ex.DAGRepo.GetBatch(ctx, dagIDs, txn)
+	if err != nil {
+		return errors.Wrap(err, "Unexpected error occurred while retrieving workflow dags.")
+	}
+
 	if err := txn.Commit(ctx); err != nil {
 		return errors.Wrap(err, "Failed to commit retention transaction.")
 	}
 
+	// The storage files are deleted only once the transaction is committed,
+	// so that no remaining artifact result refers to a missing file.
+	storagePathsByDAG := make(map[uuid.UUID][]string, len(dags))
+	for contentPath, dagID := range contentPathToDAG {
+		storagePathsByDAG[dagID] = append(storagePathsByDAG[dagID], contentPath)
+	}
+
+	for _, dag := range dags {
+		if storagePaths, ok := storagePathsByDAG[dag.ID]; ok {
+			workflow_utils.CleanupStorageFiles(ctx, &dag.StorageConfig, storagePaths)
+		}
+	}
+
+	log.Infof(
+		"Deleted %d runs and %d storage files of workflow %v.",
+		len(dagResultIDs),
+		len(contentPathToDAG),
+		workflowObjectID,
+	)
 	return nil
 }
@@ -22,17 +22,19 @@ var (
 )
 
 const (
-	createUsage  = `create NAME TYPE       Creates a migration script with NAME of TYPE [sql|go].`
-	gotoUsage    = `goto V                 Migrate to version V.`
-	upUsage      = `up                     Migrate up one version.`
-	downUsage    = `down                   Migrate down one version.`
-	versionUsage = `version                Print out the current version.`
-
-	createCmd  = "create"
-	gotoCmd    = "goto"
-	upCmd      = "up"
-	downCmd    = "down"
-	versionCmd = "version"
+	createUsage   = `create NAME TYPE       Creates a migration script with NAME of TYPE [sql|go].`
+	gotoUsage     = `goto V                 Migrate to version V.`
+	upUsage       = `up                     Migrate up one version.`
+	downUsage     = `down                   Migrate down one version.`
+	versionUsage  = `version                Print out the current version.`
+	transferUsage = `transfer               Copy the SQLite database at -file into the Postgres database.`
+
+	createCmd   = "create"
+	gotoCmd     = "goto"
+	upCmd       = "up"
+	downCmd     = "down"
+	versionCmd  = "version"
+	transferCmd = "transfer"
 
 	createErrMsg = "create must be of form: migrate create NAME [sql|go]"
 	goToErrMsg   = "goto must be of form: migrate goto V"
@@ -48,6 +50,7 @@ func printUsage() {
 			- username 	Username for connecting to Postgres
 			- password 	Password for connecting to Postgres
 			- database 	The Postgres database to connect to
+			- file 		The SQLite database file
 		
 		COMMANDS:
 			%s 
@@ -55,7 +58,8 @@ func printUsage() {
 			%s
 			%s
 			%s
-	`, createUsage, gotoUsage, upUsage, downUsage, versionUsage)
+			%s
+	`, createUsage, gotoUsage, upUsage, downUsage, versionUsage, transferUsage)
 }
 
 func main() {
@@ -69,18 +73,12 @@ func main() {
 
 	var pgxConfig *database.PostgresConfig = nil
 	if databaseType == database.PostgresType {
-		pgxConfig = &database.PostgresConfig{
-			Address:  *host,
-			UserName: *user,
-			Password: *pwd,
-			Database: *db,
-			Port:     *port,
-		}
+		pgxConfig = newPostgresConfig()
 	}
 
 	var sqliteConfig *database.SqliteConfig = nil
 	if databaseType == database.SqliteType {
-		sqliteConfig = &database.SqliteConfig{File: *file}
+		sqliteConfig = newSqliteConfig()
 	}
 
 	conf := &database.DatabaseConfig{
@@ -115,8 +113,28 @@ func main() {
 		migrator.HandleDown(conf)
 	case versionCmd:
 		migrator.HandleVersion(conf)
+	case transferCmd:
+		// The transfer always reads from SQLite and writes to Postgres, regardless of -type.
+		migrator.HandleTransfer(
+			&database.DatabaseConfig{Type: database.SqliteType, Sqlite: newSqliteConfig()},
+			&database.DatabaseConfig{Type: database.PostgresType, Postgres: newPostgresConfig()},
+		)
 	default:
 		printUsage()
 		log.Fatal("Unknown command specified.")
 	}
 }
+
+func newPostgresConfig() *database.PostgresConfig {
+	return &database.PostgresConfig{
+		Address:  *host,
+		UserName: *user,
+		Password: *pwd,
+		Database: *db,
+		Port:     *port,
+	}
+}
+
+func newSqliteConfig() *database.SqliteConfig {
+	return &database.SqliteConfig{File: *file}
+}
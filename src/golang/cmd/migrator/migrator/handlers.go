@@ -3,6 +3,7 @@ package migrator
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
@@ -81,6 +82,24 @@ func HandleVersion(conf *database.DatabaseConfig) {
 	}
 }
 
+func HandleTransfer(srcConf *database.DatabaseConfig, destConf *database.DatabaseConfig) {
+	if _, err := os.Stat(srcConf.Sqlite.File); err != nil {
+		log.Fatalf("Unable to find the SQLite database to transfer: %v", err)
+	}
+
+	src := createDatabaseClient(srcConf)
+	defer src.Close()
+
+	dest := createDatabaseClient(destConf)
+	defer dest.Close()
+
+	if err := Transfer(context.Background(), src, dest); err != nil {
+		log.Fatalf("Unexpected error running transfer: %v", err)
+	}
+
+	log.Info("Successfully transferred the SQLite database to Postgres.")
+}
+
 // createDatabaseClient creates a database.Database client based on config provided.
 func createDatabaseClient(conf *database.DatabaseConfig) database.Database {
 	db, err := database.NewDatabase(conf)
@@ -13,8 +13,7 @@ import (
 func GoTo(ctx context.Context, version int64, db database.Database) error {
 	current, dirty, err := Version(ctx, db)
 	if err != nil {
-		if strings.Contains(err.Error(), database.ErrCodeTableDoesNotExist) ||
-			strings.Contains(err.Error(), "no such table") {
+		if isSchemaVersionTableMissing(err) {
 			// We are running a schema migration for the first time, so
 			// the schema_version table does not exist.
 			current, dirty = 0, false
@@ -28,10 +27,6 @@ func GoTo(ctx context.Context, version int64, db database.Database) error {
 		return nil
 	}
 
-	if version < current && db.Type() == database.SqliteType {
-		return errors.New("Down schema changes are not supported for SQLite.")
-	}
-
 	migrator, err := newMigrator(current, version, dirty)
 	if err != nil {
 		return errors.Wrap(err, "Unable to initiate migration.")
@@ -65,10 +60,6 @@ func Up(ctx context.Context, db database.Database) error {
 
 // Down performs a single down migration. It returns an error, if any.
 func Down(ctx context.Context, db database.Database) error {
-	if db.Type() == database.SqliteType {
-		return errors.New("Down schema changes are not supported for SQLite.")
-	}
-
 	current, dirty, err := Version(ctx, db)
 	if err != nil {
 		return errors.Wrap(err, "Unable to check current version.")
@@ -85,3 +76,10 @@ func Down(ctx context.Context, db database.Database) error {
 
 	return nil
 }
+
+// isSchemaVersionTableMissing returns whether err was caused by the
+// schema_version table not existing yet.
+func isSchemaVersionTableMissing(err error) bool {
+	return strings.Contains(err.Error(), database.ErrCodeTableDoesNotExist) ||
+		strings.Contains(err.Error(), "no such table")
+}
@@ -6,7 +6,7 @@ import (
 
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models"
-	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
 	"github.com/dropbox/godropbox/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -138,11 +138,21 @@ func executeDown(ctx context.Context, db database.Database, step *migrationStep)
 		return errors.Wrap(err, "Unable to set schema version record to dirty.")
 	}
 
-	if db.Type() != database.PostgresType {
-		return errors.Newf("Down schema changes are not defined for: %v", db.Type())
+	var down migrationFunc
+	switch db.Type() {
+	case database.PostgresType:
+		down = step.downPostgres
+	case database.SqliteType:
+		down = step.downSqlite
+	default:
+		return errors.Newf("Unknown database type: %v", db.Type())
+	}
+
+	if down == nil {
+		return errors.Newf("Down schema change is not defined for schema version %d.", step.version)
 	}
 
-	err := step.downPostgres(ctx, db)
+	err := down(ctx, db)
 	if err != nil {
 		log.Errorf("Failed migrate down to schema version %d.", step.version)
 		return err
@@ -160,7 +170,7 @@ func executeDown(ctx context.Context, db database.Database, step *migrationStep)
 // If the schema version record already exists (due to a previous dirty run), then the
 // schema version is simply set to dirty.
 func createSchemaVersionRecord(ctx context.Context, version int64, name string, db database.Database) error {
-	schemaVersionRepo := sqlite.NewSchemaVersionRepo()
+	schemaVersionRepo := sql.NewSchemaVersionRepo()
 
 	_, err := schemaVersionRepo.Get(ctx, version, db)
 	if err == nil {
@@ -174,7 +184,7 @@ func createSchemaVersionRecord(ctx context.Context, version int64, name string,
 
 // setSchemaVersionRecordDirty updates the dirty column in the database for the schema version.
 func setSchemaVersionRecordDirty(ctx context.Context, version int64, dirty bool, db database.Database) error {
-	schemaVersionRepo := sqlite.NewSchemaVersionRepo()
+	schemaVersionRepo := sql.NewSchemaVersionRepo()
 
 	_, err := schemaVersionRepo.Update(
 		ctx,
@@ -189,7 +199,7 @@ func setSchemaVersionRecordDirty(ctx context.Context, version int64, dirty bool,
 
 // deleteSchemaVersionRecord deletes the record for the schema version from the database.
 func deleteSchemaVersionRecord(ctx context.Context, version int64, db database.Database) error {
-	schemaVersionRepo := sqlite.NewSchemaVersionRepo()
+	schemaVersionRepo := sql.NewSchemaVersionRepo()
 
 	return schemaVersionRepo.Delete(ctx, version, db)
 }
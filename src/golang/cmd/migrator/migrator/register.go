@@ -31,6 +31,15 @@ import (
 	_000026 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000026_drop_integration_validated_column"
 	_000027 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000027_rename_integrations_table"
 	_000028 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000028_add_artifact_should_persist_column"
+	_000029 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000029_add_workflow_max_parallel_operators_column"
+	_000030 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000030_add_dag_result_pinned_column"
+	_000031 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000031_add_storage_migration_progress_columns"
+	_000032 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000032_add_run_request_table"
+	_000033 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000033_add_scheduler_lease_table"
+	_000034 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000034_add_gc_column_to_env_table_postgres"
+	_000035 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000035_add_storage_migration_object_table"
+	_000036 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000036_add_workflow_cascade_triggered_at_column"
+	_000037 "github.com/aqueducthq/aqueduct/cmd/migrator/versions/000037_add_run_request_dag_result_id_column"
 	"github.com/aqueducthq/aqueduct/lib/database"
 )
 
@@ -42,6 +51,7 @@ type migration struct {
 	upPostgres   migrationFunc
 	upSqlite     migrationFunc
 	downPostgres migrationFunc
+	downSqlite   migrationFunc
 	name         string
 }
 
@@ -52,157 +62,211 @@ func init() {
 	}
 	registeredMigrations[2] = &migration{
 		upPostgres: _000002.UpPostgres, upSqlite: _000002.UpSqlite,
-		downPostgres: _000002.DownPostgres,
-		name:         "add integration.user_id",
+		downPostgres: _000002.DownPostgres, downSqlite: _000002.DownSqlite,
+		name: "add integration.user_id",
 	}
 	registeredMigrations[3] = &migration{
 		upPostgres: _000003.UpPostgres, upSqlite: _000003.UpSqlite,
-		downPostgres: _000003.DownPostgres,
-		name:         "add workflow_dag.storage_config",
+		downPostgres: _000003.DownPostgres, downSqlite: _000003.DownSqlite,
+		name: "add workflow_dag.storage_config",
 	}
 	registeredMigrations[4] = &migration{
 		upPostgres: _000004.Up, upSqlite: _000004.Up,
-		downPostgres: _000004.Down,
-		name:         "backfill workflow_dag.storage_config and operator.spec.storage_path",
+		downPostgres: _000004.Down, downSqlite: _000004.Down,
+		name: "backfill workflow_dag.storage_config and operator.spec.storage_path",
 	}
 	registeredMigrations[5] = &migration{
 		upPostgres: _000005.UpPostgres, upSqlite: _000005.UpSqlite,
-		downPostgres: _000005.DownPostgres,
-		name:         "add not null constraint to workflow_dag.storage_config",
+		downPostgres: _000005.DownPostgres, downSqlite: _000005.DownSqlite,
+		name: "add not null constraint to workflow_dag.storage_config",
 	}
 	registeredMigrations[6] = &migration{
 		upPostgres: _000006.UpPostgres, upSqlite: _000006.UpSqlite,
-		downPostgres: _000006.DownPostgres,
-		name:         "add workflow.retention_policy",
+		downPostgres: _000006.DownPostgres, downSqlite: _000006.DownSqlite,
+		name: "add workflow.retention_policy",
 	}
 	registeredMigrations[7] = &migration{
 		upPostgres: _000007.UpPostgres, upSqlite: _000007.UpSqlite,
-		downPostgres: _000007.DownPostgres,
-		name:         "add primary key constraint to workflow_dag_edge on workflow_dag_id, from_id, to_id",
+		downPostgres: _000007.DownPostgres, downSqlite: _000007.DownSqlite,
+		name: "add primary key constraint to workflow_dag_edge on workflow_dag_id, from_id, to_id",
 	}
 
 	registeredMigrations[8] = &migration{
 		upPostgres: _000008.UpPostgres, upSqlite: _000008.UpSqlite,
-		downPostgres: _000008.DownPostgres,
-		name:         "delete outdated s3_config column",
+		downPostgres: _000008.DownPostgres, downSqlite: _000008.DownSqlite,
+		name: "delete outdated s3_config column",
 	}
 
 	registeredMigrations[9] = &migration{
 		upPostgres: _000009.Up, upSqlite: _000009.Up,
-		downPostgres: _000009.Down,
-		name:         "backfill metadata in artifact_results",
+		downPostgres: _000009.Down, downSqlite: _000009.Down,
+		name: "backfill metadata in artifact_results",
 	}
 
 	registeredMigrations[10] = &migration{
 		upPostgres: _000010.UpPostgres, upSqlite: _000010.UpSqlite,
-		downPostgres: _000010.DownPostgres,
-		name:         "add exec state column to operator_result",
+		downPostgres: _000010.DownPostgres, downSqlite: _000010.DownSqlite,
+		name: "add exec state column to operator_result",
 	}
 
 	registeredMigrations[11] = &migration{
 		upPostgres: _000011.Up, upSqlite: _000011.Up,
-		downPostgres: _000011.Down,
-		name:         "backfill exec state column in operator_result",
+		downPostgres: _000011.Down, downSqlite: _000011.Down,
+		name: "backfill exec state column in operator_result",
 	}
 
 	registeredMigrations[12] = &migration{
 		upPostgres: _000012.UpPostgres, upSqlite: _000012.UpSqlite,
-		downPostgres: _000012.DownPostgres,
-		name:         "remove metadata in operator_result",
+		downPostgres: _000012.DownPostgres, downSqlite: _000012.DownSqlite,
+		name: "remove metadata in operator_result",
 	}
 
 	registeredMigrations[13] = &migration{
 		upPostgres: _000013.UpPostgres, upSqlite: _000013.UpSqlite,
-		downPostgres: _000013.DownPostgres,
-		name:         "add workflow_dag.engine_config",
+		downPostgres: _000013.DownPostgres, downSqlite: _000013.DownSqlite,
+		name: "add workflow_dag.engine_config",
 	}
 
 	registeredMigrations[14] = &migration{
 		upPostgres: _000014.UpPostgres, upSqlite: _000014.UpSqlite,
-		downPostgres: _000014.DownPostgres,
-		name:         "add exec state column to artifact result",
+		downPostgres: _000014.DownPostgres, downSqlite: _000014.DownSqlite,
+		name: "add exec state column to artifact result",
 	}
 
 	registeredMigrations[15] = &migration{
 		upPostgres: _000015.Up, upSqlite: _000015.Up,
-		downPostgres: _000015.Down,
-		name:         "backfill exec state column in artifact result",
+		downPostgres: _000015.Down, downSqlite: _000015.Down,
+		name: "backfill exec state column in artifact result",
 	}
 
 	registeredMigrations[16] = &migration{
 		upPostgres: _000016.UpPostgres, upSqlite: _000016.UpSqlite,
-		downPostgres: _000016.DownPostgres,
-		name:         "add type column to artifact",
+		downPostgres: _000016.DownPostgres, downSqlite: _000016.DownSqlite,
+		name: "add type column to artifact",
 	}
 
 	registeredMigrations[17] = &migration{
 		upPostgres: _000017.Up, upSqlite: _000017.Up,
-		downPostgres: _000017.Down,
-		name:         "add canceled status to results",
+		downPostgres: _000017.Down, downSqlite: _000017.Down,
+		name: "add canceled status to results",
 	}
 
 	registeredMigrations[18] = &migration{
 		upPostgres: _000018.UpPostgres, upSqlite: _000018.UpSqlite,
-		downPostgres: _000018.DownPostgres,
-		name:         "add exec state to workflow_dag_result",
+		downPostgres: _000018.DownPostgres, downSqlite: _000018.DownSqlite,
+		name: "add exec state to workflow_dag_result",
 	}
 
 	registeredMigrations[19] = &migration{
 		upPostgres: _000019.Up, upSqlite: _000019.Up,
-		downPostgres: _000019.Down,
-		name:         "add serialization type and value to param op",
+		downPostgres: _000019.Down, downSqlite: _000019.Down,
+		name: "add serialization type and value to param op",
 	}
 
 	registeredMigrations[20] = &migration{
 		upPostgres: _000020.UpPostgres, upSqlite: _000020.UpSqlite,
-		downPostgres: _000020.DownPostgres,
-		name:         "add execution environment table",
+		downPostgres: _000020.DownPostgres, downSqlite: _000020.DownSqlite,
+		name: "add execution environment table",
 	}
 
 	registeredMigrations[21] = &migration{
 		upPostgres: _000021.UpPostgres, upSqlite: _000021.UpSqlite,
-		downPostgres: _000021.DownPostgres,
-		name:         "add gc column to the execution environment table",
+		downPostgres: _000021.DownPostgres, downSqlite: _000021.DownSqlite,
+		name: "add gc column to the execution environment table",
 	}
 
 	registeredMigrations[22] = &migration{
 		upPostgres: _000022.Up, upSqlite: _000022.Up,
-		downPostgres: _000022.Down,
-		name:         "backfill python type to the artifact result table",
+		downPostgres: _000022.Down, downSqlite: _000022.Down,
+		name: "backfill python type to the artifact result table",
 	}
 
 	registeredMigrations[23] = &migration{
 		upPostgres: _000023.UpPostgres, upSqlite: _000023.UpSqlite,
-		downPostgres: _000023.DownPostgres,
-		name:         "add notification_settings column to workflow table",
+		downPostgres: _000023.DownPostgres, downSqlite: _000023.DownSqlite,
+		name: "add notification_settings column to workflow table",
 	}
 
 	registeredMigrations[24] = &migration{
 		upPostgres: _000024.UpPostgres, upSqlite: _000024.UpSqlite,
-		downPostgres: _000024.DownPostgres,
-		name:         "migrate exec env to conda engine",
+		downPostgres: _000024.DownPostgres, downSqlite: _000024.DownSqlite,
+		name: "migrate exec env to conda engine",
 	}
 
 	registeredMigrations[25] = &migration{
 		upPostgres: _000025.UpPostgres, upSqlite: _000025.UpSqlite,
-		downPostgres: _000025.DownPostgres,
-		name:         "add storage migration table",
+		downPostgres: _000025.DownPostgres, downSqlite: _000025.DownSqlite,
+		name: "add storage migration table",
 	}
 
 	registeredMigrations[26] = &migration{
 		upPostgres: _000026.UpPostgres, upSqlite: _000026.UpSqlite,
-		downPostgres: _000026.DownPostgres,
-		name:         "remove validated column from integration table",
+		downPostgres: _000026.DownPostgres, downSqlite: _000026.DownSqlite,
+		name: "remove validated column from integration table",
 	}
 	registeredMigrations[27] = &migration{
 		upPostgres: _000027.UpPostgres, upSqlite: _000027.UpSqlite,
-		downPostgres: _000027.DownPostgres,
-		name:         "rename integration table to resource",
+		downPostgres: _000027.DownPostgres, downSqlite: _000027.DownSqlite,
+		name: "rename integration table to resource",
 	}
 
 	registeredMigrations[28] = &migration{
 		upPostgres: _000028.UpPostgres, upSqlite: _000028.UpSqlite,
-		downPostgres: _000028.DownPostgres,
-		name:         "add should_persist column to artifact table",
+		downPostgres: _000028.DownPostgres, downSqlite: _000028.DownSqlite,
+		name: "add should_persist column to artifact table",
+	}
+
+	registeredMigrations[29] = &migration{
+		upPostgres: _000029.UpPostgres, upSqlite: _000029.UpSqlite,
+		downPostgres: _000029.DownPostgres, downSqlite: _000029.DownSqlite,
+		name: "add max_parallel_operators column to workflow table",
+	}
+
+	registeredMigrations[30] = &migration{
+		upPostgres: _000030.UpPostgres, upSqlite: _000030.UpSqlite,
+		downPostgres: _000030.DownPostgres, downSqlite: _000030.DownSqlite,
+		name: "add pinned column to workflow_dag_result table",
+	}
+
+	registeredMigrations[31] = &migration{
+		upPostgres: _000031.UpPostgres, upSqlite: _000031.UpSqlite,
+		downPostgres: _000031.DownPostgres, downSqlite: _000031.DownSqlite,
+		name: "add progress columns to storage_migration table",
+	}
+
+	registeredMigrations[32] = &migration{
+		upPostgres: _000032.UpPostgres, upSqlite: _000032.UpSqlite,
+		downPostgres: _000032.DownPostgres, downSqlite: _000032.DownSqlite,
+		name: "add run_request table",
+	}
+
+	registeredMigrations[33] = &migration{
+		upPostgres: _000033.UpPostgres, upSqlite: _000033.UpSqlite,
+		downPostgres: _000033.DownPostgres, downSqlite: _000033.DownSqlite,
+		name: "add scheduler_lease table",
+	}
+
+	registeredMigrations[34] = &migration{
+		upPostgres: _000034.UpPostgres, upSqlite: _000034.UpSqlite,
+		downPostgres: _000034.DownPostgres, downSqlite: _000034.DownSqlite,
+		name: "add garbage_collected column to execution_environment table on postgres",
+	}
+
+	registeredMigrations[35] = &migration{
+		upPostgres: _000035.UpPostgres, upSqlite: _000035.UpSqlite,
+		downPostgres: _000035.DownPostgres, downSqlite: _000035.DownSqlite,
+		name: "add storage_migration_object table",
+	}
+
+	registeredMigrations[36] = &migration{
+		upPostgres: _000036.UpPostgres, upSqlite: _000036.UpSqlite,
+		downPostgres: _000036.DownPostgres, downSqlite: _000036.DownSqlite,
+		name: "add cascade_triggered_at column to workflow table",
+	}
+
+	registeredMigrations[37] = &migration{
+		upPostgres: _000037.UpPostgres, upSqlite: _000037.UpSqlite,
+		downPostgres: _000037.DownPostgres, downSqlite: _000037.DownSqlite,
+		name: "add dag_result_id column to run_request table",
 	}
 }
@@ -15,6 +15,7 @@ const (
 	sqliteScript = ""
 	upPostgresScript = ""
 	downPostgresScript = ""
+	downSqliteScript = ""
 )
 
 func UpPostgres(ctx context.Context, db database.Database) error {
@@ -29,6 +30,10 @@ func UpSqlite(ctx context.Context, db database.Database) error {
 	return db.Execute(ctx, sqliteScript)
 }
 
+func DownSqlite(ctx context.Context, db database.Database) error {
+	return db.Execute(ctx, downSqliteScript)
+}
+
 `
 
 	goScriptHeader = `package _{{.Dir}}
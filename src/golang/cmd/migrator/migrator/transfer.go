@@ -0,0 +1,406 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/database/stmt_preparers"
+	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// transferBatchSize is the number of rows copied in each insert statement.
+const transferBatchSize = 100
+
+// transferTables lists the metadata tables in the order they are copied, so that
+// the rows referenced by a foreign key are always copied before the rows referencing them.
+// Both `integration` and `resource` are listed, since the table was renamed in v000027.
+var transferTables = []string{
+	"app_user",
+	"integration",
+	"resource",
+	"notification",
+	"workflow",
+	"run_request",
+	"workflow_dag",
+	"execution_environment",
+	"operator",
+	"artifact",
+	"workflow_dag_edge",
+	"workflow_dag_result",
+	"operator_result",
+	"artifact_result",
+	"workflow_watcher",
+	"storage_migration",
+	"storage_migration_object",
+}
+
+// skippedTables lists the tables that are not transferred.
+var skippedTables = map[string]bool{
+	// The schema version records are created by migrating the destination.
+	"schema_version": true,
+	// Leases are only held while the server that acquired them is running. A copied lease
+	// would keep the servers using dest from electing a scheduler leader until it expires.
+	"scheduler_lease": true,
+}
+
+// Transfer copies all metadata in the SQLite database src into the Postgres database dest.
+// dest is first migrated to the same schema version as src. Tables that were already fully
+// copied are skipped and rows that already exist in dest are ignored, so an interrupted
+// transfer can be resumed by running it again. Once all rows are copied, the row counts of
+// each table are compared between src and dest. It returns an error, if any.
+func Transfer(ctx context.Context, src database.Database, dest database.Database) error {
+	if src.Type() != database.SqliteType || dest.Type() != database.PostgresType {
+		return errors.Newf(
+			"Transfer is only supported from SQLite to Postgres, but got %v to %v.",
+			src.Type(),
+			dest.Type(),
+		)
+	}
+
+	srcVersion, dirty, err := Version(ctx, src)
+	if err != nil {
+		return errors.Wrap(err, "Unable to check source schema version.")
+	}
+	if dirty {
+		return errors.Newf("The source schema version %d is dirty.", srcVersion)
+	}
+
+	destVersion, dirty, err := Version(ctx, dest)
+	if err != nil && !isSchemaVersionTableMissing(err) {
+		return errors.Wrap(err, "Unable to check destination schema version.")
+	}
+	if err == nil && (destVersion != srcVersion || dirty) {
+		return errors.Newf(
+			"The destination schema version %d (dirty: %v) does not match the source schema version %d.",
+			destVersion,
+			dirty,
+			srcVersion,
+		)
+	}
+
+	if err := GoTo(ctx, srcVersion, dest); err != nil {
+		return errors.Wrap(err, "Unable to migrate destination to the source schema version.")
+	}
+
+	tables, err := getSqliteTables(ctx, src)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list source tables.")
+	}
+
+	for _, table := range transferTables {
+		if _, ok := tables[table]; !ok {
+			continue
+		}
+
+		if err := transferTable(ctx, table, src, dest); err != nil {
+			return errors.Wrapf(err, "Unable to transfer table %s.", table)
+		}
+	}
+
+	return verifyTransfer(ctx, tables, src, dest)
+}
+
+// transferTable copies all rows of table from src to dest in batches.
+func transferTable(ctx context.Context, table string, src database.Database, dest database.Database) error {
+	srcCount, err := countRows(ctx, table, src)
+	if err != nil {
+		return err
+	}
+
+	destCount, err := countRows(ctx, table, dest)
+	if err != nil {
+		return err
+	}
+
+	if destCount == srcCount {
+		log.Infof("Skipping table %s since all %d rows have already been transferred.", table, srcCount)
+		return nil
+	}
+
+	if destCount > srcCount {
+		return errors.Newf("Destination has %d rows, but source only has %d rows.", destCount, srcCount)
+	}
+
+	columns, err := getSqliteColumns(ctx, table, src)
+	if err != nil {
+		return err
+	}
+
+	destColumnTypes, err := getPostgresColumnTypes(ctx, table, dest)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range columns {
+		if _, ok := destColumnTypes[column]; !ok {
+			return errors.Newf("Column %s does not exist in the destination.", column)
+		}
+	}
+
+	log.Infof("Transferring %d rows of table %s.", srcCount, table)
+
+	// Rows are paged through by rowid, which is stable for the lifetime of the source table.
+	lastRowID := int64(0)
+	for {
+		rowIDs, rows, err := readSqliteRows(ctx, table, columns, lastRowID, src)
+		if err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			for i, column := range columns {
+				row[i] = convertValue(row[i], destColumnTypes[column])
+			}
+		}
+
+		if err := insertPostgresRows(ctx, table, columns, rows, dest); err != nil {
+			return err
+		}
+
+		lastRowID = rowIDs[len(rowIDs)-1]
+	}
+
+	return nil
+}
+
+// verifyTransfer checks that every table in tables has the same number of rows in src and dest.
+func verifyTransfer(ctx context.Context, tables map[string]bool, src database.Database, dest database.Database) error {
+	mismatches := make([]string, 0)
+	for _, table := range transferTables {
+		if _, ok := tables[table]; !ok {
+			continue
+		}
+
+		srcCount, err := countRows(ctx, table, src)
+		if err != nil {
+			return err
+		}
+
+		destCount, err := countRows(ctx, table, dest)
+		if err != nil {
+			return err
+		}
+
+		if srcCount != destCount {
+			mismatches = append(
+				mismatches,
+				fmt.Sprintf("%s (source: %d, destination: %d)", table, srcCount, destCount),
+			)
+			continue
+		}
+
+		log.Infof("Verified %d rows in table %s.", srcCount, table)
+	}
+
+	if len(mismatches) > 0 {
+		return errors.Newf("Row counts do not match for tables: %s", strings.Join(mismatches, ", "))
+	}
+
+	return nil
+}
+
+// getSqliteTables returns the set of tables in db that must be transferred.
+// It returns an error if db contains a table that is not known to the transfer.
+func getSqliteTables(ctx context.Context, db database.Database) (map[string]bool, error) {
+	query := `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%';`
+
+	var results []struct {
+		Name string `db:"name"`
+	}
+	if err := db.Query(ctx, &results, query); err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(transferTables))
+	for _, table := range transferTables {
+		known[table] = true
+	}
+
+	tables := make(map[string]bool, len(results))
+	for _, result := range results {
+		if skippedTables[result.Name] {
+			continue
+		}
+
+		if !known[result.Name] {
+			return nil, errors.Newf("Table %s is not supported for transfer.", result.Name)
+		}
+
+		tables[result.Name] = true
+	}
+
+	return tables, nil
+}
+
+// getSqliteColumns returns the column names of table in db.
+func getSqliteColumns(ctx context.Context, table string, db database.Database) ([]string, error) {
+	query := `SELECT name FROM pragma_table_info($1) ORDER BY cid;`
+
+	var results []struct {
+		Name string `db:"name"`
+	}
+	if err := db.Query(ctx, &results, query, table); err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(results))
+	for _, result := range results {
+		columns = append(columns, result.Name)
+	}
+
+	return columns, nil
+}
+
+// getPostgresColumnTypes returns a map of the column names of table in db to their data type.
+func getPostgresColumnTypes(ctx context.Context, table string, db database.Database) (map[string]string, error) {
+	query := `
+	SELECT column_name, data_type
+	FROM information_schema.columns
+	WHERE table_schema = current_schema() AND table_name = $1;`
+
+	var results []struct {
+		Name     string `db:"column_name"`
+		DataType string `db:"data_type"`
+	}
+	if err := db.Query(ctx, &results, query, table); err != nil {
+		return nil, err
+	}
+
+	columnTypes := make(map[string]string, len(results))
+	for _, result := range results {
+		columnTypes[result.Name] = result.DataType
+	}
+
+	return columnTypes, nil
+}
+
+// readSqliteRows reads the next batch of rows of table with a rowid greater than afterRowID.
+// It returns the rowids and the values of columns for each row.
+func readSqliteRows(
+	ctx context.Context,
+	table string,
+	columns []string,
+	afterRowID int64,
+	db database.Database,
+) ([]int64, [][]interface{}, error) {
+	// The rows are scanned into a struct that is built at runtime, since the columns
+	// of each table are only known once the source database is inspected.
+	fields := make([]reflect.StructField, 0, len(columns)+1)
+	fields = append(fields, reflect.StructField{
+		Name: "RowID",
+		Type: reflect.TypeOf(int64(0)),
+		Tag:  `db:"transfer_rowid"`,
+	})
+	for i, column := range columns {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Column%d", i),
+			Type: reflect.TypeOf((*interface{})(nil)).Elem(),
+			Tag:  reflect.StructTag(fmt.Sprintf(`db:"%s"`, column)),
+		})
+	}
+	rowType := reflect.StructOf(fields)
+
+	quotedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, stmt_preparers.DoubleQuoteIdentifier(column))
+	}
+
+	query := fmt.Sprintf(
+		`SELECT rowid AS transfer_rowid, %s FROM %s WHERE rowid > $1 ORDER BY rowid LIMIT $2;`,
+		strings.Join(quotedColumns, ", "),
+		stmt_preparers.DoubleQuoteIdentifier(table),
+	)
+
+	results := reflect.New(reflect.SliceOf(rowType))
+	if err := db.Query(ctx, results.Interface(), query, afterRowID, transferBatchSize); err != nil {
+		return nil, nil, err
+	}
+
+	resultSlice := results.Elem()
+	rowIDs := make([]int64, 0, resultSlice.Len())
+	rows := make([][]interface{}, 0, resultSlice.Len())
+	for i := 0; i < resultSlice.Len(); i++ {
+		result := resultSlice.Index(i)
+		rowIDs = append(rowIDs, result.Field(0).Int())
+
+		row := make([]interface{}, 0, len(columns))
+		for j := range columns {
+			row = append(row, result.Field(j+1).Interface())
+		}
+		rows = append(rows, row)
+	}
+
+	return rowIDs, rows, nil
+}
+
+// convertValue converts a value read from SQLite to a value that can be written
+// to a Postgres column of dataType.
+func convertValue(value interface{}, dataType string) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		// JSON columns are stored as BLOBs in SQLite. Postgres parses the text
+		// representation into the JSONB column, as well as into UUID and text columns.
+		return string(v)
+	case int64:
+		// SQLite does not have a boolean type and stores booleans as integers.
+		if dataType == "boolean" {
+			return v != 0
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// insertPostgresRows inserts rows into table with a single statement, so either all or none
+// of the rows are written. Rows that already exist in db are ignored.
+func insertPostgresRows(
+	ctx context.Context,
+	table string,
+	columns []string,
+	rows [][]interface{},
+	db database.Database,
+) error {
+	quotedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, stmt_preparers.DoubleQuoteIdentifier(column))
+	}
+
+	values := make([]string, 0, len(rows))
+	args := make([]interface{}, 0, len(rows)*len(columns))
+	for _, row := range rows {
+		values = append(values, fmt.Sprintf("(%s)", stmt_preparers.GenerateArgsList(len(columns), len(args)+1)))
+		args = append(args, row...)
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES %s ON CONFLICT DO NOTHING;`,
+		stmt_preparers.DoubleQuoteIdentifier(table),
+		strings.Join(quotedColumns, ", "),
+		strings.Join(values, ", "),
+	)
+
+	return db.Execute(ctx, query, args...)
+}
+
+// countRows returns the number of rows in table.
+func countRows(ctx context.Context, table string, db database.Database) (int64, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) AS count FROM %s;`, stmt_preparers.DoubleQuoteIdentifier(table))
+
+	var result struct {
+		Count int64 `db:"count"`
+	}
+	if err := db.Query(ctx, &result, query); err != nil {
+		return -1, err
+	}
+
+	return result.Count, nil
+}
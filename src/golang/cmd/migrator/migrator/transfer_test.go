@@ -0,0 +1,188 @@
+package migrator
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	runPostgres = flag.Bool("postgres", false, "If this flag is set, the transfer to Postgres is tested. All tables in the Postgres database are dropped.")
+	host        = flag.String("host", "localhost", "The host of the Postgres server to connect to.")
+	port        = flag.String("port", "5432", "The port number to connect to the Postgres database.")
+	user        = flag.String("username", "postgres", "The username for connecting to the Postgres database.")
+	password    = flag.String("password", "", "The password for connecting to the Postgres database.")
+	dbName      = flag.String("dbName", "aqueduct_test", "The Postgres database to transfer to.")
+)
+
+// newSqliteDatabase returns an in-memory SQLite database migrated to version.
+func newSqliteDatabase(t *testing.T, version int64) database.Database {
+	DB, err := database.NewSqliteInMemoryDatabase(&database.SqliteConfig{})
+	require.Nil(t, err)
+	t.Cleanup(DB.Close)
+
+	require.Nil(t, GoTo(context.Background(), version, DB))
+	return DB
+}
+
+func TestMigrateDownAndUp(t *testing.T) {
+	ctx := context.Background()
+	DB := newSqliteDatabase(t, models.CurrentSchemaVersion)
+
+	// The initial schema of version 1 cannot be migrated down.
+	require.Nil(t, GoTo(ctx, 1, DB))
+	version, dirty, err := Version(ctx, DB)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), version)
+	require.False(t, dirty)
+
+	// The tables added by later versions are dropped by the down migrations.
+	tables, err := getSqliteTables(ctx, DB)
+	require.Nil(t, err)
+	require.True(t, tables["workflow"])
+	require.False(t, tables["run_request"])
+	require.False(t, tables["storage_migration"])
+
+	require.Nil(t, GoTo(ctx, models.CurrentSchemaVersion, DB))
+	version, dirty, err = Version(ctx, DB)
+	require.Nil(t, err)
+	require.Equal(t, int64(models.CurrentSchemaVersion), version)
+	require.False(t, dirty)
+}
+
+func TestGetSqliteTables(t *testing.T) {
+	DB := newSqliteDatabase(t, models.CurrentSchemaVersion)
+
+	// Every table of the current schema must be either transferred or skipped.
+	tables, err := getSqliteTables(context.Background(), DB)
+	require.Nil(t, err)
+	require.True(t, tables["run_request"])
+	require.True(t, tables["storage_migration_object"])
+	require.False(t, tables["scheduler_lease"])
+	require.False(t, tables["schema_version"])
+
+	require.Nil(t, DB.Execute(context.Background(), `CREATE TABLE unknown (id INTEGER);`))
+	_, err = getSqliteTables(context.Background(), DB)
+	require.NotNil(t, err)
+}
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		dataType string
+		expected interface{}
+	}{
+		{name: "json", value: []byte(`{"a": 1}`), dataType: "jsonb", expected: `{"a": 1}`},
+		{name: "uuid", value: []byte("c6b0e9a4-8a51-4c87-9c5c-1d4b1e7f7c11"), dataType: "uuid", expected: "c6b0e9a4-8a51-4c87-9c5c-1d4b1e7f7c11"},
+		{name: "true", value: int64(1), dataType: "boolean", expected: true},
+		{name: "false", value: int64(0), dataType: "boolean", expected: false},
+		{name: "integer", value: int64(1), dataType: "integer", expected: int64(1)},
+		{name: "text", value: "text", dataType: "character varying", expected: "text"},
+		{name: "null", value: nil, dataType: "jsonb", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, convertValue(test.value, test.dataType))
+		})
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	flag.Parse()
+	if !*runPostgres {
+		t.Skip("Skipping transfer to Postgres.")
+	}
+
+	ctx := context.Background()
+	src := newSqliteDatabase(t, models.CurrentSchemaVersion)
+
+	dest, err := database.NewPostgresDatabaseWithPort(&database.PostgresConfig{
+		Address:  *host,
+		Port:     *port,
+		UserName: *user,
+		Password: *password,
+		Database: *dbName,
+	})
+	require.Nil(t, err)
+	t.Cleanup(dest.Close)
+	require.Nil(t, dest.Execute(ctx, `DROP SCHEMA public CASCADE; CREATE SCHEMA public;`))
+
+	testUser, err := sql.NewUserRepo().Create(ctx, "aqueduct", uuid.NewString(), src)
+	require.Nil(t, err)
+
+	workflowRepo := sql.NewWorklowRepo()
+	workflow, err := workflowRepo.Create(
+		ctx,
+		testUser.ID,
+		"workflow",
+		"",
+		&shared.Schedule{Trigger: shared.ManualUpdateTrigger},
+		&shared.RetentionPolicy{},
+		&shared.NotificationSettings{},
+		0, /* maxParallelOperators */
+		src,
+	)
+	require.Nil(t, err)
+
+	runRequestRepo := sql.NewRunRequestRepo()
+	runRequest, err := runRequestRepo.Create(ctx, workflow.ID, shared.RunParameters{}, nil, "", src)
+	require.Nil(t, err)
+
+	storageMigrationRepo := sql.NewStorageMigrationRepo()
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+	storageMigration, err := storageMigrationRepo.Create(ctx, nil, storageConfig, storageConfig, false /* dryRun */, src)
+	require.Nil(t, err)
+	_, err = storageMigrationRepo.Update(ctx, storageMigration.ID, map[string]interface{}{
+		models.StorageMigrationCurrent: true,
+	}, src)
+	require.Nil(t, err)
+	require.Nil(t, storageMigrationRepo.CreateObjects(ctx, storageMigration.ID, map[string]string{"key": "checksum"}, src))
+
+	// A lease is only valid on the database it was acquired in.
+	_, err = sql.NewSchedulerLeaseRepo().Acquire(ctx, "scheduler", "holder", time.Minute, src)
+	require.Nil(t, err)
+
+	require.Nil(t, Transfer(ctx, src, dest))
+
+	version, dirty, err := Version(ctx, dest)
+	require.Nil(t, err)
+	require.Equal(t, int64(models.CurrentSchemaVersion), version)
+	require.False(t, dirty)
+
+	destWorkflow, err := workflowRepo.Get(ctx, workflow.ID, dest)
+	require.Nil(t, err)
+	require.Equal(t, workflow.Name, destWorkflow.Name)
+	require.Equal(t, workflow.Schedule, destWorkflow.Schedule)
+
+	destRunRequest, err := runRequestRepo.Get(ctx, runRequest.ID, dest)
+	require.Nil(t, err)
+	require.Equal(t, runRequest.Status, destRunRequest.Status)
+
+	destStorageMigration, err := storageMigrationRepo.Get(ctx, storageMigration.ID, dest)
+	require.Nil(t, err)
+	require.True(t, destStorageMigration.Current)
+
+	objects, err := storageMigrationRepo.GetObjects(ctx, storageMigration.ID, dest)
+	require.Nil(t, err)
+	require.Len(t, objects, 1)
+
+	leaseCount, err := countRows(ctx, "scheduler_lease", dest)
+	require.Nil(t, err)
+	require.Equal(t, int64(0), leaseCount)
+
+	// Transferring again skips the tables that were already copied.
+	require.Nil(t, Transfer(ctx, src, dest))
+}
@@ -0,0 +1,25 @@
+package _000002_add_user_id_to_integration
+
+const downSqliteScript = `
+BEGIN TRANSACTION;
+
+CREATE TABLE tmp_integration (
+    id BLOB NOT NULL PRIMARY KEY,
+    organization_id TEXT NOT NULL,
+    service TEXT NOT NULL,
+    name TEXT NOT NULL,
+    config BLOB NOT NULL,
+    created_at DATETIME NOT NULL,
+    validated BOOL NOT NULL
+);
+
+INSERT INTO tmp_integration(id, organization_id, service, name, config, created_at, validated)
+SELECT id, organization_id, service, name, config, created_at, validated
+FROM integration;
+
+DROP TABLE integration;
+
+ALTER TABLE tmp_integration RENAME TO integration;
+
+COMMIT;
+`
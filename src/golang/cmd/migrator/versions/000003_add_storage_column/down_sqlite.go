@@ -0,0 +1,6 @@
+package _000003_add_storage_column
+
+const downSqliteScript = `
+ALTER TABLE workflow_dag
+DROP COLUMN storage_config;
+`
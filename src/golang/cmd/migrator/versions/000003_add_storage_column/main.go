@@ -17,3 +17,7 @@ func DownPostgres(ctx context.Context, db database.Database) error {
 func UpSqlite(ctx context.Context, db database.Database) error {
 	return db.Execute(ctx, sqliteScript)
 }
+
+func DownSqlite(ctx context.Context, db database.Database) error {
+	return db.Execute(ctx, downSqliteScript)
+}
@@ -0,0 +1,23 @@
+package _000005_storage_interface_not_null
+
+const downSqliteScript = `
+BEGIN TRANSACTION;
+
+CREATE TABLE tmp_workflow_dag (
+    id BLOB NOT NULL PRIMARY KEY,
+    workflow_id BLOB NOT NULL REFERENCES workflow (id),
+    s3_config BLOB,
+    created_at DATETIME NOT NULL,
+    storage_config BLOB
+);
+
+INSERT INTO tmp_workflow_dag(id, workflow_id, s3_config, created_at, storage_config)
+SELECT id, workflow_id, s3_config, created_at, storage_config
+FROM workflow_dag;
+
+DROP TABLE workflow_dag;
+
+ALTER TABLE tmp_workflow_dag RENAME TO workflow_dag;
+
+COMMIT;
+`
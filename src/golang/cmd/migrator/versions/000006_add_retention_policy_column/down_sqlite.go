@@ -0,0 +1,6 @@
+package _000006_add_retention_policy_column
+
+const downSqliteScript = `
+ALTER TABLE workflow
+DROP COLUMN retention_policy;
+`
@@ -0,0 +1,23 @@
+package _000007_workflow_dag_edge_pk
+
+const downSqliteScript = `
+BEGIN TRANSACTION;
+
+CREATE TABLE tmp_workflow_dag_edge (
+    workflow_dag_id BLOB NOT NULL REFERENCES workflow_dag (id),
+    type TEXT NOT NULL,
+    from_id BLOB NOT NULL,
+    to_id BLOB NOT NULL,
+    idx INTEGER NOT NULL
+);
+
+INSERT INTO tmp_workflow_dag_edge(workflow_dag_id, type, from_id, to_id, idx)
+SELECT workflow_dag_id, type, from_id, to_id, idx
+FROM workflow_dag_edge;
+
+DROP TABLE workflow_dag_edge;
+
+ALTER TABLE tmp_workflow_dag_edge RENAME TO workflow_dag_edge;
+
+COMMIT;
+`
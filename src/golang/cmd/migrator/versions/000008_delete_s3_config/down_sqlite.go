@@ -0,0 +1,6 @@
+package _000008_delete_s3_config
+
+const downSqliteScript = `
+ALTER TABLE workflow_dag
+ADD COLUMN s3_config BLOB;
+`
@@ -0,0 +1,6 @@
+package _000010_add_exec_state_column
+
+const downSqliteScript = `
+ALTER TABLE operator_result
+DROP COLUMN execution_state;
+`
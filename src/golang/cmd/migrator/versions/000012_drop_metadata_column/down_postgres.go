@@ -1,5 +1,5 @@
 package _000012_drop_metadata_column
 
 const downPostgresScript = `
-ALTER TABLE op_result ADD COLUMN metadata JSONB;
+ALTER TABLE operator_result ADD COLUMN metadata JSONB;
 `
@@ -0,0 +1,6 @@
+package _000012_drop_metadata_column
+
+const downSqliteScript = `
+ALTER TABLE operator_result
+ADD COLUMN metadata BLOB;
+`
@@ -0,0 +1,6 @@
+package _000013_add_workflow_dag_engine_config
+
+const downSqliteScript = `
+ALTER TABLE workflow_dag
+DROP COLUMN engine_config;
+`
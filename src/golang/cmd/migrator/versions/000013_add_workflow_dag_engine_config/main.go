@@ -42,3 +42,7 @@ func UpSqlite(ctx context.Context, db database.Database) error {
 
 	return txn.Commit(ctx)
 }
+
+func DownSqlite(ctx context.Context, db database.Database) error {
+	return db.Execute(ctx, downSqliteScript)
+}
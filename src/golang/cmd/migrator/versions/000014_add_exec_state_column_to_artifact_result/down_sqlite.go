@@ -0,0 +1,6 @@
+package _000014_add_exec_state_column_to_artifact_result
+
+const downSqliteScript = `
+ALTER TABLE artifact_result
+DROP COLUMN execution_state;
+`
@@ -0,0 +1,20 @@
+package _000016_add_artifact_type_column_to_artifact
+
+// SQLite cannot add a NOT NULL column without a default, so the spec column is added
+// with an empty default and then rebuilt on a best-effort basis from the artifact type.
+const downSqliteScript = `
+ALTER TABLE artifact
+ADD COLUMN spec BLOB NOT NULL DEFAULT '{}';
+
+UPDATE artifact SET spec = CAST(
+	CASE type
+		WHEN 'table' THEN '{"type":"table","table":{}}'
+		WHEN 'numeric' THEN '{"type":"float","float":{}}'
+		WHEN 'boolean' THEN '{"type":"boolean","bool":{}}'
+		ELSE '{"type":"json","jsonable":{}}'
+	END AS BLOB
+);
+
+ALTER TABLE artifact
+DROP COLUMN type;
+`
@@ -43,3 +43,7 @@ func UpSqlite(ctx context.Context, db database.Database) error {
 func DownPostgres(ctx context.Context, db database.Database) error {
 	return db.Execute(ctx, downPostgresScript)
 }
+
+func DownSqlite(ctx context.Context, db database.Database) error {
+	return db.Execute(ctx, downSqliteScript)
+}
@@ -0,0 +1,6 @@
+package _000018_add_dag_result_exec_state_column
+
+const downSqliteScript = `
+ALTER TABLE workflow_dag_result
+DROP COLUMN execution_state;
+`
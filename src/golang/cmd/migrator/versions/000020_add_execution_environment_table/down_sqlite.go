@@ -0,0 +1,24 @@
+package _000020_add_execution_environment_table
+
+const downSqliteScript = `
+BEGIN TRANSACTION;
+
+CREATE TABLE tmp_operator (
+    id BLOB NOT NULL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    spec BLOB NOT NULL
+);
+
+INSERT INTO tmp_operator(id, name, description, spec)
+SELECT id, name, description, spec
+FROM operator;
+
+DROP TABLE operator;
+
+ALTER TABLE tmp_operator RENAME TO operator;
+
+DROP TABLE IF EXISTS execution_environment;
+
+COMMIT;
+`
@@ -0,0 +1,23 @@
+package _000021_add_gc_column_to_env_table
+
+const downSqliteScript = `
+BEGIN TRANSACTION;
+
+CREATE TABLE tmp_execution_environment (
+    id BLOB NOT NULL PRIMARY KEY,
+    spec BLOB NOT NULL,
+    hash BLOB NOT NULL UNIQUE
+);
+
+-- The hash column is only unique prior to this version, so any duplicate
+-- environments are dropped.
+INSERT OR IGNORE INTO tmp_execution_environment(id, spec, hash)
+SELECT id, spec, hash
+FROM execution_environment;
+
+DROP TABLE execution_environment;
+
+ALTER TABLE tmp_execution_environment RENAME TO execution_environment;
+
+COMMIT;
+`
@@ -6,8 +6,12 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/database"
 )
 
+// The Postgres scripts of this version use SQLite column types and fail on every Postgres
+// database, so no Postgres database has this version applied. They are kept as released, and
+// version 34 makes the same change on Postgres instead.
+
 func UpPostgres(ctx context.Context, db database.Database) error {
-	return db.Execute(ctx, upPostgresScript)
+	return nil
 }
 
 func UpSqlite(ctx context.Context, db database.Database) error {
@@ -15,5 +19,9 @@ func UpSqlite(ctx context.Context, db database.Database) error {
 }
 
 func DownPostgres(ctx context.Context, db database.Database) error {
-	return db.Execute(ctx, downPostgresScript)
+	return nil
+}
+
+func DownSqlite(ctx context.Context, db database.Database) error {
+	return db.Execute(ctx, downSqliteScript)
 }
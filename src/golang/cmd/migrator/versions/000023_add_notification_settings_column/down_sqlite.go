@@ -0,0 +1,6 @@
+package _000023_add_notification_settings_column
+
+const downSqliteScript = `
+ALTER TABLE workflow
+DROP COLUMN notification_settings;
+`
@@ -0,0 +1,10 @@
+package _000024_migrate_exec_env_to_conda_engine
+
+const downSqliteScript = `
+UPDATE operator SET execution_environment_id = NULL
+WHERE json_extract(spec, '$.engine_config.type') != 'aqueduct_conda';
+
+UPDATE operator SET spec = CAST(
+	json_set(spec, '$.engine_config', json_object('type', 'aqueduct')) AS BLOB
+) WHERE json_extract(spec, '$.engine_config.type') = 'aqueduct_conda';
+`
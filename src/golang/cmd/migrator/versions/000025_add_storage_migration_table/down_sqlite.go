@@ -0,0 +1,5 @@
+package _00025_add_storage_migration_table
+
+const downSqliteScript = `
+DROP TABLE IF EXISTS storage_migration;
+`
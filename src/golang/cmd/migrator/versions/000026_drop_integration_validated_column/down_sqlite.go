@@ -0,0 +1,8 @@
+package _00026_drop_integration_validated_column
+
+const downSqliteScript = `
+-- Every integration is validated before it is created, so existing rows are
+-- backfilled as validated.
+ALTER TABLE integration
+ADD COLUMN validated BOOL NOT NULL DEFAULT TRUE;
+`
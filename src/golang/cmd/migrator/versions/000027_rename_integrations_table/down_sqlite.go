@@ -0,0 +1,5 @@
+package _00027_rename_integrations_table
+
+const downSqliteScript = `
+ALTER TABLE resource RENAME TO integration;
+`
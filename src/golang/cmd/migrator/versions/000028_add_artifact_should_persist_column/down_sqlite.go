@@ -0,0 +1,6 @@
+package _000028_add_artifact_should_persist_column
+
+const downSqliteScript = `
+ALTER TABLE artifact
+DROP COLUMN should_persist;
+`
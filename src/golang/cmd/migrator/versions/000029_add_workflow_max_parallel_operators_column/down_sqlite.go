@@ -0,0 +1,6 @@
+package _000029_add_workflow_max_parallel_operators_column
+
+const downSqliteScript = `
+ALTER TABLE workflow
+DROP COLUMN max_parallel_operators;
+`
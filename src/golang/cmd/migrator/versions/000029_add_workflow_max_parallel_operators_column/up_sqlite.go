@@ -0,0 +1,6 @@
+package _000029_add_workflow_max_parallel_operators_column
+
+const upSqliteScript = `
+ALTER TABLE workflow
+ADD COLUMN max_parallel_operators INTEGER DEFAULT 0 NOT NULL;
+`
@@ -0,0 +1,5 @@
+package _000030_add_dag_result_pinned_column
+
+const downPostgresScript = `
+ALTER TABLE workflow_dag_result DROP COLUMN IF EXISTS pinned;
+`
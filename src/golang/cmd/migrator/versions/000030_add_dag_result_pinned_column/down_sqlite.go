@@ -0,0 +1,6 @@
+package _000030_add_dag_result_pinned_column
+
+const downSqliteScript = `
+ALTER TABLE workflow_dag_result
+DROP COLUMN pinned;
+`
@@ -0,0 +1,6 @@
+package _000030_add_dag_result_pinned_column
+
+const upSqliteScript = `
+ALTER TABLE workflow_dag_result
+ADD COLUMN pinned BOOLEAN DEFAULT FALSE NOT NULL;
+`
@@ -0,0 +1,10 @@
+package _000031_add_storage_migration_progress_columns
+
+const downPostgresScript = `
+ALTER TABLE storage_migration
+DROP COLUMN IF EXISTS prev_storage_config,
+DROP COLUMN IF EXISTS dest_storage_config,
+DROP COLUMN IF EXISTS dry_run,
+DROP COLUMN IF EXISTS progress,
+DROP COLUMN IF EXISTS migrated_objects;
+`
@@ -0,0 +1,9 @@
+package _000031_add_storage_migration_progress_columns
+
+const downSqliteScript = `
+ALTER TABLE storage_migration DROP COLUMN prev_storage_config;
+ALTER TABLE storage_migration DROP COLUMN dest_storage_config;
+ALTER TABLE storage_migration DROP COLUMN dry_run;
+ALTER TABLE storage_migration DROP COLUMN progress;
+ALTER TABLE storage_migration DROP COLUMN migrated_objects;
+`
@@ -0,0 +1,10 @@
+package _000031_add_storage_migration_progress_columns
+
+const upPostgresScript = `
+ALTER TABLE storage_migration
+ADD COLUMN prev_storage_config JSONB,
+ADD COLUMN dest_storage_config JSONB,
+ADD COLUMN dry_run BOOLEAN DEFAULT FALSE NOT NULL,
+ADD COLUMN progress JSONB,
+ADD COLUMN migrated_objects JSONB;
+`
@@ -0,0 +1,9 @@
+package _000031_add_storage_migration_progress_columns
+
+const upSqliteScript = `
+ALTER TABLE storage_migration ADD COLUMN prev_storage_config BLOB;
+ALTER TABLE storage_migration ADD COLUMN dest_storage_config BLOB;
+ALTER TABLE storage_migration ADD COLUMN dry_run BOOL DEFAULT FALSE NOT NULL;
+ALTER TABLE storage_migration ADD COLUMN progress BLOB;
+ALTER TABLE storage_migration ADD COLUMN migrated_objects BLOB;
+`
@@ -0,0 +1,5 @@
+package _000032_add_run_request_table
+
+const downSqliteScript = `
+DROP TABLE IF EXISTS run_request;
+`
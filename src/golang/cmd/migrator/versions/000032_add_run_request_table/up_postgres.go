@@ -0,0 +1,18 @@
+package _000032_add_run_request_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS run_request (
+	id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+	workflow_id UUID NOT NULL REFERENCES workflow (id) ON DELETE CASCADE,
+	status VARCHAR NOT NULL,
+	parameters JSONB NOT NULL,
+	resumed_dag_result_id UUID,
+	wait_reason VARCHAR NOT NULL DEFAULT '',
+	claimed_by VARCHAR NOT NULL DEFAULT '',
+	lease_expires_at TIMESTAMPTZ,
+	attempts INT NOT NULL DEFAULT 0,
+	created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS run_request_status_created_at_idx ON run_request (status, created_at);
+`
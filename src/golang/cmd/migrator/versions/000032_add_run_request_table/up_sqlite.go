@@ -0,0 +1,18 @@
+package _000032_add_run_request_table
+
+const upSqliteScript = `
+CREATE TABLE IF NOT EXISTS run_request (
+	id BLOB NOT NULL PRIMARY KEY,
+	workflow_id BLOB NOT NULL REFERENCES workflow (id) ON DELETE CASCADE,
+	status TEXT NOT NULL,
+	parameters BLOB NOT NULL,
+	resumed_dag_result_id BLOB,
+	wait_reason TEXT NOT NULL DEFAULT '',
+	claimed_by TEXT NOT NULL DEFAULT '',
+	lease_expires_at DATETIME,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS run_request_status_created_at_idx ON run_request (status, created_at);
+`
@@ -0,0 +1,5 @@
+package _000033_add_scheduler_lease_table
+
+const downSqliteScript = `
+DROP TABLE IF EXISTS scheduler_lease;
+`
@@ -0,0 +1,9 @@
+package _000033_add_scheduler_lease_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS scheduler_lease (
+	name VARCHAR PRIMARY KEY,
+	holder VARCHAR NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);
+`
@@ -0,0 +1,9 @@
+package _000033_add_scheduler_lease_table
+
+const upSqliteScript = `
+CREATE TABLE IF NOT EXISTS scheduler_lease (
+	name TEXT NOT NULL PRIMARY KEY,
+	holder TEXT NOT NULL,
+	expires_at DATETIME NOT NULL
+);
+`
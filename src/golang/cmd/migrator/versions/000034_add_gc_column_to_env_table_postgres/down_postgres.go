@@ -0,0 +1,9 @@
+package _000034_add_gc_column_to_env_table_postgres
+
+const downPostgresScript = `
+ALTER TABLE execution_environment
+DROP COLUMN IF EXISTS garbage_collected;
+
+ALTER TABLE execution_environment
+ADD CONSTRAINT execution_environment_hash_key UNIQUE (hash);
+`
@@ -0,0 +1,26 @@
+package _000034_add_gc_column_to_env_table_postgres
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+)
+
+// This version makes the change of version 21 on Postgres, whose scripts in version 21 do not run
+// on Postgres. SQLite databases already have the change from version 21.
+
+func UpPostgres(ctx context.Context, db database.Database) error {
+	return db.Execute(ctx, upPostgresScript)
+}
+
+func UpSqlite(ctx context.Context, db database.Database) error {
+	return nil
+}
+
+func DownPostgres(ctx context.Context, db database.Database) error {
+	return db.Execute(ctx, downPostgresScript)
+}
+
+func DownSqlite(ctx context.Context, db database.Database) error {
+	return nil
+}
@@ -0,0 +1,9 @@
+package _000034_add_gc_column_to_env_table_postgres
+
+const upPostgresScript = `
+ALTER TABLE execution_environment
+ADD COLUMN IF NOT EXISTS garbage_collected BOOLEAN DEFAULT FALSE NOT NULL;
+
+ALTER TABLE execution_environment
+DROP CONSTRAINT IF EXISTS execution_environment_hash_key;
+`
@@ -0,0 +1,14 @@
+package _000035_add_storage_migration_object_table
+
+const downPostgresScript = `
+ALTER TABLE storage_migration ADD COLUMN IF NOT EXISTS migrated_objects JSONB;
+
+UPDATE storage_migration
+SET migrated_objects = (
+	SELECT jsonb_object_agg(object_key, checksum)
+	FROM storage_migration_object
+	WHERE storage_migration_object.storage_migration_id = storage_migration.id
+);
+
+DROP TABLE IF EXISTS storage_migration_object;
+`
@@ -0,0 +1,14 @@
+package _000035_add_storage_migration_object_table
+
+const downSqliteScript = `
+ALTER TABLE storage_migration ADD COLUMN migrated_objects BLOB;
+
+UPDATE storage_migration
+SET migrated_objects = (
+	SELECT CAST(json_group_object(object_key, checksum) AS BLOB)
+	FROM storage_migration_object
+	WHERE storage_migration_object.storage_migration_id = storage_migration.id
+);
+
+DROP TABLE IF EXISTS storage_migration_object;
+`
@@ -0,0 +1,19 @@
+package _000035_add_storage_migration_object_table
+
+// The objects that a storage migration copied were recorded in the migrated_objects column,
+// which was rewritten whenever the progress was saved. They are moved to their own table.
+const upSqliteScript = `
+CREATE TABLE IF NOT EXISTS storage_migration_object (
+	storage_migration_id BLOB NOT NULL REFERENCES storage_migration (id) ON DELETE CASCADE,
+	object_key TEXT NOT NULL,
+	checksum TEXT NOT NULL,
+	PRIMARY KEY (storage_migration_id, object_key)
+);
+
+INSERT INTO storage_migration_object (storage_migration_id, object_key, checksum)
+SELECT storage_migration.id, objects.key, objects.value
+FROM storage_migration, json_each(storage_migration.migrated_objects) AS objects
+WHERE json_type(storage_migration.migrated_objects) = 'object';
+
+ALTER TABLE storage_migration DROP COLUMN migrated_objects;
+`
@@ -0,0 +1,5 @@
+package _000036_add_workflow_cascade_triggered_at_column
+
+const downPostgresScript = `
+ALTER TABLE workflow DROP COLUMN IF EXISTS cascade_triggered_at;
+`
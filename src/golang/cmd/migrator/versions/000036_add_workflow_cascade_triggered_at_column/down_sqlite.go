@@ -0,0 +1,6 @@
+package _000036_add_workflow_cascade_triggered_at_column
+
+const downSqliteScript = `
+ALTER TABLE workflow
+DROP COLUMN cascade_triggered_at;
+`
@@ -0,0 +1,8 @@
+package _000036_add_workflow_cascade_triggered_at_column
+
+// cascade_triggered_at is the last time a cascade triggered the workflow, so that a workflow
+// that waits for all of its sources is triggered once per round of successful source runs.
+const upPostgresScript = `
+ALTER TABLE workflow
+ADD COLUMN cascade_triggered_at TIMESTAMPTZ;
+`
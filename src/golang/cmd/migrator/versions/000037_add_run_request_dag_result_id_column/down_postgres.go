@@ -0,0 +1,7 @@
+package _000037_add_run_request_dag_result_id_column
+
+const downPostgresScript = `
+DROP INDEX IF EXISTS run_request_dag_result_id_idx;
+
+ALTER TABLE run_request DROP COLUMN IF EXISTS dag_result_id;
+`
@@ -0,0 +1,8 @@
+package _000037_add_run_request_dag_result_id_column
+
+const downSqliteScript = `
+DROP INDEX IF EXISTS run_request_dag_result_id_idx;
+
+ALTER TABLE run_request
+DROP COLUMN dag_result_id;
+`
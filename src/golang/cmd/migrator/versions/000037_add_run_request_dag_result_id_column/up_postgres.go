@@ -0,0 +1,10 @@
+package _000037_add_run_request_dag_result_id_column
+
+// dag_result_id is the workflow run created by the executor that the request launched,
+// so that the run can be canceled once no worker holds a lease on the request.
+const upPostgresScript = `
+ALTER TABLE run_request
+ADD COLUMN dag_result_id UUID;
+
+CREATE INDEX IF NOT EXISTS run_request_dag_result_id_idx ON run_request (dag_result_id);
+`
@@ -4,14 +4,20 @@ import (
 	"context"
 	"net/http"
 	"path"
+	"strconv"
 
 	"github.com/aqueducthq/aqueduct/cmd/server/routes"
 	"github.com/aqueducthq/aqueduct/config"
 	aq_context "github.com/aqueducthq/aqueduct/lib/context"
 	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/storage_migration"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/auth"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -19,12 +25,19 @@ import (
 
 // Route: /config/storage/{resourceID}
 // Method: POST
+// Params:
+//
+//	`resourceID`: ID of the S3, GCS or Azure Blob Storage resource to use as the new storage layer,
+//		or `local` for the local filesystem.
+//
 // Request:
 //
 //	Headers:
 //		`api-key`: user's API Key
+//		`dry-run`: Optional. If `true`, the migration only estimates the number of objects and bytes
+//			to copy, without pausing the server or changing the storage layer.
 //
-// Response: none
+// Response: the storage migration entry, whose progress can be polled with `ListStorageMigrationsHandler`.
 type ConfigureStorageHandler struct {
 	PostHandler
 
@@ -36,6 +49,7 @@ type ConfigureStorageHandler struct {
 	ResourceRepo         repos.Resource
 	OperatorRepo         repos.Operator
 	StorageMigrationRepo repos.StorageMigration
+	WorkflowRepo         repos.Workflow
 
 	PauseServerFn   func()
 	RestartServerFn func()
@@ -47,6 +61,11 @@ type configureStorageArgs struct {
 	// It should only be set if configureLocalStorage is false.
 	storageResourceID     uuid.UUID
 	configureLocalStorage bool
+	dryRun                bool
+}
+
+func (*ConfigureStorageHandler) Headers() []string {
+	return []string{routes.StorageMigrationDryRunHeader}
 }
 
 func (*ConfigureStorageHandler) Name() string {
@@ -59,58 +78,135 @@ func (h *ConfigureStorageHandler) Prepare(r *http.Request) (interface{}, int, er
 		return nil, statusCode, errors.Wrap(err, "Unable to configure storage layer.")
 	}
 
+	dryRun := false
+	if dryRunVal := r.Header.Get(routes.StorageMigrationDryRunHeader); len(dryRunVal) > 0 {
+		dryRun, err = strconv.ParseBool(dryRunVal)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Invalid dry-run header.")
+		}
+	}
+
 	resourceIDStr := chi.URLParam(r, routes.ResourceIDUrlParam)
+	if resourceIDStr == "local" {
+		return &configureStorageArgs{
+			AqContext:             aqContext,
+			storageResourceID:     uuid.Nil,
+			configureLocalStorage: true,
+			dryRun:                dryRun,
+		}, http.StatusOK, nil
+	}
 
-	if resourceIDStr != "local" {
-		return nil, http.StatusBadRequest, errors.Wrap(err, "We currently only support changing the storage layer to the local filesystem from this route.")
+	resourceID, err := uuid.Parse(resourceIDStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed resource ID.")
 	}
 
 	return &configureStorageArgs{
-		AqContext: aqContext,
-		// TODO ENG-2574: Add support for switching to non-local storage
-		storageResourceID:     uuid.Nil,
-		configureLocalStorage: true,
+		AqContext:         aqContext,
+		storageResourceID: resourceID,
+		dryRun:            dryRun,
 	}, http.StatusOK, nil
 }
 
 func (h *ConfigureStorageHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
 	args := interfaceArgs.(*configureStorageArgs)
 
-	// TODO ENG-2574: Remove this assumption
-	if !args.configureLocalStorage {
-		return nil, http.StatusBadRequest, errors.New("We currently only support changing the storage layer to the local filesystem from this route.")
+	var (
+		destResourceObj  *models.Resource
+		newStorageConfig *shared.StorageConfig
+		statusCode       int
+		err              error
+	)
+	if args.configureLocalStorage {
+		newStorageConfig, statusCode, err = h.localStorageConfig()
+	} else {
+		destResourceObj, newStorageConfig, statusCode, err = h.resourceStorageConfig(ctx, args)
+	}
+	if err != nil {
+		return nil, statusCode, err
 	}
 
-	currentStorageConfig := config.Storage()
+	storageMigrationObj, err := storage_migration.Perform(
+		ctx,
+		args.OrgID,
+		destResourceObj,
+		newStorageConfig,
+		args.dryRun,
+		h.PauseServerFn,
+		h.RestartServerFn,
+		&storage_migration.Repos{
+			ArtifactRepo:         h.ArtifactRepo,
+			ArtifactResultRepo:   h.ArtifactResultRepo,
+			DAGRepo:              h.DAGRepo,
+			ResourceRepo:         h.ResourceRepo,
+			OperatorRepo:         h.OperatorRepo,
+			StorageMigrationRepo: h.StorageMigrationRepo,
+			WorkflowRepo:         h.WorkflowRepo,
+		},
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to migrate storage layer.")
+	}
+	return storageMigrationObj, http.StatusOK, nil
+}
 
+// localStorageConfig returns the storage config of the local filesystem.
+func (h *ConfigureStorageHandler) localStorageConfig() (*shared.StorageConfig, int, error) {
+	currentStorageConfig := config.Storage()
 	if currentStorageConfig.Type == shared.FileStorageType {
 		return nil, http.StatusBadRequest, errors.New("The storage layer is already set to the local filesystem.")
 	}
 
-	newStorageConfig := shared.StorageConfig{
+	return &shared.StorageConfig{
 		Type: shared.FileStorageType,
 		FileConfig: &shared.FileConfig{
 			Directory: path.Join(config.AqueductPath(), "storage"),
 		},
+	}, http.StatusOK, nil
+}
+
+// resourceStorageConfig returns the resource `args.storageResourceID` and its storage config.
+func (h *ConfigureStorageHandler) resourceStorageConfig(
+	ctx context.Context,
+	args *configureStorageArgs,
+) (*models.Resource, *shared.StorageConfig, int, error) {
+	resourceObj, err := h.ResourceRepo.Get(ctx, args.storageResourceID, h.Database)
+	if err != nil {
+		if aq_errors.Is(err, database.ErrNoRows()) {
+			return nil, nil, http.StatusBadRequest, errors.New("The resource does not exist.")
+		}
+		return nil, nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve resource.")
 	}
 
-	err := storage_migration.Perform(
-		ctx,
-		args.OrgID,
-		nil, /* destResourceObj */
-		&newStorageConfig,
-		h.PauseServerFn,
-		h.RestartServerFn,
-		h.ArtifactRepo,
-		h.ArtifactResultRepo,
-		h.DAGRepo,
-		h.ResourceRepo,
-		h.OperatorRepo,
-		h.StorageMigrationRepo,
-		h.Database,
-	)
+	if resourceObj.OrgID != args.OrgID {
+		return nil, nil, http.StatusBadRequest, errors.New("The organization does not own this resource.")
+	}
+
+	if !shared.IsStorageResource(resourceObj.Service) {
+		return nil, nil, http.StatusBadRequest, errors.Newf("%v cannot be used as the storage layer.", resourceObj.Service)
+	}
+
+	currentStorageConfig := config.Storage()
+	vaultObject, err := vault.NewVault(&currentStorageConfig, config.EncryptionKey())
 	if err != nil {
-		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to migrate storage layer.")
+		return nil, nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+	}
+
+	resourceConfig, err := auth.ReadConfigFromSecret(ctx, resourceObj.ID, vaultObject)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to read resource config.")
+	}
+
+	confData, err := resourceConfig.Marshal()
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	newStorageConfig, err := storage.ConvertResourceConfigToStorageConfig(resourceObj.Service, confData)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, errors.Wrap(err, "Resource config is malformed.")
 	}
-	return nil, http.StatusOK, nil
+
+	return resourceObj, newStorageConfig, http.StatusOK, nil
 }
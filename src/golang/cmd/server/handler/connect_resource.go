@@ -75,6 +75,7 @@ type ConnectResourceHandler struct {
 	ResourceRepo         repos.Resource
 	StorageMigrationRepo repos.StorageMigration
 	OperatorRepo         repos.Operator
+	WorkflowRepo         repos.Workflow
 
 	PauseServer   func()
 	RestartServer func()
@@ -137,14 +138,21 @@ func (h *ConnectResourceHandler) Prepare(r *http.Request) (interface{}, int, err
 		return nil, http.StatusBadRequest, errors.Wrap(err, "Error getting server's home directory path")
 	}
 
-	// Sanitize the root directory path for S3. We remove any leading slash, but force there to always
-	// be a trailing slash. eg: `path/to/root/`.
-	if service == shared.S3 {
-		if root_dir, ok := configMap["root_dir"]; ok && root_dir != "" {
+	// Sanitize the root directory path for S3 and the prefix for Azure. We remove any leading slash,
+	// but force there to always be a trailing slash. eg: `path/to/root/`.
+	rootDirKey := ""
+	switch service {
+	case shared.S3:
+		rootDirKey = "root_dir"
+	case shared.AzureBlob:
+		rootDirKey = "prefix"
+	}
+	if rootDirKey != "" {
+		if root_dir, ok := configMap[rootDirKey]; ok && root_dir != "" {
 			if root_dir[len(root_dir)-1] != '/' {
 				root_dir += "/"
 			}
-			configMap["root_dir"] = strings.TrimLeft(root_dir, "/")
+			configMap[rootDirKey] = strings.TrimLeft(root_dir, "/")
 		}
 	}
 
@@ -216,19 +224,23 @@ func (h *ConnectResourceHandler) Perform(ctx context.Context, interfaceArgs inte
 			return emptyResp, http.StatusBadRequest, errors.Wrap(err, "Resource config is malformed.")
 		}
 
-		err = storage_migration.Perform(
+		_, err = storage_migration.Perform(
 			ctx,
 			args.OrgID,
 			resourceObj,
 			newStorageConfig,
+			false, /* dryRun */
 			h.PauseServer,
 			h.RestartServer,
-			h.ArtifactRepo,
-			h.ArtifactResultRepo,
-			h.DAGRepo,
-			h.ResourceRepo,
-			h.OperatorRepo,
-			h.StorageMigrationRepo,
+			&storage_migration.Repos{
+				ArtifactRepo:         h.ArtifactRepo,
+				ArtifactResultRepo:   h.ArtifactResultRepo,
+				DAGRepo:              h.DAGRepo,
+				ResourceRepo:         h.ResourceRepo,
+				OperatorRepo:         h.OperatorRepo,
+				StorageMigrationRepo: h.StorageMigrationRepo,
+				WorkflowRepo:         h.WorkflowRepo,
+			},
 			h.Database,
 		)
 		if err != nil {
@@ -538,6 +550,12 @@ func ValidateConfig(
 		return validateGARConfig(config)
 	}
 
+	if service == shared.AzureBlob {
+		// Azure Blob Storage is only used as artifact storage, so there is no Python
+		// connector to authenticate it with. The credentials are checked by listing the container instead.
+		return validateAzureBlobConfig(ctx, config)
+	}
+
 	jobName := fmt.Sprintf("authenticate-operator-%s", uuid.New().String())
 	if service == shared.Conda {
 		return validateConda()
@@ -612,8 +630,8 @@ func validateAirflowConfig(
 
 // checkIfUseResourceAsStorage returns whether this resource should be used as the storage layer.
 func checkIfUseResourceAsStorage(svc shared.Service, conf auth.Config) (bool, error) {
-	if svc != shared.S3 && svc != shared.GCS {
-		// Only S3 and GCS can be used for storage
+	if !shared.IsStorageResource(svc) {
+		// Only S3, GCS and Azure Blob Storage can be used for storage
 		return false, nil
 	}
 
@@ -635,11 +653,40 @@ func checkIfUseResourceAsStorage(svc shared.Service, conf auth.Config) (bool, er
 			return false, err
 		}
 		return bool(c.UseAsStorage), nil
+	case shared.AzureBlob:
+		var c shared.AzureBlobResourceConfig
+		if err := json.Unmarshal(data, &c); err != nil {
+			return false, err
+		}
+		return bool(c.UseAsStorage), nil
 	default:
 		return false, errors.Newf("%v cannot be used as the metadata storage layer", svc)
 	}
 }
 
+// validateAzureBlobConfig authenticates the Azure Blob Storage config provided.
+// It returns a status code and an error, if any.
+func validateAzureBlobConfig(
+	ctx context.Context,
+	config auth.Config,
+) (int, error) {
+	data, err := config.Marshal()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	var c shared.AzureBlobResourceConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return http.StatusBadRequest, errors.Wrap(err, "Azure Blob Storage config is malformed.")
+	}
+
+	if err := storage.ValidateAzureConfig(ctx, &c.AzureConfig); err != nil {
+		return http.StatusBadRequest, errors.Wrap(err, "Unable to authenticate Azure Blob Storage credentials. Please check them.")
+	}
+
+	return http.StatusOK, nil
+}
+
 func validateKubernetesConfig(
 	ctx context.Context,
 	config auth.Config,
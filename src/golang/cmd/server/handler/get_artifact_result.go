@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"strconv"
 
+	server_resp "github.com/aqueducthq/aqueduct/cmd/server/response"
 	"github.com/aqueducthq/aqueduct/cmd/server/routes"
 	aq_context "github.com/aqueducthq/aqueduct/lib/context"
 	"github.com/aqueducthq/aqueduct/lib/database"
@@ -17,6 +20,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/workflow/artifact"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -67,7 +71,8 @@ type getArtifactResultResponse struct {
 	Metadata *artifactResultMetadata `json:"metadata"`
 
 	// Only populated if the artifact content was written, regardless of whether the operator succeeded or not.
-	Data []byte `json:"data"`
+	// It is streamed from storage and closed by `SendResponse`.
+	Data io.ReadCloser `json:"-"`
 }
 
 type GetArtifactResultHandlerDeprecated struct {
@@ -93,12 +98,26 @@ func (*GetArtifactResultHandlerDeprecated) Headers() []string {
 // 1: "metadata" contains a json serialized blob of artifact result metadata.
 // 2: "data" contains the artifact result data blob generated the serialization method
 // specified in the metadata field.
+// The data is streamed from storage. If reading it fails before anything is written, an error
+// response is sent. If it fails later, the connection is aborted, so that the client does not
+// mistake the truncated data for the whole content.
 func (*GetArtifactResultHandlerDeprecated) SendResponse(w http.ResponseWriter, response interface{}) {
 	resp := response.(*getArtifactResultResponse)
-	multipartWriter := multipart.NewWriter(w)
-	defer multipartWriter.Close()
 
-	w.Header().Set("Content-Type", multipartWriter.FormDataContentType())
+	var data *bufio.Reader
+	if resp.Data != nil {
+		defer resp.Data.Close()
+
+		// The data field is omitted if the content is empty.
+		data = bufio.NewReader(resp.Data)
+		if _, err := data.Peek(1); err == io.EOF {
+			data = nil
+		} else if err != nil {
+			log.Errorf("Failed to read artifact result content: %v", err)
+			server_resp.SendErrorResponse(w, "Failed to retrieve data for the artifact result.", http.StatusInternalServerError)
+			return
+		}
+	}
 
 	metadataJsonBlob, err := json.Marshal(resp.Metadata)
 	if err != nil {
@@ -106,6 +125,9 @@ func (*GetArtifactResultHandlerDeprecated) SendResponse(w http.ResponseWriter, r
 		return
 	}
 
+	multipartWriter := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", multipartWriter.FormDataContentType())
+
 	// The second argument is the file name, which is redundant but required by the UI to parse the file correctly.
 	formFieldWriter, err := multipartWriter.CreateFormFile(metadataFormFieldName, metadataFormFieldName)
 	if err != nil {
@@ -119,19 +141,23 @@ func (*GetArtifactResultHandlerDeprecated) SendResponse(w http.ResponseWriter, r
 		return
 	}
 
-	if len(resp.Data) > 0 {
+	if data != nil {
 		formFieldWriter, err = multipartWriter.CreateFormFile(dataFormFieldName, dataFormFieldName)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		_, err = formFieldWriter.Write(resp.Data)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		if _, err := io.Copy(formFieldWriter, data); err != nil {
+			// The status was already sent, so the response cannot be turned into an error response.
+			log.Errorf("Failed to write artifact result content to the response: %v", err)
+			panic(http.ErrAbortHandler)
 		}
 	}
+
+	if err := multipartWriter.Close(); err != nil {
+		log.Errorf("Failed to write artifact result response: %v", err)
+	}
 }
 
 func (h *GetArtifactResultHandlerDeprecated) Prepare(r *http.Request) (interface{}, int, error) {
@@ -265,9 +291,9 @@ func (h *GetArtifactResultHandlerDeprecated) Perform(ctx context.Context, interf
 		}, http.StatusOK, nil
 	}
 
-	data, isDownsampled, err := artifactObject.SampleContent(ctx)
+	content, isDownsampled, err := artifactObject.SampleContentReader(ctx)
 	if err == nil {
-		response.Data = data
+		response.Data = content
 		metadata.IsDownsampled = isDownsampled
 	} else if !errors.Is(err, storage.ErrObjectDoesNotExist()) {
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Failed to retrieve data for the artifact result.")
@@ -18,6 +18,9 @@ type AuthMethod string
 
 const (
 	ApiKeyAuthMethod AuthMethod = "ApiKey"
+	// Requests are authenticated by the handler itself, by verifying
+	// their signature with the webhook secret of the workflow.
+	WebhookSignatureAuthMethod AuthMethod = "WebhookSignature"
 )
 
 type Handler interface {
@@ -26,7 +29,7 @@ type Handler interface {
 	Headers() []string
 	// 'GET' or 'POST'
 	Method() RequestMethod
-	// Auth on this route. For now, we supports APIKey.
+	// Auth on this route. For now, we support APIKey and webhook signatures.
 	AuthMethod() AuthMethod
 	// Parse the request and returns structured arguments of the request as an `interface{}`
 	Prepare(r *http.Request) (interface{}, int, error)
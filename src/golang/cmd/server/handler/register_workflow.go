@@ -14,6 +14,7 @@ import (
 	exec_env "github.com/aqueducthq/aqueduct/lib/execution_environment"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	mdl_utils "github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/aqueducthq/aqueduct/lib/repos"
@@ -141,6 +142,7 @@ func (h *RegisterWorkflowHandler) Prepare(r *http.Request) (interface{}, int, er
 		// Since the libraries we call use the workflow id to tell whether a workflow already exists.
 		dagSummary.Dag.WorkflowID = collidingWorkflow.ID
 	}
+	dagSummary.Dag.StorageConfig = dagSummary.Dag.StorageConfig.ForWorkflow(dagSummary.Dag.WorkflowID)
 
 	if err := dag_utils.Validate(
 		dagSummary.Dag,
@@ -280,6 +282,18 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to update workflow.")
 		}
 
+		// The max parallel operators is always overwritten, since 0 means there is no limit.
+		_, err = h.WorkflowRepo.Update(
+			ctx,
+			workflowId,
+			map[string]interface{}{
+				models.WorkflowMaxParallelOperators: dbWorkflowDag.Metadata.MaxParallelOperators,
+			},
+			txn,
+		)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to update workflow.")
+		}
 	} else {
 		// We should create cron jobs for newly created, non-manually triggered workflows.
 		if string(dbWorkflowDag.Metadata.Schedule.CronSchedule) != "" {
@@ -289,6 +303,7 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 				workflowId,
 				shared_utils.AppendPrefix(dbWorkflowDag.Metadata.ID.String()),
 				string(dbWorkflowDag.Metadata.Schedule.CronSchedule),
+				dbWorkflowDag.Metadata.Schedule.Timezone,
 			)
 
 			if err != nil {
@@ -0,0 +1,156 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/response"
+	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/google/uuid"
+)
+
+// Route: /v2/workflow/{workflowId}/result/{dagResultID}/cancel
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+//  `dagResultID`: ID for `workflow_dag_result` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response:
+//	Body:
+//		serialized `response.DAGResult`
+//
+// The `DAGResultCancelPostHandler` marks a pending or running workflow run as canceled.
+// The engine executing the run stops all in-progress operators once it observes the
+// cancellation, and marks them along with all downstream operators as canceled.
+
+type dagResultCancelPostArgs struct {
+	*aq_context.AqContext
+	workflowID  uuid.UUID
+	dagResultID uuid.UUID
+}
+
+type DAGResultCancelPostHandler struct {
+	handler.PostHandler
+
+	Database database.Database
+
+	WorkflowRepo       repos.Workflow
+	DAGRepo            repos.DAG
+	DAGResultRepo      repos.DAGResult
+	ArtifactResultRepo repos.ArtifactResult
+	OperatorResultRepo repos.OperatorResult
+	NotificationRepo   repos.Notification
+}
+
+func (*DAGResultCancelPostHandler) Name() string {
+	return "DAGResultCancelPost"
+}
+
+func (h *DAGResultCancelPostHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowID, err := (parser.WorkflowIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	dagResultID, err := (parser.DAGResultIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return &dagResultCancelPostArgs{
+		AqContext:   aqContext,
+		workflowID:  workflowID,
+		dagResultID: dagResultID,
+	}, http.StatusOK, nil
+}
+
+func (h *DAGResultCancelPostHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*dagResultCancelPostArgs)
+
+	ok, err := h.WorkflowRepo.ValidateOrg(
+		ctx,
+		args.workflowID,
+		args.OrgID,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+
+	if !ok {
+		return nil, http.StatusBadRequest, errors.New("The organization does not own this workflow.")
+	}
+
+	dbDAG, err := h.DAGRepo.GetByDAGResult(ctx, args.dagResultID, h.Database)
+	if err != nil {
+		if errors.Is(err, database.ErrNoRows()) {
+			return nil, http.StatusBadRequest, errors.New("The workflow run does not exist.")
+		}
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow run.")
+	}
+
+	if dbDAG.WorkflowID != args.workflowID {
+		return nil, http.StatusBadRequest, errors.New("The workflow run does not belong to this workflow.")
+	}
+
+	if dbDAG.EngineConfig.Type == shared.AirflowEngineType {
+		return nil, http.StatusBadRequest, errors.New("Canceling workflow runs on Airflow is not supported.")
+	}
+
+	dbDAGResult, err := h.DAGResultRepo.Get(ctx, args.dagResultID, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow run.")
+	}
+
+	if dbDAGResult.Status != shared.PendingExecutionStatus && dbDAGResult.Status != shared.RunningExecutionStatus {
+		return nil, http.StatusBadRequest, errors.Newf("The workflow run cannot be canceled, since it has status %s.", dbDAGResult.Status)
+	}
+
+	execState := dbDAGResult.ExecState.ExecutionState
+	if execState.Timestamps == nil {
+		execState.Timestamps = &shared.ExecutionTimestamps{}
+	}
+	now := time.Now()
+	execState.Status = shared.CanceledExecutionStatus
+	execState.Timestamps.FinishedAt = &now
+
+	// The engine polls the status of the DAGResult and stops the run once it is canceled.
+	if err := workflow_utils.UpdateDAGResultMetadata(
+		ctx,
+		args.dagResultID,
+		&execState,
+		h.DAGResultRepo,
+		h.ArtifactResultRepo,
+		h.OperatorResultRepo,
+		h.WorkflowRepo,
+		h.NotificationRepo,
+		h.Database,
+	); err != nil {
+		if errors.Is(err, database.ErrNoRows()) {
+			return nil, http.StatusBadRequest, errors.New("The workflow run cannot be canceled, since it has already finished.")
+		}
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to cancel workflow run.")
+	}
+
+	dbDAGResult, err = h.DAGResultRepo.Get(ctx, args.dagResultID, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow run.")
+	}
+
+	return response.NewDAGResultFromDBObject(dbDAGResult), http.StatusOK, nil
+}
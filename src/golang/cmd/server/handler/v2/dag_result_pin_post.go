@@ -0,0 +1,125 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/response"
+	"github.com/google/uuid"
+)
+
+// Route: /v2/workflow/{workflowId}/result/{dagResultID}/pin
+//        /v2/workflow/{workflowId}/result/{dagResultID}/unpin
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+//  `dagResultID`: ID for `workflow_dag_result` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response:
+//	Body:
+//		serialized `response.DAGResult`
+//
+// The `DAGResultPinPostHandler` pins or unpins a workflow run, depending on `Pinned`.
+// Pinned runs are never removed by the retention policy of the workflow.
+
+type dagResultPinPostArgs struct {
+	*aq_context.AqContext
+	workflowID  uuid.UUID
+	dagResultID uuid.UUID
+}
+
+type DAGResultPinPostHandler struct {
+	handler.PostHandler
+
+	// Pinned is whether this handler pins or unpins the workflow run.
+	Pinned bool
+
+	Database database.Database
+
+	WorkflowRepo  repos.Workflow
+	DAGRepo       repos.DAG
+	DAGResultRepo repos.DAGResult
+}
+
+func (h *DAGResultPinPostHandler) Name() string {
+	if h.Pinned {
+		return "DAGResultPinPost"
+	}
+	return "DAGResultUnpinPost"
+}
+
+func (h *DAGResultPinPostHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowID, err := (parser.WorkflowIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	dagResultID, err := (parser.DAGResultIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return &dagResultPinPostArgs{
+		AqContext:   aqContext,
+		workflowID:  workflowID,
+		dagResultID: dagResultID,
+	}, http.StatusOK, nil
+}
+
+func (h *DAGResultPinPostHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*dagResultPinPostArgs)
+
+	ok, err := h.WorkflowRepo.ValidateOrg(
+		ctx,
+		args.workflowID,
+		args.OrgID,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+
+	if !ok {
+		return nil, http.StatusBadRequest, errors.New("The organization does not own this workflow.")
+	}
+
+	dbDAG, err := h.DAGRepo.GetByDAGResult(ctx, args.dagResultID, h.Database)
+	if err != nil {
+		if errors.Is(err, database.ErrNoRows()) {
+			return nil, http.StatusBadRequest, errors.New("The workflow run does not exist.")
+		}
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow run.")
+	}
+
+	if dbDAG.WorkflowID != args.workflowID {
+		return nil, http.StatusBadRequest, errors.New("The workflow run does not belong to this workflow.")
+	}
+
+	dbDAGResult, err := h.DAGResultRepo.Update(
+		ctx,
+		args.dagResultID,
+		map[string]interface{}{
+			models.DAGResultPinned: h.Pinned,
+		},
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to update workflow run.")
+	}
+
+	return response.NewDAGResultFromDBObject(dbDAGResult), http.StatusOK, nil
+}
@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/engine"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/google/uuid"
+)
+
+// Route: /v2/workflow/{workflowId}/result/{dagResultID}/resume
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+//  `dagResultID`: ID for `workflow_dag_result` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response: none
+//
+// The `DAGResultResumePostHandler` triggers a new run of the workflow that resumes a failed or
+// canceled run. Operators that completed in that run are not executed again, and the new run
+// reuses their results. Only the remaining operators are executed.
+
+type dagResultResumePostArgs struct {
+	*aq_context.AqContext
+	workflowID  uuid.UUID
+	dagResultID uuid.UUID
+}
+
+type DAGResultResumePostHandler struct {
+	handler.PostHandler
+
+	Database database.Database
+	Engine   engine.Engine
+
+	WorkflowRepo  repos.Workflow
+	DAGRepo       repos.DAG
+	DAGResultRepo repos.DAGResult
+}
+
+func (*DAGResultResumePostHandler) Name() string {
+	return "DAGResultResumePost"
+}
+
+func (h *DAGResultResumePostHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowID, err := (parser.WorkflowIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	dagResultID, err := (parser.DAGResultIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return &dagResultResumePostArgs{
+		AqContext:   aqContext,
+		workflowID:  workflowID,
+		dagResultID: dagResultID,
+	}, http.StatusOK, nil
+}
+
+func (h *DAGResultResumePostHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*dagResultResumePostArgs)
+
+	emptyResp := struct{}{}
+
+	ok, err := h.WorkflowRepo.ValidateOrg(
+		ctx,
+		args.workflowID,
+		args.OrgID,
+		h.Database,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+
+	if !ok {
+		return emptyResp, http.StatusBadRequest, errors.New("The organization does not own this workflow.")
+	}
+
+	dbDAG, err := h.DAGRepo.GetByDAGResult(ctx, args.dagResultID, h.Database)
+	if err != nil {
+		if errors.Is(err, database.ErrNoRows()) {
+			return emptyResp, http.StatusBadRequest, errors.New("The workflow run does not exist.")
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow run.")
+	}
+
+	if dbDAG.WorkflowID != args.workflowID {
+		return emptyResp, http.StatusBadRequest, errors.New("The workflow run does not belong to this workflow.")
+	}
+
+	// Databricks executes all operators of a run as a single job, so individual operators cannot be skipped.
+	if dbDAG.EngineConfig.Type == shared.AirflowEngineType || dbDAG.EngineConfig.Type == shared.DatabricksEngineType {
+		return emptyResp, http.StatusBadRequest, errors.Newf("Resuming workflow runs on %s is not supported.", dbDAG.EngineConfig.Type)
+	}
+
+	dbDAGResult, err := h.DAGResultRepo.Get(ctx, args.dagResultID, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow run.")
+	}
+
+	if dbDAGResult.Status != shared.FailedExecutionStatus && dbDAGResult.Status != shared.CanceledExecutionStatus {
+		return emptyResp, http.StatusBadRequest, errors.Newf("The workflow run cannot be resumed, since it has status %s.", dbDAGResult.Status)
+	}
+
+	timeConfig := &engine.AqueductTimeConfig{
+		OperatorPollInterval: engine.DefaultPollIntervalMillisec,
+		ExecTimeout:          engine.DefaultExecutionTimeout,
+		CleanupTimeout:       engine.DefaultCleanupTimeout,
+	}
+
+	_, err = h.Engine.TriggerWorkflowResume(
+		ctx,
+		args.workflowID,
+		shared_utils.AppendPrefix(args.workflowID.String()),
+		args.dagResultID,
+		timeConfig,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to resume workflow run.")
+	}
+
+	return emptyResp, http.StatusOK, nil
+}
@@ -35,6 +35,11 @@ Request:
 
 	We always return storage migrations in descending chronological order (by start time).
 	The order the filters are applied in is: status, completed-since, then limit.
+
+Response:
+	The storage migration entries. The `progress` of each entry reports the number of objects
+	that were copied and verified so far. For dry runs, it reports the estimated number of
+	objects and bytes to copy.
 */
 
 type ListStorageMigrationsHandler struct {
@@ -1,11 +1,17 @@
 package v2
 
 import (
+	"bufio"
 	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/aqueducthq/aqueduct/cmd/server/handler"
 	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	server_resp "github.com/aqueducthq/aqueduct/cmd/server/response"
+	"github.com/aqueducthq/aqueduct/cmd/server/routes"
 	aq_context "github.com/aqueducthq/aqueduct/lib/context"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/errors"
@@ -14,6 +20,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/workflow/artifact"
 	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
 )
 
 // This file should map directly to
@@ -29,9 +36,18 @@ import (
 // Request:
 //	Headers:
 //		`api-key`: user's API Key
+//		`accept`: (optional) `application/octet-stream` to get the raw content
+//		`range`: (optional) byte range of the raw content to get
 // Response:
 //	Body:
-//		`response.NodeContent`
+//		serialized `nodeResultGetResponse`, with a sample of the content that fits the client,
+//		or the raw content if it is requested by either `accept` or `range`.
+//		The content is streamed from storage in both cases. If reading it from storage fails
+//		after the response has started, the connection is aborted instead of ending the
+//		truncated response, so clients get an error rather than partial content.
+
+// rawContentType is the `accept` header value of requests for the raw content.
+const rawContentType = "application/octet-stream"
 
 type NodeArtifactResultContentGetHandler struct {
 	handler.GetHandler
@@ -50,6 +66,9 @@ type nodeResultGetArgs struct {
 	dagID        uuid.UUID
 	nodeID       uuid.UUID
 	nodeResultID uuid.UUID
+
+	// request is only set for requests of the raw content, which is served with `http.ServeContent`.
+	request *http.Request
 }
 
 type nodeResultGetResponse struct {
@@ -57,10 +76,26 @@ type nodeResultGetResponse struct {
 	Content       []byte `json:"content"`
 }
 
+// nodeArtifactResultContentGetResponse is sent as a `nodeResultGetResponse`, whose content is streamed.
+type nodeArtifactResultContentGetResponse struct {
+	isDownsampled bool
+	// content is nil if there is no content.
+	content io.ReadCloser
+
+	// The following fields are only set for requests of the raw content.
+	request    *http.Request
+	rawContent io.ReadSeekCloser
+	rawInfo    *storage.ObjectInfo
+}
+
 func (*NodeArtifactResultContentGetHandler) Name() string {
 	return "NodeArtifactResultContentGet"
 }
 
+func (*NodeArtifactResultContentGetHandler) Headers() []string {
+	return []string{routes.RangeHeader}
+}
+
 func (h *NodeArtifactResultContentGetHandler) Prepare(r *http.Request) (interface{}, int, error) {
 	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
 	if err != nil {
@@ -87,18 +122,24 @@ func (h *NodeArtifactResultContentGetHandler) Prepare(r *http.Request) (interfac
 		return nil, http.StatusBadRequest, err
 	}
 
-	return &nodeResultGetArgs{
+	args := &nodeResultGetArgs{
 		AqContext:    aqContext,
 		workflowID:   workflowID,
 		dagID:        dagID,
 		nodeID:       nodeID,
 		nodeResultID: nodeResultID,
-	}, http.StatusOK, nil
+	}
+
+	if r.Header.Get(routes.RangeHeader) != "" || r.Header.Get(routes.AcceptHeader) == rawContentType {
+		args.request = r
+	}
+
+	return args, http.StatusOK, nil
 }
 
 func (h *NodeArtifactResultContentGetHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
 	args := interfaceArgs.(*nodeResultGetArgs)
-	emptyResp := &nodeResultGetResponse{}
+	emptyResp := &nodeArtifactResultContentGetResponse{}
 
 	dag, err := h.DAGRepo.Get(
 		ctx,
@@ -137,6 +178,28 @@ func (h *NodeArtifactResultContentGetHandler) Perform(ctx context.Context, inter
 		execState.UserLogs = dbArtifactResult.ExecState.UserLogs
 	}
 
+	if args.request != nil {
+		if dbArtifactResult == nil || dbArtifactResult.ContentPath == "" {
+			return emptyResp, http.StatusNotFound, errors.New("The artifact result has no content.")
+		}
+
+		store := storage.NewStorage(&dag.StorageConfig)
+		info, err := store.Stat(ctx, dbArtifactResult.ContentPath)
+		if err != nil {
+			if errors.Is(err, storage.ErrObjectDoesNotExist()) {
+				return emptyResp, http.StatusNotFound, errors.New("The artifact result has no content.")
+			}
+
+			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Failed to retrieve data for the artifact result.")
+		}
+
+		return &nodeArtifactResultContentGetResponse{
+			request:    args.request,
+			rawContent: storage.NewReadSeeker(ctx, store, dbArtifactResult.ContentPath, info.Size),
+			rawInfo:    info,
+		}, http.StatusOK, nil
+	}
+
 	artifactObject := artifact.NewArtifactFromDBObjects(
 		uuid.UUID{}, /* signature */
 		dbArtifact,
@@ -148,7 +211,7 @@ func (h *NodeArtifactResultContentGetHandler) Perform(ctx context.Context, inter
 		h.Database,
 	)
 
-	data, isDownsampled, err := artifactObject.SampleContent(ctx)
+	content, isDownsampled, err := artifactObject.SampleContentReader(ctx)
 	if err != nil {
 		if errors.Is(err, storage.ErrObjectDoesNotExist()) {
 			return emptyResp, http.StatusOK, nil
@@ -157,5 +220,61 @@ func (h *NodeArtifactResultContentGetHandler) Perform(ctx context.Context, inter
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Failed to retrieve data for the artifact result.")
 	}
 
-	return &nodeResultGetResponse{IsDownsampled: isDownsampled, Content: data}, http.StatusOK, nil
+	return &nodeArtifactResultContentGetResponse{isDownsampled: isDownsampled, content: content}, http.StatusOK, nil
+}
+
+// This custom implementation of SendResponse streams the content from storage, instead of
+// serializing the whole response in memory. The raw content is served with `http.ServeContent`,
+// which handles the `range` header. If the storage is compressed, each range is decompressed
+// from the start of the object.
+func (*NodeArtifactResultContentGetHandler) SendResponse(w http.ResponseWriter, interfaceResp interface{}) {
+	resp := interfaceResp.(*nodeArtifactResultContentGetResponse)
+
+	if resp.rawContent != nil {
+		defer resp.rawContent.Close()
+
+		w.Header().Set(routes.ContentTypeHeader, rawContentType)
+		http.ServeContent(w, resp.request, "" /* name */, resp.rawInfo.LastModified, resp.rawContent)
+		return
+	}
+
+	if resp.content == nil {
+		server_resp.SendJsonResponse(w, &nodeResultGetResponse{}, http.StatusOK)
+		return
+	}
+	defer resp.content.Close()
+
+	// The first chunk is read before the status is sent, so that a failed storage read
+	// is sent as an error response.
+	content := bufio.NewReader(resp.content)
+	if _, err := content.Peek(1); err != nil && err != io.EOF {
+		log.Errorf("Failed to read artifact result content: %v", err)
+		server_resp.SendErrorResponse(w, "Failed to retrieve the artifact result content.", http.StatusInternalServerError)
+		return
+	}
+
+	// This writes the same JSON as serializing the content as a `[]byte` field.
+	w.Header().Set(routes.ContentTypeHeader, "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, `{"is_downsampled":%t,"content":"`, resp.isDownsampled); err != nil {
+		log.Errorf("Failed to write artifact result content to the response: %v", err)
+		return
+	}
+
+	encoder := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err := io.Copy(encoder, content); err != nil {
+		// The status was already sent, so the response cannot be turned into an error response.
+		log.Errorf("Failed to write artifact result content to the response: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if err := encoder.Close(); err != nil {
+		log.Errorf("Failed to write artifact result content to the response: %v", err)
+		return
+	}
+
+	if _, err := io.WriteString(w, `"}`); err != nil {
+		log.Errorf("Failed to write artifact result content to the response: %v", err)
+	}
 }
@@ -0,0 +1,87 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+)
+
+/*
+Route: /v2/run-queue
+Method: GET
+Request:
+	Headers:
+		`api-key`:
+			User's API Key
+Response:
+	Body:
+		`depth`: the number of queued runs that no worker has claimed yet.
+		`requests`: the run requests that are not done, oldest first. Each request has its
+			`status`, the `wait_reason` it has not been launched yet, and, if it is queued,
+			its 1-based `position` in the queue.
+*/
+
+type RunQueueGetHandler struct {
+	handler.GetHandler
+
+	Database database.Database
+
+	RunRequestRepo repos.RunRequest
+}
+
+type runQueueGetArgs struct {
+	*aq_context.AqContext
+}
+
+type runQueueRequest struct {
+	models.RunRequest
+	Position int `json:"position,omitempty"`
+}
+
+type runQueueGetResponse struct {
+	Depth    int               `json:"depth"`
+	Requests []runQueueRequest `json:"requests"`
+}
+
+func (*RunQueueGetHandler) Name() string {
+	return "RunQueueGet"
+}
+
+func (*RunQueueGetHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return &runQueueGetArgs{
+		AqContext: aqContext,
+	}, http.StatusOK, nil
+}
+
+func (h *RunQueueGetHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	runRequests, err := h.RunRequestRepo.ListUnfinished(ctx, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to read the run queue.")
+	}
+
+	resp := runQueueGetResponse{
+		Requests: make([]runQueueRequest, 0, len(runRequests)),
+	}
+	for _, runRequest := range runRequests {
+		request := runQueueRequest{RunRequest: runRequest}
+		if runRequest.Status == shared.QueuedRunRequestStatus {
+			resp.Depth++
+			request.Position = resp.Depth
+		}
+		resp.Requests = append(resp.Requests, request)
+	}
+
+	return resp, http.StatusOK, nil
+}
@@ -0,0 +1,47 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+)
+
+type storageCacheGetResponse struct {
+	// Whether objects of remote storage are cached on the server's disk.
+	Enabled bool `json:"enabled"`
+	storage.CacheStats
+}
+
+// Route: /api/v2/storage/cache
+// Method: GET
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//
+// Response: the hit and miss counters and the size of the server's storage cache.
+type StorageCacheGetHandler struct {
+	handler.GetHandler
+}
+
+func (*StorageCacheGetHandler) Name() string {
+	return "GetStorageCache"
+}
+
+func (*StorageCacheGetHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	return nil, http.StatusOK, nil
+}
+
+func (*StorageCacheGetHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	cache := storage.GetCache()
+	if cache == nil {
+		return storageCacheGetResponse{}, http.StatusOK, nil
+	}
+
+	return storageCacheGetResponse{
+		Enabled:    true,
+		CacheStats: cache.Stats(),
+	}, http.StatusOK, nil
+}
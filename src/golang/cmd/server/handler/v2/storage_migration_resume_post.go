@@ -0,0 +1,88 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/storage_migration"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// Route: /v2/storage-migrations/{storageMigrationID}/resume
+// Method: POST
+// Params:
+//
+//	`storageMigrationID`: ID of the `storage_migration` entry
+//
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//
+// Response: the storage migration entry.
+//
+// The `StorageMigrationResumePostHandler` resumes a storage migration that failed or was interrupted
+// by a restart of the server. The objects that were already copied and verified are not copied again.
+type StorageMigrationResumePostHandler struct {
+	handler.PostHandler
+
+	Database database.Database
+	Repos    *storage_migration.Repos
+
+	PauseServerFn   func()
+	RestartServerFn func()
+}
+
+type storageMigrationResumePostArgs struct {
+	*aq_context.AqContext
+	storageMigrationID uuid.UUID
+}
+
+func (*StorageMigrationResumePostHandler) Name() string {
+	return "StorageMigrationResumePost"
+}
+
+func (h *StorageMigrationResumePostHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	storageMigrationID, err := (parser.StorageMigrationIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return &storageMigrationResumePostArgs{
+		AqContext:          aqContext,
+		storageMigrationID: storageMigrationID,
+	}, http.StatusOK, nil
+}
+
+func (h *StorageMigrationResumePostHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*storageMigrationResumePostArgs)
+
+	storageMigrationObj, err := storage_migration.Resume(
+		ctx,
+		args.OrgID,
+		args.storageMigrationID,
+		h.PauseServerFn,
+		h.RestartServerFn,
+		h.Repos,
+		h.Database,
+	)
+	if err != nil {
+		if aq_errors.Is(err, storage_migration.ErrInvalidStorageMigration()) {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Unable to resume storage migration.")
+		}
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to resume storage migration.")
+	}
+
+	return storageMigrationObj, http.StatusOK, nil
+}
@@ -0,0 +1,69 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/storage_migration"
+	"github.com/dropbox/godropbox/errors"
+)
+
+// Route: /v2/storage-migrations/rollback
+// Method: POST
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//
+// Response: the storage migration entry of the rollback.
+//
+// The `StorageMigrationRollbackPostHandler` migrates the storage layer back to the storage config
+// before the current storage migration. The rollback is a storage migration itself, so its progress
+// is reported by `ListStorageMigrationsHandler` and it can be resumed if it fails.
+type StorageMigrationRollbackPostHandler struct {
+	handler.PostHandler
+
+	Database database.Database
+	Repos    *storage_migration.Repos
+
+	PauseServerFn   func()
+	RestartServerFn func()
+}
+
+func (*StorageMigrationRollbackPostHandler) Name() string {
+	return "StorageMigrationRollbackPost"
+}
+
+func (h *StorageMigrationRollbackPostHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return aqContext, http.StatusOK, nil
+}
+
+func (h *StorageMigrationRollbackPostHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	aqContext := interfaceArgs.(*aq_context.AqContext)
+
+	storageMigrationObj, err := storage_migration.Rollback(
+		ctx,
+		aqContext.OrgID,
+		h.PauseServerFn,
+		h.RestartServerFn,
+		h.Repos,
+		h.Database,
+	)
+	if err != nil {
+		if aq_errors.Is(err, storage_migration.ErrInvalidStorageMigration()) {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "Unable to roll back storage migration.")
+		}
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to roll back storage migration.")
+	}
+
+	return storageMigrationObj, http.StatusOK, nil
+}
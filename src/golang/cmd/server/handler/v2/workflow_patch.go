@@ -117,6 +117,12 @@ func (h *WorkflowPatchHandler) Prepare(r *http.Request) (interface{}, int, error
 		return nil, http.StatusBadRequest, errors.New("Cannot pause a manually updated workflow.")
 	}
 
+	if input.RetentionPolicy != nil {
+		if err := input.RetentionPolicy.Validate(); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+
 	// Finally, we check if there are an updates at all.
 	if input.WorkflowName == "" && input.WorkflowDescription == "" && input.Schedule.Trigger == "" {
 		return nil, http.StatusBadRequest, errors.New("Edit request issued without any updates specified.")
@@ -0,0 +1,171 @@
+package v2
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/engine"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/webhook"
+	"github.com/google/uuid"
+)
+
+const maxWebhookBodyBytes = 1 << 20
+
+// Route: /v2/workflow/{workflowId}/webhook
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+// Request:
+//	Headers:
+//		`x-aqueduct-timestamp`: the time the request was sent, in seconds since the Unix epoch
+//		`x-aqueduct-signature`: "sha256=" followed by the hex-encoded HMAC-SHA256
+//			of the timestamp, ".", and the request body, keyed by the webhook secret of the workflow
+//	Body:
+//		JSON payload, whose fields are mapped to the parameters of the run
+// Response: none
+//
+// The `WorkflowWebhookPostHandler` triggers a run of a workflow with a webhook trigger.
+// It does not require an API key. Instead, the request must be signed with the webhook secret
+// of the workflow, see `WorkflowWebhookSecretPostHandler`. Requests whose timestamp is more than
+// `webhook.TimestampTolerance` away from the server time are rejected. The body fields listed in the
+// workflow's webhook config are passed to the run as parameters.
+
+type workflowWebhookPostArgs struct {
+	workflowID uuid.UUID
+	body       []byte
+	timestamp  string
+	signature  string
+}
+
+type WorkflowWebhookPostHandler struct {
+	handler.PostHandler
+
+	Database database.Database
+	Engine   engine.Engine
+
+	WorkflowRepo repos.Workflow
+}
+
+func (*WorkflowWebhookPostHandler) Name() string {
+	return "WorkflowWebhookPost"
+}
+
+func (*WorkflowWebhookPostHandler) AuthMethod() handler.AuthMethod {
+	return handler.WebhookSignatureAuthMethod
+}
+
+func (*WorkflowWebhookPostHandler) Headers() []string {
+	return []string{routes.WebhookSignatureHeader, routes.WebhookTimestampHeader}
+}
+
+func (h *WorkflowWebhookPostHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	workflowID, err := (parser.WorkflowIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	signature := r.Header.Get(routes.WebhookSignatureHeader)
+	timestamp := r.Header.Get(routes.WebhookTimestampHeader)
+	if signature == "" || timestamp == "" {
+		return nil, http.StatusUnauthorized, errors.New("The webhook request is not signed.")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodyBytes+1))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Unable to read webhook body.")
+	}
+
+	if len(body) > maxWebhookBodyBytes {
+		return nil, http.StatusRequestEntityTooLarge, errors.New("The webhook body is too large.")
+	}
+
+	return &workflowWebhookPostArgs{
+		workflowID: workflowID,
+		body:       body,
+		timestamp:  timestamp,
+		signature:  signature,
+	}, http.StatusOK, nil
+}
+
+func (h *WorkflowWebhookPostHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*workflowWebhookPostArgs)
+
+	emptyResp := struct{}{}
+
+	// The same error is returned whenever the request cannot be verified,
+	// so that it does not reveal which workflows exist.
+	unauthorizedErr := errors.New("Unable to verify the webhook request.")
+
+	workflowObj, err := h.WorkflowRepo.Get(ctx, args.workflowID, h.Database)
+	if err != nil {
+		if errors.Is(err, database.ErrNoRows()) {
+			return emptyResp, http.StatusUnauthorized, unauthorizedErr
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow.")
+	}
+
+	if workflowObj.Schedule.Trigger != shared.WebhookUpdateTrigger {
+		return emptyResp, http.StatusUnauthorized, unauthorizedErr
+	}
+
+	storageConfig := config.Storage()
+	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+	}
+
+	secret, err := webhook.ReadSecret(ctx, args.workflowID, vaultObject)
+	if err != nil {
+		// The secret has not been generated yet.
+		return emptyResp, http.StatusUnauthorized, unauthorizedErr
+	}
+
+	if !webhook.VerifySignature(secret, args.timestamp, args.body, args.signature, time.Now()) {
+		return emptyResp, http.StatusUnauthorized, unauthorizedErr
+	}
+
+	if workflowObj.Schedule.Paused {
+		return emptyResp, http.StatusBadRequest, errors.New("The workflow is paused.")
+	}
+
+	var paramFields map[string]string
+	if workflowObj.Schedule.Webhook != nil {
+		paramFields = workflowObj.Schedule.Webhook.ParamFields
+	}
+
+	parameters, err := webhook.ExtractParams(args.body, paramFields)
+	if err != nil {
+		return emptyResp, http.StatusBadRequest, errors.Wrap(err, "Unable to extract parameters from the webhook body.")
+	}
+
+	timeConfig := &engine.AqueductTimeConfig{
+		OperatorPollInterval: engine.DefaultPollIntervalMillisec,
+		ExecTimeout:          engine.DefaultExecutionTimeout,
+		CleanupTimeout:       engine.DefaultCleanupTimeout,
+	}
+
+	_, err = h.Engine.TriggerWorkflow(
+		ctx,
+		args.workflowID,
+		shared_utils.AppendPrefix(args.workflowID.String()),
+		timeConfig,
+		parameters,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to trigger workflow.")
+	}
+
+	return emptyResp, http.StatusOK, nil
+}
@@ -0,0 +1,111 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/handler"
+	"github.com/aqueducthq/aqueduct/cmd/server/request/parser"
+	"github.com/aqueducthq/aqueduct/config"
+	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/webhook"
+	"github.com/google/uuid"
+)
+
+// Route: /v2/workflow/{workflowId}/webhook/secret
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response:
+//	Body:
+//		serialized `workflowWebhookSecretResponse`
+//
+// The `WorkflowWebhookSecretPostHandler` generates a new webhook secret for a workflow with a
+// webhook trigger, and returns it. Any previous secret of the workflow stops being accepted.
+// Webhook requests must be signed with this secret, see `WorkflowWebhookPostHandler`.
+
+type workflowWebhookSecretPostArgs struct {
+	*aq_context.AqContext
+	workflowID uuid.UUID
+}
+
+type workflowWebhookSecretResponse struct {
+	Secret string `json:"secret"`
+}
+
+type WorkflowWebhookSecretPostHandler struct {
+	handler.PostHandler
+
+	Database database.Database
+
+	WorkflowRepo repos.Workflow
+}
+
+func (*WorkflowWebhookSecretPostHandler) Name() string {
+	return "WorkflowWebhookSecretPost"
+}
+
+func (h *WorkflowWebhookSecretPostHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	aqContext, statusCode, err := aq_context.ParseAqContext(r.Context())
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowID, err := (parser.WorkflowIDParser{}).Parse(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return &workflowWebhookSecretPostArgs{
+		AqContext:  aqContext,
+		workflowID: workflowID,
+	}, http.StatusOK, nil
+}
+
+func (h *WorkflowWebhookSecretPostHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*workflowWebhookSecretPostArgs)
+
+	ok, err := h.WorkflowRepo.ValidateOrg(
+		ctx,
+		args.workflowID,
+		args.OrgID,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+
+	if !ok {
+		return nil, http.StatusBadRequest, errors.New("The organization does not own this workflow.")
+	}
+
+	workflowObj, err := h.WorkflowRepo.Get(ctx, args.workflowID, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error reading workflow.")
+	}
+
+	if workflowObj.Schedule.Trigger != shared.WebhookUpdateTrigger {
+		return nil, http.StatusBadRequest, errors.New("The workflow does not have a webhook trigger.")
+	}
+
+	storageConfig := config.Storage()
+	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to initialize vault.")
+	}
+
+	secret, err := webhook.RotateSecret(ctx, args.workflowID, vaultObject)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to generate webhook secret.")
+	}
+
+	return &workflowWebhookSecretResponse{Secret: secret}, http.StatusOK, nil
+}
@@ -1,15 +1,18 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/aqueducthq/aqueduct/cmd/server/server"
 	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/connection"
 	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	log "github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/writer"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -27,6 +30,12 @@ var (
 	externalIP        = flag.String("external-ip", "", "The IP address that the server exposed. For now, it's used to generate links for notifications.")
 	serverLogPath     = filepath.Join(os.Getenv("HOME"), ".aqueduct", "server", "logs", "server")
 	disableUsageStats = flag.Bool("disable-usage-stats", false, "Whether to disable usage statistics reporting.")
+	newStorageKeyPath = flag.String(
+		"rotate-storage-master-key",
+		"",
+		"The path to a file with a new master key for the encrypted storage. The data keys are re-wrapped with it "+
+			"and it is saved to the config, then the server exits. All servers must be stopped beforehand.",
+	)
 
 	envPath             = filepath.Join(os.Getenv("HOME"), ".aqueduct", "server", "config", "env")
 	allowedEnvironments = map[aq_context.ServerEnvironment]bool{
@@ -112,6 +121,20 @@ func main() {
 		log.Fatalf("Failed to initialize server config: %v", err)
 	}
 
+	if *newStorageKeyPath != "" {
+		if err := rotateStorageMasterKey(*newStorageKeyPath); err != nil {
+			log.Fatalf("Failed to rotate storage master key: %v", err)
+		}
+		log.Infof("Rotated the storage master key.")
+		return
+	}
+
+	if cacheConfig := config.StorageCache(); cacheConfig.MaxSizeMB > 0 {
+		if err := storage.EnableCache(cacheConfig.Directory, cacheConfig.MaxSizeMB<<20); err != nil {
+			log.Fatalf("Failed to enable storage cache: %v", err)
+		}
+	}
+
 	environment := parseEnv()
 
 	s := server.NewAqServer(environment, *externalIP, *port, *disableUsageStats)
@@ -121,17 +144,48 @@ func main() {
 		log.Fatalf("Failed to start workflow retention cronjob: %v", err)
 	}
 
+	err = s.StartStorageGCJob(config.StorageGC())
+	if err != nil {
+		log.Fatalf("Failed to start storage gc cronjob: %v", err)
+	}
+
 	err = s.StartDynamicTeardownJob()
 	if err != nil {
 		log.Fatalf("Failed to deployed dynamic teardown cronjob: %v", err)
 	}
 
-	err = s.SyncCronJobs()
+	err = s.StartScheduler()
 	if err != nil {
 		log.Errorf("Failed to sync scheduled workflows: %v", err)
 	}
 
+	err = s.StartRunQueue(config.RunQueue())
+	if err != nil {
+		log.Fatalf("Failed to start run queue: %v", err)
+	}
+
 	// Start the HTTP server and listen for requests indefinitely.
 	log.Infof("You can use api key %s to connect to the server", config.APIKey())
 	s.Run(*expose)
 }
+
+// rotateStorageMasterKey re-wraps the data keys of the encrypted storage with the master key
+// in the file at keyPath, and saves it as the storage master key in the config.
+func rotateStorageMasterKey(keyPath string) error {
+	storageConfig := config.Storage()
+	if storageConfig.Encryption == nil {
+		return errors.New("The storage is not encrypted.")
+	}
+
+	newKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+
+	newMasterKey := strings.TrimSpace(string(newKey))
+	if err := storage.RotateMasterKey(context.Background(), &storageConfig, config.StorageMasterKey(), newMasterKey); err != nil {
+		return err
+	}
+
+	return config.UpdateStorageMasterKey(newMasterKey)
+}
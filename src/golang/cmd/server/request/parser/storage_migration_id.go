@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+type StorageMigrationIDParser struct{}
+
+func (StorageMigrationIDParser) Parse(r *http.Request) (uuid.UUID, error) {
+	storageMigrationIDStr := (pathParser{URLParam: routes.StorageMigrationIDParam}).Parse(r)
+
+	id, err := uuid.Parse(storageMigrationIDStr)
+	if err != nil {
+		return uuid.UUID{}, errors.Wrap(
+			err,
+			fmt.Sprintf("Malformed storage migration ID %s", storageMigrationIDStr),
+		)
+	}
+
+	return id, nil
+}
@@ -19,6 +19,7 @@ const (
 	StorageMigrationFilterStatusHeader   = "status"
 	StorageMigrationLimitHeader          = "limit"
 	StorageMigrationCompletedSinceHeader = "completed-since"
+	StorageMigrationDryRunHeader         = "dry-run"
 
 	// Export Function headers
 	ExportFnUserFriendlyHeader = "user-friendly"
@@ -29,4 +30,11 @@ const (
 
 	RunNowHeader              = "run-now"
 	DynamicEngineActionHeader = "action"
+
+	WebhookSignatureHeader = "x-aqueduct-signature"
+	WebhookTimestampHeader = "x-aqueduct-timestamp"
+
+	// Headers of requests for the raw content of an artifact result.
+	AcceptHeader = "accept"
+	RangeHeader  = "range"
 )
@@ -12,10 +12,11 @@ const (
 	// v2 params
 	// Each V2 parameters should have a corresponding parser
 	// in request/parser package.
-	WorkflowIDParam   = "workflowID"
-	DagIDParam        = "dagID"
-	DAGResultIDParam  = "dagResultID"
-	NodeIDParam       = "nodeID"
-	NodeResultIDParam = "nodeResultID"
-	ResourceIDParam   = "resourceID"
+	WorkflowIDParam         = "workflowID"
+	DagIDParam              = "dagID"
+	DAGResultIDParam        = "dagResultID"
+	NodeIDParam             = "nodeID"
+	NodeResultIDParam       = "nodeResultID"
+	ResourceIDParam         = "resourceID"
+	StorageMigrationIDParam = "storageMigrationID"
 )
@@ -6,14 +6,23 @@ const (
 	ResourceOperatorsRoute         = "/api/v2/resource/{resourceID}/nodes/operators"
 	ResourcesWorkflowsRoute        = "/api/v2/resources/workflows"
 	ResourceWorkflowsRoute         = "/api/v2/resource/{resourceID}/workflows"
+	RunQueueRoute                  = "/api/v2/run-queue"
 	ListStorageMigrationRoute      = "/api/v2/storage-migrations"
+	StorageMigrationRollbackRoute  = "/api/v2/storage-migrations/rollback"
+	StorageMigrationResumeRoute    = "/api/v2/storage-migrations/{storageMigrationID}/resume"
 	WorkflowsRoute                 = "/api/v2/workflows"
 	WorkflowRoute                  = "/api/v2/workflow/{workflowID}"
 	WorkflowObjectsRoute           = "/api/v2/workflow/{workflowId}/objects"
+	WorkflowWebhookRoute           = "/api/v2/workflow/{workflowID}/webhook"
+	WorkflowWebhookSecretRoute     = "/api/v2/workflow/{workflowID}/webhook/secret"
 	DAGsRoute                      = "/api/v2/workflow/{workflowID}/dags"
 	DAGRoute                       = "/api/v2/workflow/{workflowID}/dag/{dagID}"
 	DAGResultsRoute                = "/api/v2/workflow/{workflowID}/results"
 	DAGResultRoute                 = "/api/v2/workflow/{workflowID}/result/{dagResultID}"
+	DAGResultCancelRoute           = "/api/v2/workflow/{workflowID}/result/{dagResultID}/cancel"
+	DAGResultPinRoute              = "/api/v2/workflow/{workflowID}/result/{dagResultID}/pin"
+	DAGResultResumeRoute           = "/api/v2/workflow/{workflowID}/result/{dagResultID}/resume"
+	DAGResultUnpinRoute            = "/api/v2/workflow/{workflowID}/result/{dagResultID}/unpin"
 	NodesRoute                     = "/api/v2/workflow/{workflowID}/dag/{dagID}/nodes"
 	NodeArtifactRoute              = "/api/v2/workflow/{workflowID}/dag/{dagID}/node/artifact/{nodeID}"
 	NodeArtifactResultContentRoute = "/api/v2/workflow/{workflowID}/dag/{dagID}/node/artifact/{nodeID}/result/{nodeResultID}/content"
@@ -27,6 +36,7 @@ const (
 	NodeOperatorContentRoute       = "/api/v2/workflow/{workflowID}/dag/{dagID}/node/operator/{nodeID}/content"
 	NodesResultsRoute              = "/api/v2/workflow/{workflowID}/result/{dagResultID}/nodes/results"
 	EnvironmentRoute               = "/api/v2/environment"
+	StorageCacheRoute              = "/api/v2/storage/cache"
 
 	// V2 hacky routes
 	// These routes are supposed to be `v2/workflow/{workflowId}`
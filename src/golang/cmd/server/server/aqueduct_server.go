@@ -17,12 +17,12 @@ import (
 	"github.com/aqueducthq/aqueduct/cmd/server/middleware/usage"
 	"github.com/aqueducthq/aqueduct/config"
 	aq_context "github.com/aqueducthq/aqueduct/lib/context"
+	"github.com/aqueducthq/aqueduct/lib/cronjob"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/engine"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/aqueducthq/aqueduct/lib/logging"
 	"github.com/aqueducthq/aqueduct/lib/models"
-	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
 	"github.com/aqueducthq/aqueduct/lib/workflow/preview_cache"
@@ -39,6 +39,9 @@ const (
 
 	// The maximum number of entries this cache can have.
 	previewCacheSize = 200
+
+	// How long the storage gc job keeps an orphaned storage object by default.
+	defaultStorageGCGracePeriod = 24 * time.Hour
 )
 
 var uiDir = path.Join(os.Getenv("HOME"), ".aqueduct", "ui")
@@ -58,6 +61,11 @@ type AqServer struct {
 	AqEngine   engine.AqEngine
 	AqPath     string
 
+	// The cron jobs and the scheduler leader election are kept when the server restarts,
+	// so that the cron jobs are not deployed twice.
+	CronjobManager  cronjob.CronjobManager
+	SchedulerLeader *cronjob.LeaderElector
+
 	// UnderMaintenance indicates whether the server is currently down for system maintenance.
 	UnderMaintenance atomic.Value
 	// RequestMutex's read lock is acquired and released by each request to indicate when there
@@ -71,21 +79,21 @@ type AqServer struct {
 
 func NewAqServer(environment aq_context.ServerEnvironment, externalIP string, port int, disableUsageStats bool) *AqServer {
 	ctx := context.Background()
-	aqPath := config.AqueductPath()
 
 	// The database cannot be reinitialized when the server restarts, because the database is passed
 	// to the middleware functions.
-	db, err := database.NewSqliteDatabase(&database.SqliteConfig{
-		File: path.Join(aqPath, database.SqliteDatabasePath),
-	})
+	dbConfig := config.Database()
+	db, err := database.NewDatabase(&dbConfig)
 	if err != nil {
 		log.Fatalf("Unable to initialize database: %v", err)
 	}
 
+	serverRepos := CreateRepos()
+
 	if err := requireSchemaVersion(
 		context.Background(),
 		models.CurrentSchemaVersion,
-		sqlite.NewSchemaVersionRepo(),
+		serverRepos.SchemaVersionRepo,
 		db,
 	); err != nil {
 		db.Close()
@@ -97,7 +105,7 @@ func NewAqServer(environment aq_context.ServerEnvironment, externalIP string, po
 		ExternalIP:        externalIP,
 		Port:              port,
 		Database:          db,
-		Repos:             CreateRepos(),
+		Repos:             serverRepos,
 		UnderMaintenance:  atomic.Value{},
 		RequestMutex:      sync.RWMutex{},
 		Environment:       environment,
@@ -105,6 +113,14 @@ func NewAqServer(environment aq_context.ServerEnvironment, externalIP string, po
 	}
 	s.UnderMaintenance.Store(false)
 
+	schedulerLeader, err := newSchedulerLeader(config.Scheduler(), serverRepos.SchedulerLeaseRepo, db)
+	if err != nil {
+		db.Close()
+		log.Fatalf("Unable to initialize scheduler leader election: %v", err)
+	}
+	s.CronjobManager = cronjob.NewProcessCronjobManager()
+	s.SchedulerLeader = schedulerLeader
+
 	// Initialize the other server fields
 	if err := s.Init(); err != nil {
 		db.Close()
@@ -141,7 +157,7 @@ func NewAqServer(environment aq_context.ServerEnvironment, externalIP string, po
 		log.Fatal(err)
 	}
 
-	err = s.initializeWorkflowCronJobs(ctx)
+	err = s.AqEngine.SyncScheduledWorkflows(ctx)
 	if err != nil {
 		db.Close()
 		log.Fatalf("Failed to create cron jobs for existing workflows: %v", err)
@@ -178,23 +194,38 @@ func (s *AqServer) Init() error {
 		return err
 	}
 
-	vault, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+	vaultObj, err := vault.NewVault(&storageConfig, config.EncryptionKey())
 	if err != nil {
 		return err
 	}
 
 	if err := syncVaultWithStorage(
-		vault,
+		vaultObj,
 		s.ResourceRepo,
+		s.WorkflowRepo,
 		s.Database,
 	); err != nil {
 		return err
 	}
 
+	if vault.IsExternal() {
+		if err := syncExternalVault(
+			vaultObj,
+			&storageConfig,
+			s.ResourceRepo,
+			s.WorkflowRepo,
+			s.Database,
+		); err != nil {
+			return errors.Wrap(err, "Unable to move secrets from storage to the external vault.")
+		}
+	}
+
 	eng, err := engine.NewAqEngine(
 		s.Database,
 		githubManager,
 		previewCacheManager,
+		s.CronjobManager,
+		s.SchedulerLeader,
 		aqPath,
 		s.fullDisplayAddress(),
 		GetEngineRepos(s.Repos),
@@ -265,6 +296,76 @@ func (s *AqServer) StartWorkflowRetentionJob(period string) error {
 	return nil
 }
 
+// StartStorageGCJob deploys the job that deletes orphaned objects from storage, if it is enabled by gcConfig.
+func (s *AqServer) StartStorageGCJob(gcConfig config.StorageGCConfig) error {
+	name := job.StorageGCName
+	ctx := context.Background()
+
+	// Delete old CronJob if it exists
+	err := s.JobManager.DeleteCronJob(ctx, name)
+	if err != nil {
+		return errors.Wrap(err, "Unable to delete existing storage gc job")
+	}
+
+	if gcConfig.JobPeriod == "" {
+		return nil
+	}
+
+	gracePeriod := defaultStorageGCGracePeriod
+	if gcConfig.GracePeriod != "" {
+		var parseErr error
+		gracePeriod, parseErr = time.ParseDuration(gcConfig.GracePeriod)
+		if parseErr != nil {
+			return errors.Wrapf(parseErr, "Invalid storage gc grace period %s", gcConfig.GracePeriod)
+		}
+	}
+
+	spec := job.NewStorageGCJobSpec(
+		s.Database.Config(),
+		s.JobManager.Config(),
+		gracePeriod,
+		!gcConfig.Delete,
+	)
+
+	err = s.JobManager.DeployCronJob(
+		ctx,
+		name,
+		gcConfig.JobPeriod,
+		spec,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to start storage gc cron job")
+	}
+	return nil
+}
+
+// StartRunQueue starts the workers that launch the queued workflow runs, as configured by queueConfig.
+func (s *AqServer) StartRunQueue(queueConfig config.RunQueueConfig) error {
+	numWorkers := engine.DefaultRunQueueWorkers
+	if queueConfig.NumWorkers > 0 {
+		numWorkers = queueConfig.NumWorkers
+	}
+
+	leaseDuration := engine.DefaultRunQueueLeaseDuration
+	if queueConfig.LeaseDuration != "" {
+		var parseErr error
+		leaseDuration, parseErr = time.ParseDuration(queueConfig.LeaseDuration)
+		if parseErr != nil {
+			return errors.Wrapf(parseErr, "Invalid run queue lease duration %s", queueConfig.LeaseDuration)
+		}
+	}
+
+	if leaseDuration < engine.MinRunQueueLeaseDuration {
+		return errors.Newf(
+			"The run queue lease duration must be at least %v, but got %v",
+			engine.MinRunQueueLeaseDuration,
+			leaseDuration,
+		)
+	}
+
+	return s.AqEngine.StartRunQueue(context.Background(), numWorkers, leaseDuration)
+}
+
 func (s *AqServer) AddHandler(route string, handlerObj handler.Handler) {
 	middleware := alice.New()
 
@@ -278,6 +379,11 @@ func (s *AqServer) AddHandler(route string, handlerObj handler.Handler) {
 			request_id.WithRequestId(),
 			authentication.RequireApiKey(s.UserRepo, s.Database),
 		)
+	} else if handlerObj.AuthMethod() == handler.WebhookSignatureAuthMethod {
+		middleware = middleware.Append(
+			maintenance.Check(&s.UnderMaintenance),
+			request_id.WithRequestId(),
+		)
 	} else {
 		panic(errors.New("Auth method is not supported."))
 	}
@@ -351,6 +457,11 @@ func (s *AqServer) Restart() {
 	if err := s.Init(); err != nil {
 		log.Fatalf("Unable to restart server: %v", err)
 	}
+
+	// The existing cron jobs are redeployed so that they trigger the workflows through the new engine.
+	if err := s.AqEngine.SyncScheduledWorkflows(context.Background()); err != nil {
+		log.Errorf("Unable to sync scheduled workflows after restart: %v", err)
+	}
 	s.RequestMutex.Unlock()
 	s.UnderMaintenance.Store(false)
 }
@@ -4,16 +4,15 @@ import (
 	"context"
 	"time"
 
-	v2 "github.com/aqueducthq/aqueduct/cmd/server/handler/v2"
 	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/engine"
 	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
-	"github.com/gorhill/cronexpr"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -26,30 +25,49 @@ func (s *AqServer) SyncCronJobs() error {
 	return s.runMissedCronJobs(ctx)
 }
 
+// triggerMissedCronJobs triggers the cron runs of a workflow that were missed between referenceTime
+// and until, according to the catch-up policy of its schedule. Missed runs in a blackout window are
+// recorded as skipped instead.
 func (s *AqServer) triggerMissedCronJobs(
 	ctx context.Context,
 	workflowId uuid.UUID,
-	cronSchedule string,
+	schedule shared.Schedule,
 	referenceTime time.Time,
+	until time.Time,
 ) {
-	nextTwoTriggerTimeStamp := cronexpr.MustParse(cronSchedule).NextN(time.Now(), 2)
-	// We subtract the next two trigger timestamp to get the duration between consecutive cron jobs.
-	duration := nextTwoTriggerTimeStamp[1].Unix() - nextTwoTriggerTimeStamp[0].Unix()
-	// Subtracting the duration from the next trigger timestamp gives us the last expected trigger timestamp.
-	lastExpectedTriggerTime := nextTwoTriggerTimeStamp[0].Unix() - duration
-	if lastExpectedTriggerTime > referenceTime.Unix() {
-		// This means that the workflow should have been triggered, but it wasn't.
-		// So we manually trigger the workflow here.
-		_, _, err := (&v2.WorkflowPostHandler{
-			Database: s.Database,
-			Engine:   s.AqEngine,
-
-			WorkflowRepo: s.WorkflowRepo,
-		}).Perform(
+	runTimes, droppedTimes, err := workflow.MissedRunTimes(&schedule, referenceTime, until)
+	if err != nil {
+		log.Errorf("Unable to determine missed runs of workflow %v: %v", workflowId, err)
+		return
+	}
+
+	// The earliest runs beyond the catch-up limit do not run, but they are still visible in the run history.
+	for _, droppedTime := range droppedTimes {
+		if err := s.AqEngine.RecordSkippedRun(ctx, workflowId, droppedTime, engine.CatchUpLimitSkipReason()); err != nil {
+			log.Errorf("Unable to record skipped run of workflow: %v", err)
+		}
+	}
+
+	timeConfig := &engine.AqueductTimeConfig{
+		OperatorPollInterval: engine.DefaultPollIntervalMillisec,
+		ExecTimeout:          engine.DefaultExecutionTimeout,
+		CleanupTimeout:       engine.DefaultCleanupTimeout,
+	}
+
+	for _, runTime := range runTimes {
+		if window := schedule.BlackoutAt(runTime); window != nil {
+			if err := s.AqEngine.RecordSkippedRun(ctx, workflowId, runTime, engine.BlackoutSkipReason(window)); err != nil {
+				log.Errorf("Unable to record skipped run of workflow: %v", err)
+			}
+			continue
+		}
+
+		_, err := s.AqEngine.TriggerWorkflow(
 			ctx,
-			&v2.WorkflowPostArgs{
-				WorkflowId: workflowId,
-			},
+			workflowId,
+			shared_utils.AppendPrefix(workflowId.String()),
+			timeConfig,
+			workflow.ScheduledTimeParams(runTime),
 		)
 		if err != nil {
 			log.Errorf("Unable to trigger workflow: %v", err)
@@ -57,9 +75,9 @@ func (s *AqServer) triggerMissedCronJobs(
 	}
 }
 
-// backfillKilledJobs backfills all pending and running op/artf/DAG _results
-// and mark them as canceled. For non-aqueduct jobs like Airflow, we sync these
-// jobs from the remote servers.
+// backfillKilledJobs syncs the pending and running runs of non-aqueduct jobs like Airflow
+// from the remote servers. The runs that the run queue workers of a killed server replica
+// were executing are canceled by the run queue once their leases expire.
 func (s *AqServer) backfillKilledJobs(ctx context.Context) error {
 	txn, err := s.Database.BeginTx(ctx)
 	if err != nil {
@@ -67,68 +85,12 @@ func (s *AqServer) backfillKilledJobs(ctx context.Context) error {
 	}
 	defer database.TxnRollbackIgnoreErr(ctx, txn)
 
-	if _, err := s.OperatorResultRepo.UpdateBatchStatusByStatus(
-		ctx,
-		shared.RunningExecutionStatus,
-		shared.CanceledExecutionStatus,
-		txn,
-	); err != nil {
-		return err
-	}
-
-	if _, err := s.OperatorResultRepo.UpdateBatchStatusByStatus(
-		ctx,
-		shared.PendingExecutionStatus,
-		shared.CanceledExecutionStatus,
-		txn,
-	); err != nil {
-		return err
-	}
-
-	if _, err := s.ArtifactResultRepo.UpdateBatchStatusByStatus(
-		ctx,
-		shared.RunningExecutionStatus,
-		shared.CanceledExecutionStatus,
-		txn,
-	); err != nil {
-		return err
-	}
-
-	if _, err := s.ArtifactResultRepo.UpdateBatchStatusByStatus(
-		ctx,
-		shared.PendingExecutionStatus,
-		shared.CanceledExecutionStatus,
-		txn,
-	); err != nil {
-		return err
-	}
-
-	if _, err := s.DAGResultRepo.UpdateBatchStatusByStatus(
-		ctx,
-		shared.RunningExecutionStatus,
-		shared.CanceledExecutionStatus,
-		txn,
-	); err != nil {
-		return err
-	}
-
-	if _, err := s.DAGResultRepo.UpdateBatchStatusByStatus(
-		ctx,
-		shared.PendingExecutionStatus,
-		shared.CanceledExecutionStatus,
-		txn,
-	); err != nil {
-		return err
-	}
-
 	storageConfig := config.Storage()
 	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
 	if err != nil {
 		return err
 	}
 
-	// Backfill self-orchestrated workflows after the above steps and overwrite
-	// within the txn.
 	if err := engine.SyncSelfOrchestratedWorkflows(
 		ctx,
 		"", /* orgID */
@@ -149,81 +111,98 @@ func (s *AqServer) backfillKilledJobs(ctx context.Context) error {
 	return txn.Commit(ctx)
 }
 
+// missedCronJobs is a workflow whose missed cron runs are caught up since referenceTime.
+type missedCronJobs struct {
+	workflowID    uuid.UUID
+	schedule      shared.Schedule
+	referenceTime time.Time
+}
+
 // runMissedCronJobs first gets the latest workflow run timestamp of all deployed workflows that are
 // running on Aqueduct, on a schedule, and are not paused. For each workflow, it compares the latest workflow
-// run's timestamp with the expected trigger timestamp calculated based on the cron schedule, and manually
-// triggers the workflow if the cron triggering did not happen.
+// run's timestamp with the expected trigger timestamps calculated based on the cron schedule, and manually
+// triggers the workflow for the cron triggerings that did not happen, according to its catch-up policy.
+// Runs that are still in the run queue count as the latest run, so that the runs enqueued by a previous
+// scheduler leader are not triggered again.
+// The cron jobs of this replica start firing once the latest runs are read, and the runs scheduled
+// until then are the ones that are caught up, so that no run is both fired and caught up.
 func (s *AqServer) runMissedCronJobs(ctx context.Context) error {
+	missed, err := s.getMissedCronJobs(ctx)
+	// The cron jobs fire even if the missed runs cannot be determined.
+	firingSince := s.SchedulerLeader.EnableFiring()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range missed {
+		s.triggerMissedCronJobs(ctx, m.workflowID, m.schedule, m.referenceTime, firingSince)
+	}
+
+	return nil
+}
+
+// getMissedCronJobs returns the scheduled workflows that are not paused, with the time
+// of their latest run that the missed runs are caught up since.
+func (s *AqServer) getMissedCronJobs(ctx context.Context) ([]missedCronJobs, error) {
+	runRequests, err := s.RunRequestRepo.ListUnfinished(ctx, s.Database)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to get unfinished run requests from database.")
+	}
+
+	lastEnqueuedAt := make(map[uuid.UUID]time.Time, len(runRequests))
+	for _, runRequest := range runRequests {
+		if runRequest.CreatedAt.After(lastEnqueuedAt[runRequest.WorkflowID]) {
+			lastEnqueuedAt[runRequest.WorkflowID] = runRequest.CreatedAt
+		}
+	}
+
 	wfLastRuns, err := s.WorkflowRepo.GetLastRunByEngine(
 		ctx,
 		shared.AqueductEngineType,
 		s.Database,
 	)
 	if err != nil {
-		return errors.Wrap(err, "Unable to get workflow last run data from database.")
+		return nil, errors.Wrap(err, "Unable to get workflow last run data from database.")
 	}
 
+	missed := []missedCronJobs{}
 	workflowsRan := map[uuid.UUID]bool{}
 
 	for _, wfLastRun := range wfLastRuns {
 		if wfLastRun.Schedule.CronSchedule != "" && !wfLastRun.Schedule.Paused {
-			s.triggerMissedCronJobs(
-				ctx,
-				wfLastRun.ID,
-				string(wfLastRun.Schedule.CronSchedule),
-				wfLastRun.LastRunAt,
-			)
+			missed = append(missed, missedCronJobs{
+				workflowID:    wfLastRun.ID,
+				schedule:      wfLastRun.Schedule,
+				referenceTime: latestTime(wfLastRun.LastRunAt, lastEnqueuedAt[wfLastRun.ID]),
+			})
 		}
 		workflowsRan[wfLastRun.ID] = true
 	}
 
 	allWorkflows, err := s.WorkflowRepo.List(ctx, s.Database)
 	if err != nil {
-		return errors.Wrap(err, "Unable to get workflows from database.")
+		return nil, errors.Wrap(err, "Unable to get workflows from database.")
 	}
 
 	for _, workflow := range allWorkflows {
 		if _, ok := workflowsRan[workflow.ID]; !ok {
 			// If we reach here, it means this workflow hasn't produced any run yet.
 			if workflow.Schedule.CronSchedule != "" && !workflow.Schedule.Paused {
-				s.triggerMissedCronJobs(
-					ctx,
-					workflow.ID,
-					string(workflow.Schedule.CronSchedule),
-					workflow.CreatedAt,
-				)
+				missed = append(missed, missedCronJobs{
+					workflowID:    workflow.ID,
+					schedule:      workflow.Schedule,
+					referenceTime: latestTime(workflow.CreatedAt, lastEnqueuedAt[workflow.ID]),
+				})
 			}
 		}
 	}
 
-	return nil
+	return missed, nil
 }
 
-func (s *AqServer) initializeWorkflowCronJobs(ctx context.Context) error {
-	workflows, err := s.WorkflowRepo.List(ctx, s.Database)
-	if err != nil {
-		return err
+func latestTime(a time.Time, b time.Time) time.Time {
+	if b.After(a) {
+		return b
 	}
-
-	for _, wf := range workflows {
-		if wf.Schedule.CronSchedule != "" {
-			if wf.Schedule.Paused {
-				wf.Schedule.CronSchedule = ""
-			}
-			name := shared_utils.AppendPrefix(wf.ID.String())
-			period := string(wf.Schedule.CronSchedule)
-
-			err = s.AqEngine.ScheduleWorkflow(
-				ctx,
-				wf.ID,
-				name,
-				period,
-			)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return a
 }
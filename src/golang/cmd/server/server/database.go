@@ -6,7 +6,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/engine"
 	"github.com/aqueducthq/aqueduct/lib/repos"
-	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
 	"github.com/dropbox/godropbox/errors"
 )
 
@@ -22,6 +22,8 @@ type Repos struct {
 	NotificationRepo         repos.Notification
 	OperatorRepo             repos.Operator
 	OperatorResultRepo       repos.OperatorResult
+	RunRequestRepo           repos.RunRequest
+	SchedulerLeaseRepo       repos.SchedulerLease
 	SchemaVersionRepo        repos.SchemaVersion
 	UserRepo                 repos.User
 	WatcherRepo              repos.Watcher
@@ -30,21 +32,23 @@ type Repos struct {
 
 func CreateRepos() *Repos {
 	return &Repos{
-		ArtifactRepo:             sqlite.NewArtifactRepo(),
-		ArtifactResultRepo:       sqlite.NewArtifactResultRepo(),
-		DAGRepo:                  sqlite.NewDAGRepo(),
-		DAGEdgeRepo:              sqlite.NewDAGEdgeRepo(),
-		DAGResultRepo:            sqlite.NewDAGResultRepo(),
-		ExecutionEnvironmentRepo: sqlite.NewExecutionEnvironmentRepo(),
-		ResourceRepo:             sqlite.NewResourceRepo(),
-		StorageMigrationRepo:     sqlite.NewStorageMigrationRepo(),
-		NotificationRepo:         sqlite.NewNotificationRepo(),
-		OperatorRepo:             sqlite.NewOperatorRepo(),
-		OperatorResultRepo:       sqlite.NewOperatorResultRepo(),
-		SchemaVersionRepo:        sqlite.NewSchemaVersionRepo(),
-		UserRepo:                 sqlite.NewUserRepo(),
-		WatcherRepo:              sqlite.NewWatcherRepo(),
-		WorkflowRepo:             sqlite.NewWorklowRepo(),
+		ArtifactRepo:             sql.NewArtifactRepo(),
+		ArtifactResultRepo:       sql.NewArtifactResultRepo(),
+		DAGRepo:                  sql.NewDAGRepo(),
+		DAGEdgeRepo:              sql.NewDAGEdgeRepo(),
+		DAGResultRepo:            sql.NewDAGResultRepo(),
+		ExecutionEnvironmentRepo: sql.NewExecutionEnvironmentRepo(),
+		ResourceRepo:             sql.NewResourceRepo(),
+		StorageMigrationRepo:     sql.NewStorageMigrationRepo(),
+		NotificationRepo:         sql.NewNotificationRepo(),
+		OperatorRepo:             sql.NewOperatorRepo(),
+		OperatorResultRepo:       sql.NewOperatorResultRepo(),
+		RunRequestRepo:           sql.NewRunRequestRepo(),
+		SchedulerLeaseRepo:       sql.NewSchedulerLeaseRepo(),
+		SchemaVersionRepo:        sql.NewSchemaVersionRepo(),
+		UserRepo:                 sql.NewUserRepo(),
+		WatcherRepo:              sql.NewWatcherRepo(),
+		WorkflowRepo:             sql.NewWorklowRepo(),
 	}
 }
 
@@ -60,6 +64,7 @@ func GetEngineRepos(repos *Repos) *engine.Repos {
 		NotificationRepo:         repos.NotificationRepo,
 		OperatorRepo:             repos.OperatorRepo,
 		OperatorResultRepo:       repos.OperatorResultRepo,
+		RunRequestRepo:           repos.RunRequestRepo,
 		WatcherRepo:              repos.WatcherRepo,
 		WorkflowRepo:             repos.WorkflowRepo,
 	}
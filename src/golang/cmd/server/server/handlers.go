@@ -4,9 +4,20 @@ import (
 	"github.com/aqueducthq/aqueduct/cmd/server/handler"
 	v2 "github.com/aqueducthq/aqueduct/cmd/server/handler/v2"
 	"github.com/aqueducthq/aqueduct/cmd/server/routes"
+	"github.com/aqueducthq/aqueduct/lib/storage_migration"
 )
 
 func (s *AqServer) Handlers() map[string]handler.Handler {
+	storageMigrationRepos := &storage_migration.Repos{
+		ArtifactRepo:         s.ArtifactRepo,
+		ArtifactResultRepo:   s.ArtifactResultRepo,
+		DAGRepo:              s.DAGRepo,
+		ResourceRepo:         s.ResourceRepo,
+		OperatorRepo:         s.OperatorRepo,
+		StorageMigrationRepo: s.StorageMigrationRepo,
+		WorkflowRepo:         s.WorkflowRepo,
+	}
+
 	return map[string]handler.Handler{
 		// V2 Handlers
 		routes.WorkflowRoute: &v2.WorkflowGetHandler{
@@ -33,15 +44,73 @@ func (s *AqServer) Handlers() map[string]handler.Handler {
 			WorkflowRepo:  s.WorkflowRepo,
 			DAGResultRepo: s.DAGResultRepo,
 		},
+		routes.DAGResultCancelRoute: &v2.DAGResultCancelPostHandler{
+			Database:           s.Database,
+			WorkflowRepo:       s.WorkflowRepo,
+			DAGRepo:            s.DAGRepo,
+			DAGResultRepo:      s.DAGResultRepo,
+			ArtifactResultRepo: s.ArtifactResultRepo,
+			OperatorResultRepo: s.OperatorResultRepo,
+			NotificationRepo:   s.NotificationRepo,
+		},
+		routes.DAGResultPinRoute: &v2.DAGResultPinPostHandler{
+			Pinned:        true,
+			Database:      s.Database,
+			WorkflowRepo:  s.WorkflowRepo,
+			DAGRepo:       s.DAGRepo,
+			DAGResultRepo: s.DAGResultRepo,
+		},
+		routes.DAGResultUnpinRoute: &v2.DAGResultPinPostHandler{
+			Pinned:        false,
+			Database:      s.Database,
+			WorkflowRepo:  s.WorkflowRepo,
+			DAGRepo:       s.DAGRepo,
+			DAGResultRepo: s.DAGResultRepo,
+		},
+		routes.DAGResultResumeRoute: &v2.DAGResultResumePostHandler{
+			Database: s.Database,
+			Engine:   s.AqEngine,
+
+			WorkflowRepo:  s.WorkflowRepo,
+			DAGRepo:       s.DAGRepo,
+			DAGResultRepo: s.DAGResultRepo,
+		},
+		routes.WorkflowWebhookRoute: &v2.WorkflowWebhookPostHandler{
+			Database: s.Database,
+			Engine:   s.AqEngine,
+
+			WorkflowRepo: s.WorkflowRepo,
+		},
+		routes.WorkflowWebhookSecretRoute: &v2.WorkflowWebhookSecretPostHandler{
+			Database: s.Database,
+
+			WorkflowRepo: s.WorkflowRepo,
+		},
 		routes.DAGResultsRoute: &v2.DAGResultsGetHandler{
 			Database:      s.Database,
 			WorkflowRepo:  s.WorkflowRepo,
 			DAGResultRepo: s.DAGResultRepo,
 		},
+		routes.RunQueueRoute: &v2.RunQueueGetHandler{
+			Database:       s.Database,
+			RunRequestRepo: s.RunRequestRepo,
+		},
 		routes.ListStorageMigrationRoute: &v2.ListStorageMigrationsHandler{
 			Database:             s.Database,
 			StorageMigrationRepo: s.StorageMigrationRepo,
 		},
+		routes.StorageMigrationResumeRoute: &v2.StorageMigrationResumePostHandler{
+			Database:        s.Database,
+			Repos:           storageMigrationRepos,
+			PauseServerFn:   s.Pause,
+			RestartServerFn: s.Restart,
+		},
+		routes.StorageMigrationRollbackRoute: &v2.StorageMigrationRollbackPostHandler{
+			Database:        s.Database,
+			Repos:           storageMigrationRepos,
+			PauseServerFn:   s.Pause,
+			RestartServerFn: s.Restart,
+		},
 		routes.WorkflowsRoute: &v2.WorkflowsGetHandler{
 			Database:     s.Database,
 			WorkflowRepo: s.WorkflowRepo,
@@ -137,7 +206,8 @@ func (s *AqServer) Handlers() map[string]handler.Handler {
 			DAGResultRepo: s.DAGResultRepo,
 			OperatorRepo:  s.OperatorRepo,
 		},
-		routes.EnvironmentRoute: &v2.EnvironmentHandler{},
+		routes.EnvironmentRoute:  &v2.EnvironmentHandler{},
+		routes.StorageCacheRoute: &v2.StorageCacheGetHandler{},
 
 		// V1 Handlers
 		// (ENG-2715) Remove deprecated ones
@@ -156,6 +226,7 @@ func (s *AqServer) Handlers() map[string]handler.Handler {
 			ResourceRepo:         s.ResourceRepo,
 			StorageMigrationRepo: s.StorageMigrationRepo,
 			OperatorRepo:         s.OperatorRepo,
+			WorkflowRepo:         s.WorkflowRepo,
 
 			PauseServer:   s.Pause,
 			RestartServer: s.Restart,
@@ -258,6 +329,7 @@ func (s *AqServer) Handlers() map[string]handler.Handler {
 			ResourceRepo:         s.ResourceRepo,
 			OperatorRepo:         s.OperatorRepo,
 			StorageMigrationRepo: s.StorageMigrationRepo,
+			WorkflowRepo:         s.WorkflowRepo,
 
 			PauseServerFn:   s.Pause,
 			RestartServerFn: s.Restart,
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/cronjob"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// scheduleSyncInterval is how often the scheduler leader reloads the schedules of the workflows,
+// so that it picks up the schedules that were changed through another replica.
+const scheduleSyncInterval = 30 * time.Second
+
+func newSchedulerLeader(
+	schedulerConfig config.SchedulerConfig,
+	leaseRepo repos.SchedulerLease,
+	db database.Database,
+) (*cronjob.LeaderElector, error) {
+	replicaID := schedulerConfig.ReplicaID
+	if replicaID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to determine the replica ID from the hostname.")
+		}
+		replicaID = hostname
+	}
+
+	leaseDuration := cronjob.DefaultSchedulerLeaseDuration
+	if schedulerConfig.LeaseDuration != "" {
+		var err error
+		leaseDuration, err = time.ParseDuration(schedulerConfig.LeaseDuration)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid scheduler lease duration %s", schedulerConfig.LeaseDuration)
+		}
+	}
+
+	if leaseDuration < cronjob.MinSchedulerLeaseDuration {
+		return nil, errors.Newf(
+			"The scheduler lease duration must be at least %v, but got %v",
+			cronjob.MinSchedulerLeaseDuration,
+			leaseDuration,
+		)
+	}
+
+	return cronjob.NewLeaderElector(replicaID, leaseDuration, leaseRepo, db), nil
+}
+
+// StartScheduler elects the replica that fires the cron jobs of the workflows among the replicas that
+// share the database. If this replica is elected on startup, it syncs the runs of self-orchestrated
+// engines and triggers the missed cron runs. Otherwise, it takes over the cron jobs once the leader stops
+// renewing its lease.
+func (s *AqServer) StartScheduler() error {
+	ctx := context.Background()
+
+	isLeader, err := s.SchedulerLeader.TryAcquire(ctx)
+	if err != nil {
+		log.Errorf("Replica %s is unable to acquire the scheduler lease: %v", s.SchedulerLeader.ReplicaID(), err)
+	}
+
+	go s.SchedulerLeader.Run(ctx, s.onElectedSchedulerLeader)
+	go s.syncScheduledWorkflows(ctx)
+
+	if !isLeader {
+		log.Infof(
+			"Replica %s is not the scheduler leader, so it does not fire cron jobs until it is elected.",
+			s.SchedulerLeader.ReplicaID(),
+		)
+		return nil
+	}
+
+	log.Infof("Replica %s is the scheduler leader.", s.SchedulerLeader.ReplicaID())
+	if err := s.SyncCronJobs(); err != nil {
+		// The cron jobs still fire, even though the missed runs were not caught up.
+		s.SchedulerLeader.EnableFiring()
+		return err
+	}
+
+	return nil
+}
+
+// onElectedSchedulerLeader is called when this replica takes over the cron jobs from another replica.
+// The runs of self-orchestrated engines are not synced again, since the previous leader synced them.
+// The cron jobs of this replica do not fire until the missed runs are determined.
+func (s *AqServer) onElectedSchedulerLeader(ctx context.Context) {
+	if err := s.AqEngine.SyncScheduledWorkflows(ctx); err != nil {
+		log.Errorf("Unable to sync scheduled workflows: %v", err)
+	}
+
+	if err := s.runMissedCronJobs(ctx); err != nil {
+		log.Errorf("Unable to run missed cron jobs: %v", err)
+	}
+}
+
+// syncScheduledWorkflows periodically reloads the schedules of the workflows while this replica
+// is the scheduler leader.
+func (s *AqServer) syncScheduledWorkflows(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(scheduleSyncInterval):
+		}
+
+		if !s.SchedulerLeader.IsLeader() {
+			continue
+		}
+
+		if err := s.AqEngine.SyncScheduledWorkflows(ctx); err != nil {
+			log.Errorf("Unable to sync scheduled workflows: %v", err)
+		}
+	}
+}
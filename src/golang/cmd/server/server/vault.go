@@ -7,10 +7,14 @@ import (
 
 	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/aqueducthq/aqueduct/lib/workflow/webhook"
+	log "github.com/sirupsen/logrus"
 )
 
 // syncVaultWithStorage checks if this server's vault is out of sync
@@ -19,6 +23,7 @@ import (
 func syncVaultWithStorage(
 	vaultObj vault.Vault,
 	resourceRepo repos.Resource,
+	workflowRepo repos.Workflow,
 	DB database.Database,
 ) error {
 	oldVaultPath := path.Join(config.AqueductPath(), "vault")
@@ -31,7 +36,7 @@ func syncVaultWithStorage(
 		return err
 	}
 
-	oldVault, err := vault.NewVault(
+	oldVault, err := vault.NewStorageVault(
 		&shared.StorageConfig{
 			Type: shared.FileStorageType,
 			FileConfig: &shared.FileConfig{
@@ -50,6 +55,7 @@ func syncVaultWithStorage(
 		vaultObj,
 		accountOrganizationId,
 		resourceRepo,
+		workflowRepo,
 		DB,
 	); err != nil {
 		return err
@@ -59,3 +65,63 @@ func syncVaultWithStorage(
 	// is not repeated.
 	return os.RemoveAll(oldVaultPath)
 }
+
+// syncExternalVault moves the secrets that are still stored in the storage layer into `vaultObj`,
+// which is the external vault configured by the server. This only happens once the server
+// is configured to use an external vault, since the secrets are deleted from storage afterwards.
+func syncExternalVault(
+	vaultObj vault.Vault,
+	storageConfig *shared.StorageConfig,
+	resourceRepo repos.Resource,
+	workflowRepo repos.Workflow,
+	DB database.Database,
+) error {
+	ctx := context.Background()
+
+	storageVault, err := vault.NewStorageVault(storageConfig, config.EncryptionKey())
+	if err != nil {
+		return err
+	}
+
+	resources, err := resourceRepo.GetByOrg(ctx, accountOrganizationId, DB)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(resources))
+	for _, resourceObj := range resources {
+		keys = append(keys, resourceObj.ID.String())
+	}
+
+	webhookWorkflows, err := workflowRepo.GetByScheduleTrigger(ctx, shared.WebhookUpdateTrigger, DB)
+	if err != nil {
+		return err
+	}
+
+	for _, workflowObj := range webhookWorkflows {
+		keys = append(keys, webhook.SecretVaultKey(workflowObj.ID))
+	}
+
+	for _, key := range keys {
+		secrets, err := storageVault.Get(ctx, key)
+		if err != nil {
+			if errors.Is(err, storage.ErrObjectDoesNotExist()) {
+				// The secret was already moved.
+				continue
+			}
+			return err
+		}
+
+		if err := vaultObj.Put(ctx, key, secrets); err != nil {
+			return err
+		}
+
+		if err := storageVault.Delete(ctx, key); err != nil {
+			return err
+		}
+
+		log.Infof("Moved secret %s from storage to the external vault.", key)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path"
 
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/dropbox/godropbox/errors"
 	"gopkg.in/yaml.v2"
@@ -17,12 +18,109 @@ var (
 )
 
 type serverConfiguration struct {
-	AqPath             string                `yaml:"aqPath"`
-	EncryptionKey      string                `yaml:"encryptionKey"`
-	RetentionJobPeriod string                `yaml:"retentionJobPeriod"`
-	ApiKey             string                `yaml:"apiKey"`
-	StorageConfig      *shared.StorageConfig `yaml:"storageConfig"`
-	VersionTag         string                `yaml:"versionTag"`
+	AqPath                 string                   `yaml:"aqPath"`
+	EncryptionKey          string                   `yaml:"encryptionKey"`
+	StorageMasterKey       string                   `yaml:"storageMasterKey"`
+	RetentionJobPeriod     string                   `yaml:"retentionJobPeriod"`
+	ApiKey                 string                   `yaml:"apiKey"`
+	StorageConfig          *shared.StorageConfig    `yaml:"storageConfig"`
+	DatabaseConfig         *database.DatabaseConfig `yaml:"databaseConfig"`
+	VersionTag             string                   `yaml:"versionTag"`
+	MaxConcurrentOperators int                      `yaml:"maxConcurrentOperators"`
+	StorageGC              StorageGCConfig          `yaml:"storageGC"`
+	StorageCache           StorageCacheConfig       `yaml:"storageCache"`
+	RunQueue               RunQueueConfig           `yaml:"runQueue"`
+	Scheduler              SchedulerConfig          `yaml:"scheduler"`
+	Vault                  VaultConfig              `yaml:"vault"`
+}
+
+// StorageGCConfig configures the job that deletes objects in storage that the metadata database
+// no longer refers to, e.g. the outputs of failed runs.
+type StorageGCConfig struct {
+	// JobPeriod is the cron schedule of the job. The job is disabled if it is empty.
+	JobPeriod string `yaml:"jobPeriod"`
+	// GracePeriod is how long an orphaned object is kept, e.g. "24h", since it
+	// may belong to a run that is still in progress. It defaults to 24 hours.
+	GracePeriod string `yaml:"gracePeriod"`
+	// Delete deletes the orphaned objects. Otherwise, the job only reports them and their size.
+	Delete bool `yaml:"delete"`
+}
+
+// StorageCacheConfig configures the local disk cache of objects read from remote storage.
+type StorageCacheConfig struct {
+	// MaxSizeMB is the maximum size of the cache in megabytes. The cache is disabled if it is 0.
+	MaxSizeMB int64 `yaml:"maxSizeMB"`
+	// Directory holds the cached objects. It defaults to the storage_cache directory under AqPath.
+	Directory string `yaml:"directory"`
+}
+
+// RunQueueConfig configures the workers that launch the queued workflow runs.
+type RunQueueConfig struct {
+	// NumWorkers is the maximum number of runs that are launched at the same time. It defaults to 16.
+	NumWorkers int `yaml:"numWorkers"`
+	// LeaseDuration is how long a worker holds a run without renewing its lease, e.g. "1m".
+	// A run that was claimed but not launched when its lease expires is requeued.
+	// It defaults to 1 minute.
+	LeaseDuration string `yaml:"leaseDuration"`
+}
+
+// SchedulerConfig configures the election of the server replica that fires the cron jobs,
+// when several replicas share the same database.
+type SchedulerConfig struct {
+	// ReplicaID identifies this replica. It must be unique among the replicas, and should not
+	// change when the replica restarts. It defaults to the hostname.
+	ReplicaID string `yaml:"replicaId"`
+	// LeaseDuration is how long the leader holds the scheduler lease without renewing it, e.g. "30s".
+	// Another replica takes over once the lease expires. It defaults to 30 seconds.
+	LeaseDuration string `yaml:"leaseDuration"`
+}
+
+// VaultType is where the secrets of resources are stored.
+type VaultType string
+
+const (
+	// StorageVaultType encrypts the secrets with EncryptionKey and stores them in the storage layer.
+	StorageVaultType VaultType = "storage"
+	// HashiCorpVaultType stores the secrets in a KV v2 secrets engine of HashiCorp Vault.
+	HashiCorpVaultType VaultType = "hashicorp"
+)
+
+// VaultConfig configures where the secrets of resources are stored.
+type VaultConfig struct {
+	// Type defaults to StorageVaultType if it is empty.
+	Type      VaultType             `yaml:"type"`
+	HashiCorp *HashiCorpVaultConfig `yaml:"hashicorp"`
+}
+
+type HashiCorpAuthMethod string
+
+const (
+	HashiCorpTokenAuth   HashiCorpAuthMethod = "token"
+	HashiCorpAppRoleAuth HashiCorpAuthMethod = "approle"
+)
+
+// HashiCorpVaultConfig configures the HashiCorp Vault server and the KV v2 mount that store the secrets.
+type HashiCorpVaultConfig struct {
+	// Address of the Vault server, e.g. "https://vault.example.com:8200".
+	Address string `yaml:"address"`
+	// Namespace is only supported by Vault Enterprise.
+	Namespace string `yaml:"namespace"`
+	// Mount is the path of the KV v2 secrets engine. It defaults to "secret".
+	Mount string `yaml:"mount"`
+	// PathPrefix is prepended to the name of every secret, e.g. "aqueduct/".
+	PathPrefix string `yaml:"pathPrefix"`
+	// CACertPath is the PEM-encoded CA certificate of the Vault server, if it is not signed by a trusted CA.
+	CACertPath string `yaml:"caCertPath"`
+
+	// AuthMethod defaults to HashiCorpTokenAuth if it is empty.
+	AuthMethod HashiCorpAuthMethod `yaml:"authMethod"`
+	// Token is used by the token auth method. It defaults to the VAULT_TOKEN environment variable.
+	Token string `yaml:"token"`
+	// AppRoleMount is the path of the AppRole auth method. It defaults to "approle".
+	AppRoleMount string `yaml:"appRoleMount"`
+	RoleID       string `yaml:"roleId"`
+	// SecretID defaults to the VAULT_SECRET_ID environment variable.
+	SecretID string `yaml:"secretId"`
 }
 
 // AqueductPath is the filepath to the Aqueduct installation.
@@ -35,6 +133,15 @@ func EncryptionKey() string {
 	return globalConfig.EncryptionKey
 }
 
+// StorageMasterKey wraps the data keys of the encrypted storage. It never leaves the server.
+// It defaults to EncryptionKey, which was used before the key could be configured separately.
+func StorageMasterKey() string {
+	if globalConfig.StorageMasterKey != "" {
+		return globalConfig.StorageMasterKey
+	}
+	return globalConfig.EncryptionKey
+}
+
 // RetentionJobPeriod defines how long to wait before garbage collecting workflow runs.
 func RetentionJobPeriod() string {
 	return globalConfig.RetentionJobPeriod
@@ -50,16 +157,62 @@ func Storage() shared.StorageConfig {
 	return *globalConfig.StorageConfig
 }
 
+// Database returns the metadata database config.
+func Database() database.DatabaseConfig {
+	return *globalConfig.DatabaseConfig
+}
+
 func VersionTag() string {
 	return globalConfig.VersionTag
 }
 
+// MaxConcurrentOperators is the maximum number of operators executed at the same time
+// across all workflow runs on this server. There is no limit if it is 0.
+func MaxConcurrentOperators() int {
+	return globalConfig.MaxConcurrentOperators
+}
+
+// StorageGC returns the config of the storage garbage collection job.
+func StorageGC() StorageGCConfig {
+	return globalConfig.StorageGC
+}
+
+// StorageCache returns the config of the local storage cache.
+func StorageCache() StorageCacheConfig {
+	cacheConfig := globalConfig.StorageCache
+	if cacheConfig.Directory == "" {
+		cacheConfig.Directory = path.Join(globalConfig.AqPath, "storage_cache")
+	}
+	return cacheConfig
+}
+
+// RunQueue returns the config of the run queue workers.
+func RunQueue() RunQueueConfig {
+	return globalConfig.RunQueue
+}
+
+// Scheduler returns the config of the scheduler leader election.
+func Scheduler() SchedulerConfig {
+	return globalConfig.Scheduler
+}
+
+// Vault returns the config of the vault that stores the secrets of resources.
+func Vault() VaultConfig {
+	return globalConfig.Vault
+}
+
 // UpdateStorage updates the storage layer config.
 func UpdateStorage(newStorage *shared.StorageConfig) error {
 	globalConfig.StorageConfig = newStorage
 	return dumpConfig()
 }
 
+// UpdateStorageMasterKey updates the master key of the encrypted storage.
+func UpdateStorageMasterKey(masterKey string) error {
+	globalConfig.StorageMasterKey = masterKey
+	return dumpConfig()
+}
+
 // Init initializes the global server configuration. It must be invoked before
 // any config field is accessed, otherwise the value will be incorrect.
 func Init(path string) error {
@@ -96,6 +249,16 @@ func loadConfig() error {
 		}
 	}
 
+	if config.DatabaseConfig == nil {
+		// DatabaseConfig was not provided so the SQLite database under AqPath is used by default
+		config.DatabaseConfig = &database.DatabaseConfig{
+			Type: database.SqliteType,
+			Sqlite: &database.SqliteConfig{
+				File: path.Join(config.AqPath, database.SqliteDatabasePath),
+			},
+		}
+	}
+
 	globalConfig = &config
 
 	return nil
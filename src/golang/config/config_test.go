@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
@@ -24,6 +25,11 @@ var testConfig *serverConfiguration = &serverConfiguration{
 		Type:       shared.FileStorageType,
 		FileConfig: &shared.FileConfig{},
 	},
+	DatabaseConfig: &database.DatabaseConfig{
+		Type:   database.SqliteType,
+		Sqlite: &database.SqliteConfig{File: "/home/user/aqueduct/db/aqueduct.db"},
+	},
+	MaxConcurrentOperators: 8,
 }
 
 func TestInit(t *testing.T) {
@@ -54,6 +60,10 @@ func TestUpdateStorage(t *testing.T) {
 			Bucket:             "test",
 			CredentialsPath:    "/home/user/.aws",
 			CredentialsProfile: "default",
+			S3EndpointConfig: shared.S3EndpointConfig{
+				EndpointURL:    "http://localhost:9000",
+				ForcePathStyle: true,
+			},
 		},
 	}
 	err = UpdateStorage(expectedStorage)
@@ -304,15 +304,23 @@ func prepareStorageConfig(
 		return emptyStorageConf, errors.New("The StorageType must be S3 to use the Airflow engine.")
 	}
 
-	return shared.StorageConfig{
-		Type: storageConfig.Type,
-		S3Config: &shared.S3Config{
-			Region:             storageConfig.S3Config.Region,
-			Bucket:             storageConfig.S3Config.Bucket,
-			CredentialsPath:    airflowConf.S3CredentialsPath,
-			CredentialsProfile: airflowConf.S3CredentialsProfile,
-		},
-	}, nil
+	return airflowStorageConfig(storageConfig, airflowConf), nil
+}
+
+// airflowStorageConfig returns a copy of storageConfig that uses the S3 credentials of the Airflow
+// resource. The other fields, like the compression and encryption of the objects, are kept, so that
+// the server can read the objects written by the Airflow tasks.
+func airflowStorageConfig(storageConfig *shared.StorageConfig, airflowConf *config) shared.StorageConfig {
+	s3Config := *storageConfig.S3Config
+	s3Config.CredentialsPath = airflowConf.S3CredentialsPath
+	s3Config.CredentialsProfile = airflowConf.S3CredentialsProfile
+	// The Airflow workers authenticate with the credentials file, not with the keys of the server.
+	s3Config.AWSAccessKeyID = ""
+	s3Config.AWSSecretAccessKey = ""
+
+	airflowConfig := *storageConfig
+	airflowConfig.S3Config = &s3Config
+	return airflowConfig
 }
 
 // generateStoragePathPrefixes generates a storage path prefix for each ID.
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -101,3 +102,34 @@ func compareEdges(a, b map[string][]string, t *testing.T) bool {
 
 	return true
 }
+
+func TestAirflowStorageConfig(t *testing.T) {
+	storageConfig := &shared.StorageConfig{
+		Type: shared.S3StorageType,
+		S3Config: &shared.S3Config{
+			Region:             "us-east-2",
+			Bucket:             "bucket",
+			RootDir:            "aqueduct/",
+			AWSAccessKeyID:     "id",
+			AWSSecretAccessKey: "secret",
+		},
+		Compression: &shared.CompressionConfig{Algorithm: shared.GzipCompression},
+		Encryption:  &shared.EncryptionConfig{PerWorkflowKeys: true},
+	}
+
+	airflowConfig := airflowStorageConfig(storageConfig, &config{
+		S3CredentialsPath:    "/home/airflow/.aws/credentials",
+		S3CredentialsProfile: "airflow",
+	})
+
+	require.Equal(t, storageConfig.Compression, airflowConfig.Compression)
+	require.Equal(t, storageConfig.Encryption, airflowConfig.Encryption)
+	require.Equal(t, "aqueduct/", airflowConfig.S3Config.RootDir)
+	require.Equal(t, "/home/airflow/.aws/credentials", airflowConfig.S3Config.CredentialsPath)
+	require.Equal(t, "airflow", airflowConfig.S3Config.CredentialsProfile)
+	require.Empty(t, airflowConfig.S3Config.AWSAccessKeyID)
+	require.Empty(t, airflowConfig.S3Config.AWSSecretAccessKey)
+
+	// The server's storage config is not modified.
+	require.Equal(t, "id", storageConfig.S3Config.AWSAccessKeyID)
+}
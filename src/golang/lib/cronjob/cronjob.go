@@ -4,9 +4,11 @@ import (
 	"context"
 )
 
+// CronjobManager runs cron jobs. The cron string of a cron job is evaluated in its
+// IANA timezone, where an empty timezone is the same as UTC.
 type CronjobManager interface {
-	DeployCronJob(ctx context.Context, name string, period string, cronFunction func()) error
+	DeployCronJob(ctx context.Context, name string, period string, timezone string, cronFunction func()) error
 	CronJobExists(ctx context.Context, name string) bool
-	EditCronJob(ctx context.Context, name string, cronString string, cronFunction func()) error
+	EditCronJob(ctx context.Context, name string, cronString string, timezone string, cronFunction func()) error
 	DeleteCronJob(ctx context.Context, name string) error
 }
@@ -0,0 +1,168 @@
+package cronjob
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// SchedulerLeaseName is the name of the lease held by the server replica that fires the cron jobs.
+	SchedulerLeaseName = "cron_scheduler"
+
+	DefaultSchedulerLeaseDuration = 30 * time.Second
+	// MinSchedulerLeaseDuration leaves the leader enough time to renew its lease.
+	MinSchedulerLeaseDuration = 3 * time.Second
+)
+
+// LeaderElector elects one of the server replicas that share a database as the leader, which is
+// the only replica that fires cron jobs. The leader holds a lease in the database that it renews
+// every third of the lease duration. If the leader stops renewing the lease, e.g. because it crashed,
+// another replica acquires the lease once it expires.
+//
+// A new leader does not fire cron jobs until EnableFiring is called, so that it can determine the
+// runs that were missed before then without racing the cron jobs. It only fires the cron runs
+// that are scheduled after that time.
+type LeaderElector struct {
+	replicaID     string
+	leaseDuration time.Duration
+	leaseRepo     repos.SchedulerLease
+	db            database.Database
+
+	mu sync.RWMutex
+	// leaderUntil is when this replica stops acting as the leader unless it renews the lease.
+	// It is a third of the lease duration before the lease expires, so that the replica stops
+	// firing cron jobs before another replica can acquire the lease.
+	leaderUntil time.Time
+	// firingSince is when this replica started firing cron jobs as the leader.
+	// It is zero until EnableFiring is called after the replica is elected.
+	firingSince time.Time
+
+	// now returns the current time. It is only replaced by tests.
+	now func() time.Time
+}
+
+// NewLeaderElector returns a LeaderElector for the replica `replicaID`, which must be unique
+// among the replicas and should not change when the replica restarts.
+func NewLeaderElector(
+	replicaID string,
+	leaseDuration time.Duration,
+	leaseRepo repos.SchedulerLease,
+	db database.Database,
+) *LeaderElector {
+	return &LeaderElector{
+		replicaID:     replicaID,
+		leaseDuration: leaseDuration,
+		leaseRepo:     leaseRepo,
+		db:            db,
+		now:           time.Now,
+	}
+}
+
+func (e *LeaderElector) ReplicaID() string {
+	return e.replicaID
+}
+
+// IsLeader returns whether this replica is the leader.
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.isLeader()
+}
+
+// ShouldFire returns whether this replica fires the cron run scheduled at scheduledAt.
+// The runs scheduled before firing was enabled are caught up instead.
+func (e *LeaderElector) ShouldFire(scheduledAt time.Time) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.isLeader() && !e.firingSince.IsZero() && scheduledAt.After(e.firingSince)
+}
+
+// EnableFiring makes this replica fire the cron runs scheduled after now, as long as it is the
+// leader. It returns the time since which the replica fires cron runs, which is not moved if
+// firing was already enabled.
+func (e *LeaderElector) EnableFiring() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.firingSince.IsZero() {
+		e.firingSince = e.now()
+	}
+	return e.firingSince
+}
+
+// TryAcquire acquires the lease, or renews it if this replica already holds it.
+// It returns whether this replica is the leader.
+func (e *LeaderElector) TryAcquire(ctx context.Context) (bool, error) {
+	// The time is taken before the lease is written, so that a slow write
+	// cannot make the replica act as the leader for longer than its lease.
+	// It is only compared to the local clock, since the lease expires by the clock of the database.
+	acquiredAt := e.now()
+	_, err := e.leaseRepo.Acquire(ctx, SchedulerLeaseName, e.replicaID, e.leaseDuration, e.db)
+	if err != nil {
+		if errors.Is(err, database.ErrNoRows()) {
+			// Another replica holds the lease.
+			e.setLeaderUntil(time.Time{})
+			return false, nil
+		}
+
+		return e.IsLeader(), errors.Wrap(err, "Unable to acquire the scheduler lease.")
+	}
+
+	e.setLeaderUntil(acquiredAt.Add(e.leaseDuration * 2 / 3))
+	return true, nil
+}
+
+// Release gives up the lease if this replica holds it, so that another replica
+// becomes the leader without waiting for the lease to expire.
+func (e *LeaderElector) Release(ctx context.Context) error {
+	e.setLeaderUntil(time.Time{})
+	return e.leaseRepo.Release(ctx, SchedulerLeaseName, e.replicaID, e.db)
+}
+
+// Run tries to acquire or renew the lease every third of the lease duration until ctx is done.
+// onElected is called in a new goroutine whenever this replica becomes the leader.
+// It must call EnableFiring once it has determined the runs that were missed.
+func (e *LeaderElector) Run(ctx context.Context, onElected func(ctx context.Context)) {
+	wasLeader := e.IsLeader()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.leaseDuration / 3):
+		}
+
+		isLeader, err := e.TryAcquire(ctx)
+		if err != nil {
+			log.Errorf("Replica %s is unable to renew the scheduler lease: %v", e.replicaID, err)
+		}
+
+		if isLeader && !wasLeader {
+			log.Infof("Replica %s is now the scheduler leader.", e.replicaID)
+			go onElected(ctx)
+		} else if !isLeader && wasLeader {
+			log.Warnf("Replica %s is no longer the scheduler leader.", e.replicaID)
+		}
+		wasLeader = isLeader
+	}
+}
+
+func (e *LeaderElector) setLeaderUntil(leaderUntil time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// A replica that is elected again must catch up the runs missed since it stopped being the leader.
+	if !e.isLeader() {
+		e.firingSince = time.Time{}
+	}
+	e.leaderUntil = leaderUntil
+}
+
+func (e *LeaderElector) isLeader() bool {
+	return e.now().Before(e.leaderUntil)
+}
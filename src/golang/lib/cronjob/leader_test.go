@@ -0,0 +1,159 @@
+package cronjob
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSchedulerLeaseRepo implements repos.SchedulerLease in memory.
+type fakeSchedulerLeaseRepo struct {
+	mu     sync.Mutex
+	leases map[string]models.SchedulerLease
+	// now returns the current time of the database.
+	now func() time.Time
+}
+
+func (f *fakeSchedulerLeaseRepo) Get(ctx context.Context, name string, DB database.Database) (*models.SchedulerLease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lease, ok := f.leases[name]
+	if !ok {
+		return nil, database.ErrNoRows()
+	}
+	return &lease, nil
+}
+
+func (f *fakeSchedulerLeaseRepo) Acquire(
+	ctx context.Context,
+	name string,
+	holder string,
+	leaseDuration time.Duration,
+	DB database.Database,
+) (*models.SchedulerLease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	lease, ok := f.leases[name]
+	if ok && lease.Holder != holder && !lease.ExpiresAt.Before(now) {
+		return nil, database.ErrNoRows()
+	}
+
+	lease = models.SchedulerLease{Name: name, Holder: holder, ExpiresAt: now.Add(leaseDuration)}
+	f.leases[name] = lease
+	return &lease, nil
+}
+
+func (f *fakeSchedulerLeaseRepo) Release(ctx context.Context, name string, holder string, DB database.Database) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if lease, ok := f.leases[name]; ok && lease.Holder == holder {
+		delete(f.leases, name)
+	}
+	return nil
+}
+
+func TestLeaderElector(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, time.November, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	repo := &fakeSchedulerLeaseRepo{leases: map[string]models.SchedulerLease{}, now: clock}
+
+	first := NewLeaderElector("replica-1", 30*time.Second, repo, nil /* db */)
+	first.now = clock
+	second := NewLeaderElector("replica-2", 30*time.Second, repo, nil /* db */)
+	second.now = clock
+
+	isLeader, err := first.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.True(t, isLeader)
+	require.True(t, first.IsLeader())
+
+	isLeader, err = second.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.False(t, isLeader)
+	require.False(t, second.IsLeader())
+
+	// The lease expires by the clock of the database, so a replica with a clock that is
+	// ahead cannot take over the lease early.
+	second.now = func() time.Time { return now.Add(time.Hour) }
+	isLeader, err = second.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.False(t, isLeader)
+	second.now = clock
+
+	// The leader stops acting as the leader before its lease expires, unless it renews the lease.
+	now = now.Add(21 * time.Second)
+	require.False(t, first.IsLeader())
+
+	isLeader, err = first.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.True(t, isLeader)
+
+	// Another replica takes over once the lease expires.
+	now = now.Add(31 * time.Second)
+	isLeader, err = second.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.True(t, isLeader)
+
+	isLeader, err = first.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.False(t, isLeader)
+	require.False(t, first.IsLeader())
+
+	// A released lease is acquired right away.
+	require.Nil(t, second.Release(ctx))
+	require.False(t, second.IsLeader())
+
+	isLeader, err = first.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.True(t, isLeader)
+}
+
+func TestLeaderElectorShouldFire(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, time.November, 1, 0, 0, 30, 0, time.UTC)
+	clock := func() time.Time { return now }
+	repo := &fakeSchedulerLeaseRepo{leases: map[string]models.SchedulerLease{}, now: clock}
+
+	elector := NewLeaderElector("replica-1", 30*time.Second, repo, nil /* db */)
+	elector.now = clock
+
+	isLeader, err := elector.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.True(t, isLeader)
+
+	// A new leader does not fire cron jobs until it has determined the missed runs.
+	require.False(t, elector.ShouldFire(now.Add(30*time.Second)))
+
+	firingSince := elector.EnableFiring()
+	require.Equal(t, now, firingSince)
+
+	// The runs scheduled until firing was enabled are caught up instead.
+	require.False(t, elector.ShouldFire(now.Truncate(time.Minute)))
+	require.False(t, elector.ShouldFire(now))
+	require.True(t, elector.ShouldFire(now.Add(30*time.Second)))
+
+	// Renewing the lease does not move the time since which runs are fired.
+	now = now.Add(10 * time.Second)
+	_, err = elector.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.Equal(t, firingSince, elector.EnableFiring())
+
+	// A replica that is elected again does not fire cron jobs until it catches up again.
+	now = now.Add(time.Minute)
+	require.False(t, elector.ShouldFire(now.Add(30*time.Second)))
+	isLeader, err = elector.TryAcquire(ctx)
+	require.Nil(t, err)
+	require.True(t, isLeader)
+	require.False(t, elector.ShouldFire(now.Add(30*time.Second)))
+	require.Equal(t, now, elector.EnableFiring())
+}
@@ -12,13 +12,18 @@ import (
 type cronMetadata struct {
 	// If the cronJob is nil, it means the corresponding workflow has been paused.
 	cronJob *gocron.Job
+	// scheduler is the scheduler of the timezone that cronJob runs in.
+	scheduler *gocron.Scheduler
 }
 
 // Please use thread-safe read / insert / remove APIs to maintain maps.
 // These APIs are wrapped with proper locks to support concurrency.
 // Never try to access map using go's native APIs.
 type ProcessCronjobManager struct {
+	// cronScheduler runs the cron jobs in UTC.
 	cronScheduler *gocron.Scheduler
+	// A mapping from any other timezone to the scheduler that runs the cron jobs in that timezone.
+	zonedSchedulers map[string]*gocron.Scheduler
 	// A mapping from cron job name to cron job object pointer.
 	cronMapping map[string]*cronMetadata
 	cronMutex   *sync.RWMutex
@@ -29,9 +34,10 @@ func NewProcessCronjobManager() *ProcessCronjobManager {
 	cronScheduler.StartAsync()
 
 	return &ProcessCronjobManager{
-		cronScheduler: cronScheduler,
-		cronMapping:   map[string]*cronMetadata{},
-		cronMutex:     &sync.RWMutex{},
+		cronScheduler:   cronScheduler,
+		zonedSchedulers: map[string]*gocron.Scheduler{},
+		cronMapping:     map[string]*cronMetadata{},
+		cronMutex:       &sync.RWMutex{},
 	}
 }
 
@@ -54,24 +60,56 @@ func (j *ProcessCronjobManager) deleteCronMap(key string) {
 	j.cronMutex.Unlock()
 }
 
+// getScheduler returns the scheduler that runs cron jobs in `timezone`,
+// and starts it if it does not exist yet.
+func (j *ProcessCronjobManager) getScheduler(timezone string) (*gocron.Scheduler, error) {
+	if timezone == "" || timezone == time.UTC.String() {
+		return j.cronScheduler, nil
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid timezone %s", timezone)
+	}
+
+	j.cronMutex.Lock()
+	defer j.cronMutex.Unlock()
+
+	scheduler, ok := j.zonedSchedulers[location.String()]
+	if !ok {
+		scheduler = gocron.NewScheduler(location)
+		scheduler.StartAsync()
+		j.zonedSchedulers[location.String()] = scheduler
+	}
+
+	return scheduler, nil
+}
+
 func (j *ProcessCronjobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
 	period string,
+	timezone string,
 	cronFunction func(),
 ) error {
 	if _, ok := j.getCronMap(name); ok {
 		return errors.Newf("Cron job with name %s already exists", name)
 	}
 
+	scheduler, err := j.getScheduler(timezone)
+	if err != nil {
+		return err
+	}
+
 	cron := &cronMetadata{
-		cronJob: nil,
+		cronJob:   nil,
+		scheduler: scheduler,
 	}
 
 	j.setCronMap(name, cron)
 
 	if period != "" {
-		cronJob, err := j.cronScheduler.Cron(period).Do(cronFunction)
+		cronJob, err := scheduler.Cron(period).Do(cronFunction)
 		if err != nil {
 			return err
 		}
@@ -87,43 +125,66 @@ func (j *ProcessCronjobManager) CronJobExists(ctx context.Context, name string)
 	return ok
 }
 
-func (j *ProcessCronjobManager) EditCronJob(ctx context.Context, name string, cronString string, cronFunction func()) error {
+func (j *ProcessCronjobManager) EditCronJob(
+	ctx context.Context,
+	name string,
+	cronString string,
+	timezone string,
+	cronFunction func(),
+) error {
 	cronMetadata, ok := j.getCronMap(name)
 	if !ok {
 		return errors.New("Cron job not found")
-	} else {
-		if cronMetadata.cronJob == nil {
-			// This means the current cron job is already paused.
-			if cronString == "" {
-				return nil
-			}
+	}
+
+	scheduler, err := j.getScheduler(timezone)
+	if err != nil {
+		return err
+	}
+
+	if cronMetadata.cronJob == nil {
+		// This means the current cron job is already paused.
+		cronMetadata.scheduler = scheduler
+		if cronString == "" {
+			return nil
+		}
 
-			cronJob, err := j.cronScheduler.Cron(cronString).Do(cronFunction)
+		cronJob, err := scheduler.Cron(cronString).Do(cronFunction)
+		if err != nil {
+			return err
+		}
+
+		cronMetadata.cronJob = cronJob
+	} else {
+		if cronString == "" {
+			// This means we want to pause the cron job.
+			cronMetadata.scheduler.RemoveByReference(cronMetadata.cronJob)
+			cronMetadata.cronJob = nil
+			cronMetadata.scheduler = scheduler
+		} else if cronMetadata.scheduler != scheduler {
+			// The timezone has changed, so the cron job moves to the scheduler of the new timezone.
+			cronJob, err := scheduler.Cron(cronString).Do(cronFunction)
 			if err != nil {
 				return err
 			}
 
+			cronMetadata.scheduler.RemoveByReference(cronMetadata.cronJob)
 			cronMetadata.cronJob = cronJob
+			cronMetadata.scheduler = scheduler
 		} else {
-			if cronString == "" {
-				// This means we want to pause the cron job.
-				j.cronScheduler.RemoveByReference(cronMetadata.cronJob)
-				cronMetadata.cronJob = nil
-			} else {
-				_, err := j.cronScheduler.Job(cronMetadata.cronJob).Cron(cronString).Update()
-				if err != nil {
-					return err
-				}
+			_, err := scheduler.Job(cronMetadata.cronJob).Cron(cronString).Update()
+			if err != nil {
+				return err
 			}
 		}
-		return nil
 	}
+	return nil
 }
 
 func (j *ProcessCronjobManager) DeleteCronJob(ctx context.Context, name string) error {
 	cronMetadata, ok := j.getCronMap(name)
 	if ok {
-		j.cronScheduler.RemoveByReference(cronMetadata.cronJob)
+		cronMetadata.scheduler.RemoveByReference(cronMetadata.cronJob)
 		j.deleteCronMap(name)
 	}
 
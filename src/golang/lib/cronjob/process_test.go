@@ -21,7 +21,7 @@ func TestDeployCronJob(t *testing.T) {
 	cronString := "0 * * * *"
 
 	// Deploy an unpaused workflow.
-	err := cronjobManager.DeployCronJob(ctx, workflowName, cronString, generateDummyFunction())
+	err := cronjobManager.DeployCronJob(ctx, workflowName, cronString, "", generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 1, len(cronjobManager.cronMapping))
 	require.NotEqual(t, (*gocron.Job)(nil), cronjobManager.cronMapping[workflowName].cronJob)
@@ -30,7 +30,7 @@ func TestDeployCronJob(t *testing.T) {
 	// Deploy a paused workflow.
 	pausedWorkflowName := "paused_workflow"
 	emptyCronString := ""
-	err = cronjobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, generateDummyFunction())
+	err = cronjobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, "", generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 2, len(cronjobManager.cronMapping))
 	require.Equal(t, (*gocron.Job)(nil), cronjobManager.cronMapping[pausedWorkflowName].cronJob)
@@ -48,25 +48,25 @@ func TestEditCronJob(t *testing.T) {
 	newCronString := "1 * * * *"
 	emptyCronString := ""
 
-	cronjobManager.DeployCronJob(ctx, workflowName, cronString, generateDummyFunction())
-	cronjobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, generateDummyFunction())
+	cronjobManager.DeployCronJob(ctx, workflowName, cronString, "", generateDummyFunction())
+	cronjobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, "", generateDummyFunction())
 
 	// Edit an unpaused workflow to another schedule.
-	err := cronjobManager.EditCronJob(ctx, workflowName, newCronString, generateDummyFunction())
+	err := cronjobManager.EditCronJob(ctx, workflowName, newCronString, "", generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 2, len(cronjobManager.cronMapping))
 	require.NotEqual(t, (*gocron.Job)(nil), cronjobManager.cronMapping[workflowName].cronJob)
 	require.Equal(t, 1, len(cronjobManager.cronScheduler.Jobs()))
 
 	// Edit an unpaused workflow to paused.
-	err = cronjobManager.EditCronJob(ctx, workflowName, emptyCronString, generateDummyFunction())
+	err = cronjobManager.EditCronJob(ctx, workflowName, emptyCronString, "", generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 2, len(cronjobManager.cronMapping))
 	require.Equal(t, (*gocron.Job)(nil), cronjobManager.cronMapping[workflowName].cronJob)
 	require.Equal(t, 0, len(cronjobManager.cronScheduler.Jobs()))
 
 	// Edit a paused workflow to unpaused.
-	err = cronjobManager.EditCronJob(ctx, pausedWorkflowName, cronString, generateDummyFunction())
+	err = cronjobManager.EditCronJob(ctx, pausedWorkflowName, cronString, "", generateDummyFunction())
 	require.Nil(t, err)
 	require.Equal(t, 2, len(cronjobManager.cronMapping))
 	require.NotEqual(t, (*gocron.Job)(nil), cronjobManager.cronMapping[pausedWorkflowName].cronJob)
@@ -81,9 +81,43 @@ func TestDeleteCronJob(t *testing.T) {
 	workflowName := "workflow"
 	cronString := "0 * * * *"
 
-	cronjobManager.DeployCronJob(ctx, workflowName, cronString, generateDummyFunction())
+	cronjobManager.DeployCronJob(ctx, workflowName, cronString, "", generateDummyFunction())
 	err := cronjobManager.DeleteCronJob(ctx, workflowName)
 	require.Nil(t, err)
 	require.Equal(t, 0, len(cronjobManager.cronMapping))
 	require.Equal(t, 0, len(cronjobManager.cronScheduler.Jobs()))
 }
+
+func TestCronJobTimezone(t *testing.T) {
+	cronjobManager := NewProcessCronjobManager()
+
+	ctx := context.Background()
+
+	workflowName := "workflow"
+	cronString := "0 9 * * *"
+	timezone := "America/New_York"
+
+	// Deploy a workflow in a timezone other than UTC.
+	err := cronjobManager.DeployCronJob(ctx, workflowName, cronString, timezone, generateDummyFunction())
+	require.Nil(t, err)
+	require.Equal(t, 0, len(cronjobManager.cronScheduler.Jobs()))
+	zonedScheduler := cronjobManager.zonedSchedulers[timezone]
+	require.NotNil(t, zonedScheduler)
+	require.Equal(t, 1, len(zonedScheduler.Jobs()))
+
+	// The next run is at 9am in the timezone, regardless of daylight saving time.
+	nextRun := cronjobManager.cronMapping[workflowName].cronJob.NextRun()
+	require.Equal(t, timezone, nextRun.Location().String())
+	require.Equal(t, 9, nextRun.Hour())
+
+	// Edit the workflow to run in UTC.
+	err = cronjobManager.EditCronJob(ctx, workflowName, cronString, "", generateDummyFunction())
+	require.Nil(t, err)
+	require.Equal(t, 1, len(cronjobManager.cronScheduler.Jobs()))
+	require.Equal(t, 0, len(zonedScheduler.Jobs()))
+
+	// An invalid timezone is rejected.
+	err = cronjobManager.DeployCronJob(ctx, "other_workflow", cronString, "Not/A_Timezone", generateDummyFunction())
+	require.NotNil(t, err)
+	require.False(t, cronjobManager.CronJobExists(ctx, "other_workflow"))
+}
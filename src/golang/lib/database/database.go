@@ -74,6 +74,10 @@ func NewDatabase(conf *DatabaseConfig) (Database, error) {
 			return nil, errors.New("Invalid database config, expected `Postgres` field to be set.")
 		}
 
+		if conf.Postgres.Port != "" {
+			return NewPostgresDatabaseWithPort(conf.Postgres)
+		}
+
 		return NewPostgresDatabase(conf.Postgres)
 	}
 
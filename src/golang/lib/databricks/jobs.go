@@ -171,6 +171,21 @@ func GetRun(
 	return getRunResp, nil
 }
 
+func CancelRun(
+	ctx context.Context,
+	databricksClient *databricks_sdk.WorkspaceClient,
+	runID int64,
+) error {
+	cancelRunReq := &jobs.CancelRun{
+		RunId: runID,
+	}
+	err := databricksClient.Jobs.CancelRun(ctx, *cancelRunReq)
+	if err != nil {
+		return errors.Wrap(err, "Unable to cancel run in databricks.")
+	}
+	return nil
+}
+
 func GetTaskRunIDs(
 	ctx context.Context,
 	databricksClient *databricks_sdk.WorkspaceClient,
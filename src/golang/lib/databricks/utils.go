@@ -10,6 +10,9 @@ import (
 
 func AddEntrypointFilesToStorage(ctx context.Context) error {
 	config := config.Storage()
+	// Databricks reads the scripts directly from storage, so they must not be compressed or encrypted.
+	config.Compression = nil
+	config.Encryption = nil
 	storageManager := storage.NewStorage(&config)
 
 	filesToWrite := map[string]string{
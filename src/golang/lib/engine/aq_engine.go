@@ -3,8 +3,8 @@ package engine
 import (
 	"context"
 	"fmt"
-	"path"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/aqueducthq/aqueduct/config"
@@ -12,6 +12,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/cronjob"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/dynamic"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
 	exec_env "github.com/aqueducthq/aqueduct/lib/execution_environment"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
@@ -21,11 +22,13 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
 	"github.com/aqueducthq/aqueduct/lib/repos"
 	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow"
 	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
 	"github.com/aqueducthq/aqueduct/lib/workflow/preview_cache"
 	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/aqueducthq/aqueduct/lib/workflow/webhook"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -55,6 +58,7 @@ type Repos struct {
 	NotificationRepo         repos.Notification
 	OperatorRepo             repos.Operator
 	OperatorResultRepo       repos.OperatorResult
+	RunRequestRepo           repos.RunRequest
 	WatcherRepo              repos.Watcher
 	WorkflowRepo             repos.Workflow
 }
@@ -66,9 +70,16 @@ type aqEngine struct {
 	CronjobManager cronjob.CronjobManager
 	AqPath         string
 
+	// Only the leader fires cron jobs. If it is nil, this engine always fires them.
+	SchedulerLeader SchedulerLeader
+
 	// Only used for previews.
 	PreviewCacheManager preview_cache.CacheManager
 
+	// The schedules that the cron jobs were last synced to by SyncScheduledWorkflows.
+	scheduledWorkflows     map[uuid.UUID]shared.Schedule
+	scheduledWorkflowsLock sync.Mutex
+
 	*Repos
 }
 
@@ -98,48 +109,38 @@ func NewAqEngine(
 	database database.Database,
 	githubManager github.Manager,
 	previewCacheManager preview_cache.CacheManager,
+	cronjobManager cronjob.CronjobManager,
+	schedulerLeader SchedulerLeader,
 	aqPath string,
 	displayIP string,
 	repos *Repos,
 ) (*aqEngine, error) {
-	cronjobManager := cronjob.NewProcessCronjobManager()
-
 	return &aqEngine{
 		DisplayIP:           displayIP,
 		Database:            database,
 		GithubManager:       githubManager,
 		PreviewCacheManager: previewCacheManager,
 		CronjobManager:      cronjobManager,
+		SchedulerLeader:     schedulerLeader,
 		AqPath:              aqPath,
+		scheduledWorkflows:  map[uuid.UUID]shared.Schedule{},
 		Repos:               repos,
 	}, nil
 }
 
-// TODO ENG-1444: Remove jobSpec/ creation once we get rid of executor
 func (eng *aqEngine) ScheduleWorkflow(
 	ctx context.Context,
 	workflowId uuid.UUID,
 	name string,
 	period string,
+	timezone string,
 ) error {
-	jobSpec := job.NewWorkflowSpec(
-		name,
-		workflowId.String(),
-		eng.Database.Config(),
-		&job.ProcessConfig{
-			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
-			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
-		},
-		eng.GithubManager.Config(),
-		eng.AqPath,
-		eng.DisplayIP,
-		nil,
-	)
 	err := eng.CronjobManager.DeployCronJob(
 		ctx,
 		name,
 		period,
-		eng.generateCronFunction(name, jobSpec),
+		timezone,
+		eng.generateCronFunction(name, workflowId),
 	)
 	if err != nil {
 		return errors.Wrap(err, "Unable to schedule workflow.")
@@ -147,12 +148,87 @@ func (eng *aqEngine) ScheduleWorkflow(
 	return nil
 }
 
+func (eng *aqEngine) SyncScheduledWorkflows(ctx context.Context) error {
+	workflows, err := eng.WorkflowRepo.List(ctx, eng.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list workflows.")
+	}
+
+	eng.scheduledWorkflowsLock.Lock()
+	defer eng.scheduledWorkflowsLock.Unlock()
+
+	exists := make(map[uuid.UUID]bool, len(workflows))
+	for _, wf := range workflows {
+		exists[wf.ID] = true
+
+		prevSchedule, ok := eng.scheduledWorkflows[wf.ID]
+		if ok &&
+			prevSchedule.CronSchedule == wf.Schedule.CronSchedule &&
+			prevSchedule.Timezone == wf.Schedule.Timezone &&
+			prevSchedule.Paused == wf.Schedule.Paused {
+			continue
+		}
+
+		if err := eng.syncWorkflowCronJob(ctx, wf.ID, &wf.Schedule); err != nil {
+			return errors.Wrapf(err, "Unable to sync the cron job of workflow %v.", wf.ID)
+		}
+		eng.scheduledWorkflows[wf.ID] = wf.Schedule
+	}
+
+	for workflowID := range eng.scheduledWorkflows {
+		if exists[workflowID] {
+			continue
+		}
+
+		if err := eng.CronjobManager.DeleteCronJob(ctx, shared_utils.AppendPrefix(workflowID.String())); err != nil {
+			return errors.Wrapf(err, "Unable to delete the cron job of workflow %v.", workflowID)
+		}
+		delete(eng.scheduledWorkflows, workflowID)
+	}
+
+	return nil
+}
+
+// syncWorkflowCronJob deploys or edits the cron job of the workflow so that it runs on `schedule`.
+func (eng *aqEngine) syncWorkflowCronJob(ctx context.Context, workflowID uuid.UUID, schedule *shared.Schedule) error {
+	name := shared_utils.AppendPrefix(workflowID.String())
+	cronString := string(schedule.CronSchedule)
+	if schedule.Paused {
+		// A cron job with an empty cron string is paused.
+		cronString = ""
+	}
+
+	if !eng.CronjobManager.CronJobExists(ctx, name) {
+		if schedule.CronSchedule == "" {
+			// The workflow is triggered manually.
+			return nil
+		}
+
+		return eng.CronjobManager.DeployCronJob(
+			ctx,
+			name,
+			cronString,
+			schedule.Timezone,
+			eng.generateCronFunction(name, workflowID),
+		)
+	}
+
+	return eng.CronjobManager.EditCronJob(
+		ctx,
+		name,
+		cronString,
+		schedule.Timezone,
+		eng.generateCronFunction(name, workflowID),
+	)
+}
+
 func (eng *aqEngine) ExecuteWorkflow(
 	ctx context.Context,
 	workflowID uuid.UUID,
 	timeConfig *AqueductTimeConfig,
 	parameters map[string]param.Param,
-) (_ shared.ExecutionStatus, err error) {
+	runRequestID uuid.UUID,
+) (shared.ExecutionStatus, error) {
 	dbDAG, err := workflow_utils.ReadLatestDAGFromDatabase(
 		ctx,
 		workflowID,
@@ -167,20 +243,72 @@ func (eng *aqEngine) ExecuteWorkflow(
 		return shared.FailedExecutionStatus, errors.Wrap(err, "Error reading latest workflowDag.")
 	}
 
-	pendingAt := time.Now()
-	execState := &shared.ExecutionState{
-		Status: shared.PendingExecutionStatus,
-		Timestamps: &shared.ExecutionTimestamps{
-			PendingAt: &pendingAt,
-		},
+	return eng.executeDAG(ctx, dbDAG, timeConfig, parameters, uuid.Nil /* resumedDAGResultID */, runRequestID)
+}
+
+func (eng *aqEngine) ResumeWorkflow(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	dagResultID uuid.UUID,
+	timeConfig *AqueductTimeConfig,
+	runRequestID uuid.UUID,
+) (shared.ExecutionStatus, error) {
+	dbDAGResult, err := eng.DAGResultRepo.Get(ctx, dagResultID, eng.Database)
+	if err != nil {
+		return shared.FailedExecutionStatus, errors.Wrap(err, "Error reading the workflow run to resume.")
+	}
+
+	if dbDAGResult.Status != shared.FailedExecutionStatus && dbDAGResult.Status != shared.CanceledExecutionStatus {
+		return shared.FailedExecutionStatus, errors.Newf(
+			"Workflow run %v cannot be resumed, since it has status %s.",
+			dagResultID,
+			dbDAGResult.Status,
+		)
 	}
 
-	dagResult, err := eng.DAGResultRepo.Create(
+	// The resumed run executes the same DAG as the failed run, since results can only be
+	// reused if the operators that produced them have not changed.
+	dbDAG, err := workflow_utils.ReadDAGFromDatabase(
 		ctx,
-		dbDAG.ID,
-		execState,
+		dbDAGResult.DagID,
+		eng.WorkflowRepo,
+		eng.DAGRepo,
+		eng.OperatorRepo,
+		eng.ArtifactRepo,
+		eng.DAGEdgeRepo,
 		eng.Database,
 	)
+	if err != nil {
+		return shared.FailedExecutionStatus, errors.Wrap(err, "Error reading workflowDag of the workflow run.")
+	}
+
+	if dbDAG.WorkflowID != workflowID {
+		return shared.FailedExecutionStatus, errors.Newf("Workflow run %v does not belong to workflow %v.", dagResultID, workflowID)
+	}
+
+	return eng.executeDAG(ctx, dbDAG, timeConfig, nil /* parameters */, dagResultID, runRequestID)
+}
+
+// executeDAG creates a new run of `dbDAG` and executes it. If `resumedDAGResultID` is set,
+// the operators that completed in that run are not executed again, and their results are reused.
+// Otherwise, the DAG is first updated to the latest version of its Github-backed operators.
+// The new run is skipped or cancels the previous runs according to the overlap policy of the
+// workflow's schedule. Runs of workflows with a queue overlap policy are queued by the run queue.
+// If `runRequestID` is set, the new run is recorded on the run request that launched it.
+func (eng *aqEngine) executeDAG(
+	ctx context.Context,
+	dbDAG *models.DAG,
+	timeConfig *AqueductTimeConfig,
+	parameters map[string]param.Param,
+	resumedDAGResultID uuid.UUID,
+	runRequestID uuid.UUID,
+) (_ shared.ExecutionStatus, err error) {
+	execState := &shared.ExecutionState{
+		Status:     shared.PendingExecutionStatus,
+		Timestamps: &shared.ExecutionTimestamps{},
+	}
+
+	dagResult, inProgress, err := eng.createRun(ctx, dbDAG, execState, runRequestID)
 	if err != nil {
 		return shared.FailedExecutionStatus, errors.Wrap(err, "Error initializing workflowDagResult.")
 	}
@@ -215,28 +343,44 @@ func (eng *aqEngine) ExecuteWorkflow(
 			eng.NotificationRepo,
 			eng.Database,
 		); updateErr != nil {
-			log.Errorf("Unable to update DAGResult metadata for %v", dagResult.ID)
+			if aq_errors.Is(updateErr, database.ErrNoRows()) {
+				// The run was canceled while it was executing, so its status is kept.
+				log.Infof("Workflow run %v finished after it was canceled.", dagResult.ID)
+			} else {
+				log.Errorf("Unable to update DAGResult metadata for %v", dagResult.ID)
+			}
 		}
 	}()
 
-	githubClient, err := eng.GithubManager.GetClient(ctx, dbDAG.Metadata.UserID)
+	execute, err := eng.applyOverlapPolicy(ctx, dbDAG, dagResult, inProgress, execState, timeConfig)
 	if err != nil {
-		return shared.FailedExecutionStatus, errors.Wrap(err, "Error getting github client.")
+		return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to apply the overlap policy of the workflow.")
 	}
 
-	dbDAG, err = workflow_utils.UpdateWorkflowDagToLatest(
-		ctx,
-		githubClient,
-		dbDAG,
-		eng.WorkflowRepo,
-		eng.DAGRepo,
-		eng.OperatorRepo,
-		eng.DAGEdgeRepo,
-		eng.ArtifactRepo,
-		eng.Database,
-	)
-	if err != nil {
-		return shared.FailedExecutionStatus, errors.Wrap(err, "Error updating workflowDag to latest.")
+	if !execute {
+		return execState.Status, nil
+	}
+
+	if resumedDAGResultID == uuid.Nil {
+		githubClient, err := eng.GithubManager.GetClient(ctx, dbDAG.Metadata.UserID)
+		if err != nil {
+			return shared.FailedExecutionStatus, errors.Wrap(err, "Error getting github client.")
+		}
+
+		dbDAG, err = workflow_utils.UpdateWorkflowDagToLatest(
+			ctx,
+			githubClient,
+			dbDAG,
+			eng.WorkflowRepo,
+			eng.DAGRepo,
+			eng.OperatorRepo,
+			eng.DAGEdgeRepo,
+			eng.ArtifactRepo,
+			eng.Database,
+		)
+		if err != nil {
+			return shared.FailedExecutionStatus, errors.Wrap(err, "Error updating workflowDag to latest.")
+		}
 	}
 
 	// Overwrite the parameter specs for all custom parameters defined by the user.
@@ -333,6 +477,13 @@ func (eng *aqEngine) ExecuteWorkflow(
 		CompletedOps:        make(map[uuid.UUID]operator.Operator, len(dag.Operators())),
 	}
 
+	if resumedDAGResultID != uuid.Nil {
+		err = eng.reuseResults(ctx, dag, dbDAG, resumedDAGResultID)
+		if err != nil {
+			return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to reuse the results of the resumed workflow run.")
+		}
+	}
+
 	err = dag.InitOpAndArtifactResults(ctx)
 	if err != nil {
 		return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to initialize dag results.")
@@ -353,7 +504,12 @@ func (eng *aqEngine) ExecuteWorkflow(
 		vaultObject,
 		jobManager,
 	)
-	if err != nil {
+	if isRunCanceledError(err) {
+		execState.Status = shared.CanceledExecutionStatus
+		now := time.Now()
+		execState.Timestamps.FinishedAt = &now
+		return shared.CanceledExecutionStatus, nil
+	} else if err != nil {
 		execState.Status = shared.FailedExecutionStatus
 		now := time.Now()
 		execState.Timestamps.FinishedAt = &now
@@ -643,7 +799,8 @@ func (eng *aqEngine) DeleteWorkflow(
 		return errors.Wrap(err, "Unexpected error occurred while deleting workflow.")
 	}
 
-	// For each target workflow, update its schedule to have a ManualTrigger
+	// For each target workflow, remove this workflow from its sources. If it has no
+	// other source left, update its schedule to have a ManualTrigger.
 	for _, targetWorkflowID := range targetWorkflowIDs {
 		targetWorkflow, err := eng.WorkflowRepo.Get(ctx, targetWorkflowID, txn)
 		if err != nil {
@@ -651,8 +808,11 @@ func (eng *aqEngine) DeleteWorkflow(
 		}
 
 		schedule := targetWorkflow.Schedule
-		schedule.SourceID = uuid.Nil
-		schedule.Trigger = shared.ManualUpdateTrigger
+		schedule.RemoveSource(workflowID)
+		if len(schedule.Sources()) == 0 {
+			schedule.Trigger = shared.ManualUpdateTrigger
+			schedule.CascadeMode = ""
+		}
 
 		if _, err := eng.WorkflowRepo.Update(
 			ctx,
@@ -662,7 +822,7 @@ func (eng *aqEngine) DeleteWorkflow(
 			},
 			txn,
 		); err != nil {
-			return errors.Wrap(err, "Unexpected error occurred while updating target workflow trigger from cascading to manual.")
+			return errors.Wrap(err, "Unexpected error occurred while updating target workflow schedule.")
 		}
 	}
 
@@ -693,6 +853,19 @@ func (eng *aqEngine) DeleteWorkflow(
 
 	workflow_utils.CleanupStorageFiles(ctx, &storageConfig, storagePaths)
 
+	// Delete the webhook secret if it had one.
+	if workflowObj.Schedule.Trigger == shared.WebhookUpdateTrigger {
+		vaultStorageConfig := config.Storage()
+		vaultObject, err := vault.NewVault(&vaultStorageConfig, config.EncryptionKey())
+		if err != nil {
+			return errors.Wrap(err, "Unable to initialize vault.")
+		}
+
+		if err := vaultObject.Delete(ctx, webhook.SecretVaultKey(workflowID)); err != nil {
+			log.Errorf("Unable to delete webhook secret of workflow %v: %v", workflowID, err)
+		}
+	}
+
 	// Delete the cron job if it had one.
 	if workflowObj.Schedule.CronSchedule != "" {
 		cronjobName := shared_utils.AppendPrefix(workflowID.String())
@@ -748,6 +921,62 @@ func (eng *aqEngine) EditWorkflow(
 	return nil
 }
 
+func (eng *aqEngine) RecordSkippedRun(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	scheduledAt time.Time,
+	reason string,
+) error {
+	dag, err := eng.DAGRepo.GetLatestByWorkflow(ctx, workflowID, eng.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to get latest DAG of workflow.")
+	}
+
+	_, err = eng.DAGResultRepo.Create(
+		ctx,
+		dag.ID,
+		&shared.ExecutionState{
+			Status: shared.SkippedExecutionStatus,
+			Error: &shared.Error{
+				Context: reason,
+			},
+			Timestamps: &shared.ExecutionTimestamps{
+				PendingAt:  &scheduledAt,
+				FinishedAt: &scheduledAt,
+			},
+		},
+		eng.Database,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create skipped workflow run.")
+	}
+
+	log.Infof("Skipped run of workflow %v scheduled at %v: %s", workflowID, scheduledAt, reason)
+	return nil
+}
+
+// BlackoutSkipReason is the reason of a run that was skipped since it was scheduled during `window`.
+func BlackoutSkipReason(window *shared.BlackoutWindow) string {
+	if window.Name != "" {
+		return fmt.Sprintf("The scheduled run was skipped during the blackout window %s.", window.Name)
+	}
+
+	return fmt.Sprintf(
+		"The scheduled run was skipped during the blackout window from %s to %s.",
+		window.Start.Format(time.RFC3339),
+		window.End.Format(time.RFC3339),
+	)
+}
+
+// CatchUpLimitSkipReason is the reason of a missed run that was not caught up, since more runs were
+// missed than the number of runs that are caught up.
+func CatchUpLimitSkipReason() string {
+	return fmt.Sprintf(
+		"The scheduled run was missed while the server was down, and only the latest %d missed runs are caught up.",
+		workflow.MaxCatchUpRuns,
+	)
+}
+
 // TODO ENG-1444: This function is only used to trigger a Workflow.
 // Remove once executor is done.
 func (eng *aqEngine) TriggerWorkflow(
@@ -756,6 +985,29 @@ func (eng *aqEngine) TriggerWorkflow(
 	name string,
 	timeConfig *AqueductTimeConfig,
 	parameters map[string]param.Param,
+) (shared.ExecutionStatus, error) {
+	return eng.triggerWorkflow(ctx, workflowID, parameters, nil /* resumedDAGResultID */)
+}
+
+// TODO ENG-1444: This function is only used to resume a Workflow run.
+// Remove once executor is done.
+func (eng *aqEngine) TriggerWorkflowResume(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	name string,
+	dagResultID uuid.UUID,
+	timeConfig *AqueductTimeConfig,
+) (shared.ExecutionStatus, error) {
+	return eng.triggerWorkflow(ctx, workflowID, nil /* parameters */, &dagResultID)
+}
+
+// triggerWorkflow enqueues a run of the workflow in the run queue, which resumes the run
+// resumedDAGResultID if it is set. Workflows on Airflow are triggered on Airflow instead.
+func (eng *aqEngine) triggerWorkflow(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	parameters map[string]param.Param,
+	resumedDAGResultID *uuid.UUID,
 ) (shared.ExecutionStatus, error) {
 	dag, err := workflow_utils.ReadLatestDAGFromDatabase(
 		ctx,
@@ -771,13 +1023,17 @@ func (eng *aqEngine) TriggerWorkflow(
 		return shared.FailedExecutionStatus, err
 	}
 
-	storageConfig := config.Storage()
-	vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
-	if err != nil {
-		return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to initialize vault.")
-	}
-
 	if dag.EngineConfig.Type == shared.AirflowEngineType {
+		if resumedDAGResultID != nil {
+			return shared.FailedExecutionStatus, errors.New("Resuming workflow runs on Airflow is not supported.")
+		}
+
+		storageConfig := config.Storage()
+		vaultObject, err := vault.NewVault(&storageConfig, config.EncryptionKey())
+		if err != nil {
+			return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to initialize vault.")
+		}
+
 		// This is an Airflow workflow so the executor binary is not used
 		if err := airflow.TriggerWorkflow(ctx, dag, vaultObject); err != nil {
 			return shared.FailedExecutionStatus, errors.Wrap(
@@ -788,39 +1044,13 @@ func (eng *aqEngine) TriggerWorkflow(
 		return shared.SucceededExecutionStatus, nil
 	}
 
-	jobManager, err := job.NewProcessJobManager(
-		&job.ProcessConfig{
-			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
-			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
-		},
-	)
+	runRequest, err := eng.enqueueRun(ctx, workflowID, parameters, resumedDAGResultID)
 	if err != nil {
-		log.Errorf("Unable to create JobManager: %v", err)
+		return shared.FailedExecutionStatus, err
 	}
 
-	jobSpec := job.NewWorkflowSpec(
-		name,
-		workflowID.String(),
-		eng.Database.Config(),
-		&job.ProcessConfig{
-			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
-			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
-		},
-		eng.GithubManager.Config(),
-		eng.AqPath,
-		eng.DisplayIP,
-		parameters,
-	)
-
-	jobName := fmt.Sprintf("%s-%d", name, time.Now().Unix())
-	err = jobManager.Launch(context.Background(), jobName, jobSpec)
-	if err != nil {
-		log.Errorf("Error running job %s: %v", jobName, err)
-		return shared.FailedExecutionStatus, errors.Wrap(err, "Error running job.")
-	} else {
-		log.Infof("Launched job %s", jobName)
-		return shared.PendingExecutionStatus, nil
-	}
+	log.Infof("Enqueued run request %v of workflow %v", runRequest.ID, workflowID)
+	return shared.PendingExecutionStatus, nil
 }
 
 func (eng *aqEngine) cleanupWorkflow(ctx context.Context, workflowDag dag_utils.WorkflowDag) {
@@ -857,6 +1087,7 @@ func (eng *aqEngine) executeWithEngine(
 			databricksJobManager,
 			vaultObject,
 			eng.ResourceRepo,
+			eng.DAGResultRepo,
 			eng.Database,
 		)
 	default:
@@ -886,6 +1117,12 @@ func onFinishExecution(
 ) {
 	// Wait a little bit for all active operators to finish before exiting on failure.
 	waitForInProgressOperators(ctx, inProgressOps, pollInterval, cleanupTimeout)
+
+	// The user canceled the run, so there is nothing to notify them about.
+	if isRunCanceledError(curErr) {
+		return
+	}
+
 	if curErr != nil && notificationContent == nil {
 		notificationContent = &notificationContentStruct{
 			level:            shared.ErrorNotificationLevel,
@@ -940,6 +1177,10 @@ func (eng *aqEngine) execute(
 		return errors.Newf("No initial operators to schedule.")
 	}
 
+	// Operators are held pending until the limiter hands them an execution slot.
+	limiter := newOperatorLimiter(workflowDag.MaxParallelOperators(), config.MaxConcurrentOperators())
+	defer limiter.ReleaseAll()
+
 	defer func() {
 		onFinishExecution(
 			ctx,
@@ -957,6 +1198,7 @@ func (eng *aqEngine) execute(
 	}()
 
 	start := time.Now()
+	cancelChecker := newRunCancelChecker(dag.ResultID(), timeConfig.OperatorPollInterval, eng.DAGResultRepo, eng.Database)
 
 	// We defer save operations until all other computer operations are completed successfully.
 	// This flag tracks whether the save operations are scheduled for execution.
@@ -967,6 +1209,16 @@ func (eng *aqEngine) execute(
 			return errors.Newf("Reached timeout %s waiting for workflow to complete.", timeConfig.ExecTimeout)
 		}
 
+		if opExecMode == operator.Publish && cancelChecker.Canceled(ctx) {
+			log.Infof("Canceling workflow run %v", dag.ResultID())
+			err = cancelOperators(ctx, dag, inProgressOps, completedOps, opExecMode)
+			if err != nil {
+				return err
+			}
+
+			return ErrWorkflowRunCanceled
+		}
+
 		for _, op := range inProgressOps {
 			if op.Dynamic() && !op.GetDynamicProperties().Prepared() {
 				err = dynamic.PrepareCluster(
@@ -990,19 +1242,43 @@ func (eng *aqEngine) execute(
 			}
 
 			if execState.Status == shared.PendingExecutionStatus {
+				acquired, err := limiter.TryAcquire(op.ID())
+				if err != nil {
+					return err
+				}
+
+				if !acquired {
+					continue
+				}
+
 				err = op.Launch(ctx)
 				if err != nil {
 					return errors.Wrapf(err, "Unable to schedule operator %s.", op.Name())
 				}
+
+				// The operator is still waiting for its retry backoff, so it does not need the slot yet.
+				if op.ExecState().Status == shared.PendingExecutionStatus {
+					limiter.Release(op.ID())
+				}
 				continue
 			} else if execState.Status == shared.RunningExecutionStatus {
-				continue
+				if !op.EnforceTimeout(ctx) {
+					continue
+				}
+				execState = op.ExecState()
 			}
 
 			if !execState.Terminated() {
 				return errors.Newf("Internal error: the operator is expected to have terminated, but instead has status %s", execState.Status)
 			}
 
+			limiter.Release(op.ID())
+
+			// The operator is relaunched on a later poll, if its retry policy allows it.
+			if execState.HasBlockingFailure() && op.ScheduleRetry(ctx) {
+				continue
+			}
+
 			// From here on we can assume that the operator has terminated.
 			if opExecMode == operator.Publish {
 				err = op.PersistResult(ctx)
@@ -1029,7 +1305,7 @@ func (eng *aqEngine) execute(
 						continue
 					}
 
-					dagOp.Cancel()
+					dagOp.Cancel(ctx)
 					if opExecMode == operator.Publish {
 						err = dagOp.PersistResult(ctx)
 						if err != nil {
@@ -1146,27 +1422,46 @@ func (eng *aqEngine) execute(
 	return nil
 }
 
-func (eng *aqEngine) generateCronFunction(name string, jobSpec job.Spec) func() {
-	// TODO ENG-1444: Creating this process job manager just to
-	// launch the executor which calls ExecuteWorkflow().
-	// Replace with call to ExecuteWorkflow() once executor is removed.
-	jobManager, err := job.NewProcessJobManager(
-		&job.ProcessConfig{
-			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
-			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
-		},
-	)
-	if err != nil {
-		log.Errorf("Unable to create JobManager: %v", err)
-	}
-
+// generateCronFunction returns the function that the cron job `name` calls to enqueue a run of the workflow.
+func (eng *aqEngine) generateCronFunction(name string, workflowID uuid.UUID) func() {
 	return func() {
-		jobName := fmt.Sprintf("%s-%d", name, time.Now().Unix())
-		err := jobManager.Launch(context.Background(), jobName, jobSpec)
+		firedAt := time.Now()
+		// The run receives the time it was scheduled at, which is a whole minute
+		// since cron schedules do not have seconds.
+		scheduledAt := firedAt.Truncate(time.Minute)
+		if eng.SchedulerLeader != nil && !eng.SchedulerLeader.ShouldFire(scheduledAt) {
+			// Another server replica fires the cron job, or the run is caught up by this replica.
+			return
+		}
+
+		// The schedule is read when the cron job fires, since the cron function is not
+		// replaced when only the cron string of the cron job is edited.
+		workflowObj, err := eng.WorkflowRepo.Get(context.Background(), workflowID, eng.Database)
+		if aq_errors.Is(err, database.ErrNoRows()) {
+			// The workflow was deleted through another server replica.
+			if err := eng.CronjobManager.DeleteCronJob(context.Background(), name); err != nil {
+				log.Errorf("Unable to delete cron job %s of deleted workflow: %v", name, err)
+			}
+			return
+		} else if err != nil {
+			log.Errorf("Unable to check blackout windows of cron job %s: %v", name, err)
+		} else if window := workflowObj.Schedule.BlackoutAt(firedAt); window != nil {
+			if err := eng.RecordSkippedRun(context.Background(), workflowID, firedAt, BlackoutSkipReason(window)); err != nil {
+				log.Errorf("Unable to record skipped run of cron job %s: %v", name, err)
+			}
+			return
+		}
+
+		runRequest, err := eng.enqueueRun(
+			context.Background(),
+			workflowID,
+			workflow.ScheduledTimeParams(scheduledAt),
+			nil, /* resumedDAGResultID */
+		)
 		if err != nil {
-			log.Errorf("Error running cron job %s: %v", jobName, err)
+			log.Errorf("Unable to enqueue run of cron job %s: %v", name, err)
 		} else {
-			log.Infof("Launched cron job %s", jobName)
+			log.Infof("Enqueued run request %v of cron job %s", runRequest.ID, name)
 		}
 	}
 }
@@ -1188,6 +1483,7 @@ func (eng *aqEngine) updateWorkflowSchedule(
 				workflowId,
 				cronjobName,
 				string(newSchedule.CronSchedule),
+				newSchedule.Timezone,
 			)
 			if err != nil {
 				return errors.Wrap(err, "Unable to deploy new cron job.")
@@ -1206,26 +1502,12 @@ func (eng *aqEngine) updateWorkflowSchedule(
 			// you set the cron job schedule to an empty string.
 			newCronSchedule = ""
 		}
-		// TODO ENG-1444: Remove jobSpec once executor is removed.
-		jobSpec := job.NewWorkflowSpec(
-			cronjobName,
-			workflowId.String(),
-			eng.Database.Config(),
-			&job.ProcessConfig{
-				BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
-				OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
-			},
-			eng.GithubManager.Config(),
-			eng.AqPath,
-			eng.DisplayIP,
-			nil,
-		)
-
 		err := eng.CronjobManager.EditCronJob(
 			ctx,
 			cronjobName,
 			newCronSchedule,
-			eng.generateCronFunction(cronjobName, jobSpec),
+			newSchedule.Timezone,
+			eng.generateCronFunction(cronjobName, workflowId),
 		)
 		if err != nil {
 			return errors.Wrap(err, "Unable to change workflow schedule.")
@@ -14,6 +14,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
 	"github.com/databricks/databricks-sdk-go/service/jobs"
 	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 // We separate out the execution step for Databricks Jobs since
@@ -33,6 +34,7 @@ func ExecuteDatabricks(
 	databricksJobManager *job.DatabricksJobManager,
 	vaultObject vault.Vault,
 	resourceRepo repos.Resource,
+	dagResultRepo repos.DAGResult,
 	DB database.Database,
 ) (err error) {
 	inProgressOps := workflowRunMetadata.InProgressOps
@@ -81,6 +83,7 @@ func ExecuteDatabricks(
 	}()
 
 	start := time.Now()
+	cancelChecker := newRunCancelChecker(dag.ResultID(), timeConfig.OperatorPollInterval, dagResultRepo, DB)
 	var operatorError error
 
 	for len(inProgressOps) > 0 {
@@ -88,6 +91,20 @@ func ExecuteDatabricks(
 			return errors.New("Reached timeout waiting for workflow to complete.")
 		}
 
+		if opExecMode == operator.Publish && cancelChecker.Canceled(ctx) {
+			// The operators are tasks of a single Databricks job, so the whole job run is canceled.
+			if err := databricksJobManager.Cancel(ctx, workflowName); err != nil {
+				log.Errorf("Unable to cancel Databricks job %s: %v", workflowName, err)
+			}
+
+			err = cancelOperators(ctx, dag, inProgressOps, completedOps, opExecMode)
+			if err != nil {
+				return err
+			}
+
+			return ErrWorkflowRunCanceled
+		}
+
 		for _, op := range inProgressOps {
 			// Poll on the individual operator
 			execState := PollDatabricksOperator(ctx, op, databricksJobManager)
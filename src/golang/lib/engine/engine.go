@@ -22,6 +22,7 @@ const (
 var (
 	ErrOpExecSystemFailure       = errors.New("Operator execution failed due to system error.")
 	ErrOpExecBlockingUserFailure = errors.New("Operator execution failed due to user error.")
+	ErrWorkflowRunCanceled       = errors.New("Workflow run was canceled.")
 )
 
 type Engine interface {
@@ -30,12 +31,27 @@ type Engine interface {
 		workflowId uuid.UUID,
 		name string,
 		period string,
+		timezone string,
 	) error
+	// ExecuteWorkflow executes a new run of the workflow. If `runRequestId` is set, the new run
+	// is recorded on the run request that launched it.
 	ExecuteWorkflow(
 		ctx context.Context,
 		workflowId uuid.UUID,
 		timeConfig *AqueductTimeConfig,
 		parameters map[string]param.Param,
+		runRequestId uuid.UUID,
+	) (shared.ExecutionStatus, error)
+	// ResumeWorkflow executes a new run of the workflow that resumes the failed or canceled run
+	// `dagResultId`. Operators that completed in that run are not executed again, and their
+	// results are reused by the new run. If `runRequestId` is set, the new run is recorded on
+	// the run request that launched it.
+	ResumeWorkflow(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		dagResultId uuid.UUID,
+		timeConfig *AqueductTimeConfig,
+		runRequestId uuid.UUID,
 	) (shared.ExecutionStatus, error)
 	DeleteWorkflow(
 		ctx context.Context,
@@ -51,9 +67,18 @@ type Engine interface {
 		retentionPolicy *shared.RetentionPolicy,
 		notificationSettings *shared.NotificationSettings,
 	) error
+	// RecordSkippedRun records that the run of the workflow scheduled at `scheduledAt` was skipped,
+	// as a DAG result of the workflow's latest DAG with SkippedExecutionStatus.
+	RecordSkippedRun(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		scheduledAt time.Time,
+		reason string,
+	) error
 
 	// TODO ENG-1444: Used as a wrapper to trigger a workflow via executor binary.
 	// Remove once executor is removed.
+	// The run is added to the run queue, unless the workflow runs on Airflow.
 	TriggerWorkflow(
 		ctx context.Context,
 		workflowId uuid.UUID,
@@ -61,6 +86,23 @@ type Engine interface {
 		timeConfig *AqueductTimeConfig,
 		parameters map[string]param.Param,
 	) (shared.ExecutionStatus, error)
+
+	// TODO ENG-1444: Used as a wrapper to resume a workflow run via executor binary.
+	// Remove once executor is removed.
+	TriggerWorkflowResume(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		name string,
+		dagResultId uuid.UUID,
+		timeConfig *AqueductTimeConfig,
+	) (shared.ExecutionStatus, error)
+}
+
+// SchedulerLeader reports whether this server replica is the leader that fires the cron jobs
+// of scheduled workflows, when several replicas share the same database.
+type SchedulerLeader interface {
+	// ShouldFire returns whether this replica fires the cron run scheduled at scheduledAt.
+	ShouldFire(scheduledAt time.Time) bool
 }
 
 // AqEngine should be implemented by aqEngine
@@ -74,6 +116,18 @@ type AqEngine interface {
 		execEnvByOperatorId map[uuid.UUID]exec_env.ExecutionEnvironment,
 		timeConfig *AqueductTimeConfig,
 	) (*WorkflowPreviewResult, error)
+
+	// SyncScheduledWorkflows deploys, edits or deletes the cron jobs of the workflows, so that they
+	// match the schedules in the database, which may have been changed through other server replicas.
+	SyncScheduledWorkflows(ctx context.Context) error
+
+	// StartRunQueue starts the workers that launch the workflow runs enqueued by TriggerWorkflow,
+	// TriggerWorkflowResume and the cron jobs of scheduled workflows.
+	StartRunQueue(
+		ctx context.Context,
+		numWorkers int,
+		leaseDuration time.Duration,
+	) error
 }
 
 // SelfOrchestratedEngine should be implemented for each self-orchestrated engine.
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"math/rand"
+
+	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/dropbox/godropbox/sys/filelock"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// operatorLimiter bounds the number of operators that are executed at the same time.
+// A workflow run executes at most `maxParallel` of its operators at once, and at most
+// `maxConcurrent` operators are executed across all runs. Since every workflow run is
+// executed by its own process, the latter is enforced with one file lock per slot, which
+// are released automatically if the process exits. A limit of 0 means there is no limit.
+type operatorLimiter struct {
+	maxParallel   int
+	maxConcurrent int
+
+	// held maps every operator that is allowed to execute to the slot lock it holds.
+	// The lock is nil if there is no limit across runs.
+	held map[uuid.UUID]*filelock.FileLock
+}
+
+func newOperatorLimiter(maxParallel int, maxConcurrent int) *operatorLimiter {
+	return &operatorLimiter{
+		maxParallel:   maxParallel,
+		maxConcurrent: maxConcurrent,
+		held:          map[uuid.UUID]*filelock.FileLock{},
+	}
+}
+
+// TryAcquire returns whether the operator can start executing. If it returns true,
+// the operator holds its slot until it is released.
+func (l *operatorLimiter) TryAcquire(opID uuid.UUID) (bool, error) {
+	if _, ok := l.held[opID]; ok {
+		return true, nil
+	}
+
+	if l.maxParallel > 0 && len(l.held) >= l.maxParallel {
+		return false, nil
+	}
+
+	var lock *filelock.FileLock
+	if l.maxConcurrent > 0 {
+		// Start from a random slot, so that concurrent runs do not all contend on the first ones.
+		offset := rand.Intn(l.maxConcurrent)
+		for i := 0; i < l.maxConcurrent; i++ {
+			slotLock := workflow_utils.NewOperatorSlotLock((offset + i) % l.maxConcurrent)
+			err := slotLock.TryLock()
+			if err == nil {
+				lock = slotLock
+				break
+			}
+
+			if !filelock.IsHeldElsewhere(err) {
+				return false, errors.Wrap(err, "Unable to acquire operator execution slot.")
+			}
+		}
+
+		if lock == nil {
+			return false, nil
+		}
+	}
+
+	l.held[opID] = lock
+	return true, nil
+}
+
+// Release frees the slot held by the operator, if any.
+func (l *operatorLimiter) Release(opID uuid.UUID) {
+	lock, ok := l.held[opID]
+	if !ok {
+		return
+	}
+
+	delete(l.held, opID)
+	if lock != nil {
+		if err := lock.Unlock(); err != nil {
+			log.Errorf("Unable to release execution slot of operator %v: %v", opID, err)
+		}
+	}
+}
+
+// ReleaseAll frees all slots held by this limiter.
+func (l *operatorLimiter) ReleaseAll() {
+	for opID := range l.held {
+		l.Release(opID)
+	}
+}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorLimiterMaxParallel(t *testing.T) {
+	limiter := newOperatorLimiter(2, 0)
+	opA, opB, opC := uuid.New(), uuid.New(), uuid.New()
+
+	for _, opID := range []uuid.UUID{opA, opB} {
+		acquired, err := limiter.TryAcquire(opID)
+		require.Nil(t, err)
+		require.True(t, acquired)
+	}
+
+	// An operator that already holds a slot can acquire it again.
+	acquired, err := limiter.TryAcquire(opA)
+	require.Nil(t, err)
+	require.True(t, acquired)
+
+	acquired, err = limiter.TryAcquire(opC)
+	require.Nil(t, err)
+	require.False(t, acquired)
+
+	limiter.Release(opA)
+	acquired, err = limiter.TryAcquire(opC)
+	require.Nil(t, err)
+	require.True(t, acquired)
+}
+
+func TestOperatorLimiterMaxConcurrent(t *testing.T) {
+	// The slots are shared by all limiters, as if they were held by different runs.
+	runA := newOperatorLimiter(0, 2)
+	runB := newOperatorLimiter(0, 2)
+	defer runA.ReleaseAll()
+	defer runB.ReleaseAll()
+
+	acquired, err := runA.TryAcquire(uuid.New())
+	require.Nil(t, err)
+	require.True(t, acquired)
+
+	opB := uuid.New()
+	acquired, err = runB.TryAcquire(opB)
+	require.Nil(t, err)
+	require.True(t, acquired)
+
+	acquired, err = runA.TryAcquire(uuid.New())
+	require.Nil(t, err)
+	require.False(t, acquired)
+
+	runB.Release(opB)
+	acquired, err = runA.TryAcquire(uuid.New())
+	require.Nil(t, err)
+	require.True(t, acquired)
+
+	runA.ReleaseAll()
+	acquired, err = runB.TryAcquire(uuid.New())
+	require.Nil(t, err)
+	require.True(t, acquired)
+}
@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// createRun records a new pending run of `dbDAG` with execState. It also returns the runs of the workflow
+// that were in progress when the run was created, which it may overlap with. The workflow is locked
+// while the run is created, so that concurrent runs of the workflow see each other in the order they
+// were created, and at most one of them finds no run in progress. If runRequestID is set, the run is
+// recorded on that run request in the same transaction, so that the run is never left without a request
+// whose lease tells whether it is still being executed.
+func (eng *aqEngine) createRun(
+	ctx context.Context,
+	dbDAG *models.DAG,
+	execState *shared.ExecutionState,
+	runRequestID uuid.UUID,
+) (*models.DAGResult, []models.DAGResult, error) {
+	txn, err := eng.Database.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	if err := eng.WorkflowRepo.Lock(ctx, dbDAG.WorkflowID, txn); err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to lock the workflow.")
+	}
+
+	inProgress, err := eng.DAGResultRepo.GetInProgressByWorkflow(ctx, dbDAG.WorkflowID, txn)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to read the in-progress runs of the workflow.")
+	}
+
+	// The run is created after it acquired the lock, so that it is ordered after the runs it sees.
+	pendingAt := time.Now()
+	execState.Timestamps.PendingAt = &pendingAt
+
+	dagResult, err := eng.DAGResultRepo.Create(ctx, dbDAG.ID, execState, txn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if runRequestID != uuid.Nil {
+		if _, err := eng.RunRequestRepo.Update(
+			ctx,
+			runRequestID,
+			map[string]interface{}{
+				models.RunRequestDAGResultID: dagResult.ID,
+			},
+			txn,
+		); err != nil {
+			return nil, nil, errors.Wrap(err, "Unable to record the run on its run request.")
+		}
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return dagResult, inProgress, nil
+}
+
+// applyOverlapPolicy applies the overlap policy of the workflow's schedule to the new run `dagResult`,
+// given the runs that were `inProgress` when it was created. It returns whether the run should be executed.
+// Otherwise, execState is set to the final state of the run.
+func (eng *aqEngine) applyOverlapPolicy(
+	ctx context.Context,
+	dbDAG *models.DAG,
+	dagResult *models.DAGResult,
+	inProgress []models.DAGResult,
+	execState *shared.ExecutionState,
+	timeConfig *AqueductTimeConfig,
+) (bool, error) {
+	policy := dbDAG.Metadata.Schedule.OverlapPolicy
+	if policy == "" || policy == shared.AllowOverlapPolicy {
+		return true, nil
+	}
+
+	// Runs that have been in progress for longer than a run can execute were abandoned by a server
+	// that stopped while executing them, so they do not overlap with the new run.
+	previous := activeRuns(inProgress, time.Now().Add(-(timeConfig.ExecTimeout + timeConfig.CleanupTimeout)))
+	if len(previous) == 0 {
+		return true, nil
+	}
+
+	switch policy {
+	case shared.SkipOverlapPolicy:
+		now := time.Now()
+		execState.Status = shared.SkippedExecutionStatus
+		execState.Error = &shared.Error{
+			Context: fmt.Sprintf(
+				"The run was skipped, since the previous run %v of the workflow is still executing.",
+				previous[len(previous)-1].ID,
+			),
+		}
+		execState.Timestamps.FinishedAt = &now
+		return false, nil
+	case shared.CancelPreviousOverlapPolicy:
+		for _, previousRun := range previous {
+			if err := eng.cancelRun(ctx, &previousRun); err != nil {
+				return false, err
+			}
+			log.Infof("Canceled workflow run %v, since it overlaps with run %v.", previousRun.ID, dagResult.ID)
+		}
+		return true, nil
+	case shared.QueueOverlapPolicy:
+		// The run queue does not claim the run until the previous runs of the workflow are done,
+		// so the previous runs are still in progress only if their workers stopped tracking them.
+		log.Warnf(
+			"Executing workflow run %v, even though the previous run %v of the workflow is still in progress.",
+			dagResult.ID,
+			previous[len(previous)-1].ID,
+		)
+		return true, nil
+	default:
+		return false, errors.Newf("Unsupported overlap policy %s.", policy)
+	}
+}
+
+// activeRuns returns the runs in `inProgress` that were created at or after staleBefore.
+func activeRuns(inProgress []models.DAGResult, staleBefore time.Time) []models.DAGResult {
+	active := make([]models.DAGResult, 0, len(inProgress))
+	for _, run := range inProgress {
+		if !run.CreatedAt.Before(staleBefore) {
+			active = append(active, run)
+		}
+	}
+
+	return active
+}
+
+// cancelRun marks the in-progress run `dagResult` as canceled.
+// The engine executing the run polls its status, and stops the run once it is canceled.
+func (eng *aqEngine) cancelRun(ctx context.Context, dagResult *models.DAGResult) error {
+	execState := dagResult.ExecState.ExecutionState
+	if execState.Timestamps == nil {
+		execState.Timestamps = &shared.ExecutionTimestamps{}
+	}
+	now := time.Now()
+	execState.Status = shared.CanceledExecutionStatus
+	execState.Timestamps.FinishedAt = &now
+
+	if err := workflow_utils.UpdateDAGResultMetadata(
+		ctx,
+		dagResult.ID,
+		&execState,
+		eng.DAGResultRepo,
+		eng.ArtifactResultRepo,
+		eng.OperatorResultRepo,
+		eng.WorkflowRepo,
+		eng.NotificationRepo,
+		eng.Database,
+	); err != nil {
+		if aq_errors.Is(err, database.ErrNoRows()) {
+			// The run finished before it could be canceled.
+			return nil
+		}
+		return errors.Wrapf(err, "Unable to cancel workflow run %v.", dagResult.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/cmd/migrator/migrator"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var overlapTestTimeConfig = &AqueductTimeConfig{
+	OperatorPollInterval: DefaultPollIntervalMillisec * time.Millisecond,
+	ExecTimeout:          time.Hour,
+	CleanupTimeout:       DefaultCleanupTimeout,
+}
+
+// newTestEngine returns an engine backed by a SQLite database, along with a DAG of a workflow with overlap `policy`.
+func newTestEngine(t *testing.T, policy shared.OverlapPolicy) (*aqEngine, *models.DAG) {
+	ctx := context.Background()
+	DB, err := database.NewSqliteInMemoryDatabase(&database.SqliteConfig{})
+	require.Nil(t, err)
+	t.Cleanup(DB.Close)
+	require.Nil(t, migrator.GoTo(ctx, models.CurrentSchemaVersion, DB))
+
+	eng := &aqEngine{
+		Database: DB,
+		Repos: &Repos{
+			ArtifactResultRepo: sql.NewArtifactResultRepo(),
+			DAGRepo:            sql.NewDAGRepo(),
+			DAGResultRepo:      sql.NewDAGResultRepo(),
+			NotificationRepo:   sql.NewNotificationRepo(),
+			OperatorResultRepo: sql.NewOperatorResultRepo(),
+			RunRequestRepo:     sql.NewRunRequestRepo(),
+			WorkflowRepo:       sql.NewWorklowRepo(),
+		},
+	}
+
+	user, err := sql.NewUserRepo().Create(ctx, "aqueduct", uuid.NewString(), DB)
+	require.Nil(t, err)
+
+	workflow, err := eng.WorkflowRepo.Create(
+		ctx,
+		user.ID,
+		"workflow",
+		"",
+		&shared.Schedule{Trigger: shared.ManualUpdateTrigger, OverlapPolicy: policy},
+		&shared.RetentionPolicy{},
+		&shared.NotificationSettings{},
+		0, /* maxParallelOperators */
+		DB,
+	)
+	require.Nil(t, err)
+
+	dag, err := eng.DAGRepo.Create(
+		ctx,
+		workflow.ID,
+		&shared.StorageConfig{Type: shared.FileStorageType, FileConfig: &shared.FileConfig{Directory: t.TempDir()}},
+		&shared.EngineConfig{Type: shared.AqueductEngineType, AqueductConfig: &shared.AqueductConfig{}},
+		DB,
+	)
+	require.Nil(t, err)
+	dag.Metadata = workflow
+
+	return eng, dag
+}
+
+// startRun creates a new run of dbDAG, and applies the overlap policy to it.
+// It returns the run, whether it should be executed, and its state otherwise.
+func startRun(t *testing.T, eng *aqEngine, dbDAG *models.DAG) (*models.DAGResult, bool, *shared.ExecutionState) {
+	execState := &shared.ExecutionState{
+		Status:     shared.PendingExecutionStatus,
+		Timestamps: &shared.ExecutionTimestamps{},
+	}
+	dagResult, inProgress, err := eng.createRun(context.Background(), dbDAG, execState, uuid.Nil /* runRequestID */)
+	require.Nil(t, err)
+
+	execute, err := eng.applyOverlapPolicy(context.Background(), dbDAG, dagResult, inProgress, execState, overlapTestTimeConfig)
+	require.Nil(t, err)
+	return dagResult, execute, execState
+}
+
+func getRunStatus(t *testing.T, eng *aqEngine, dagResultID uuid.UUID) shared.ExecutionStatus {
+	dagResult, err := eng.DAGResultRepo.Get(context.Background(), dagResultID, eng.Database)
+	require.Nil(t, err)
+	return dagResult.Status
+}
+
+// finishRun records the final status of the run with dagResultID.
+func finishRun(t *testing.T, eng *aqEngine, dagResultID uuid.UUID, status shared.ExecutionStatus) {
+	_, err := eng.DAGResultRepo.UpdateInProgress(
+		context.Background(),
+		dagResultID,
+		map[string]interface{}{models.DAGResultStatus: status},
+		eng.Database,
+	)
+	require.Nil(t, err)
+}
+
+func TestOverlapPolicySkip(t *testing.T) {
+	eng, dbDAG := newTestEngine(t, shared.SkipOverlapPolicy)
+
+	first, execute, _ := startRun(t, eng, dbDAG)
+	require.True(t, execute)
+
+	second, execute, execState := startRun(t, eng, dbDAG)
+	require.False(t, execute)
+	require.Equal(t, shared.SkippedExecutionStatus, execState.Status)
+	require.Contains(t, execState.Error.Context, first.ID.String())
+	finishRun(t, eng, second.ID, execState.Status)
+
+	// Once the first run finishes, new runs are executed again.
+	finishRun(t, eng, first.ID, shared.SucceededExecutionStatus)
+	_, execute, _ = startRun(t, eng, dbDAG)
+	require.True(t, execute)
+}
+
+func TestOverlapPolicyCancelPrevious(t *testing.T) {
+	eng, dbDAG := newTestEngine(t, shared.CancelPreviousOverlapPolicy)
+
+	first, execute, _ := startRun(t, eng, dbDAG)
+	require.True(t, execute)
+
+	second, execute, _ := startRun(t, eng, dbDAG)
+	require.True(t, execute)
+	require.Equal(t, shared.CanceledExecutionStatus, getRunStatus(t, eng, first.ID))
+	require.Equal(t, shared.PendingExecutionStatus, getRunStatus(t, eng, second.ID))
+
+	// A run that finished before it could be canceled keeps its status.
+	finishRun(t, eng, second.ID, shared.SucceededExecutionStatus)
+	require.Nil(t, eng.cancelRun(context.Background(), second))
+	require.Equal(t, shared.SucceededExecutionStatus, getRunStatus(t, eng, second.ID))
+}
+
+func TestOverlapPolicyQueue(t *testing.T) {
+	eng, dbDAG := newTestEngine(t, shared.QueueOverlapPolicy)
+
+	first, execute, _ := startRun(t, eng, dbDAG)
+	require.True(t, execute)
+
+	// The run queue waits for the previous run before it starts the next one, so a run that is
+	// started while a previous run is in progress is executed.
+	_, execute, _ = startRun(t, eng, dbDAG)
+	require.True(t, execute)
+	require.Equal(t, shared.PendingExecutionStatus, getRunStatus(t, eng, first.ID))
+}
+
+func TestOverlapPolicyStaleRuns(t *testing.T) {
+	eng, dbDAG := newTestEngine(t, shared.SkipOverlapPolicy)
+
+	// A run that has been pending for longer than a run can execute was abandoned.
+	pendingAt := time.Now().Add(-2 * overlapTestTimeConfig.ExecTimeout)
+	_, err := eng.DAGResultRepo.Create(
+		context.Background(),
+		dbDAG.ID,
+		&shared.ExecutionState{
+			Status:     shared.PendingExecutionStatus,
+			Timestamps: &shared.ExecutionTimestamps{PendingAt: &pendingAt},
+		},
+		eng.Database,
+	)
+	require.Nil(t, err)
+
+	_, execute, _ := startRun(t, eng, dbDAG)
+	require.True(t, execute)
+}
@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
+	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// reuseResults marks all operators of `dag` that do not have to be executed again when
+// resuming the run `resumedDAGResultID` as completed, with their results from that run.
+// It must be called before the results of `dag` are initialized.
+func (eng *aqEngine) reuseResults(
+	ctx context.Context,
+	dag dag_utils.WorkflowDag,
+	dbDAG *models.DAG,
+	resumedDAGResultID uuid.UUID,
+) error {
+	dbOpResults, err := eng.OperatorResultRepo.GetByDAGResultBatch(ctx, []uuid.UUID{resumedDAGResultID}, eng.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to read operator results.")
+	}
+
+	dbArtifactResults, err := eng.ArtifactResultRepo.GetByDAGResults(ctx, []uuid.UUID{resumedDAGResultID}, eng.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to read artifact results.")
+	}
+
+	opResults := make(map[uuid.UUID]models.OperatorResult, len(dbOpResults))
+	for _, opResult := range dbOpResults {
+		opResults[opResult.OperatorID] = opResult
+	}
+
+	artifactResults := make(map[uuid.UUID]models.ArtifactResult, len(dbArtifactResults))
+	for _, artifactResult := range dbArtifactResults {
+		// The content may have been deleted since, e.g. by the retention policy of the workflow.
+		if !workflow_utils.ObjectExistsInStorage(ctx, &dbDAG.StorageConfig, artifactResult.ContentPath) {
+			continue
+		}
+		artifactResults[artifactResult.ArtifactID] = artifactResult
+	}
+
+	reusableOpIDs := reusableOperators(dbDAG, opResults, artifactResults)
+	for opID := range reusableOpIDs {
+		op, ok := dag.Operators()[opID]
+		if !ok {
+			return errors.Newf("Internal error: operator %v is not in the DAG.", opID)
+		}
+
+		opResult := opResults[opID]
+		if err := op.Reuse(ctx, &opResult, artifactResults); err != nil {
+			return errors.Wrapf(err, "Unable to reuse the result of operator %s.", op.Name())
+		}
+	}
+
+	log.Infof(
+		"Reusing the results of %d of %d operators from workflow run %v.",
+		len(reusableOpIDs),
+		len(dbDAG.Operators),
+		resumedDAGResultID,
+	)
+	return nil
+}
+
+// reusableOperators returns the IDs of the operators of `dag` whose results from a previous run
+// can be reused. An operator can be reused if it completed without a blocking failure, if the content
+// of all its output artifacts was persisted, and if all of its upstream operators can be reused.
+// `opResults` and `artifactResults` are the results of the previous run keyed by operator and
+// artifact ID. They must not contain artifact results whose content is no longer available.
+func reusableOperators(
+	dag *models.DAG,
+	opResults map[uuid.UUID]models.OperatorResult,
+	artifactResults map[uuid.UUID]models.ArtifactResult,
+) map[uuid.UUID]bool {
+	artifactToProducer := make(map[uuid.UUID]uuid.UUID, len(dag.Artifacts))
+	for _, op := range dag.Operators {
+		for _, artifactID := range op.Outputs {
+			artifactToProducer[artifactID] = op.ID
+		}
+	}
+
+	// Maps every visited operator to whether it can be reused.
+	visited := make(map[uuid.UUID]bool, len(dag.Operators))
+
+	var canReuse func(opID uuid.UUID) bool
+	canReuse = func(opID uuid.UUID) bool {
+		if reusable, ok := visited[opID]; ok {
+			return reusable
+		}
+		visited[opID] = false
+
+		op, ok := dag.Operators[opID]
+		if !ok {
+			return false
+		}
+
+		opResult, ok := opResults[opID]
+		if !ok || opResult.ExecState.IsNull || !completedWithoutBlockingFailure(&opResult.ExecState.ExecutionState) {
+			return false
+		}
+
+		for _, artifactID := range op.Outputs {
+			artifactResult, ok := artifactResults[artifactID]
+			if !ok || artifactResult.Metadata.IsNull || !dag.Artifacts[artifactID].ShouldPersist {
+				return false
+			}
+		}
+
+		for _, artifactID := range op.Inputs {
+			if !canReuse(artifactToProducer[artifactID]) {
+				return false
+			}
+		}
+
+		visited[opID] = true
+		return true
+	}
+
+	reusable := make(map[uuid.UUID]bool, len(dag.Operators))
+	for opID := range dag.Operators {
+		if canReuse(opID) {
+			reusable[opID] = true
+		}
+	}
+
+	return reusable
+}
+
+// completedWithoutBlockingFailure returns whether the operator either succeeded,
+// or failed with a non-blocking failure, such as a check with warning severity.
+func completedWithoutBlockingFailure(execState *shared.ExecutionState) bool {
+	if execState.Status == shared.SucceededExecutionStatus {
+		return true
+	}
+
+	return execState.Status == shared.FailedExecutionStatus &&
+		execState.FailureType != nil &&
+		*execState.FailureType == shared.UserNonFatalFailure
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func opResultWithState(status shared.ExecutionStatus, failureType *shared.FailureType) models.OperatorResult {
+	return models.OperatorResult{
+		Status: status,
+		ExecState: shared.NullExecutionState{
+			ExecutionState: shared.ExecutionState{
+				Status:      status,
+				FailureType: failureType,
+			},
+		},
+	}
+}
+
+func TestReusableOperators(t *testing.T) {
+	// This creates a DAG as follows:
+	// extract_0 -> artifact_0 -> func_0 -> artifact_1 -> func_1 -> artifact_2
+	//                   |
+	//                   --> check_0 -> artifact_3
+	// extract_1 -> artifact_4 -> func_2 -> artifact_5
+	artifactIDs := make([]uuid.UUID, 6)
+	artifacts := make(map[uuid.UUID]models.Artifact, len(artifactIDs))
+	for i := range artifactIDs {
+		artifactIDs[i] = uuid.New()
+		artifacts[artifactIDs[i]] = models.Artifact{ID: artifactIDs[i], ShouldPersist: true}
+	}
+
+	// The content of artifact_4 is not persisted, so it cannot be reused.
+	artifacts[artifactIDs[4]] = models.Artifact{ID: artifactIDs[4], ShouldPersist: false}
+
+	newOp := func(inputs []uuid.UUID, outputs []uuid.UUID) models.Operator {
+		return models.Operator{ID: uuid.New(), Inputs: inputs, Outputs: outputs}
+	}
+	extract0 := newOp(nil, []uuid.UUID{artifactIDs[0]})
+	func0 := newOp([]uuid.UUID{artifactIDs[0]}, []uuid.UUID{artifactIDs[1]})
+	func1 := newOp([]uuid.UUID{artifactIDs[1]}, []uuid.UUID{artifactIDs[2]})
+	check0 := newOp([]uuid.UUID{artifactIDs[0]}, []uuid.UUID{artifactIDs[3]})
+	extract1 := newOp(nil, []uuid.UUID{artifactIDs[4]})
+	func2 := newOp([]uuid.UUID{artifactIDs[4]}, []uuid.UUID{artifactIDs[5]})
+
+	dag := &models.DAG{
+		Operators: map[uuid.UUID]models.Operator{},
+		Artifacts: artifacts,
+	}
+	for _, op := range []models.Operator{extract0, func0, func1, check0, extract1, func2} {
+		dag.Operators[op.ID] = op
+	}
+
+	systemFailure := shared.SystemFailure
+	warning := shared.UserNonFatalFailure
+	opResults := map[uuid.UUID]models.OperatorResult{
+		extract0.ID: opResultWithState(shared.SucceededExecutionStatus, nil),
+		func0.ID:    opResultWithState(shared.FailedExecutionStatus, &systemFailure),
+		func1.ID:    opResultWithState(shared.CanceledExecutionStatus, nil),
+		check0.ID:   opResultWithState(shared.FailedExecutionStatus, &warning),
+		extract1.ID: opResultWithState(shared.SucceededExecutionStatus, nil),
+		func2.ID:    opResultWithState(shared.SucceededExecutionStatus, nil),
+	}
+
+	artifactResults := make(map[uuid.UUID]models.ArtifactResult, len(artifactIDs))
+	for _, artifactID := range artifactIDs {
+		artifactResults[artifactID] = models.ArtifactResult{ArtifactID: artifactID, ContentPath: uuid.NewString()}
+	}
+
+	// Only the extract and the check with a warning can be reused. func_2 succeeded, but its input
+	// must be computed again, since it was not persisted.
+	require.Equal(
+		t,
+		map[uuid.UUID]bool{extract0.ID: true, check0.ID: true},
+		reusableOperators(dag, opResults, artifactResults),
+	)
+
+	// Once the content of artifact_0 is no longer available, nothing can be reused.
+	delete(artifactResults, artifactIDs[0])
+	require.Empty(t, reusableOperators(dag, opResults, artifactResults))
+}
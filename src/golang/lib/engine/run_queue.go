@@ -0,0 +1,386 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultRunQueueWorkers       = 16
+	DefaultRunQueueLeaseDuration = time.Minute
+	// MinRunQueueLeaseDuration leaves a worker enough time to renew its lease before it expires.
+	MinRunQueueLeaseDuration = 10 * time.Second
+
+	// maxRunRequestAttempts is the number of times a worker tries to launch a run before giving up.
+	maxRunRequestAttempts = 3
+
+	queuedWaitReason          = "Waiting for a worker to claim the run."
+	previousRunWaitReason     = "Waiting for the previous run of the workflow to finish, since its overlap policy is queue."
+	leaseExpiredWaitReason    = "Requeued since the worker that claimed the run stopped renewing its lease."
+	launchFailedWaitReasonFmt = "Requeued since the executor failed to launch: %v"
+)
+
+// runQueuePollInterval is how often an idle worker checks for queued runs, and how often
+// a busy worker checks whether its run has finished.
+var runQueuePollInterval = 2 * time.Second
+
+// enqueueRun adds a request to run the workflow to the run queue, which resumes the run
+// resumedDAGResultID if it is set.
+func (eng *aqEngine) enqueueRun(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	parameters map[string]param.Param,
+	resumedDAGResultID *uuid.UUID,
+) (*models.RunRequest, error) {
+	runRequest, err := eng.RunRequestRepo.Create(
+		ctx,
+		workflowID,
+		parameters,
+		resumedDAGResultID,
+		queuedWaitReason,
+		eng.Database,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to enqueue run of workflow %v.", workflowID)
+	}
+
+	return runRequest, nil
+}
+
+// StartRunQueue starts numWorkers workers that claim the queued runs, oldest first, and launch
+// an executor for each of them. A worker holds a lease on its run that it renews every third of
+// leaseDuration until the run finishes. Runs whose lease expires, e.g. because the server crashed,
+// are requeued if they were not launched yet.
+func (eng *aqEngine) StartRunQueue(ctx context.Context, numWorkers int, leaseDuration time.Duration) error {
+	jobManager, err := job.NewProcessJobManager(
+		&job.ProcessConfig{
+			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
+			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create JobManager.")
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "aqueduct"
+	}
+	queueID := uuid.New().String()[:8]
+
+	go eng.maintainRunQueue(ctx)
+	for i := 0; i < numWorkers; i++ {
+		workerID := fmt.Sprintf("%s-%s-%d", hostname, queueID, i)
+		go eng.runQueueWorker(ctx, workerID, jobManager, leaseDuration)
+	}
+
+	log.Infof("Started %d run queue workers.", numWorkers)
+	return nil
+}
+
+// maintainRunQueue periodically requeues the runs whose worker stopped renewing its lease before
+// launching them. Runs that were already launched are marked as done instead, and their DAG results
+// are canceled, since running them again could execute the same run twice. It also explains why the queued runs that wait for a previous run of their workflow
+// are not claimed.
+func (eng *aqEngine) maintainRunQueue(ctx context.Context) {
+	for {
+		requeued, err := eng.RunRequestRepo.RequeueExpired(ctx, leaseExpiredWaitReason, eng.Database)
+		if err != nil {
+			log.Errorf("Unable to requeue expired run requests: %v", err)
+		}
+		for _, runRequest := range requeued {
+			log.Warnf("Requeued run request %v of workflow %v, since its lease expired.", runRequest.ID, runRequest.WorkflowID)
+		}
+
+		finished, err := eng.RunRequestRepo.FinishExpired(ctx, eng.Database)
+		if err != nil {
+			log.Errorf("Unable to finish expired run requests: %v", err)
+		}
+		for _, runRequest := range finished {
+			log.Warnf(
+				"Run request %v of workflow %v is marked as done, since its worker stopped renewing its lease.",
+				runRequest.ID,
+				runRequest.WorkflowID,
+			)
+		}
+
+		eng.cancelAbandonedRuns(ctx)
+
+		if _, err := eng.RunRequestRepo.MarkWaitingForPreviousRun(ctx, previousRunWaitReason, eng.Database); err != nil {
+			log.Errorf("Unable to update the wait reason of queued run requests: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(runQueuePollInterval):
+		}
+	}
+}
+
+// cancelAbandonedRuns cancels the runs that are still pending or running, even though no worker
+// holds the lease of the run request that launched them, e.g. because the server replica that
+// launched them crashed. Every replica does this, so that the runs of a crashed replica are
+// canceled even if it does not restart.
+func (eng *aqEngine) cancelAbandonedRuns(ctx context.Context) {
+	dagResults, err := eng.DAGResultRepo.GetAbandoned(ctx, eng.Database)
+	if err != nil {
+		log.Errorf("Unable to get abandoned workflow runs: %v", err)
+		return
+	}
+
+	for i := range dagResults {
+		if err := eng.cancelRun(ctx, &dagResults[i]); err != nil {
+			log.Errorf("Unable to cancel abandoned workflow run: %v", err)
+			continue
+		}
+		log.Warnf("Canceled workflow run %v, since no worker is executing it.", dagResults[i].ID)
+	}
+}
+
+func (eng *aqEngine) runQueueWorker(
+	ctx context.Context,
+	workerID string,
+	jobManager job.JobManager,
+	leaseDuration time.Duration,
+) {
+	for {
+		runRequest, err := eng.RunRequestRepo.Claim(ctx, workerID, leaseDuration, eng.Database)
+		if err == nil {
+			eng.processRunRequest(ctx, workerID, jobManager, runRequest, leaseDuration)
+			continue
+		}
+
+		if !errors.Is(err, database.ErrNoRows()) {
+			log.Errorf("Worker %s is unable to claim a run request: %v", workerID, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(runQueuePollInterval):
+		}
+	}
+}
+
+// processRunRequest launches the executor of the claimed runRequest, and renews its lease until the
+// executor exits.
+func (eng *aqEngine) processRunRequest(
+	ctx context.Context,
+	workerID string,
+	jobManager job.JobManager,
+	runRequest *models.RunRequest,
+	leaseDuration time.Duration,
+) {
+	// TODO ENG-1444: The executor calls ExecuteWorkflow() or ResumeWorkflow().
+	// Call them directly once the executor is removed.
+	name := shared_utils.AppendPrefix(runRequest.WorkflowID.String())
+	resumedDAGResultID := ""
+	if !runRequest.ResumedDAGResultID.IsNull {
+		resumedDAGResultID = runRequest.ResumedDAGResultID.UUID.String()
+	}
+
+	jobSpec := job.NewWorkflowSpec(
+		name,
+		runRequest.WorkflowID.String(),
+		eng.Database.Config(),
+		&job.ProcessConfig{
+			BinaryDir:          path.Join(eng.AqPath, job.BinaryDir),
+			OperatorStorageDir: path.Join(eng.AqPath, job.OperatorStorageDir),
+		},
+		eng.GithubManager.Config(),
+		eng.AqPath,
+		eng.DisplayIP,
+		runRequest.Parameters,
+		resumedDAGResultID,
+		runRequest.ID.String(),
+	)
+
+	// The name of the job is unique, since there is one job per run request.
+	jobName := fmt.Sprintf("%s-%s", name, runRequest.ID)
+	if err := jobManager.Launch(ctx, jobName, jobSpec); err != nil {
+		log.Errorf("Error running job %s: %v", jobName, err)
+		eng.retryRunRequest(ctx, runRequest, err)
+		return
+	}
+	log.Infof("Launched job %s", jobName)
+
+	if _, err := eng.RunRequestRepo.Update(
+		ctx,
+		runRequest.ID,
+		map[string]interface{}{
+			models.RunRequestStatus: shared.RunningRunRequestStatus,
+		},
+		eng.Database,
+	); err != nil {
+		log.Errorf("Unable to mark run request %v as running: %v", runRequest.ID, err)
+	}
+
+	if !eng.waitForRun(ctx, workerID, jobManager, jobName, runRequest.ID, leaseDuration) {
+		return
+	}
+
+	eng.finishRunRequest(ctx, runRequest.ID)
+}
+
+// waitForRun waits until the job jobName of the run request with runRequestID exits, while the lease
+// of the request is renewed by a separate goroutine. Polling a process job blocks until the job exits
+// once its process is not actively running, so the lease cannot be renewed between polls.
+// It returns false if ctx is done before the job exits.
+func (eng *aqEngine) waitForRun(
+	ctx context.Context,
+	workerID string,
+	jobManager job.JobManager,
+	jobName string,
+	runRequestID uuid.UUID,
+	leaseDuration time.Duration,
+) bool {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		eng.renewLease(ctx, done, workerID, runRequestID, leaseDuration)
+	}()
+
+	// The lease is no longer renewed once the job exits, so that it is not renewed after the request is done.
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(runQueuePollInterval):
+		}
+
+		status, jobErr := jobManager.Poll(ctx, jobName)
+		if jobErr != nil || status != shared.RunningExecutionStatus {
+			return true
+		}
+	}
+}
+
+// renewLease renews the lease of the run request with runRequestID every third of leaseDuration,
+// until done is closed.
+func (eng *aqEngine) renewLease(
+	ctx context.Context,
+	done <-chan struct{},
+	workerID string,
+	runRequestID uuid.UUID,
+	leaseDuration time.Duration,
+) {
+	ticker := time.NewTicker(leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := eng.RunRequestRepo.RenewLease(
+			ctx,
+			runRequestID,
+			workerID,
+			leaseDuration,
+			eng.Database,
+		); err != nil {
+			// The run is still executing, but the request can no longer be tracked by this worker.
+			log.Errorf("Unable to renew the lease of run request %v: %v", runRequestID, err)
+			return
+		}
+	}
+}
+
+// retryRunRequest puts runRequest back in the queue after its executor failed to launch with launchErr,
+// unless it has been attempted maxRunRequestAttempts times. In that case, the run is recorded as failed.
+func (eng *aqEngine) retryRunRequest(ctx context.Context, runRequest *models.RunRequest, launchErr error) {
+	if runRequest.Attempts >= maxRunRequestAttempts {
+		log.Errorf("Giving up on run request %v after %d attempts.", runRequest.ID, runRequest.Attempts)
+		if err := eng.recordFailedRun(ctx, runRequest, launchErr); err != nil {
+			log.Errorf("Unable to record the failed run of run request %v: %v", runRequest.ID, err)
+		}
+		eng.finishRunRequest(ctx, runRequest.ID)
+		return
+	}
+
+	if _, err := eng.RunRequestRepo.Update(
+		ctx,
+		runRequest.ID,
+		map[string]interface{}{
+			models.RunRequestStatus:         shared.QueuedRunRequestStatus,
+			models.RunRequestClaimedBy:      "",
+			models.RunRequestLeaseExpiresAt: nil,
+			models.RunRequestWaitReason:     fmt.Sprintf(launchFailedWaitReasonFmt, launchErr),
+		},
+		eng.Database,
+	); err != nil {
+		log.Errorf("Unable to requeue run request %v: %v", runRequest.ID, err)
+	}
+}
+
+// recordFailedRun records a failed run of the workflow of runRequest, since its executor could not be
+// launched with launchErr. The run does not have any operator results, since none of them ran.
+func (eng *aqEngine) recordFailedRun(ctx context.Context, runRequest *models.RunRequest, launchErr error) error {
+	dag, err := eng.DAGRepo.GetLatestByWorkflow(ctx, runRequest.WorkflowID, eng.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to get latest DAG of workflow.")
+	}
+
+	now := time.Now()
+	systemFailure := shared.SystemFailure
+	_, err = eng.DAGResultRepo.Create(
+		ctx,
+		dag.ID,
+		&shared.ExecutionState{
+			Status:      shared.FailedExecutionStatus,
+			FailureType: &systemFailure,
+			Error: &shared.Error{
+				Context: fmt.Sprintf(
+					"The executor of the run failed to launch %d times. The last error was: %v",
+					runRequest.Attempts,
+					launchErr,
+				),
+				Tip: shared.TipCreateBugReport,
+			},
+			Timestamps: &shared.ExecutionTimestamps{
+				PendingAt:  &runRequest.CreatedAt,
+				FinishedAt: &now,
+			},
+		},
+		eng.Database,
+	)
+	return err
+}
+
+func (eng *aqEngine) finishRunRequest(ctx context.Context, runRequestID uuid.UUID) {
+	if _, err := eng.RunRequestRepo.Update(
+		ctx,
+		runRequestID,
+		map[string]interface{}{
+			models.RunRequestStatus:         shared.DoneRunRequestStatus,
+			models.RunRequestLeaseExpiresAt: nil,
+		},
+		eng.Database,
+	); err != nil {
+		log.Errorf("Unable to mark run request %v as done: %v", runRequestID, err)
+	}
+}
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+// slowJobManager runs a single job, which keeps running until `exit` is closed. Like the process
+// job manager, polling the job blocks until it exits.
+type slowJobManager struct {
+	job.JobManager
+	exit chan struct{}
+}
+
+func (j *slowJobManager) Poll(ctx context.Context, name string) (shared.ExecutionStatus, job.JobError) {
+	<-j.exit
+	return shared.SucceededExecutionStatus, nil
+}
+
+func TestWaitForRunRenewsLease(t *testing.T) {
+	prevPollInterval := runQueuePollInterval
+	runQueuePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { runQueuePollInterval = prevPollInterval })
+
+	ctx := context.Background()
+	eng, dbDAG := newTestEngine(t, shared.AllowOverlapPolicy)
+	leaseDuration := 300 * time.Millisecond
+
+	_, err := eng.RunRequestRepo.Create(ctx, dbDAG.WorkflowID, nil /* parameters */, nil /* resumedDAGResultID */, queuedWaitReason, eng.Database)
+	require.Nil(t, err)
+	runRequest, err := eng.RunRequestRepo.Claim(ctx, "worker", leaseDuration, eng.Database)
+	require.Nil(t, err)
+	_, err = eng.RunRequestRepo.Update(
+		ctx,
+		runRequest.ID,
+		map[string]interface{}{models.RunRequestStatus: shared.RunningRunRequestStatus},
+		eng.Database,
+	)
+	require.Nil(t, err)
+
+	jobManager := &slowJobManager{exit: make(chan struct{})}
+	exited := make(chan bool)
+	go func() {
+		exited <- eng.waitForRun(ctx, "worker", jobManager, "job", runRequest.ID, leaseDuration)
+	}()
+
+	// The job runs for several lease durations, but its lease does not expire.
+	for i := 0; i < 3; i++ {
+		time.Sleep(leaseDuration)
+
+		finished, err := eng.RunRequestRepo.FinishExpired(ctx, eng.Database)
+		require.Nil(t, err)
+		require.Empty(t, finished)
+	}
+
+	close(jobManager.exit)
+	select {
+	case finished := <-exited:
+		require.True(t, finished)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Waiting for the run did not finish after the job exited.")
+	}
+
+	// The lease is no longer renewed once the job exits.
+	runRequest, err = eng.RunRequestRepo.Get(ctx, runRequest.ID, eng.Database)
+	require.Nil(t, err)
+	time.Sleep(leaseDuration)
+	finished, err := eng.RunRequestRepo.FinishExpired(ctx, eng.Database)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(finished))
+	require.Equal(t, runRequest.ID, finished[0].ID)
+}
+
+func TestRetryRunRequestRecordsFailedRun(t *testing.T) {
+	ctx := context.Background()
+	eng, dbDAG := newTestEngine(t, shared.AllowOverlapPolicy)
+
+	_, err := eng.RunRequestRepo.Create(ctx, dbDAG.WorkflowID, nil /* parameters */, nil /* resumedDAGResultID */, queuedWaitReason, eng.Database)
+	require.Nil(t, err)
+
+	// The request is requeued until it was attempted maxRunRequestAttempts times.
+	for i := 0; i < maxRunRequestAttempts; i++ {
+		runRequest, err := eng.RunRequestRepo.Claim(ctx, "worker", time.Minute, eng.Database)
+		require.Nil(t, err)
+		eng.retryRunRequest(ctx, runRequest, errors.New("Unable to start the executor."))
+	}
+
+	_, err = eng.RunRequestRepo.Claim(ctx, "worker", time.Minute, eng.Database)
+	require.True(t, errors.Is(err, database.ErrNoRows()))
+
+	dagResults, err := eng.DAGResultRepo.GetByWorkflow(ctx, dbDAG.WorkflowID, "", -1 /* limit */, false /* orderDescending */, eng.Database)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(dagResults))
+	require.Equal(t, shared.FailedExecutionStatus, dagResults[0].Status)
+	require.Contains(t, dagResults[0].ExecState.Error.Context, "Unable to start the executor.")
+}
+
+func TestCancelAbandonedRuns(t *testing.T) {
+	ctx := context.Background()
+	eng, dbDAG := newTestEngine(t, shared.AllowOverlapPolicy)
+
+	// Each run is launched by its own run request.
+	dagResults := make([]*models.DAGResult, 0, 2)
+	runRequests := make([]*models.RunRequest, 0, 2)
+	for i := 0; i < 2; i++ {
+		_, err := eng.RunRequestRepo.Create(ctx, dbDAG.WorkflowID, nil /* parameters */, nil /* resumedDAGResultID */, queuedWaitReason, eng.Database)
+		require.Nil(t, err)
+		runRequest, err := eng.RunRequestRepo.Claim(ctx, "worker", time.Minute, eng.Database)
+		require.Nil(t, err)
+
+		execState := &shared.ExecutionState{
+			Status:     shared.PendingExecutionStatus,
+			Timestamps: &shared.ExecutionTimestamps{},
+		}
+		dagResult, _, err := eng.createRun(ctx, dbDAG, execState, runRequest.ID)
+		require.Nil(t, err)
+
+		runRequest, err = eng.RunRequestRepo.Get(ctx, runRequest.ID, eng.Database)
+		require.Nil(t, err)
+		require.Equal(t, dagResult.ID, runRequest.DAGResultID.UUID)
+
+		dagResults = append(dagResults, dagResult)
+		runRequests = append(runRequests, runRequest)
+	}
+
+	// The runs are not canceled while their workers hold the leases.
+	eng.cancelAbandonedRuns(ctx)
+	require.Equal(t, shared.PendingExecutionStatus, getRunStatus(t, eng, dagResults[0].ID))
+	require.Equal(t, shared.PendingExecutionStatus, getRunStatus(t, eng, dagResults[1].ID))
+
+	// A run is canceled once the lease of its request expires, e.g. because the server replica
+	// crashed, even though another run of the workflow is still executed.
+	_, err := eng.RunRequestRepo.RenewLease(ctx, runRequests[0].ID, "worker", -time.Minute, eng.Database)
+	require.Nil(t, err)
+
+	eng.cancelAbandonedRuns(ctx)
+	require.Equal(t, shared.CanceledExecutionStatus, getRunStatus(t, eng, dagResults[0].ID))
+	require.Equal(t, shared.PendingExecutionStatus, getRunStatus(t, eng, dagResults[1].ID))
+}
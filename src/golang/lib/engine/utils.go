@@ -4,8 +4,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	dag_utils "github.com/aqueducthq/aqueduct/lib/workflow/dag"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -51,3 +54,87 @@ func opFailureError(failureType shared.FailureType, op operator.Operator) error
 func isOpFailureError(err error) bool {
 	return errors.Is(err, ErrOpExecSystemFailure) || errors.Is(err, ErrOpExecBlockingUserFailure)
 }
+
+func isRunCanceledError(err error) bool {
+	return errors.Is(err, ErrWorkflowRunCanceled)
+}
+
+// isRunCanceled returns whether a user has requested to cancel the workflow run with dagResultID.
+// A cancellation is requested by marking the DAGResult as canceled.
+func isRunCanceled(
+	ctx context.Context,
+	dagResultID uuid.UUID,
+	dagResultRepo repos.DAGResult,
+	DB database.Database,
+) bool {
+	dagResult, err := dagResultRepo.Get(ctx, dagResultID, DB)
+	if err != nil {
+		log.Errorf("Unable to check whether workflow run %s was canceled: %v", dagResultID, err)
+		return false
+	}
+
+	return dagResult.Status == shared.CanceledExecutionStatus
+}
+
+// runCancelChecker checks whether the workflow run with dagResultID was canceled, at most once every
+// interval, so that the orchestration loops do not query the database on every iteration.
+type runCancelChecker struct {
+	dagResultID   uuid.UUID
+	interval      time.Duration
+	lastCheckedAt time.Time
+	dagResultRepo repos.DAGResult
+	DB            database.Database
+}
+
+func newRunCancelChecker(
+	dagResultID uuid.UUID,
+	interval time.Duration,
+	dagResultRepo repos.DAGResult,
+	DB database.Database,
+) *runCancelChecker {
+	return &runCancelChecker{
+		dagResultID:   dagResultID,
+		interval:      interval,
+		dagResultRepo: dagResultRepo,
+		DB:            DB,
+	}
+}
+
+// Canceled returns whether the run was canceled. It only checks the database if the
+// interval has passed since the last check, and returns false otherwise.
+func (c *runCancelChecker) Canceled(ctx context.Context) bool {
+	if time.Since(c.lastCheckedAt) < c.interval {
+		return false
+	}
+
+	c.lastCheckedAt = time.Now()
+	return isRunCanceled(ctx, c.dagResultID, c.dagResultRepo, c.DB)
+}
+
+// cancelOperators stops all in-progress operators, and marks them along with all operators that
+// have not run yet as canceled. The results of the canceled operators are persisted in publish mode.
+func cancelOperators(
+	ctx context.Context,
+	dag dag_utils.WorkflowDag,
+	inProgressOps map[uuid.UUID]operator.Operator,
+	completedOps map[uuid.UUID]operator.Operator,
+	execMode operator.ExecutionMode,
+) error {
+	for id, op := range dag.Operators() {
+		if _, ok := completedOps[id]; ok {
+			continue
+		}
+
+		op.Cancel(ctx)
+		if execMode == operator.Publish {
+			if err := op.PersistResult(ctx); err != nil {
+				return errors.Wrapf(err, "Error when finishing execution of operator %s", op.Name())
+			}
+		}
+
+		completedOps[id] = op
+		delete(inProgressOps, id)
+	}
+
+	return nil
+}
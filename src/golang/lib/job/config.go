@@ -111,6 +111,7 @@ func RegisterGobTypes() {
 	gob.Register(&WorkflowSpec{})
 	gob.Register(&WorkflowRetentionSpec{})
 	gob.Register(&DynamicTeardownSpec{})
+	gob.Register(&StorageGCSpec{})
 }
 
 func init() {
@@ -162,8 +163,8 @@ func GenerateJobManagerConfig(
 			CondaEnvName:       engineConfig.AqueductCondaConfig.Env,
 		}, nil
 	case shared.K8sEngineType:
-		if storageConfig.Type != shared.S3StorageType && storageConfig.Type != shared.GCSStorageType {
-			return nil, errors.New("Must use S3 or GCS storage config for K8s engine.")
+		if storageConfig.Type == shared.FileStorageType {
+			return nil, errors.New("Must use S3, GCS or Azure storage config for K8s engine.")
 		}
 
 		var awsAccessKeyId, awsSecretAccessKey string
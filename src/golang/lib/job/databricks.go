@@ -95,6 +95,19 @@ func (j *DatabricksJobManager) Poll(ctx context.Context, name string) (shared.Ex
 	}
 }
 
+func (j *DatabricksJobManager) Cancel(ctx context.Context, name string) JobError {
+	runID, ok := j.runMap[name]
+	if !ok {
+		return jobMissingError(errors.New("Job doesn't exist."))
+	}
+
+	if err := databricks_lib.CancelRun(ctx, j.databricksClient, runID); err != nil {
+		return systemError(err)
+	}
+
+	return nil
+}
+
 func (j *DatabricksJobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
@@ -11,6 +11,8 @@ type JobManager interface {
 	Config() Config
 	Launch(ctx context.Context, name string, spec Spec) JobError
 	Poll(ctx context.Context, name string) (shared.ExecutionStatus, JobError)
+	// Cancel stops the job `name` if it is still running.
+	Cancel(ctx context.Context, name string) JobError
 	DeployCronJob(ctx context.Context, name string, period string, spec Spec) JobError
 	CronJobExists(ctx context.Context, name string) bool
 	EditCronJob(ctx context.Context, name string, cronString string) JobError
@@ -297,6 +297,32 @@ func (j *k8sJobManager) Poll(ctx context.Context, name string) (shared.Execution
 	return status, nil
 }
 
+func (j *k8sJobManager) Cancel(ctx context.Context, name string) JobError {
+	if j.k8sClient == nil {
+		if err := j.initialize(); err != nil {
+			return systemError(err)
+		}
+	}
+
+	job, err := k8s.GetJob(ctx, name, j.k8sClient)
+	if err != nil {
+		return jobMissingError(err)
+	}
+
+	// Deleting the job also deletes its pods.
+	if err := k8s.DeleteJob(ctx, name, j.k8sClient); err != nil {
+		return systemError(err)
+	}
+
+	for _, secret := range job.Spec.Template.Spec.ImagePullSecrets {
+		if err := k8s.DeleteSecret(ctx, secret.Name, j.k8sClient); err != nil {
+			log.Errorf("Failed to delete image pull secret %s for job %s: %v", secret.Name, name, err)
+		}
+	}
+
+	return nil
+}
+
 func (j *k8sJobManager) DeployCronJob(ctx context.Context, name string, period string, spec Spec) JobError {
 	return nil
 }
@@ -197,6 +197,11 @@ func (j *lambdaJobManager) Poll(ctx context.Context, name string) (shared.Execut
 	return shared.UnknownExecutionStatus, noopError(errors.New("Cannot poll a lambda job manager."))
 }
 
+func (j *lambdaJobManager) Cancel(ctx context.Context, name string) JobError {
+	// Lambda functions are invoked asynchronously, and an invocation cannot be stopped once it started.
+	return noopError(errors.New("Cannot cancel a lambda job manager."))
+}
+
 func (j *lambdaJobManager) DeployCronJob(ctx context.Context, name string, period string, spec Spec) JobError {
 	return nil
 }
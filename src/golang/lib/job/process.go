@@ -193,6 +193,27 @@ func (j *ProcessJobManager) mapJobTypeToCmd(jobName string, spec Spec) (*exec.Cm
 		logFilePath := path.Join(defaultLogsDir, jobName)
 		log.Infof("Logs for job %s are stored in %s", jobName, logFilePath)
 
+		cmd = exec.Command(
+			fmt.Sprintf("%s/%s", j.conf.BinaryDir, executorBinary),
+			"--spec",
+			specStr,
+			"--logs-path",
+			logFilePath,
+		)
+	} else if spec.Type() == StorageGCType {
+		storageGCSpec, ok := spec.(*StorageGCSpec)
+		if !ok {
+			return nil, errors.New("Unable to cast job spec to storageGCSpec.")
+		}
+
+		specStr, err := EncodeSpec(storageGCSpec, GobSerializationType)
+		if err != nil {
+			return nil, err
+		}
+
+		logFilePath := path.Join(defaultLogsDir, jobName)
+		log.Infof("Logs for job %s are stored in %s", jobName, logFilePath)
+
 		cmd = exec.Command(
 			fmt.Sprintf("%s/%s", j.conf.BinaryDir, executorBinary),
 			"--spec",
@@ -390,6 +411,59 @@ func (j *ProcessJobManager) Poll(ctx context.Context, name string) (shared.Execu
 	return shared.SucceededExecutionStatus, nil
 }
 
+func (j *ProcessJobManager) Cancel(ctx context.Context, name string) JobError {
+	command, ok := j.getCmd(name)
+	if !ok {
+		return jobMissingError(errors.Newf("Job %s does not exist.", name))
+	}
+
+	if command.cmd.Process == nil {
+		// The process was never started.
+		j.deleteCmd(name)
+		return nil
+	}
+
+	proc, err := process.NewProcess(int32(command.cmd.Process.Pid))
+	if err == nil {
+		if err := killProcessTree(proc); err != nil {
+			return systemError(err)
+		}
+	}
+
+	// Wait releases the resources of the killed process. It errors because the process was
+	// killed, so the error is ignored.
+	_ = command.cmd.Wait()
+	j.deleteCmd(name)
+
+	log.Infof("Cancelled job %s.", name)
+	return nil
+}
+
+// killProcessTree kills proc and all of its descendants. This is necessary since some jobs,
+// like function operators, are run by a wrapper script that spawns the actual process.
+func killProcessTree(proc *process.Process) error {
+	// The children must be fetched before the parent is killed, since they are
+	// re-parented once the parent exits.
+	children, err := proc.Children()
+	if err != nil && err != process.ErrorNoChildren {
+		return err
+	}
+
+	if err := proc.Kill(); err != nil {
+		if running, runningErr := proc.IsRunning(); runningErr == nil && running {
+			return err
+		}
+	}
+
+	for _, child := range children {
+		if err := killProcessTree(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (j *ProcessJobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
@@ -1,10 +1,14 @@
 package job
 
 import (
+	"bytes"
 	"context"
+	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/go-co-op/gocron"
+	"github.com/shirou/gopsutil/process"
 	"github.com/stretchr/testify/require"
 )
 
@@ -91,3 +95,47 @@ func TestDeleteCronJob(t *testing.T) {
 	require.Equal(t, 0, len(jobManager.cronMapping))
 	require.Equal(t, 0, len(jobManager.cronScheduler.Jobs()))
 }
+
+func TestCancel(t *testing.T) {
+	jobManager, err := NewProcessJobManager(dummyProcessConfig)
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	jobName := "job"
+
+	// The job spawns a child process, which must be killed along with the job.
+	cmd := exec.Command("bash", "-c", "sleep 60 & wait")
+	cmd.Stdout = &bytes.Buffer{}
+	cmd.Stderr = &bytes.Buffer{}
+	require.Nil(t, cmd.Start())
+	jobManager.setCmd(jobName, &Command{
+		cmd:    cmd,
+		stdout: &bytes.Buffer{},
+		stderr: &bytes.Buffer{},
+	})
+
+	proc, err := process.NewProcess(int32(cmd.Process.Pid))
+	require.Nil(t, err)
+	require.Eventually(t, func() bool {
+		children, err := proc.Children()
+		return err == nil && len(children) == 1
+	}, time.Second*5, time.Millisecond*10)
+	children, err := proc.Children()
+	require.Nil(t, err)
+
+	jobErr := jobManager.Cancel(ctx, jobName)
+	require.Nil(t, jobErr)
+	require.Equal(t, 0, len(jobManager.cmds))
+
+	// The killed child is either gone or a zombie waiting to be reaped by init.
+	require.Eventually(t, func() bool {
+		status, err := children[0].Status()
+		return err != nil || status == "Z"
+	}, time.Second*5, time.Millisecond*10)
+
+	// Cancelling a job that does not exist should fail.
+	jobErr = jobManager.Cancel(ctx, jobName)
+	require.NotNil(t, jobErr)
+	require.Equal(t, JobMissing, jobErr.Code())
+}
@@ -100,6 +100,19 @@ func (j *SparkJobManager) Poll(ctx context.Context, name string) (shared.Executi
 	return shared.UnknownExecutionStatus, nil
 }
 
+func (j *SparkJobManager) Cancel(ctx context.Context, name string) JobError {
+	statementID, ok := j.runMap[name]
+	if !ok {
+		return jobMissingError(errors.New("Job doesn't exist."))
+	}
+
+	if err := j.livyClient.CancelStatement(j.sessionID, statementID); err != nil {
+		return systemError(errors.Wrap(err, "Unable to cancel statement on spark."))
+	}
+
+	return nil
+}
+
 func (j *SparkJobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
@@ -2,9 +2,11 @@ package job
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/gob"
 	"encoding/json"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
@@ -12,6 +14,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/check"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/connector"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
+	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/auth"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
 	"github.com/dropbox/godropbox/errors"
@@ -25,6 +28,7 @@ type JobType string
 const (
 	WorkflowRetentionName = "workflowretentionjob"
 	DynamicTeardownName   = "dynamicteardownjob"
+	StorageGCName         = "storagegcjob"
 )
 
 type SerializationType string
@@ -48,6 +52,7 @@ const (
 	WorkflowRetentionType     JobType = "workflow_retention"
 	CompileAirflowJobType     JobType = "compile_airflow"
 	DynamicTeardownType       JobType = "dynamic_teardown"
+	StorageGCType             JobType = "storage_gc"
 )
 
 // `ExecutorConfiguration` represents the configuration variables that are
@@ -103,6 +108,23 @@ func (wrs *WorkflowRetentionSpec) GetStorageConfig() (*shared.StorageConfig, err
 	return nil, errors.New("WorkflowRetention job specs don't have a storage config.")
 }
 
+type StorageGCSpec struct {
+	BaseSpec
+	ExecutorConfig *ExecutorConfiguration
+	// GracePeriod is how long an orphaned storage object is kept before it is deleted.
+	GracePeriod time.Duration
+	// DryRun only reports the orphaned storage objects without deleting them.
+	DryRun bool
+}
+
+func (gcs *StorageGCSpec) HasStorageConfig() bool {
+	return false
+}
+
+func (gcs *StorageGCSpec) GetStorageConfig() (*shared.StorageConfig, error) {
+	return nil, errors.New("StorageGC job specs don't have a storage config.")
+}
+
 type WorkflowSpec struct {
 	BaseSpec
 	WorkflowId     string                 `json:"workflow_id" yaml:"workflowId"`
@@ -111,6 +133,11 @@ type WorkflowSpec struct {
 	AqPath         string                 `json:"aq_path" yaml:"aqPath"`
 	DisplayIP      string                 `json:"display_ip" yaml:"displayIP"`
 	ExecutorConfig *ExecutorConfiguration
+
+	// If set, the job resumes the failed workflow run with this ID instead of starting a new run.
+	ResumedDAGResultId string `json:"resumed_dag_result_id" yaml:"resumedDagResultId"`
+	// The run request that launched the job, which records the workflow run that the job creates.
+	RunRequestId string `json:"run_request_id" yaml:"runRequestId"`
 }
 
 func (ws *WorkflowSpec) HasStorageConfig() bool {
@@ -243,6 +270,10 @@ func (*WorkflowRetentionSpec) Type() JobType {
 	return WorkflowRetentionType
 }
 
+func (*StorageGCSpec) Type() JobType {
+	return StorageGCType
+}
+
 func (*WorkflowSpec) Type() JobType {
 	return WorkflowJobType
 }
@@ -336,6 +367,28 @@ func NewWorkflowRetentionJobSpec(
 	}
 }
 
+// NewStorageGCJobSpec constructs a Spec for a StorageGCJob.
+func NewStorageGCJobSpec(
+	database *database.DatabaseConfig,
+	jobManager Config,
+	gracePeriod time.Duration,
+	dryRun bool,
+) Spec {
+	return &StorageGCSpec{
+		BaseSpec: BaseSpec{
+			Type: StorageGCType,
+			Name: StorageGCName,
+		},
+
+		ExecutorConfig: &ExecutorConfiguration{
+			Database:   database,
+			JobManager: jobManager,
+		},
+		GracePeriod: gracePeriod,
+		DryRun:      dryRun,
+	}
+}
+
 // NewWorkflowSpec constructs a Spec for a WorkflowJob.
 func NewWorkflowSpec(
 	name string,
@@ -346,6 +399,8 @@ func NewWorkflowSpec(
 	aqPath string,
 	displayIP string,
 	parameters map[string]param.Param,
+	resumedDAGResultId string,
+	runRequestId string,
 ) Spec {
 	return &WorkflowSpec{
 		BaseSpec: BaseSpec{
@@ -361,6 +416,8 @@ func NewWorkflowSpec(
 			Database:   database,
 			JobManager: jobManager,
 		},
+		ResumedDAGResultId: resumedDAGResultId,
+		RunRequestId:       runRequestId,
 	}
 }
 
@@ -571,6 +628,11 @@ func EncodeSpec(spec Spec, serializationType SerializationType) (string, error)
 	var specData []byte
 	var err error
 	if serializationType == JsonSerializationType {
+		// Only Python jobs are JSON serialized. Go jobs read the storage master key from the server config.
+		if err := setStorageDataKeys(spec); err != nil {
+			return "", err
+		}
+
 		specData, err = json.Marshal(spec)
 		if err != nil {
 			return "", err
@@ -589,6 +651,35 @@ func EncodeSpec(spec Spec, serializationType SerializationType) (string, error)
 	return "", errors.Newf("Unsupported serialization type %s.", serializationType)
 }
 
+// setStorageDataKeys sets the data keys of the encrypted storage of spec, since the
+// Python job has no access to the storage master key.
+func setStorageDataKeys(spec Spec) error {
+	if !spec.HasStorageConfig() {
+		return nil
+	}
+
+	storageConfig, err := spec.GetStorageConfig()
+	if err != nil {
+		return err
+	}
+
+	if storageConfig.Encryption == nil || storageConfig.Encryption.WriteKeyID != "" {
+		return nil
+	}
+
+	dataKeys, writeKeyID, err := storage.JobDataKeys(context.Background(), storageConfig)
+	if err != nil {
+		return errors.Wrap(err, "Unable to read the data keys of the storage.")
+	}
+
+	// The encryption config is copied, since it may be shared with the storage config of the DAG.
+	encryption := *storageConfig.Encryption
+	encryption.DataKeys = dataKeys
+	encryption.WriteKeyID = writeKeyID
+	storageConfig.Encryption = &encryption
+	return nil
+}
+
 func DecodeSpec(specData string, serializationType SerializationType) (Spec, error) {
 	specBytes, err := base64.StdEncoding.DecodeString(specData)
 	if err != nil {
@@ -18,6 +18,7 @@ const (
 	DAGResultStatus    = "status"
 	DAGResultCreatedAt = "created_at"
 	DAGResultExecState = "execution_state"
+	DAGResultPinned    = "pinned"
 )
 
 // A DAGResult maps to the workflow_dag_result table.
@@ -28,6 +29,8 @@ type DAGResult struct {
 	// TODO ENG-1701: deprecate `CreatedAt` field.
 	CreatedAt time.Time                 `db:"created_at" json:"created_at"`
 	ExecState shared.NullExecutionState `db:"execution_state" json:"execution_state"`
+	// Pinned DAGResults are never removed by the workflow's retention policy.
+	Pinned bool `db:"pinned" json:"pinned"`
 }
 
 // DAGResultCols returns a comma-separated string of all DAGResult columns.
@@ -53,5 +56,6 @@ func AllDAGResultCols() []string {
 		DAGResultStatus,
 		DAGResultCreatedAt,
 		DAGResultExecState,
+		DAGResultPinned,
 	}
 }
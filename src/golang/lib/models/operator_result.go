@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
@@ -38,6 +39,17 @@ func OperatorResultCols() string {
 	return strings.Join(allOperatorResultCols(), ",")
 }
 
+// OperatorResultColsWithPrefix returns a comma-separated string of all
+// OperatorResult columns prefixed by the table name.
+func OperatorResultColsWithPrefix() string {
+	cols := allOperatorResultCols()
+	for i, col := range cols {
+		cols[i] = fmt.Sprintf("%s.%s", OperatorResultTable, col)
+	}
+
+	return strings.Join(cols, ",")
+}
+
 func allOperatorResultCols() []string {
 	return []string{
 		OperatorResultID,
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	RunRequestTable = "run_request"
+
+	// RunRequest table column names
+	RunRequestID         = "id"
+	RunRequestWorkflowID = "workflow_id"
+	RunRequestStatus     = "status"
+	RunRequestParameters = "parameters"
+	// If set, the run resumes the failed or canceled run with this ID.
+	RunRequestResumedDAGResultID = "resumed_dag_result_id"
+	// Explains why the request has not started running yet.
+	RunRequestWaitReason = "wait_reason"
+	// The worker that holds the lease of the request.
+	RunRequestClaimedBy      = "claimed_by"
+	RunRequestLeaseExpiresAt = "lease_expires_at"
+	// The number of times the request was claimed.
+	RunRequestAttempts  = "attempts"
+	RunRequestCreatedAt = "created_at"
+	// The run that the executor of the request created.
+	RunRequestDAGResultID = "dag_result_id"
+)
+
+// A RunRequest maps to the run_request table.
+type RunRequest struct {
+	ID                 uuid.UUID               `db:"id" json:"id"`
+	WorkflowID         uuid.UUID               `db:"workflow_id" json:"workflow_id"`
+	Status             shared.RunRequestStatus `db:"status" json:"status"`
+	Parameters         shared.RunParameters    `db:"parameters" json:"-"`
+	ResumedDAGResultID utils.NullUUID          `db:"resumed_dag_result_id" json:"-"`
+	WaitReason         string                  `db:"wait_reason" json:"wait_reason"`
+	ClaimedBy          string                  `db:"claimed_by" json:"claimed_by"`
+	LeaseExpiresAt     utils.NullTime          `db:"lease_expires_at" json:"-"`
+	Attempts           int                     `db:"attempts" json:"attempts"`
+	CreatedAt          time.Time               `db:"created_at" json:"created_at"`
+	DAGResultID        utils.NullUUID          `db:"dag_result_id" json:"-"`
+}
+
+// RunRequestCols returns a comma-separated string of all RunRequest columns.
+func RunRequestCols() string {
+	return strings.Join(allRunRequestCols(), ",")
+}
+
+func allRunRequestCols() []string {
+	return []string{
+		RunRequestID,
+		RunRequestWorkflowID,
+		RunRequestStatus,
+		RunRequestParameters,
+		RunRequestResumedDAGResultID,
+		RunRequestWaitReason,
+		RunRequestClaimedBy,
+		RunRequestLeaseExpiresAt,
+		RunRequestAttempts,
+		RunRequestCreatedAt,
+		RunRequestDAGResultID,
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	SchedulerLeaseTable = "scheduler_lease"
+
+	// SchedulerLease table column names
+	SchedulerLeaseName = "name"
+	// The server replica that holds the lease.
+	SchedulerLeaseHolder    = "holder"
+	SchedulerLeaseExpiresAt = "expires_at"
+)
+
+// A SchedulerLease maps to the scheduler_lease table.
+// The server replica that holds the lease is the leader that runs the cron jobs.
+type SchedulerLease struct {
+	Name      string    `db:"name" json:"name"`
+	Holder    string    `db:"holder" json:"holder"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
+
+// SchedulerLeaseCols returns a comma-separated string of all SchedulerLease columns.
+func SchedulerLeaseCols() string {
+	return strings.Join(allSchedulerLeaseCols(), ",")
+}
+
+func allSchedulerLeaseCols() []string {
+	return []string{
+		SchedulerLeaseName,
+		SchedulerLeaseHolder,
+		SchedulerLeaseExpiresAt,
+	}
+}
@@ -14,6 +14,8 @@ type ArtifactResultMetadata struct {
 	SerializationType ArtifactSerializationType `json:"serialization_type,omitempty"`
 	ArtifactType      ArtifactType              `json:"artifact_type,omitempty"`
 	PythonType        string                    `json:"python_type,omitempty"`
+	// The hex-encoded SHA-256 checksum of the serialized content of the artifact.
+	Checksum string `json:"checksum,omitempty"`
 }
 
 type NullArtifactResultMetadata struct {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/utils"
+	"github.com/google/uuid"
 )
 
 type ExecutionState struct {
@@ -12,10 +13,21 @@ type ExecutionState struct {
 	Status   ExecutionStatus `json:"status"`
 
 	// These two failure fields are only set if status == Failed.
+	// Error is also set if status == Skipped, to explain why the run was skipped.
 	FailureType *FailureType `json:"failure_type"`
 	Error       *Error       `json:"error"`
 
 	Timestamps *ExecutionTimestamps `json:"timestamps"`
+
+	// Attempts are the execution states of previous failed attempts of an operator
+	// that was retried. This is only set for operators with a retry policy.
+	Attempts []ExecutionState `json:"attempts,omitempty"`
+
+	// ResultCacheKey identifies the code, parameters and inputs of an operator with a cache
+	// policy in this run. CachedFromDAGResultID is set if the operator did not run, but reused
+	// the results of the run with this ID, which had the same ResultCacheKey.
+	ResultCacheKey        string     `json:"result_cache_key,omitempty"`
+	CachedFromDAGResultID *uuid.UUID `json:"cached_from_dag_result_id,omitempty"`
 }
 
 func (e ExecutionState) Terminated() bool {
@@ -10,6 +10,8 @@ const (
 		githubIssueLink + " . " +
 		"We will get back to you as soon as we can."
 	TipUnknownInternalError = "Sorry, we've run into an unexpected error! " + TipCreateBugReport
+	TipOperatorTimeout      = "The operator was stopped since it exceeded its timeout. " +
+		"Please increase the timeout of the operator, or make it run faster."
 )
 
 type ExecutionStatus string
@@ -29,6 +31,9 @@ const (
 	// Caller should consider 'deleted' as success for error handling,
 	// but should not expect any non-metadata content to be available.
 	DeletedExecutionStatus ExecutionStatus = "deleted"
+	// 'skipped' refers to a scheduled workflow run that was not executed,
+	// e.g. because it fell into a blackout window of the schedule.
+	SkippedExecutionStatus ExecutionStatus = "skipped"
 	UnknownExecutionStatus ExecutionStatus = "unknown"
 )
 
@@ -0,0 +1,55 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/dropbox/godropbox/errors"
+)
+
+// CacheConfig specifies how long the results of an operator can be reused by later runs of
+// the workflow, as long as the operator's code, parameters and inputs have not changed.
+type CacheConfig struct {
+	TTLMillisec int64 `json:"ttl_ms"`
+}
+
+func (c *CacheConfig) Validate() error {
+	if c.TTLMillisec <= 0 {
+		return errors.Newf("Cache TTL must be positive, but got %d ms.", c.TTLMillisec)
+	}
+
+	return nil
+}
+
+func (c *CacheConfig) TTL() time.Duration {
+	return time.Duration(c.TTLMillisec) * time.Millisecond
+}
+
+// ResultCacheSpec returns a copy of the spec with only the fields that determine the results
+// of the operator. It leaves out where and how the operator runs, e.g. its engine, resources,
+// retries, timeout and cache policy, as well as the storage path of its function, which changes
+// every time the workflow is published even if the code does not.
+func (s Spec) ResultCacheSpec() Spec {
+	spec := s.spec
+	spec.Resources = nil
+	spec.Retry = nil
+	spec.TimeoutMillisec = 0
+	spec.Cache = nil
+	spec.EngineConfig = nil
+
+	switch {
+	case spec.Function != nil:
+		fn := *spec.Function
+		fn.StoragePath = ""
+		spec.Function = &fn
+	case spec.Check != nil:
+		c := *spec.Check
+		c.Function.StoragePath = ""
+		spec.Check = &c
+	case spec.Metric != nil:
+		m := *spec.Metric
+		m.Function.StoragePath = ""
+		spec.Metric = &m
+	}
+
+	return Spec{spec: spec}
+}
@@ -0,0 +1,59 @@
+package operator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/function"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheValidate(t *testing.T) {
+	require.Nil(t, (&CacheConfig{TTLMillisec: 1000}).Validate())
+	require.NotNil(t, (&CacheConfig{TTLMillisec: 0}).Validate())
+	require.NotNil(t, (&CacheConfig{TTLMillisec: -1}).Validate())
+}
+
+func TestSpecCacheJSON(t *testing.T) {
+	rawSpec := `{"type": "extract", "extract": {"service": "Postgres", "parameters": {"query": "SELECT 1;"}}, "cache": {"ttl_ms": 3600000}}`
+
+	var spec Spec
+	require.Nil(t, json.Unmarshal([]byte(rawSpec), &spec))
+	require.Equal(t, &CacheConfig{TTLMillisec: 3600000}, spec.Cache())
+	require.Equal(t, time.Hour, spec.Cache().TTL())
+}
+
+func TestResultCacheSpec(t *testing.T) {
+	spec := NewSpecFromFunction(function.Function{
+		Type:        function.FileFunctionType,
+		Language:    "Python",
+		StoragePath: "operator/code-1",
+		CustomArgs:  "{}",
+	})
+	spec.SetCache(&CacheConfig{TTLMillisec: 1000}).
+		SetTimeout(time.Minute).
+		SetRetry(&RetryConfig{MaxAttempts: 2}).
+		SetEngineConfig(&shared.EngineConfig{Type: shared.AqueductEngineType})
+
+	republished := NewSpecFromFunction(function.Function{
+		Type:        function.FileFunctionType,
+		Language:    "Python",
+		StoragePath: "operator/code-2",
+		CustomArgs:  "{}",
+	})
+	republished.SetCache(&CacheConfig{TTLMillisec: 5000})
+
+	require.Equal(t, spec.ResultCacheSpec(), republished.ResultCacheSpec())
+	require.Equal(t, &CacheConfig{TTLMillisec: 1000}, spec.Cache())
+	require.Equal(t, "operator/code-1", spec.Function().StoragePath)
+
+	changedArgs := NewSpecFromFunction(function.Function{
+		Type:        function.FileFunctionType,
+		Language:    "Python",
+		StoragePath: "operator/code-1",
+		CustomArgs:  `{"a": 1}`,
+	})
+	require.NotEqual(t, spec.ResultCacheSpec(), changedArgs.ResultCacheSpec())
+}
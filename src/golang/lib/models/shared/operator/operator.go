@@ -3,6 +3,7 @@ package operator
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/check"
@@ -71,6 +72,16 @@ type specUnion struct {
 	Resources *ComputeResourcesConfig `json:"resources,omitempty"`
 	Image     *ImageConfig            `json:"image,omitempty"`
 
+	// Retry can be set for any operator to rerun it when it fails.
+	Retry *RetryConfig `json:"retry,omitempty"`
+
+	// TimeoutMillisec can be set for any operator to stop it once it has run for this long.
+	TimeoutMillisec int64 `json:"timeout_ms,omitempty"`
+
+	// Cache can be set for operators that are not loads to reuse their results
+	// across runs of the workflow.
+	Cache *CacheConfig `json:"cache,omitempty"`
+
 	EngineConfig *shared.EngineConfig `json:"engine_config,omitempty"`
 }
 
@@ -133,6 +144,20 @@ func (s Spec) Image() *ImageConfig {
 	return s.spec.Image
 }
 
+func (s Spec) Retry() *RetryConfig {
+	return s.spec.Retry
+}
+
+// Timeout returns how long the operator can run before it is stopped.
+// A zero duration means the operator does not have a timeout.
+func (s Spec) Timeout() time.Duration {
+	return time.Duration(s.spec.TimeoutMillisec) * time.Millisecond
+}
+
+func (s Spec) Cache() *CacheConfig {
+	return s.spec.Cache
+}
+
 func (s Spec) EngineConfig() *shared.EngineConfig {
 	return s.spec.EngineConfig
 }
@@ -284,3 +309,18 @@ func (s *Spec) SetEngineConfig(engineConfig *shared.EngineConfig) *Spec {
 	s.spec.EngineConfig = engineConfig
 	return s
 }
+
+func (s *Spec) SetRetry(retry *RetryConfig) *Spec {
+	s.spec.Retry = retry
+	return s
+}
+
+func (s *Spec) SetTimeout(timeout time.Duration) *Spec {
+	s.spec.TimeoutMillisec = timeout.Milliseconds()
+	return s
+}
+
+func (s *Spec) SetCache(cache *CacheConfig) *Spec {
+	s.spec.Cache = cache
+	return s
+}
@@ -0,0 +1,91 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+)
+
+const defaultBackoffMultiplier = 2.0
+
+// RetryConfig specifies how a failed operator is retried.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of times the operator is run, including the first attempt.
+	MaxAttempts int `json:"max_attempts"`
+
+	// InitialBackoffMillisec is the delay before the first retry. Each following delay
+	// is multiplied by BackoffMultiplier, but never exceeds MaxBackoffMillisec if it is set.
+	InitialBackoffMillisec int64   `json:"initial_backoff_ms"`
+	BackoffMultiplier      float64 `json:"backoff_multiplier,omitempty"`
+	MaxBackoffMillisec     int64   `json:"max_backoff_ms,omitempty"`
+
+	// FailureTypes are the failure types that are retried.
+	// If none are specified, only system failures are retried.
+	FailureTypes []shared.FailureType `json:"failure_types,omitempty"`
+}
+
+func (r *RetryConfig) Validate() error {
+	if r.MaxAttempts < 1 {
+		return errors.Newf("Retry max attempts must be at least 1, but got %d.", r.MaxAttempts)
+	}
+
+	if r.InitialBackoffMillisec < 0 || r.MaxBackoffMillisec < 0 {
+		return errors.New("Retry backoff cannot be negative.")
+	}
+
+	if r.BackoffMultiplier != 0 && r.BackoffMultiplier < 1 {
+		return errors.Newf("Retry backoff multiplier must be at least 1, but got %v.", r.BackoffMultiplier)
+	}
+
+	for _, failureType := range r.FailureTypes {
+		if failureType != shared.SystemFailure && failureType != shared.UserFatalFailure {
+			return errors.Newf("Retrying failure type %d is not supported.", failureType)
+		}
+	}
+
+	return nil
+}
+
+// ShouldRetry returns whether the operator should be run again after its `attempt`-th
+// attempt (starting from 1) failed with failureType.
+func (r *RetryConfig) ShouldRetry(attempt int, failureType shared.FailureType) bool {
+	if attempt >= r.MaxAttempts {
+		return false
+	}
+
+	if len(r.FailureTypes) == 0 {
+		return failureType == shared.SystemFailure
+	}
+
+	for _, retriedType := range r.FailureTypes {
+		if retriedType == failureType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Backoff returns how long to wait before retrying the operator after its `attempt`-th
+// attempt (starting from 1) failed.
+func (r *RetryConfig) Backoff(attempt int) time.Duration {
+	multiplier := r.BackoffMultiplier
+	if multiplier == 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	backoff := float64(r.InitialBackoffMillisec)
+	for i := 1; i < attempt; i++ {
+		backoff *= multiplier
+		if r.MaxBackoffMillisec > 0 && backoff >= float64(r.MaxBackoffMillisec) {
+			break
+		}
+	}
+
+	if r.MaxBackoffMillisec > 0 && backoff > float64(r.MaxBackoffMillisec) {
+		backoff = float64(r.MaxBackoffMillisec)
+	}
+
+	return time.Duration(backoff) * time.Millisecond
+}
@@ -0,0 +1,69 @@
+package operator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryShouldRetry(t *testing.T) {
+	retry := &RetryConfig{MaxAttempts: 3}
+
+	// Only system failures are retried by default.
+	require.True(t, retry.ShouldRetry(1, shared.SystemFailure))
+	require.False(t, retry.ShouldRetry(1, shared.UserFatalFailure))
+
+	// The operator is not retried once it reached its max attempts.
+	require.True(t, retry.ShouldRetry(2, shared.SystemFailure))
+	require.False(t, retry.ShouldRetry(3, shared.SystemFailure))
+
+	retry.FailureTypes = []shared.FailureType{shared.UserFatalFailure}
+	require.False(t, retry.ShouldRetry(1, shared.SystemFailure))
+	require.True(t, retry.ShouldRetry(1, shared.UserFatalFailure))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	type test struct {
+		retry    RetryConfig
+		attempt  int
+		expected time.Duration
+	}
+
+	tests := []test{
+		{RetryConfig{InitialBackoffMillisec: 100}, 1, 100 * time.Millisecond},
+		{RetryConfig{InitialBackoffMillisec: 100}, 3, 400 * time.Millisecond},
+		{RetryConfig{InitialBackoffMillisec: 100, BackoffMultiplier: 3}, 3, 900 * time.Millisecond},
+		{RetryConfig{InitialBackoffMillisec: 100, MaxBackoffMillisec: 250}, 3, 250 * time.Millisecond},
+		{RetryConfig{InitialBackoffMillisec: 100, MaxBackoffMillisec: 250}, 100, 250 * time.Millisecond},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.expected, tc.retry.Backoff(tc.attempt))
+	}
+}
+
+func TestRetryValidate(t *testing.T) {
+	require.Nil(t, (&RetryConfig{MaxAttempts: 1}).Validate())
+	require.NotNil(t, (&RetryConfig{MaxAttempts: 0}).Validate())
+	require.NotNil(t, (&RetryConfig{MaxAttempts: 2, InitialBackoffMillisec: -1}).Validate())
+	require.NotNil(t, (&RetryConfig{MaxAttempts: 2, BackoffMultiplier: 0.5}).Validate())
+	require.NotNil(t, (&RetryConfig{
+		MaxAttempts:  2,
+		FailureTypes: []shared.FailureType{shared.UserNonFatalFailure},
+	}).Validate())
+}
+
+func TestSpecRetryJSON(t *testing.T) {
+	rawSpec := `{"type": "extract", "extract": {"service": "Postgres", "parameters": {"query": "SELECT 1;"}}, "retry": {"max_attempts": 3, "initial_backoff_ms": 1000, "failure_types": [1, 2]}}`
+
+	var spec Spec
+	require.Nil(t, json.Unmarshal([]byte(rawSpec), &spec))
+	require.Equal(t, &RetryConfig{
+		MaxAttempts:            3,
+		InitialBackoffMillisec: 1000,
+		FailureTypes:           []shared.FailureType{shared.SystemFailure, shared.UserFatalFailure},
+	}, spec.Retry())
+}
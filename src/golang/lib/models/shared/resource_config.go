@@ -28,6 +28,11 @@ type S3ResourceConfig struct {
 	ConfigFileContent string       `json:"config_file_content"`
 	ConfigFileProfile string       `json:"config_file_profile"`
 	UseAsStorage      ConfigBool   `json:"use_as_storage"`
+
+	// These override the AWS endpoint, to connect to S3-compatible services like MinIO.
+	EndpointURL    string     `json:"endpoint_url"`
+	ForcePathStyle ConfigBool `json:"force_path_style"`
+	SkipTLSVerify  ConfigBool `json:"skip_tls_verify"`
 }
 
 // AirflowResourceConfig contains the fields for connecting an Airflow resource.
@@ -39,6 +44,12 @@ type GCSResourceConfig struct {
 	UseAsStorage ConfigBool `json:"use_as_storage"`
 }
 
+// AzureBlobResourceConfig contains the fields for connecting an Azure Blob Storage resource.
+type AzureBlobResourceConfig struct {
+	AzureConfig
+	UseAsStorage ConfigBool `json:"use_as_storage"`
+}
+
 type K8sClusterStatusType string
 
 const (
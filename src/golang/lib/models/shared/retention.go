@@ -2,14 +2,45 @@ package shared
 
 import (
 	"database/sql/driver"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/utils"
+	"github.com/dropbox/godropbox/errors"
 )
 
-// RetentionPolicy specifies that only KLatestRuns should be saved for each
-// workflow.
+// RetentionPolicy specifies which runs of a workflow are saved. Runs that are
+// pinned or still in progress are always saved.
 type RetentionPolicy struct {
+	// KLatestRuns is the number of most recent runs that are saved. -1 saves all runs.
 	KLatestRuns int `json:"k_latest_runs"`
+
+	// MaxAgeMillisec, if set, also removes runs that were created longer ago than this.
+	MaxAgeMillisec int64 `json:"max_age_ms,omitempty"`
+
+	// KLatestSuccessfulRuns, if set, is the number of most recent successful runs
+	// that are saved, even if the options above would remove them.
+	KLatestSuccessfulRuns int `json:"k_latest_successful_runs,omitempty"`
+}
+
+func (r *RetentionPolicy) Validate() error {
+	if r.KLatestRuns < -1 {
+		return errors.Newf("Retention policy k latest runs must be at least -1, but got %d.", r.KLatestRuns)
+	}
+
+	if r.MaxAgeMillisec < 0 {
+		return errors.New("Retention policy max age cannot be negative.")
+	}
+
+	if r.KLatestSuccessfulRuns < 0 {
+		return errors.New("Retention policy k latest successful runs cannot be negative.")
+	}
+
+	return nil
+}
+
+// MaxAge returns the max age of the runs that are saved, or 0 if there is none.
+func (r *RetentionPolicy) MaxAge() time.Duration {
+	return time.Duration(r.MaxAgeMillisec) * time.Millisecond
 }
 
 func (r *RetentionPolicy) Value() (driver.Value, error) {
@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"database/sql/driver"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
+)
+
+// RunRequestStatus is the state of a request to run a workflow in the run queue.
+type RunRequestStatus string
+
+const (
+	// QueuedRunRequestStatus means the request is waiting for a worker to claim it.
+	QueuedRunRequestStatus RunRequestStatus = "queued"
+	// ClaimedRunRequestStatus means a worker holds the lease of the request, but has not
+	// launched the run yet.
+	ClaimedRunRequestStatus RunRequestStatus = "claimed"
+	// RunningRunRequestStatus means the run was launched and the worker is waiting for it to finish.
+	RunningRunRequestStatus RunRequestStatus = "running"
+	DoneRunRequestStatus    RunRequestStatus = "done"
+)
+
+// RunParameters are the parameters a workflow run is triggered with.
+type RunParameters map[string]param.Param
+
+func (p *RunParameters) Value() (driver.Value, error) {
+	return utils.ValueJSONB(*p)
+}
+
+func (p *RunParameters) Scan(value interface{}) error {
+	return utils.ScanJSONB(value, p)
+}
@@ -2,6 +2,9 @@ package shared
 
 import (
 	"database/sql/driver"
+	"time"
+	// The timezone database is embedded, since the host may not have one.
+	_ "time/tzdata"
 
 	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/google/uuid"
@@ -18,6 +21,49 @@ const (
 	PeriodicUpdateTrigger  UpdateTrigger = "periodic"
 	AirflowUpdateTrigger   UpdateTrigger = "airflow"
 	CascadingUpdateTrigger UpdateTrigger = "cascade"
+	WebhookUpdateTrigger   UpdateTrigger = "webhook"
+)
+
+// CascadeMode specifies when a workflow with a CascadingUpdateTrigger
+// and multiple source workflows is triggered.
+type CascadeMode string
+
+const (
+	// AnyCascadeMode triggers the workflow whenever one of its sources succeeds.
+	AnyCascadeMode CascadeMode = "any"
+	// AllCascadeMode triggers the workflow once every one of its sources
+	// has succeeded since its last run, and since its sources last triggered it.
+	AllCascadeMode CascadeMode = "all"
+)
+
+// CatchUpPolicy specifies which of the cron runs of a workflow that were
+// missed, e.g. while the server was down, are run once the server is up.
+type CatchUpPolicy string
+
+const (
+	// NoneCatchUpPolicy does not run any missed runs.
+	NoneCatchUpPolicy CatchUpPolicy = "none"
+	// LatestCatchUpPolicy only runs the latest missed run.
+	LatestCatchUpPolicy CatchUpPolicy = "latest"
+	// AllCatchUpPolicy runs every missed run, each with the time it was
+	// scheduled at as a built-in parameter.
+	AllCatchUpPolicy CatchUpPolicy = "all"
+)
+
+// OverlapPolicy specifies what happens to a new run of a workflow
+// while a previous run of the workflow is still executing.
+type OverlapPolicy string
+
+const (
+	// AllowOverlapPolicy executes the new run alongside the previous run.
+	AllowOverlapPolicy OverlapPolicy = "allow"
+	// SkipOverlapPolicy does not execute the new run, which is recorded
+	// as a DAG result with SkippedExecutionStatus.
+	SkipOverlapPolicy OverlapPolicy = "skip"
+	// QueueOverlapPolicy executes the new run once the previous run finishes.
+	QueueOverlapPolicy OverlapPolicy = "queue"
+	// CancelPreviousOverlapPolicy cancels the previous run and executes the new run.
+	CancelPreviousOverlapPolicy OverlapPolicy = "cancel_previous"
 )
 
 // Schedule defines the frequency for running a workflow.
@@ -27,8 +73,95 @@ type Schedule struct {
 	DisableManualTrigger bool          `json:"disable_manual_trigger"`
 	Paused               bool          `json:"paused"`
 	// SourceID is the source Workflow that triggers this
-	// Workflow upon a successful run
+	// Workflow upon a successful run.
+	// It is only set for Workflows created before SourceIDs existed.
 	SourceID uuid.UUID `json:"source_id"`
+	// SourceIDs are the source Workflows that trigger this Workflow upon
+	// a successful run, and CascadeMode specifies whether any or all of them
+	// must succeed. An empty CascadeMode is the same as AnyCascadeMode.
+	SourceIDs   []uuid.UUID `json:"source_ids,omitempty"`
+	CascadeMode CascadeMode `json:"cascade_mode,omitempty"`
+	// Webhook is only set for a WebhookUpdateTrigger. It specifies how
+	// webhook requests are mapped to the parameters of the run.
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+	// Timezone is the IANA timezone that CronSchedule is evaluated in, e.g. America/New_York,
+	// so that the time of day of the cron fires does not change with daylight saving time.
+	// An empty Timezone is the same as UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// BlackoutWindows are periods, such as holidays or maintenance, during which
+	// cron fires are skipped. Each skipped fire is recorded as a DAG result with
+	// SkippedExecutionStatus.
+	BlackoutWindows []BlackoutWindow `json:"blackout_windows,omitempty"`
+	// CatchUpPolicy specifies which missed cron runs are run once the server is up.
+	// An empty CatchUpPolicy is the same as LatestCatchUpPolicy.
+	CatchUpPolicy CatchUpPolicy `json:"catch_up_policy,omitempty"`
+	// OverlapPolicy applies to every run of the workflow, regardless of how it was triggered.
+	// An empty OverlapPolicy is the same as AllowOverlapPolicy.
+	OverlapPolicy OverlapPolicy `json:"overlap_policy,omitempty"`
+}
+
+// BlackoutWindow is the period from Start (inclusive) to End (exclusive).
+type BlackoutWindow struct {
+	Name  string    `json:"name,omitempty"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// WebhookConfig specifies the parameters of a workflow run triggered by a webhook.
+type WebhookConfig struct {
+	// ParamFields maps parameter names to the dot-separated path
+	// of a field in the JSON body of the webhook request.
+	ParamFields map[string]string `json:"param_fields"`
+}
+
+// Sources returns the IDs of the source Workflows of a CascadingUpdateTrigger.
+func (s *Schedule) Sources() []uuid.UUID {
+	if len(s.SourceIDs) > 0 {
+		return s.SourceIDs
+	}
+
+	if s.SourceID != uuid.Nil {
+		return []uuid.UUID{s.SourceID}
+	}
+
+	return nil
+}
+
+// RemoveSource removes sourceID from the source Workflows of the schedule.
+func (s *Schedule) RemoveSource(sourceID uuid.UUID) {
+	sources := make([]uuid.UUID, 0, len(s.Sources()))
+	for _, id := range s.Sources() {
+		if id != sourceID {
+			sources = append(sources, id)
+		}
+	}
+
+	s.SourceID = uuid.Nil
+	s.SourceIDs = sources
+	if len(sources) == 0 {
+		s.SourceIDs = nil
+	}
+}
+
+// Location returns the location of the schedule's Timezone.
+func (s *Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	return time.LoadLocation(s.Timezone)
+}
+
+// BlackoutAt returns the blackout window that contains t, or nil if there is none.
+func (s *Schedule) BlackoutAt(t time.Time) *BlackoutWindow {
+	for i := range s.BlackoutWindows {
+		window := &s.BlackoutWindows[i]
+		if !t.Before(window.Start) && t.Before(window.End) {
+			return window
+		}
+	}
+
+	return nil
 }
 
 func (s *Schedule) Value() (driver.Value, error) {
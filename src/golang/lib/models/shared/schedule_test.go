@@ -0,0 +1,42 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleLocation(t *testing.T) {
+	schedule := Schedule{}
+	location, err := schedule.Location()
+	require.Nil(t, err)
+	require.Equal(t, time.UTC, location)
+
+	schedule.Timezone = "America/New_York"
+	location, err = schedule.Location()
+	require.Nil(t, err)
+	require.Equal(t, "America/New_York", location.String())
+
+	schedule.Timezone = "America/Atlantis"
+	_, err = schedule.Location()
+	require.NotNil(t, err)
+}
+
+func TestScheduleBlackoutAt(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.Nil(t, err)
+
+	start := time.Date(2022, time.December, 25, 0, 0, 0, 0, newYork)
+	schedule := Schedule{
+		BlackoutWindows: []BlackoutWindow{
+			{Name: "christmas", Start: start, End: start.Add(24 * time.Hour)},
+		},
+	}
+
+	require.Nil(t, schedule.BlackoutAt(start.Add(-time.Second)))
+	require.Equal(t, "christmas", schedule.BlackoutAt(start).Name)
+	// 9am in New York is 2pm in UTC.
+	require.Equal(t, "christmas", schedule.BlackoutAt(time.Date(2022, time.December, 25, 14, 0, 0, 0, time.UTC)).Name)
+	require.Nil(t, schedule.BlackoutAt(start.Add(24*time.Hour)))
+}
@@ -24,6 +24,7 @@ const (
 	Airflow      Service = "Airflow"
 	Kubernetes   Service = "Kubernetes"
 	GCS          Service = "GCS"
+	AzureBlob    Service = "Azure Blob Storage"
 	Athena       Service = "Athena"
 	Lambda       Service = "Lambda"
 	MongoDB      Service = "MongoDB"
@@ -110,6 +111,11 @@ var ServiceToEngineConfigField map[Service]string = map[Service]string{
 	Databricks: "databricks_config",
 }
 
+// IsStorageResource returns whether the specified service can be used as the storage layer.
+func IsStorageResource(service Service) bool {
+	return service == S3 || service == GCS || service == AzureBlob
+}
+
 // ParseService decodes s into a Service or an error.
 func ParseService(s string) (Service, error) {
 	svc := Service(s)
@@ -130,6 +136,7 @@ func ParseService(s string) (Service, error) {
 		Airflow,
 		Kubernetes,
 		GCS,
+		AzureBlob,
 		Lambda,
 		MongoDB,
 		Conda,
@@ -11,23 +11,74 @@ import (
 type StorageType string
 
 const (
-	S3StorageType   StorageType = "s3"
-	FileStorageType StorageType = "file"
-	GCSStorageType  StorageType = "gcs"
+	S3StorageType    StorageType = "s3"
+	FileStorageType  StorageType = "file"
+	GCSStorageType   StorageType = "gcs"
+	AzureStorageType StorageType = "azure"
 )
 
 type StorageConfig struct {
-	Type       StorageType `yaml:"type" json:"type"`
-	S3Config   *S3Config   `yaml:"s3Config" json:"s3_config,omitempty"`
-	FileConfig *FileConfig `yaml:"fileConfig" json:"file_config,omitempty"`
-	GCSConfig  *GCSConfig  `yaml:"gcsConfig"  json:"gcs_config,omitempty"`
+	Type        StorageType  `yaml:"type" json:"type"`
+	S3Config    *S3Config    `yaml:"s3Config" json:"s3_config,omitempty"`
+	FileConfig  *FileConfig  `yaml:"fileConfig" json:"file_config,omitempty"`
+	GCSConfig   *GCSConfig   `yaml:"gcsConfig"  json:"gcs_config,omitempty"`
+	AzureConfig *AzureConfig `yaml:"azureConfig" json:"azure_config,omitempty"`
+
+	// If set, objects are compressed and checksummed before they are written,
+	// and verified on every read.
+	Compression *CompressionConfig `yaml:"compression" json:"compression,omitempty"`
+	// If set, objects are encrypted before they are written.
+	Encryption *EncryptionConfig `yaml:"encryption" json:"encryption,omitempty"`
+}
+
+// ForWorkflow returns the storage config of the objects written by the workflow with workflowID.
+// If data keys are scoped per workflow, the returned config scopes them to workflowID.
+func (s StorageConfig) ForWorkflow(workflowID uuid.UUID) StorageConfig {
+	if s.Encryption != nil && s.Encryption.PerWorkflowKeys && workflowID != uuid.Nil {
+		encryption := *s.Encryption
+		encryption.KeyScope = workflowID.String()
+		s.Encryption = &encryption
+	}
+	return s
+}
+
+type CompressionAlgorithm string
+
+const (
+	// NoCompression stores objects uncompressed, but still checksums them.
+	NoCompression   CompressionAlgorithm = "none"
+	GzipCompression CompressionAlgorithm = "gzip"
+	ZstdCompression CompressionAlgorithm = "zstd"
+)
+
+type CompressionConfig struct {
+	Algorithm CompressionAlgorithm `yaml:"algorithm" json:"algorithm"`
+}
+
+// EncryptionConfig configures the encryption of objects at rest. Objects are encrypted with data keys,
+// which are stored in the storage wrapped by the storage master key of the server.
+type EncryptionConfig struct {
+	// PerWorkflowKeys uses separate data keys for the objects of every workflow,
+	// instead of data keys that are shared by all workflows.
+	PerWorkflowKeys bool `yaml:"perWorkflowKeys" json:"per_workflow_keys,omitempty"`
+	// KeyScope is the scope of the data keys that new objects are encrypted with.
+	// It is set by `ForWorkflow`, and the data keys are shared by all workflows if it is empty.
+	KeyScope string `yaml:"-" json:"key_scope,omitempty"`
+	// DataKeys are the unwrapped data keys by key ID. They are only set in the storage config of
+	// Python job specs, since the master key never leaves the server.
+	DataKeys map[string][]byte `yaml:"-" json:"data_keys,omitempty"`
+	// WriteKeyID is the ID of the data key in DataKeys that the job encrypts new objects with.
+	WriteKeyID string `yaml:"-" json:"write_key_id,omitempty"`
 }
 
 type StorageConfigPublic struct {
-	Type            StorageType      `json:"type"`
-	S3ConfigPublic  *S3ConfigPublic  `json:"s3Config,omitempty"`
-	FileConfig      *FileConfig      `json:"fileConfig,omitempty"`
-	GCSConfigPublic *GCSConfigPublic `json:"gcsConfig,omitempty"`
+	Type              StorageType        `json:"type"`
+	S3ConfigPublic    *S3ConfigPublic    `json:"s3Config,omitempty"`
+	FileConfig        *FileConfig        `json:"fileConfig,omitempty"`
+	GCSConfigPublic   *GCSConfigPublic   `json:"gcsConfig,omitempty"`
+	AzureConfigPublic *AzureConfigPublic `json:"azureConfig,omitempty"`
+	Compression       *CompressionConfig `json:"compression,omitempty"`
+	Encrypted         bool               `json:"encrypted,omitempty"`
 
 	// These fields do not exist on the config file, but are pulled in from the database.
 	// Empty fields here mean that the local filesystem is being used as storage.
@@ -49,6 +100,19 @@ type S3Config struct {
 	CredentialsProfile string `yaml:"credentialsProfile"  json:"credentials_profile"`
 	AWSAccessKeyID     string `yaml:"awsAccessKeyId"  json:"aws_access_key_id"`
 	AWSSecretAccessKey string `yaml:"awsSecretAccessKey"  json:"aws_secret_access_key"`
+
+	S3EndpointConfig `yaml:",inline"`
+}
+
+// S3EndpointConfig overrides the AWS endpoint, to connect to S3-compatible services like MinIO.
+type S3EndpointConfig struct {
+	// The URL of the S3-compatible service, e.g. `http://localhost:9000`. If not set, we default to AWS.
+	EndpointURL string `yaml:"endpointUrl" json:"endpoint_url,omitempty"`
+	// Address buckets as `<endpoint>/<bucket>` instead of `<bucket>.<endpoint>`,
+	// which most S3-compatible services expect.
+	ForcePathStyle bool `yaml:"forcePathStyle" json:"force_path_style,omitempty"`
+	// Do not verify the TLS certificate of the endpoint, e.g. if it is self-signed.
+	SkipTLSVerify bool `yaml:"skipTlsVerify" json:"skip_tls_verify,omitempty"`
 }
 
 type S3ConfigPublic struct {
@@ -58,6 +122,9 @@ type S3ConfigPublic struct {
 	// Use this directory in the bucket as the root. If not set, we default to the root of the bucket.
 	// Expected to be santizied into the format "path/to/dir/" (without a leading slash, but with a trailing one).
 	RootDir string `yaml:"root_dir" json:"root_dir"`
+
+	EndpointURL    string `yaml:"endpointUrl" json:"endpoint_url,omitempty"`
+	ForcePathStyle bool   `yaml:"forcePathStyle" json:"force_path_style,omitempty"`
 }
 
 type FileConfig struct {
@@ -73,6 +140,31 @@ type GCSConfigPublic struct {
 	Bucket string `yaml:"bucket"  json:"bucket"`
 }
 
+type AzureConfig struct {
+	// The Blob service endpoint of the storage account, e.g. `https://<account>.blob.core.windows.net`.
+	// For the Azurite emulator, this is e.g. `http://127.0.0.1:10000/devstoreaccount1`.
+	// If not set, it is derived from the ConnectionString or the AccountName.
+	AccountURL string `yaml:"accountUrl" json:"account_url"`
+	Container  string `yaml:"container" json:"container"`
+
+	// Use this directory in the container as the root. If not set, we default to the root of the container.
+	// Expected to be santizied into the format "path/to/dir/" (without a leading slash, but with a trailing one).
+	Prefix string `yaml:"prefix" json:"prefix"`
+
+	// Exactly one of the following credentials is expected: a connection string,
+	// a shared key of the storage account, or a SAS token.
+	ConnectionString string `yaml:"connectionString" json:"connection_string"`
+	AccountName      string `yaml:"accountName" json:"account_name"`
+	AccountKey       string `yaml:"accountKey" json:"account_key"`
+	SASToken         string `yaml:"sasToken" json:"sas_token"`
+}
+
+type AzureConfigPublic struct {
+	AccountURL string `yaml:"accountUrl" json:"account_url"`
+	Container  string `yaml:"container" json:"container"`
+	Prefix     string `yaml:"prefix" json:"prefix"`
+}
+
 func (s *StorageConfig) Scan(value interface{}) error {
 	return utils.ScanJSONB(value, s)
 }
@@ -83,7 +175,9 @@ func (s *StorageConfig) Value() (driver.Value, error) {
 
 func (s *StorageConfig) ToPublic() (*StorageConfigPublic, error) {
 	storageConfigPublic := &StorageConfigPublic{
-		Type: s.Type,
+		Type:        s.Type,
+		Compression: s.Compression,
+		Encrypted:   s.Encryption != nil,
 	}
 
 	switch s.Type {
@@ -94,11 +188,20 @@ func (s *StorageConfig) ToPublic() (*StorageConfigPublic, error) {
 			Region:  s.S3Config.Region,
 			Bucket:  s.S3Config.Bucket,
 			RootDir: s.S3Config.RootDir,
+
+			EndpointURL:    s.S3Config.EndpointURL,
+			ForcePathStyle: s.S3Config.ForcePathStyle,
 		}
 	case GCSStorageType:
 		storageConfigPublic.GCSConfigPublic = &GCSConfigPublic{
 			Bucket: s.GCSConfig.Bucket,
 		}
+	case AzureStorageType:
+		storageConfigPublic.AzureConfigPublic = &AzureConfigPublic{
+			AccountURL: s.AzureConfig.AccountURL,
+			Container:  s.AzureConfig.Container,
+			Prefix:     s.AzureConfig.Prefix,
+		}
 	default:
 		return nil, errors.Newf("Unknown storage type. %s", s.Type)
 	}
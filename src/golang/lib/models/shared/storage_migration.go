@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"database/sql/driver"
+
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
+)
+
+// StorageMigrationProgress describes how many objects a storage migration has copied so far.
+type StorageMigrationProgress struct {
+	// TotalObjects is the number of objects in the old storage that are migrated.
+	TotalObjects int `json:"total_objects"`
+	// TotalBytes is the size of all objects that are migrated. It is only estimated by dry runs.
+	TotalBytes int64 `json:"total_bytes"`
+	// CopiedObjects is the number of objects that were copied and verified, including those
+	// copied before the migration was resumed.
+	CopiedObjects int   `json:"copied_objects"`
+	CopiedBytes   int64 `json:"copied_bytes"`
+	// SkippedObjects is the number of objects that do not exist in the old storage,
+	// e.g. the results of runs that failed.
+	SkippedObjects int `json:"skipped_objects"`
+}
+
+func (p *StorageMigrationProgress) Value() (driver.Value, error) {
+	return utils.ValueJSONB(*p)
+}
+
+func (p *StorageMigrationProgress) Scan(value interface{}) error {
+	return utils.ScanJSONB(value, p)
+}
@@ -21,6 +21,22 @@ const (
 	// Indicates what storage layer the server is currently using.
 	// Equivalent to the result of the last successful migration.
 	StorageMigrationCurrent = "current"
+
+	// The storage config before the migration, which a rollback migrates back to.
+	StorageMigrationPrevStorageConfig = "prev_storage_config"
+	StorageMigrationDestStorageConfig = "dest_storage_config"
+	// A dry run only estimates the objects and bytes to migrate.
+	StorageMigrationDryRun   = "dry_run"
+	StorageMigrationProgress = "progress"
+)
+
+const (
+	StorageMigrationObjectTable = "storage_migration_object"
+
+	// StorageMigrationObject table column names
+	StorageMigrationObjectStorageMigrationID = "storage_migration_id"
+	StorageMigrationObjectKey                = "object_key"
+	StorageMigrationObjectChecksum           = "checksum"
 )
 
 // A StorageMigration maps to the storage_migration table.
@@ -29,6 +45,11 @@ type StorageMigration struct {
 	DestResourceID uuid.UUID             `db:"dest_integration_id" json:"dest_integration_id"`
 	ExecState      shared.ExecutionState `db:"execution_state" json:"execution_state"`
 	Current        bool                  `db:"current" json:"current"`
+
+	PrevStorageConfig *shared.StorageConfig            `db:"prev_storage_config" json:"-"`
+	DestStorageConfig *shared.StorageConfig            `db:"dest_storage_config" json:"-"`
+	DryRun            bool                             `db:"dry_run" json:"dry_run"`
+	Progress          *shared.StorageMigrationProgress `db:"progress" json:"progress"`
 }
 
 func StorageMigrationCols() string {
@@ -41,5 +62,31 @@ func allStorageMigrationCols() []string {
 		StorageMigrationDestResourceID,
 		StorageMigrationExecutionState,
 		StorageMigrationCurrent,
+		StorageMigrationPrevStorageConfig,
+		StorageMigrationDestStorageConfig,
+		StorageMigrationDryRun,
+		StorageMigrationProgress,
+	}
+}
+
+// A StorageMigrationObject maps to the storage_migration_object table. It records an object that
+// a storage migration copied and verified, so that a resumed migration does not copy it again.
+type StorageMigrationObject struct {
+	StorageMigrationID uuid.UUID `db:"storage_migration_id" json:"storage_migration_id"`
+	Key                string    `db:"object_key" json:"object_key"`
+	// Checksum is the sha256 checksum of the object.
+	Checksum string `db:"checksum" json:"checksum"`
+}
+
+// StorageMigrationObjectCols returns a comma-separated string of all StorageMigrationObject columns.
+func StorageMigrationObjectCols() string {
+	return strings.Join(allStorageMigrationObjectCols(), ",")
+}
+
+func allStorageMigrationObjectCols() []string {
+	return []string{
+		StorageMigrationObjectStorageMigrationID,
+		StorageMigrationObjectKey,
+		StorageMigrationObjectChecksum,
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/google/uuid"
 )
 
@@ -21,6 +22,8 @@ const (
 	WorkflowCreatedAt            = "created_at"
 	WorkflowRetentionPolicy      = "retention_policy"
 	WorkflowNotificationSettings = "notification_settings"
+	WorkflowMaxParallelOperators = "max_parallel_operators"
+	WorkflowCascadeTriggeredAt   = "cascade_triggered_at"
 )
 
 // A Workflow maps to the workflow table.
@@ -33,6 +36,12 @@ type Workflow struct {
 	CreatedAt            time.Time                   `db:"created_at" json:"created_at"`
 	RetentionPolicy      shared.RetentionPolicy      `db:"retention_policy" json:"retention_policy"`
 	NotificationSettings shared.NotificationSettings `db:"notification_settings" json:"notification_settings"`
+	// MaxParallelOperators caps the number of operators of a single run that are executed
+	// at the same time. There is no cap if it is 0.
+	MaxParallelOperators int `db:"max_parallel_operators" json:"max_parallel_operators"`
+	// CascadeTriggeredAt is the last time the workflow was triggered because all of its sources
+	// succeeded. It is only set for workflows with the `all` cascade mode.
+	CascadeTriggeredAt utils.NullTime `db:"cascade_triggered_at" json:"-"`
 }
 
 // WorkflowCols returns a comma-separated string of all Workflow columns.
@@ -61,5 +70,7 @@ func allWorkflowCols() []string {
 		WorkflowCreatedAt,
 		WorkflowRetentionPolicy,
 		WorkflowNotificationSettings,
+		WorkflowMaxParallelOperators,
+		WorkflowCascadeTriggeredAt,
 	}
 }
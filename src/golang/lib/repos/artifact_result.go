@@ -36,6 +36,12 @@ type artifactResultReader interface {
 	// GetByDAGResults returns the ArtifactResult from a workflow DAG result with an ID in the dagResultIDs list.
 	GetByDAGResults(ctx context.Context, dagResultIDs []uuid.UUID, DB database.Database) ([]models.ArtifactResult, error)
 
+	// GetByContentPaths returns the ArtifactResults whose content is stored at a path in contentPaths.
+	GetByContentPaths(ctx context.Context, contentPaths []string, DB database.Database) ([]models.ArtifactResult, error)
+
+	// GetContentPaths returns the distinct content paths of all ArtifactResults.
+	GetContentPaths(ctx context.Context, DB database.Database) ([]string, error)
+
 	// GetStatusByArtifactBatch returns an ArtifactResultStatus for each ArtifactResult associated
 	// with an Artifact in artifactIDs.
 	GetStatusByArtifactBatch(ctx context.Context, artifactIDs []uuid.UUID, DB database.Database) ([]views.ArtifactResultStatus, error)
@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models"
@@ -27,6 +28,20 @@ type dagResultReader interface {
 	// GetByWorkflow returns the DAGResults of all DAGs associated with the Workflow with workflowID.
 	GetByWorkflow(ctx context.Context, workflowID uuid.UUID, orderBy string, limit int, orderDescending bool, DB database.Database) ([]models.DAGResult, error)
 
+	// GetInProgressByWorkflow returns the pending and running DAGResults of all DAGs associated
+	// with the Workflow with workflowID, ordered by DAGResult.CreatedAt from the oldest.
+	GetInProgressByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) ([]models.DAGResult, error)
+
+	// GetAbandoned returns the pending and running DAGResults that no run queue worker is executing,
+	// since the RunRequest that created them is not claimed or running with a lease that has not
+	// expired, based on the clock of the database. The DAGResults of self-orchestrated engines
+	// are not included.
+	GetAbandoned(ctx context.Context, DB database.Database) ([]models.DAGResult, error)
+
+	// GetLatestSucceededByWorkflow returns the most recent succeeded DAGResult of the Workflow with
+	// workflowID that was created after since. It returns database.ErrNoRows() if there is none.
+	GetLatestSucceededByWorkflow(ctx context.Context, workflowID uuid.UUID, since time.Time, DB database.Database) (*models.DAGResult, error)
+
 	// GetKOffsetByWorkflow returns the DAGResults of all DAGs associated with the Workflow with workflowID
 	// except for the last k DAGResults ordered by DAGResult.CreatedAt.
 	GetKOffsetByWorkflow(ctx context.Context, workflowID uuid.UUID, k int, DB database.Database) ([]models.DAGResult, error)
@@ -60,6 +75,16 @@ type dagResultWriter interface {
 		DB database.Database,
 	) (*models.DAGResult, error)
 
+	// UpdateInProgress applies changes to the DAGResult with ID, only if it is still pending or running.
+	// It returns the updated DAGResult, or database.ErrNoRows() if the DAGResult has already finished,
+	// so that concurrent updates cannot overwrite the final status of a run.
+	UpdateInProgress(
+		ctx context.Context,
+		ID uuid.UUID,
+		changes map[string]interface{},
+		DB database.Database,
+	) (*models.DAGResult, error)
+
 	// UpdateBatchStatusByStatus updates DAG result's status based on the current status.
 	UpdateBatchStatusByStatus(
 		ctx context.Context,
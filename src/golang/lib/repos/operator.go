@@ -109,6 +109,10 @@ type operatorReader interface {
 	// GetForAqueductEngine returns all operators executed on the native Aqueduct Engine.
 	GetForAqueductEngine(ctx context.Context, DB database.Database) ([]models.Operator, error)
 
+	// GetFunctionStoragePaths returns the distinct storage paths of the code of all
+	// function, check and metric Operators.
+	GetFunctionStoragePaths(ctx context.Context, DB database.Database) ([]string, error)
+
 	// ValidateOrg returns whether the Operator was created by the specified organization.
 	ValidateOrg(ctx context.Context, ID uuid.UUID, orgID string, DB database.Database) (bool, error)
 }
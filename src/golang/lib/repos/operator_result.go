@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models"
@@ -30,6 +31,19 @@ type operatorResultReader interface {
 	// GetByDAGResultBatch returns all OperatorResults for the DAGResults specified.
 	GetByDAGResultBatch(ctx context.Context, dagResultIDs []uuid.UUID, DB database.Database) ([]models.OperatorResult, error)
 
+	// GetLatestSucceededByResultCacheKey returns the most recent succeeded OperatorResult of the
+	// Operator specified whose result cache key is cacheKey, and that belongs to a DAGResult created
+	// at or after since. Results that were themselves reused from the cache are skipped, so that
+	// since is compared against the run that computed the result.
+	// It returns database.ErrNoRows() if there is no such OperatorResult.
+	GetLatestSucceededByResultCacheKey(
+		ctx context.Context,
+		operatorID uuid.UUID,
+		cacheKey string,
+		since time.Time,
+		DB database.Database,
+	) (*models.OperatorResult, error)
+
 	// GetCheckStatusByArtifactBatch returns an OperatorResultStatus for all OperatorResults
 	// associated with a Check Operator where the Operator has incoming DAGEdge
 	// from an Artifact in artifactIDs.
@@ -0,0 +1,91 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+)
+
+// RunRequest is the queue of requests to run a workflow.
+// Triggers enqueue a request, and workers claim the oldest queued request with a lease
+// that they renew until the run finishes.
+type RunRequest interface {
+	runRequestReader
+	runRequestWriter
+}
+
+type runRequestReader interface {
+	// Get returns the RunRequest with id.
+	Get(ctx context.Context, id uuid.UUID, DB database.Database) (*models.RunRequest, error)
+
+	// ListUnfinished returns all RunRequests that are not done, oldest first.
+	ListUnfinished(ctx context.Context, DB database.Database) ([]models.RunRequest, error)
+}
+
+type runRequestWriter interface {
+	// Create inserts a new queued RunRequest for the workflow with the specified fields.
+	// A nil resumedDAGResultID starts a new run instead of resuming a run.
+	Create(
+		ctx context.Context,
+		workflowID uuid.UUID,
+		parameters shared.RunParameters,
+		resumedDAGResultID *uuid.UUID,
+		waitReason string,
+		DB database.Database,
+	) (*models.RunRequest, error)
+
+	// Claim atomically claims the oldest queued RunRequest for workerID with a lease that
+	// expires after leaseDuration, based on the clock of the database.
+	// It returns a database.ErrNoRows() if no request is queued.
+	// If the workflow of a request has a queue overlap policy, the request is not claimed
+	// until the earlier requests of the workflow are done.
+	Claim(
+		ctx context.Context,
+		workerID string,
+		leaseDuration time.Duration,
+		DB database.Database,
+	) (*models.RunRequest, error)
+
+	// MarkWaitingForPreviousRun sets the wait reason of the queued RunRequests that are not claimed,
+	// since the workflow has a queue overlap policy and a previous run is not done, to waitReason.
+	// It returns the RunRequests whose wait reason changed.
+	MarkWaitingForPreviousRun(ctx context.Context, waitReason string, DB database.Database) ([]models.RunRequest, error)
+
+	// RenewLease extends the lease of the RunRequest with id to expire after leaseDuration,
+	// based on the clock of the database.
+	// It returns a database.ErrNoRows() if workerID no longer holds the lease.
+	RenewLease(
+		ctx context.Context,
+		id uuid.UUID,
+		workerID string,
+		leaseDuration time.Duration,
+		DB database.Database,
+	) (*models.RunRequest, error)
+
+	// RequeueExpired puts all claimed RunRequests whose lease expired, based on the clock
+	// of the database, back in the queue with waitReason, and returns them.
+	RequeueExpired(
+		ctx context.Context,
+		waitReason string,
+		DB database.Database,
+	) ([]models.RunRequest, error)
+
+	// FinishExpired marks all running RunRequests whose lease expired, based on the clock
+	// of the database, as done, and returns them.
+	FinishExpired(ctx context.Context, DB database.Database) ([]models.RunRequest, error)
+
+	// DeleteDoneBefore deletes the RunRequests that are done and were created before `before`.
+	DeleteDoneBefore(ctx context.Context, before time.Time, DB database.Database) error
+
+	// Update applies changes to the RunRequest with id. It returns the updated RunRequest.
+	Update(
+		ctx context.Context,
+		id uuid.UUID,
+		changes map[string]interface{},
+		DB database.Database,
+	) (*models.RunRequest, error)
+}
@@ -0,0 +1,38 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+)
+
+// SchedulerLease elects the leader among the server replicas. A lease is held by one replica
+// until it expires, unless the replica renews it.
+type SchedulerLease interface {
+	schedulerLeaseReader
+	schedulerLeaseWriter
+}
+
+type schedulerLeaseReader interface {
+	// Get returns the SchedulerLease with name.
+	Get(ctx context.Context, name string, DB database.Database) (*models.SchedulerLease, error)
+}
+
+type schedulerLeaseWriter interface {
+	// Acquire atomically acquires or renews the lease `name` for holder for leaseDuration, if the lease
+	// does not exist, is already held by holder, or expired. Both are based on the clock of the database.
+	// It returns a database.ErrNoRows() if the lease is held by another replica.
+	Acquire(
+		ctx context.Context,
+		name string,
+		holder string,
+		leaseDuration time.Duration,
+		DB database.Database,
+	) (*models.SchedulerLease, error)
+
+	// Release deletes the lease `name` if it is held by holder, so that another replica can acquire it
+	// without waiting for it to expire.
+	Release(ctx context.Context, name string, holder string, DB database.Database) error
+}
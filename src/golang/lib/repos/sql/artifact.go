@@ -1,4 +1,4 @@
-package sqlite
+package sql
 
 import (
 	"context"
@@ -15,7 +15,9 @@ import (
 	"github.com/google/uuid"
 )
 
-const artifactNodeViewSubQuery = `
+// artifactNodeViewSubQuery returns the query of the view of artifact nodes.
+func artifactNodeViewSubQuery(DB database.Database) string {
+	return fmt.Sprintf(`
 	WITH artf_with_outputs AS ( -- Aggregate outputs
 		SELECT
 			artifact.id AS id,
@@ -24,12 +26,7 @@ const artifactNodeViewSubQuery = `
 			artifact.description AS description,
 			artifact.should_persist AS should_persist,
 			artifact.type as type,
-			CAST( json_group_array( -- Group to_ids and idx into one array
-				json_object(
-					'value', workflow_dag_edge.to_id,
-					'idx', workflow_dag_edge.idx
-				)
-			) AS BLOB) AS outputs
+			%s AS outputs -- Group to_ids and idx into one array
 		FROM
 			artifact, workflow_dag, workflow_dag_edge
 		WHERE
@@ -67,7 +64,10 @@ const artifactNodeViewSubQuery = `
 	ON
 		artf_with_outputs.id = artf_with_input.id
 		AND artf_with_outputs.dag_id = artf_with_input.dag_id
-`
+`,
+		jsonArrayAgg(DB, "'value', workflow_dag_edge.to_id, 'idx', workflow_dag_edge.idx"),
+	)
+}
 
 type artifactRepo struct {
 	artifactReader
@@ -103,7 +103,7 @@ func (*artifactReader) GetNode(ctx context.Context, ID uuid.UUID, DB database.Da
 	query := fmt.Sprintf(
 		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s = $1",
 		views.ArtifactNodeView,
-		artifactNodeViewSubQuery,
+		artifactNodeViewSubQuery(DB),
 		views.ArtifactNodeCols(),
 		views.ArtifactNodeView,
 		models.ArtifactID,
@@ -201,9 +201,10 @@ func (*artifactReader) GetMetricsByUpstreamArtifactBatch(
 			artifact.id = edge_metrics_op_to_artf.to_id
 			AND edge_artf_to_metrics_op.to_id = operator.id 
 			AND edge_metrics_op_to_artf.from_id = operator.id
-			AND json_extract(operator.spec, '$.type') = '%s'
+			AND %s = '%s'
 			AND edge_artf_to_metrics_op.from_id IN (%s);`,
 		models.ArtifactColsWithPrefix(),
+		jsonText(DB, "operator.spec", "type"),
 		operator.MetricType,
 		stmt_preparers.GenerateArgsList(len(artifactIDs), 1),
 	)
@@ -248,7 +249,7 @@ func (*artifactReader) GetNodesByDAG(
 	query := fmt.Sprintf(
 		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s = $1",
 		views.ArtifactNodeView,
-		artifactNodeViewSubQuery,
+		artifactNodeViewSubQuery(DB),
 		views.ArtifactNodeCols(),
 		views.ArtifactNodeView,
 		views.ArtifactNodeDagID,
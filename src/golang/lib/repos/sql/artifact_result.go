@@ -1,4 +1,4 @@
-package sqlite
+package sql
 
 import (
 	"context"
@@ -112,6 +112,44 @@ func (*artifactResultReader) GetByDAGResults(ctx context.Context, dagResultIDs [
 	return getArtifactResults(ctx, DB, query, args...)
 }
 
+func (*artifactResultReader) GetByContentPaths(ctx context.Context, contentPaths []string, DB database.Database) ([]models.ArtifactResult, error) {
+	if len(contentPaths) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM artifact_result WHERE content_path IN (%s);`,
+		models.ArtifactResultCols(),
+		stmt_preparers.GenerateArgsList(len(contentPaths), 1),
+	)
+	args := make([]interface{}, 0, len(contentPaths))
+	for _, contentPath := range contentPaths {
+		args = append(args, contentPath)
+	}
+
+	return getArtifactResults(ctx, DB, query, args...)
+}
+
+func (*artifactResultReader) GetContentPaths(ctx context.Context, DB database.Database) ([]string, error) {
+	query := `SELECT DISTINCT content_path FROM artifact_result WHERE content_path <> '';`
+
+	type resultStruct struct {
+		ContentPath string `db:"content_path"`
+	}
+
+	var resultRows []resultStruct
+	if err := DB.Query(ctx, &resultRows, query); err != nil {
+		return nil, err
+	}
+
+	results := make([]string, 0, len(resultRows))
+	for _, row := range resultRows {
+		results = append(results, row.ContentPath)
+	}
+
+	return results, nil
+}
+
 func (*artifactResultReader) GetStatusByArtifactBatch(
 	ctx context.Context,
 	artifactIDs []uuid.UUID,
@@ -186,9 +224,10 @@ func (*artifactResultReader) GetWithArtifactOfMetricsByDAGResultBatch(
 			workflow_dag_edge.to_id = artifact.id
 			AND workflow_dag_edge.from_id = operator.id
 			AND workflow_dag_edge.workflow_dag_id = workflow_dag.id
-			AND json_extract(operator.spec, '$.type') = '%s'
+			AND %s = '%s'
 			AND artifact_result.artifact_id = artifact.id
 			AND artifact_result.workflow_dag_result_id IN (%s);`,
+		jsonText(DB, "operator.spec", "type"),
 		operator.MetricType,
 		stmt_preparers.GenerateArgsList(len(dagResultIDs), 1),
 	)
@@ -308,6 +347,7 @@ func (*artifactResultWriter) UpdateBatchStatusByStatus(
 	DB database.Database,
 ) ([]models.ArtifactResult, error) {
 	setExecStateFragment, args, err := generateUpdateExecStateSnippet(
+		DB,
 		models.ArtifactResultExecState,
 		to,
 		time.Now(),
@@ -322,12 +362,12 @@ func (*artifactResultWriter) UpdateBatchStatusByStatus(
 			%s,
 			status = $%d
 		WHERE
-			json_extract(%s, '$.status') = $%d
+			%s = $%d
 		RETURNING %s;`,
 		models.ArtifactResultTable,
 		setExecStateFragment,
 		len(args)+1,
-		models.ArtifactResultExecState,
+		jsonText(DB, models.ArtifactResultExecState, "status"),
 		len(args)+2,
 		models.ArtifactResultCols(),
 	)
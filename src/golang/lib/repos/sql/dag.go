@@ -1,4 +1,4 @@
-package sqlite
+package sql
 
 import (
 	"context"
@@ -257,10 +257,11 @@ func (*dagReader) GetLatestIDsByOrgAndEngine(
 			FROM app_user, workflow, workflow_dag 
 			WHERE
 				workflow.id = workflow_dag.workflow_id
-				AND json_extract(workflow_dag.engine_config, '$.type') = $1
+				AND %s = $1
 				%s
 		 	GROUP BY workflow.id
 		);`,
+		jsonText(DB, "workflow_dag.engine_config", "type"),
 		orgIDQuerySnippet,
 	)
 	args := []interface{}{engine}
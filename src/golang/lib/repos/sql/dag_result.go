@@ -1,9 +1,10 @@
-package sqlite
+package sql
 
 import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
@@ -85,9 +86,79 @@ func (*dagResultReader) GetByWorkflow(ctx context.Context, workflowID uuid.UUID,
 	return getDAGResults(ctx, DB, query, args...)
 }
 
+func (*dagResultReader) GetInProgressByWorkflow(ctx context.Context, workflowID uuid.UUID, DB database.Database) ([]models.DAGResult, error) {
+	query := fmt.Sprintf(
+		`SELECT %s 
+		FROM workflow_dag_result, workflow_dag 
+		WHERE 
+			workflow_dag_result.workflow_dag_id = workflow_dag.id 
+			AND workflow_dag.workflow_id = $1
+			AND workflow_dag_result.status IN ($2, $3)
+		ORDER BY workflow_dag_result.created_at ASC;`,
+		models.DAGResultColsWithPrefix(),
+	)
+	args := []interface{}{workflowID, shared.PendingExecutionStatus, shared.RunningExecutionStatus}
+
+	return getDAGResults(ctx, DB, query, args...)
+}
+
+func (*dagResultReader) GetAbandoned(ctx context.Context, DB database.Database) ([]models.DAGResult, error) {
+	// DAGResults that were not created by a RunRequest, e.g. because they were created before
+	// RunRequests recorded their DAGResult, are abandoned as well.
+	query := fmt.Sprintf(
+		`SELECT %s 
+		FROM workflow_dag_result, workflow_dag 
+		WHERE 
+			workflow_dag_result.workflow_dag_id = workflow_dag.id 
+			AND workflow_dag_result.status IN ($1, $2)
+			AND %s != $3
+			AND NOT EXISTS (
+				SELECT 1 FROM run_request
+				WHERE 
+					run_request.dag_result_id = workflow_dag_result.id
+					AND run_request.status IN ($4, $5)
+					AND run_request.lease_expires_at >= %s
+			)
+		ORDER BY workflow_dag_result.created_at ASC;`,
+		models.DAGResultColsWithPrefix(),
+		jsonText(DB, "workflow_dag.engine_config", "type"),
+		currentTime(DB),
+	)
+	args := []interface{}{
+		shared.PendingExecutionStatus,
+		shared.RunningExecutionStatus,
+		shared.AirflowEngineType,
+		shared.ClaimedRunRequestStatus,
+		shared.RunningRunRequestStatus,
+	}
+
+	return getDAGResults(ctx, DB, query, args...)
+}
+
+func (*dagResultReader) GetLatestSucceededByWorkflow(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	since time.Time,
+	DB database.Database,
+) (*models.DAGResult, error) {
+	query := fmt.Sprintf(
+		`SELECT %s 
+		FROM workflow_dag_result, workflow_dag 
+		WHERE 
+			workflow_dag_result.workflow_dag_id = workflow_dag.id 
+			AND workflow_dag.workflow_id = $1
+			AND workflow_dag_result.status = $2
+			AND workflow_dag_result.created_at > $3
+		ORDER BY workflow_dag_result.created_at DESC
+		LIMIT 1;`,
+		models.DAGResultColsWithPrefix(),
+	)
+	args := []interface{}{workflowID, shared.SucceededExecutionStatus, since}
+
+	return getDAGResult(ctx, DB, query, args...)
+}
+
 func (*dagResultReader) GetKOffsetByWorkflow(ctx context.Context, workflowID uuid.UUID, k int, DB database.Database) ([]models.DAGResult, error) {
-	// https://itecnote.com/tecnote/sqlite-limit-offset-query/
-	// `LIMIT <skip>, <count>` is equivalent to `LIMIT <count> OFFSET <skip>`
 	query := fmt.Sprintf(
 		`SELECT %s 
 		FROM workflow_dag_result, workflow_dag 
@@ -95,8 +166,9 @@ func (*dagResultReader) GetKOffsetByWorkflow(ctx context.Context, workflowID uui
 			workflow_dag_result.workflow_dag_id = workflow_dag.id 
 			AND workflow_dag.workflow_id = $1
 		ORDER BY workflow_dag_result.created_at DESC
-		LIMIT $2, -1;`,
+		%s;`,
 		models.DAGResultColsWithPrefix(),
+		offsetClause(DB, 2),
 	)
 	args := []interface{}{workflowID, k}
 
@@ -192,6 +264,37 @@ func (*dagResultWriter) Update(ctx context.Context, ID uuid.UUID, changes map[st
 	return &dagResult, err
 }
 
+func (*dagResultWriter) UpdateInProgress(
+	ctx context.Context,
+	ID uuid.UUID,
+	changes map[string]interface{},
+	DB database.Database,
+) (*models.DAGResult, error) {
+	setFragments := make([]string, 0, len(changes))
+	args := make([]interface{}, 0, len(changes)+3)
+	for column, arg := range changes {
+		args = append(args, arg)
+		setFragments = append(setFragments, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	query := fmt.Sprintf(
+		`UPDATE %s SET %s
+		WHERE %s = $%d AND %s IN ($%d, $%d)
+		RETURNING %s;`,
+		models.DAGResultTable,
+		strings.Join(setFragments, ", "),
+		models.DAGResultID,
+		len(args)+1,
+		models.DAGResultStatus,
+		len(args)+2,
+		len(args)+3,
+		models.DAGResultCols(),
+	)
+	args = append(args, ID, shared.PendingExecutionStatus, shared.RunningExecutionStatus)
+
+	return getDAGResult(ctx, DB, query, args...)
+}
+
 func (*dagResultWriter) UpdateBatchStatusByStatus(
 	ctx context.Context,
 	from shared.ExecutionStatus,
@@ -199,6 +302,7 @@ func (*dagResultWriter) UpdateBatchStatusByStatus(
 	DB database.Database,
 ) ([]models.DAGResult, error) {
 	setExecStateFragment, args, err := generateUpdateExecStateSnippet(
+		DB,
 		models.DAGResultExecState,
 		to,
 		time.Now(),
@@ -213,12 +317,12 @@ func (*dagResultWriter) UpdateBatchStatusByStatus(
 			%s,
 			status = $%d
 		WHERE
-			json_extract(%s, '$.status') = $%d
+			%s = $%d
 		RETURNING %s;`,
 		models.DAGResultTable,
 		setExecStateFragment,
 		len(args)+1,
-		models.DAGResultExecState,
+		jsonText(DB, models.DAGResultExecState, "status"),
 		len(args)+2,
 		models.DAGResultCols(),
 	)
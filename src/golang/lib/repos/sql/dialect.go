@@ -0,0 +1,87 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+)
+
+// The repos in this package are used for both SQLite and Postgres databases. The queries are
+// written in the SQL that both dialects support, and the helpers below return the fragments that
+// differ between them. These are mostly JSON operators, since SQLite stores JSON columns as BLOBs
+// and Postgres stores them as JSONB.
+
+func isPostgres(DB database.Database) bool {
+	return DB.Type() == database.PostgresType
+}
+
+// jsonText returns an expression that extracts the field at path of the JSON column as a scalar.
+// Example: jsonText(DB, "operator.spec", "load", "integration_id")
+// -> SQLite: `json_extract(operator.spec, '$.load.integration_id')`
+// -> Postgres: `operator.spec->'load'->>'integration_id'`
+func jsonText(DB database.Database, column string, path ...string) string {
+	if isPostgres(DB) {
+		expr := column
+		for i, field := range path {
+			if i == len(path)-1 {
+				expr += fmt.Sprintf("->>'%s'", field)
+			} else {
+				expr += fmt.Sprintf("->'%s'", field)
+			}
+		}
+		return expr
+	}
+
+	return fmt.Sprintf("json_extract(%s, '$.%s')", column, strings.Join(path, "."))
+}
+
+// jsonValue returns an expression that extracts the JSON value at path of the JSON column,
+// which may be an object. It is NULL if there is no value at path.
+func jsonValue(DB database.Database, column string, path ...string) string {
+	if isPostgres(DB) {
+		return fmt.Sprintf("%s->'%s'", column, strings.Join(path, "'->'"))
+	}
+
+	return fmt.Sprintf("json_extract(%s, '$.%s')", column, strings.Join(path, "."))
+}
+
+// jsonArrayAgg returns an aggregate expression that groups the JSON objects built from the
+// key-value pairs in `fields` into a JSON array.
+func jsonArrayAgg(DB database.Database, fields string) string {
+	if isPostgres(DB) {
+		return fmt.Sprintf("json_agg(json_build_object(%s))", fields)
+	}
+
+	return fmt.Sprintf("CAST(json_group_array(json_object(%s)) AS BLOB)", fields)
+}
+
+// offsetClause returns a clause that skips the number of rows in the parameter at placeholder.
+func offsetClause(DB database.Database, placeholder int) string {
+	if isPostgres(DB) {
+		return fmt.Sprintf("OFFSET $%d", placeholder)
+	}
+
+	// SQLite only supports OFFSET after a LIMIT, and a negative limit means there is no limit.
+	return fmt.Sprintf("LIMIT -1 OFFSET $%d", placeholder)
+}
+
+// currentTime returns an expression for the current time of the database server.
+func currentTime(DB database.Database) string {
+	if isPostgres(DB) {
+		return "CURRENT_TIMESTAMP"
+	}
+
+	// The time is formatted the way the SQLite driver formats the times it writes.
+	return "strftime('%Y-%m-%d %H:%M:%f', 'now')"
+}
+
+// currentTimeAfter returns an expression for the current time of the database server plus
+// the number of seconds in the parameter at placeholder.
+func currentTimeAfter(DB database.Database, placeholder int) string {
+	if isPostgres(DB) {
+		return fmt.Sprintf("CURRENT_TIMESTAMP + $%d * INTERVAL '1 second'", placeholder)
+	}
+
+	return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:%%M:%%f', 'now', $%d || ' seconds')", placeholder)
+}
@@ -1,4 +1,4 @@
-package sqlite
+package sql
 
 import (
 	"context"
@@ -16,7 +16,8 @@ import (
 	"github.com/google/uuid"
 )
 
-const operatorNodeViewSubQuery = `
+func operatorNodeViewSubQuery(DB database.Database) string {
+	return fmt.Sprintf(`
 	WITH op_with_outputs AS ( -- Aggregate outputs
 		SELECT
 			operator.id AS id,
@@ -25,12 +26,7 @@ const operatorNodeViewSubQuery = `
 			operator.description AS description,
 			operator.spec AS spec,
 			operator.execution_environment_id AS execution_environment_id,
-			CAST( json_group_array( -- Group to_ids and idx into one array
-				json_object(
-					'value', workflow_dag_edge.to_id,
-					'idx', workflow_dag_edge.idx
-				)
-			) AS BLOB) AS outputs
+			%s AS outputs -- Group to_ids and idx into one array
 		FROM
 			operator, workflow_dag, workflow_dag_edge
 		WHERE
@@ -47,12 +43,7 @@ const operatorNodeViewSubQuery = `
 			operator.description AS description,
 			operator.spec AS spec,
 			operator.execution_environment_id AS execution_environment_id,
-			CAST( json_group_array( -- Group from_ids and idx into one array
-				json_object(
-					'value', workflow_dag_edge.from_id,
-					'idx', workflow_dag_edge.idx
-				)
-			) AS BLOB) AS inputs
+			%s AS inputs -- Group from_ids and idx into one array
 		FROM
 			operator, workflow_dag, workflow_dag_edge
 		WHERE
@@ -91,9 +82,14 @@ const operatorNodeViewSubQuery = `
 		op_with_outputs.id = op_with_inputs.id
 		AND op_with_outputs.dag_id = op_with_inputs.dag_id
 	WHERE op_with_outputs.outputs IS NULL
-`
+`,
+		jsonArrayAgg(DB, "'value', workflow_dag_edge.to_id, 'idx', workflow_dag_edge.idx"),
+		jsonArrayAgg(DB, "'value', workflow_dag_edge.from_id, 'idx', workflow_dag_edge.idx"),
+	)
+}
 
-var mergedNodeViewSubQuery = fmt.Sprintf(`
+func mergedNodeViewSubQuery(DB database.Database) string {
+	return fmt.Sprintf(`
 	WITH
 		operator_node AS (%s), 
 		artifact_node AS (%s)
@@ -115,9 +111,10 @@ var mergedNodeViewSubQuery = fmt.Sprintf(`
 	ON
 		artifact_node.input = operator_node.id
 `,
-	operatorNodeViewSubQuery,
-	artifactNodeViewSubQuery,
-)
+		operatorNodeViewSubQuery(DB),
+		artifactNodeViewSubQuery(DB),
+	)
+}
 
 type operatorRepo struct {
 	operatorReader
@@ -165,7 +162,7 @@ func (*operatorReader) GetNodeBatch(ctx context.Context, IDs []uuid.UUID, DB dat
 	query := fmt.Sprintf(
 		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s IN (%s)",
 		views.OperatorNodeView,
-		operatorNodeViewSubQuery,
+		operatorNodeViewSubQuery(DB),
 		views.OperatorNodeCols(),
 		views.OperatorNodeView,
 		models.OperatorID,
@@ -191,7 +188,7 @@ func (*operatorReader) GetOperatorWithArtifactNodeBatch(ctx context.Context, IDs
 	query := fmt.Sprintf(
 		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s IN (%s)",
 		views.OperatorWithArtifactNodeView,
-		mergedNodeViewSubQuery,
+		mergedNodeViewSubQuery(DB),
 		views.OperatorWithArtifactNodeCols(),
 		views.OperatorWithArtifactNodeView,
 		views.OperatorWithArtifactNodeID,
@@ -247,7 +244,7 @@ func (*operatorReader) GetNodesByDAG(
 	query := fmt.Sprintf(
 		"WITH %s AS (%s) SELECT %s FROM %s WHERE %s = $1",
 		views.OperatorNodeView,
-		operatorNodeViewSubQuery,
+		operatorNodeViewSubQuery(DB),
 		views.OperatorNodeCols(),
 		views.OperatorNodeView,
 		views.OperatorNodeDagID,
@@ -289,6 +286,38 @@ func (*operatorReader) GetDistinctLoadOPsByWorkflow(
 		json_extract(operator.spec, '$.load')	
 	ORDER BY modified_at DESC;
 	`
+	if isPostgres(DB) {
+		// Postgres does not allow selecting columns that are not grouped by, so DISTINCT ON
+		// picks the latest row of each group instead.
+		query = `
+	SELECT * FROM (
+		SELECT DISTINCT ON (operator.name, resource.name, operator.spec->'load')
+			operator.id AS operator_id,
+			operator.name AS operator_name, 
+			workflow_dag.created_at AS modified_at,
+			resource.name AS resource_name,
+			operator.spec->'load' AS spec
+		FROM 
+			operator, resource, workflow_dag_edge, workflow_dag
+		WHERE (
+			operator.spec->>'type' = 'load' AND 
+			resource.id::text = operator.spec->'load'->>'integration_id' AND
+			( 
+				workflow_dag_edge.from_id = operator.id OR 
+				workflow_dag_edge.to_id = operator.id 
+			) AND 
+			workflow_dag_edge.workflow_dag_id = workflow_dag.id AND 
+			workflow_dag.workflow_id = $1
+		)
+		ORDER BY
+			operator.name,
+			resource.name,
+			operator.spec->'load',
+			workflow_dag.created_at DESC
+	) AS distinct_load_operator
+	ORDER BY modified_at DESC;
+	`
+	}
 	args := []interface{}{workflowID}
 
 	var operators []views.LoadOperator
@@ -305,9 +334,11 @@ func (*operatorReader) GetExtractAndLoadOPsByResource(
 		`SELECT %s 
 		FROM operator
 		WHERE 
-			json_extract(spec, '$.load.integration_id') = $1
-			OR json_extract(spec, '$.extract.integration_id') = $2`,
+			%s = $1
+			OR %s = $2`,
 		models.OperatorCols(),
+		jsonText(DB, "spec", "load", "integration_id"),
+		jsonText(DB, "spec", "extract", "integration_id"),
 	)
 	args := []interface{}{resourceID, resourceID}
 
@@ -329,12 +360,12 @@ func (*operatorReader) GetLoadOPsByWorkflowAndResource(
 	SELECT %s
 	FROM operator
 	WHERE
-		json_extract(spec, '$.type') = '%s' AND 
+		%s = '%s' AND 
 		(
-			json_extract(spec, '$.load.parameters.table') = $1 OR
-			json_extract(spec, '$.load.parameters.filepath') = $1
+			%s = $1 OR
+			%s = $1
 		) AND
-		json_extract(spec, '$.load.integration_id') = $2 AND
+		%s = $2 AND
 		EXISTS 
 		(
 			SELECT 1 
@@ -349,7 +380,11 @@ func (*operatorReader) GetLoadOPsByWorkflowAndResource(
 			workflow_dag.workflow_id = $3
 		);`,
 		models.OperatorCols(),
+		jsonText(DB, "spec", "type"),
 		operator.LoadType,
+		jsonText(DB, "spec", "load", "parameters", "table"),
+		jsonText(DB, "spec", "load", "parameters", "filepath"),
+		jsonText(DB, "spec", "load", "integration_id"),
 	)
 	args := []interface{}{objectName, resourceID, workflowID}
 
@@ -365,9 +400,11 @@ func (*operatorReader) GetLoadOPsByResource(
 	query := fmt.Sprintf(
 		`SELECT %s FROM operator
 		WHERE 
-			json_extract(spec, '$.load.integration_id') = $1
-			OR json_extract(spec, '$.extract.integration_id') = $2`,
+			%s = $1
+			OR %s = $2`,
 		models.OperatorCols(),
+		jsonText(DB, "spec", "load", "integration_id"),
+		jsonText(DB, "spec", "extract", "integration_id"),
 	)
 	args := []interface{}{resourceID, resourceID}
 
@@ -398,8 +435,9 @@ func (*operatorReader) GetLoadOPSpecsByOrg(ctx context.Context, orgID string, DB
 			AND workflow_dag.id = workflow_dag_edge.workflow_dag_id 
 			AND workflow_dag_edge.to_id = operator.id 
 			AND artifact.id = workflow_dag_edge.from_id 
-			AND json_extract(operator.spec, '$.type') = '%s' 
+			AND %s = '%s' 
 			AND app_user.organization_id = $1;`,
+		jsonText(DB, "operator.spec", "type"),
 		operator.LoadType,
 	)
 	args := []interface{}{orgID}
@@ -468,22 +506,12 @@ func (*operatorReader) GetByEngineResourceID(
 	for _, field := range shared.ServiceToEngineConfigField {
 		workflow_condition_fragments = append(
 			workflow_condition_fragments,
-			fmt.Sprintf(
-				`json_extract(
-					workflow_dag.engine_config,
-					'$.%s.integration_id'
-				) = $1`,
-				field),
+			fmt.Sprintf("%s = $1", jsonText(DB, "workflow_dag.engine_config", field, "integration_id")),
 		)
 
 		operator_condition_fragments = append(
 			operator_condition_fragments,
-			fmt.Sprintf(
-				`json_extract(
-					operator.spec,
-					'$.engine_config.%s.integration_id'
-				) = $1`,
-				field),
+			fmt.Sprintf("%s = $1", jsonText(DB, "operator.spec", "engine_config", field, "integration_id")),
 		)
 	}
 
@@ -501,12 +529,13 @@ func (*operatorReader) GetByEngineResourceID(
 		)
 		AND (
 			(
-				json_extract(operator.spec, '$.engine_config') IS NULL
+				%s IS NULL
 				AND (%s)
 			)
 			OR (%s)
 		);`,
 		models.OperatorColsWithPrefix(),
+		jsonValue(DB, "operator.spec", "engine_config"),
 		workflow_condition,
 		operator_condition,
 	)
@@ -521,12 +550,8 @@ func (*operatorReader) GetForAqueductEngine(
 	ctx context.Context,
 	DB database.Database,
 ) ([]models.Operator, error) {
-	workflowCondition := `
-		json_extract(workflow_dag.engine_config, '$.type') == 'aqueduct'
-	`
-	operatorCondition := `
-		json_extract(operator.spec, '$.engine_config.type') == 'aqueduct'
-	`
+	workflowCondition := fmt.Sprintf("%s = 'aqueduct'", jsonText(DB, "workflow_dag.engine_config", "type"))
+	operatorCondition := fmt.Sprintf("%s = 'aqueduct'", jsonText(DB, "operator.spec", "engine_config", "type"))
 
 	query := fmt.Sprintf(`
 		SELECT DISTINCT %s FROM
@@ -539,12 +564,13 @@ func (*operatorReader) GetForAqueductEngine(
 		)
 		AND (
 			(
-				json_extract(operator.spec, '$.engine_config') IS NULL
+				%s IS NULL
 				AND (%s)
 			)
 			OR (%s)
 		);`,
 		models.OperatorColsWithPrefix(),
+		jsonValue(DB, "operator.spec", "engine_config"),
 		workflowCondition,
 		operatorCondition,
 	)
@@ -558,6 +584,7 @@ func (*operatorReader) GetUnusedCondaEnvNames(ctx context.Context, DB database.D
 	// Note that we use `OperatorToArtifactType` as the filtering condition because an operator
 	// is guaranteed to generate at least one artifact, so this filter is guaranteed to capture
 	// all operators involved in a workflow DAG.
+	condaEnvName := jsonText(DB, "operator.spec", "engine_config", "aqueduct_conda_config", "env")
 	query := fmt.Sprintf(`
 	WITH latest_workflow_dag AS
 	(
@@ -580,7 +607,7 @@ func (*operatorReader) GetUnusedCondaEnvNames(ctx context.Context, DB database.D
 	all_env_names AS
 	(
 		SELECT DISTINCT
-			json_extract(operator.spec, '$.engine_config.aqueduct_conda_config.env') AS name,
+			%s AS name,
 			operator.id as op_id
 		FROM 
 			workflow_dag_edge, operator
@@ -589,7 +616,7 @@ func (*operatorReader) GetUnusedCondaEnvNames(ctx context.Context, DB database.D
 			AND 
 			workflow_dag_edge.from_id = operator.id
 			AND
-			json_extract(operator.spec, '$.engine_config.aqueduct_conda_config.env') IS NOT NULL
+			%s IS NOT NULL
 	),
 	active_env_names AS
 	(
@@ -611,7 +638,9 @@ func (*operatorReader) GetUnusedCondaEnvNames(ctx context.Context, DB database.D
 		ON all_env_names.name = active_env_names.name
 	WHERE 
 		active_env_names.name IS NULL;`,
+		condaEnvName,
 		shared.OperatorToArtifactDAGEdge,
+		condaEnvName,
 		shared.OperatorToArtifactDAGEdge,
 	)
 
@@ -633,10 +662,51 @@ func (*operatorReader) GetUnusedCondaEnvNames(ctx context.Context, DB database.D
 	return results, nil
 }
 
+func (*operatorReader) GetFunctionStoragePaths(ctx context.Context, DB database.Database) ([]string, error) {
+	query := fmt.Sprintf(`
+	SELECT DISTINCT
+		storage_path
+	FROM (
+		SELECT
+			COALESCE(
+				%s,
+				%s,
+				%s
+			) AS storage_path
+		FROM
+			operator
+	) AS operator_storage_path
+	WHERE
+		storage_path IS NOT NULL
+		AND
+		storage_path <> '';`,
+		jsonText(DB, "operator.spec", "function", "storage_path"),
+		jsonText(DB, "operator.spec", "check", "function", "storage_path"),
+		jsonText(DB, "operator.spec", "metric", "function", "storage_path"),
+	)
+
+	type resultStruct struct {
+		StoragePath string `db:"storage_path"`
+	}
+
+	var resultRows []resultStruct
+	if err := DB.Query(ctx, &resultRows, query); err != nil {
+		return nil, err
+	}
+
+	results := make([]string, 0, len(resultRows))
+	for _, row := range resultRows {
+		results = append(results, row.StoragePath)
+	}
+
+	return results, nil
+}
+
 func (*operatorReader) GetByEngineType(ctx context.Context, engineType shared.EngineType, DB database.Database) ([]models.Operator, error) {
 	query := fmt.Sprintf(
-		"SELECT %s FROM operator WHERE json_extract(operator.spec, '$.engine_config.type') = $1;",
+		"SELECT %s FROM operator WHERE %s = $1;",
 		models.OperatorCols(),
+		jsonText(DB, "operator.spec", "engine_config", "type"),
 	)
 
 	return getOperators(ctx, DB, query, engineType)
@@ -650,15 +720,13 @@ func (*operatorReader) GetEngineTypesMapByDagIDs(
 	query := fmt.Sprintf(`
 		SELECT DISTINCT
 			workflow_dag_edge.workflow_dag_id as dag_id,
-			ifnull(
-				json_extract(operator.spec, '$.engine_config.type'),
-				''
-			) as engine_type
+			COALESCE(%s, '') as engine_type
 		FROM operator, workflow_dag_edge
 		WHERE
 			(workflow_dag_edge.from_id = operator.id
 			OR workflow_dag_edge.to_id = operator.id)
 			AND workflow_dag_edge.workflow_dag_id IN (%s);`,
+		jsonText(DB, "operator.spec", "engine_config", "type"),
 		stmt_preparers.GenerateArgsList(len(DagIDs), 1),
 	)
 	args := stmt_preparers.CastIdsListToInterfaceList(DagIDs)
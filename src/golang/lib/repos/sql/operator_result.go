@@ -1,4 +1,4 @@
-package sqlite
+package sql
 
 import (
 	"context"
@@ -70,6 +70,34 @@ func (*operatorResultReader) GetByDAGResultAndOperator(
 	return getOperatorResult(ctx, DB, query, args...)
 }
 
+func (*operatorResultReader) GetLatestSucceededByResultCacheKey(
+	ctx context.Context,
+	operatorID uuid.UUID,
+	cacheKey string,
+	since time.Time,
+	DB database.Database,
+) (*models.OperatorResult, error) {
+	query := fmt.Sprintf(
+		`SELECT %s
+		FROM operator_result, workflow_dag_result
+		WHERE
+			operator_result.workflow_dag_result_id = workflow_dag_result.id
+			AND operator_result.operator_id = $1
+			AND %s = $2
+			AND operator_result.status = $3
+			AND %s IS NULL
+			AND workflow_dag_result.created_at >= $4
+		ORDER BY workflow_dag_result.created_at DESC
+		LIMIT 1;`,
+		models.OperatorResultColsWithPrefix(),
+		jsonText(DB, "operator_result.execution_state", "result_cache_key"),
+		jsonText(DB, "operator_result.execution_state", "cached_from_dag_result_id"),
+	)
+	args := []interface{}{operatorID, cacheKey, shared.SucceededExecutionStatus, since}
+
+	return getOperatorResult(ctx, DB, query, args...)
+}
+
 func (*operatorResultReader) GetWithOperatorByDAGResultBatch(
 	ctx context.Context,
 	dagResultIDs []uuid.UUID,
@@ -89,9 +117,10 @@ func (*operatorResultReader) GetWithOperatorByDAGResultBatch(
 			operator_result.execution_state as execution_state
 		FROM operator, operator_result 
 		WHERE operator_result.workflow_dag_result_id IN (%s)
-		AND json_extract(operator.spec, '$.type') IN (%s)
+		AND %s IN (%s)
 		AND operator.id = operator_result.operator_id`,
 		stmt_preparers.GenerateArgsList(len(dagResultIDs), 1),
+		jsonText(DB, "operator.spec", "type"),
 		stmt_preparers.GenerateArgsList(len(types), 1+len(dagResultIDs)),
 	)
 
@@ -142,8 +171,9 @@ func (*operatorResultReader) GetCheckStatusByArtifactBatch(
 			workflow_dag_edge.to_id = operator.id 
 			AND operator.id = operator_result.operator_id 
 			AND workflow_dag_edge.from_id IN (%s) 
-			AND json_extract(operator.spec, '$.type') = '%s';`,
+			AND %s = '%s';`,
 		stmt_preparers.GenerateArgsList(len(artifactIDs), 1),
+		jsonText(DB, "operator.spec", "type"),
 		operator.CheckType,
 	)
 	args := stmt_preparers.CastIdsListToInterfaceList(artifactIDs)
@@ -251,6 +281,7 @@ func (*operatorResultWriter) UpdateBatchStatusByStatus(
 	DB database.Database,
 ) ([]models.OperatorResult, error) {
 	setExecStateFragment, args, err := generateUpdateExecStateSnippet(
+		DB,
 		models.OperatorResultExecState,
 		to,
 		time.Now(),
@@ -265,12 +296,12 @@ func (*operatorResultWriter) UpdateBatchStatusByStatus(
 			%s,
 			status = $%d
 		WHERE
-			json_extract(%s, '$.status') = $%d
+			%s = $%d
 		RETURNING %s;`,
 		models.OperatorResultTable,
 		setExecStateFragment,
 		len(args)+1,
-		models.OperatorResultExecState,
+		jsonText(DB, models.OperatorResultExecState, "status"),
 		len(args)+2,
 		models.OperatorResultCols(),
 	)
@@ -1,4 +1,4 @@
-package sqlite
+package sql
 
 import (
 	"context"
@@ -66,6 +66,14 @@ func (*resourceReader) GetByConfigField(ctx context.Context, fieldName string, f
 	// We parametrize the extracted field_name and field_value
 	// to prevent injection.
 	args := []interface{}{"$." + fieldName, fieldValue}
+	if isPostgres(DB) {
+		query = fmt.Sprintf(
+			"SELECT %s FROM %s WHERE config->>$1 = $2;",
+			models.ResourceCols(),
+			models.ResourceTable,
+		)
+		args = []interface{}{fieldName, fieldValue}
+	}
 
 	return getResources(ctx, DB, query, args...)
 }
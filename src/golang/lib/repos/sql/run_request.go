@@ -0,0 +1,315 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+type runRequestRepo struct {
+	runRequestReader
+	runRequestWriter
+}
+
+type runRequestReader struct{}
+
+type runRequestWriter struct{}
+
+func NewRunRequestRepo() repos.RunRequest {
+	return &runRequestRepo{
+		runRequestReader: runRequestReader{},
+		runRequestWriter: runRequestWriter{},
+	}
+}
+
+func (*runRequestReader) Get(ctx context.Context, id uuid.UUID, DB database.Database) (*models.RunRequest, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM run_request WHERE id = $1;`,
+		models.RunRequestCols(),
+	)
+	args := []interface{}{id}
+
+	return getRunRequest(ctx, DB, query, args...)
+}
+
+func (*runRequestReader) ListUnfinished(ctx context.Context, DB database.Database) ([]models.RunRequest, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM run_request WHERE status != $1 ORDER BY created_at ASC, id ASC;`,
+		models.RunRequestCols(),
+	)
+	args := []interface{}{shared.DoneRunRequestStatus}
+
+	return getRunRequests(ctx, DB, query, args...)
+}
+
+func (*runRequestWriter) Create(
+	ctx context.Context,
+	workflowID uuid.UUID,
+	parameters shared.RunParameters,
+	resumedDAGResultID *uuid.UUID,
+	waitReason string,
+	DB database.Database,
+) (*models.RunRequest, error) {
+	cols := []string{
+		models.RunRequestID,
+		models.RunRequestWorkflowID,
+		models.RunRequestStatus,
+		models.RunRequestParameters,
+		models.RunRequestResumedDAGResultID,
+		models.RunRequestWaitReason,
+		models.RunRequestCreatedAt,
+	}
+	query := DB.PrepareInsertWithReturnAllStmt(models.RunRequestTable, cols, models.RunRequestCols())
+
+	id, err := GenerateUniqueUUID(ctx, models.RunRequestTable, DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if parameters == nil {
+		parameters = shared.RunParameters{}
+	}
+
+	// SQLite stores times as text, so the times of run requests are always stored
+	// and compared in UTC.
+	args := []interface{}{
+		id,
+		workflowID,
+		shared.QueuedRunRequestStatus,
+		&parameters,
+		resumedDAGResultID,
+		waitReason,
+		time.Now().UTC(),
+	}
+
+	return getRunRequest(ctx, DB, query, args...)
+}
+
+func (*runRequestWriter) Claim(
+	ctx context.Context,
+	workerID string,
+	leaseDuration time.Duration,
+	DB database.Database,
+) (*models.RunRequest, error) {
+	// SQLite executes the statement atomically, so concurrent workers cannot claim the same request.
+	// On Postgres, SKIP LOCKED lets concurrent workers claim different requests instead of
+	// waiting on each other.
+	lockClause := ""
+	if isPostgres(DB) {
+		lockClause = "FOR UPDATE SKIP LOCKED"
+	}
+
+	// Leases are computed and compared with the clock of the database, so that they do not
+	// depend on the clocks of the replicas.
+	query := fmt.Sprintf(
+		`UPDATE run_request
+		SET status = $1, claimed_by = $2, lease_expires_at = %s, wait_reason = '', attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM run_request AS queued_request
+			WHERE status = $4 AND NOT (%s)
+			ORDER BY created_at ASC, id ASC
+			LIMIT 1
+			%s
+		)
+		RETURNING %s;`,
+		currentTimeAfter(DB, 3),
+		waitingForPreviousRunClause(DB, "queued_request"),
+		lockClause,
+		models.RunRequestCols(),
+	)
+	args := []interface{}{
+		shared.ClaimedRunRequestStatus,
+		workerID,
+		leaseDuration.Seconds(),
+		shared.QueuedRunRequestStatus,
+	}
+
+	return getRunRequest(ctx, DB, query, args...)
+}
+
+func (*runRequestWriter) MarkWaitingForPreviousRun(
+	ctx context.Context,
+	waitReason string,
+	DB database.Database,
+) ([]models.RunRequest, error) {
+	query := fmt.Sprintf(
+		`UPDATE run_request
+		SET wait_reason = $1
+		WHERE status = $2 AND wait_reason != $1 AND id IN (
+			SELECT id FROM run_request AS queued_request WHERE %s
+		)
+		RETURNING %s;`,
+		waitingForPreviousRunClause(DB, "queued_request"),
+		models.RunRequestCols(),
+	)
+	args := []interface{}{waitReason, shared.QueuedRunRequestStatus}
+
+	return getRunRequests(ctx, DB, query, args...)
+}
+
+func (*runRequestWriter) RenewLease(
+	ctx context.Context,
+	id uuid.UUID,
+	workerID string,
+	leaseDuration time.Duration,
+	DB database.Database,
+) (*models.RunRequest, error) {
+	query := fmt.Sprintf(
+		`UPDATE run_request
+		SET lease_expires_at = %s
+		WHERE id = $2 AND claimed_by = $3 AND status IN ($4, $5)
+		RETURNING %s;`,
+		currentTimeAfter(DB, 1),
+		models.RunRequestCols(),
+	)
+	args := []interface{}{
+		leaseDuration.Seconds(),
+		id,
+		workerID,
+		shared.ClaimedRunRequestStatus,
+		shared.RunningRunRequestStatus,
+	}
+
+	return getRunRequest(ctx, DB, query, args...)
+}
+
+func (*runRequestWriter) RequeueExpired(
+	ctx context.Context,
+	waitReason string,
+	DB database.Database,
+) ([]models.RunRequest, error) {
+	query := fmt.Sprintf(
+		`UPDATE run_request
+		SET status = $1, claimed_by = '', lease_expires_at = NULL, wait_reason = $2
+		WHERE status = $3 AND lease_expires_at < %s
+		RETURNING %s;`,
+		currentTime(DB),
+		models.RunRequestCols(),
+	)
+	args := []interface{}{
+		shared.QueuedRunRequestStatus,
+		waitReason,
+		shared.ClaimedRunRequestStatus,
+	}
+
+	return getRunRequests(ctx, DB, query, args...)
+}
+
+func (*runRequestWriter) FinishExpired(
+	ctx context.Context,
+	DB database.Database,
+) ([]models.RunRequest, error) {
+	query := fmt.Sprintf(
+		`UPDATE run_request
+		SET status = $1, lease_expires_at = NULL
+		WHERE status = $2 AND lease_expires_at < %s
+		RETURNING %s;`,
+		currentTime(DB),
+		models.RunRequestCols(),
+	)
+	args := []interface{}{
+		shared.DoneRunRequestStatus,
+		shared.RunningRunRequestStatus,
+	}
+
+	return getRunRequests(ctx, DB, query, args...)
+}
+
+func (*runRequestWriter) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	changes map[string]interface{},
+	DB database.Database,
+) (*models.RunRequest, error) {
+	var runRequest models.RunRequest
+	err := repos.UpdateRecordToDest(
+		ctx,
+		&runRequest,
+		changes,
+		models.RunRequestTable,
+		models.RunRequestID,
+		id,
+		models.RunRequestCols(),
+		DB,
+	)
+	return &runRequest, err
+}
+
+func (*runRequestWriter) DeleteDoneBefore(ctx context.Context, before time.Time, DB database.Database) error {
+	query := `DELETE FROM run_request WHERE status = $1 AND created_at < $2;`
+	args := []interface{}{shared.DoneRunRequestStatus, before.UTC()}
+	return DB.Execute(ctx, query, args...)
+}
+
+// waitingForPreviousRunClause returns a condition that holds if the run request `alias` must wait for
+// a previous run of its workflow, since the workflow has a queue overlap policy. The run waits while an
+// earlier request of the workflow is not done, so that the runs of the workflow execute one at a time,
+// in the order they were requested.
+func waitingForPreviousRunClause(DB database.Database, alias string) string {
+	return fmt.Sprintf(
+		`EXISTS (
+			SELECT 1 FROM workflow
+			WHERE workflow.id = %[1]s.workflow_id AND %[2]s = '%[3]s'
+		) AND EXISTS (
+			SELECT 1 FROM run_request AS previous_request
+			WHERE previous_request.workflow_id = %[1]s.workflow_id
+				AND previous_request.id != %[1]s.id
+				AND (
+					previous_request.status IN ('%[4]s', '%[5]s')
+					OR (
+						previous_request.status = '%[6]s'
+						AND (
+							previous_request.created_at < %[1]s.created_at
+							OR (previous_request.created_at = %[1]s.created_at AND previous_request.id < %[1]s.id)
+						)
+					)
+				)
+		)`,
+		alias,
+		jsonText(DB, "workflow.schedule", "overlap_policy"),
+		shared.QueueOverlapPolicy,
+		shared.ClaimedRunRequestStatus,
+		shared.RunningRunRequestStatus,
+		shared.QueuedRunRequestStatus,
+	)
+}
+
+func getRunRequests(
+	ctx context.Context,
+	DB database.Database,
+	query string,
+	args ...interface{},
+) ([]models.RunRequest, error) {
+	var runRequests []models.RunRequest
+	err := DB.Query(ctx, &runRequests, query, args...)
+	return runRequests, err
+}
+
+func getRunRequest(
+	ctx context.Context,
+	DB database.Database,
+	query string,
+	args ...interface{},
+) (*models.RunRequest, error) {
+	runRequests, err := getRunRequests(ctx, DB, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(runRequests) == 0 {
+		return nil, database.ErrNoRows()
+	}
+
+	if len(runRequests) != 1 {
+		return nil, errors.Newf("Expected 1 RunRequest but got %v", len(runRequests))
+	}
+
+	return &runRequests[0], nil
+}
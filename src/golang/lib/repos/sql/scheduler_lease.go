@@ -0,0 +1,104 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+)
+
+type schedulerLeaseRepo struct {
+	schedulerLeaseReader
+	schedulerLeaseWriter
+}
+
+type schedulerLeaseReader struct{}
+
+type schedulerLeaseWriter struct{}
+
+func NewSchedulerLeaseRepo() repos.SchedulerLease {
+	return &schedulerLeaseRepo{
+		schedulerLeaseReader: schedulerLeaseReader{},
+		schedulerLeaseWriter: schedulerLeaseWriter{},
+	}
+}
+
+func (*schedulerLeaseReader) Get(ctx context.Context, name string, DB database.Database) (*models.SchedulerLease, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM scheduler_lease WHERE name = $1;`,
+		models.SchedulerLeaseCols(),
+	)
+	args := []interface{}{name}
+
+	return getSchedulerLease(ctx, DB, query, args...)
+}
+
+func (*schedulerLeaseWriter) Acquire(
+	ctx context.Context,
+	name string,
+	holder string,
+	leaseDuration time.Duration,
+	DB database.Database,
+) (*models.SchedulerLease, error) {
+	// The conflicting row is only updated if the lease can be acquired, so no row is
+	// returned if another replica holds the lease. The expiration is computed with the
+	// clock of the database, so that it does not depend on the clocks of the replicas.
+	query := fmt.Sprintf(
+		`INSERT INTO scheduler_lease (name, holder, expires_at)
+		VALUES ($1, $2, %s)
+		ON CONFLICT (name) DO UPDATE
+		SET holder = excluded.holder, expires_at = excluded.expires_at
+		WHERE scheduler_lease.holder = excluded.holder OR scheduler_lease.expires_at < %s
+		RETURNING %s;`,
+		currentTimeAfter(DB, 3),
+		currentTime(DB),
+		models.SchedulerLeaseCols(),
+	)
+	args := []interface{}{name, holder, leaseDuration.Seconds()}
+
+	return getSchedulerLease(ctx, DB, query, args...)
+}
+
+func (*schedulerLeaseWriter) Release(ctx context.Context, name string, holder string, DB database.Database) error {
+	query := `DELETE FROM scheduler_lease WHERE name = $1 AND holder = $2;`
+	args := []interface{}{name, holder}
+
+	return DB.Execute(ctx, query, args...)
+}
+
+func getSchedulerLeases(
+	ctx context.Context,
+	DB database.Database,
+	query string,
+	args ...interface{},
+) ([]models.SchedulerLease, error) {
+	var leases []models.SchedulerLease
+	err := DB.Query(ctx, &leases, query, args...)
+	return leases, err
+}
+
+func getSchedulerLease(
+	ctx context.Context,
+	DB database.Database,
+	query string,
+	args ...interface{},
+) (*models.SchedulerLease, error) {
+	leases, err := getSchedulerLeases(ctx, DB, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(leases) == 0 {
+		return nil, database.ErrNoRows()
+	}
+
+	if len(leases) != 1 {
+		return nil, errors.Newf("Expected 1 SchedulerLease but got %v", len(leases))
+	}
+
+	return &leases[0], nil
+}
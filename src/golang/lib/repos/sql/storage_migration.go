@@ -1,10 +1,12 @@
-package sqlite
+package sql
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
@@ -31,13 +33,28 @@ func (*storageMigrationReader) List(
 	ctx context.Context,
 	DB database.Database,
 ) ([]models.StorageMigration, error) {
-	query := `SELECT * FROM storage_migration ORDER BY execution_state->>'timestamps'->>'registered_at' DESC`
-	return getStorageMigrations(ctx, DB, query+";")
+	query := fmt.Sprintf(
+		"SELECT * FROM storage_migration ORDER BY %s DESC;",
+		jsonText(DB, "execution_state", "timestamps", "registered_at"),
+	)
+	return getStorageMigrations(ctx, DB, query)
+}
+
+func (*storageMigrationReader) Get(
+	ctx context.Context,
+	id uuid.UUID,
+	DB database.Database,
+) (*models.StorageMigration, error) {
+	query := `SELECT * FROM storage_migration WHERE id = $1;`
+	return getStorageMigration(ctx, DB, query, id)
 }
 
 func (*storageMigrationWriter) Create(
 	ctx context.Context,
 	destResourceID *uuid.UUID,
+	prevStorageConfig *shared.StorageConfig,
+	destStorageConfig *shared.StorageConfig,
+	dryRun bool,
 	DB database.Database,
 ) (*models.StorageMigration, error) {
 	cols := []string{
@@ -45,6 +62,10 @@ func (*storageMigrationWriter) Create(
 		models.StorageMigrationDestResourceID,
 		models.StorageMigrationExecutionState,
 		models.StorageMigrationCurrent,
+		models.StorageMigrationPrevStorageConfig,
+		models.StorageMigrationDestStorageConfig,
+		models.StorageMigrationDryRun,
+		models.StorageMigrationProgress,
 	}
 
 	query := DB.PrepareInsertWithReturnAllStmt(models.StorageMigrationTable, cols, models.StorageMigrationCols())
@@ -59,17 +80,57 @@ func (*storageMigrationWriter) Create(
 		destResourceID,
 		createPendingExecState(),
 		false, // current
+		prevStorageConfig,
+		destStorageConfig,
+		dryRun,
+		&shared.StorageMigrationProgress{},
 	}
 
 	return getStorageMigration(ctx, DB, query, args...)
 }
 
+func (*storageMigrationReader) GetObjects(
+	ctx context.Context,
+	id uuid.UUID,
+	DB database.Database,
+) ([]models.StorageMigrationObject, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM storage_migration_object WHERE storage_migration_id = $1;`,
+		models.StorageMigrationObjectCols(),
+	)
+	var objects []models.StorageMigrationObject
+	err := DB.Query(ctx, &objects, query, id)
+	return objects, err
+}
+
 // Returns nil if there is no current migration in the table.
 func (*storageMigrationReader) Current(ctx context.Context, DB database.Database) (*models.StorageMigration, error) {
 	query := `SELECT * FROM storage_migration WHERE current = true;`
 	return getStorageMigration(ctx, DB, query)
 }
 
+func (*storageMigrationWriter) CreateObjects(
+	ctx context.Context,
+	id uuid.UUID,
+	checksums map[string]string,
+	DB database.Database,
+) error {
+	cols := []string{
+		models.StorageMigrationObjectStorageMigrationID,
+		models.StorageMigrationObjectKey,
+		models.StorageMigrationObjectChecksum,
+	}
+	query := DB.PrepareInsertStmt(models.StorageMigrationObjectTable, cols)
+
+	for key, checksum := range checksums {
+		if err := DB.Execute(ctx, query, id, key, checksum); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (*storageMigrationWriter) Update(ctx context.Context, id uuid.UUID, changes map[string]interface{}, DB database.Database) (*models.StorageMigration, error) {
 	var storageMigration models.StorageMigration
 	err := repos.UpdateRecordToDest(
@@ -1,4 +1,4 @@
-package sqlite
+package sql
 
 import (
 	"context"
@@ -81,7 +81,11 @@ func validateNodeOwnership(
 //	json_set(json_set(resource.execution_state, '$.status', 'succeeded'),
 //	  '$.timestamps.finished_at', '2023-03-27 14:13PM'
 //	) AS BLOB)`
+//
+// On Postgres, the exec state is JSONB, and the timestamps object is rebuilt with `||`
+// instead of using a nested jsonb_set path, since jsonb_set does not create missing keys.
 func generateUpdateExecStateSnippet(
+	DB database.Database,
 	columnAccessPath string,
 	status shared.ExecutionStatus,
 	timestamp time.Time,
@@ -101,20 +105,37 @@ func generateUpdateExecStateSnippet(
 		return "", nil, err
 	}
 
-	return fmt.Sprintf(`%s = CAST(
-		json_set(
-			json_set(%s, '$.status', $%d),
-			'$.timestamps.%s',
-			$%d
-		) AS BLOB)`,
+	if isPostgres(DB) {
+		return fmt.Sprintf(`%s = jsonb_set(
+		jsonb_set(%s, '{status}', to_jsonb($%d::text)),
+		'{timestamps}',
+		COALESCE(%s->'timestamps', '{}'::jsonb) || jsonb_build_object('%s', $%d::text)
+	)`,
 			columnAccessPath,
 			columnAccessPath,
 			offset+1,
+			columnAccessPath,
 			timestampField,
 			offset+2,
 		), []interface{}{
 			status, string(timestampValue),
 		}, nil
+	}
+
+	return fmt.Sprintf(`%s = CAST(
+		json_set(
+			json_set(%s, '$.status', $%d),
+			'$.timestamps.%s',
+			$%d
+		) AS BLOB)`,
+		columnAccessPath,
+		columnAccessPath,
+		offset+1,
+		timestampField,
+		offset+2,
+	), []interface{}{
+		status, string(timestampValue),
+	}, nil
 }
 
 // Creates the initial execution state for when we initially kick off some process (eg. storage migration).
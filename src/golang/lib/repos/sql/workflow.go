@@ -1,4 +1,4 @@
-package sqlite
+package sql
 
 import (
 	"context"
@@ -73,9 +73,10 @@ func (*workflowReader) GetByScheduleTrigger(
 ) ([]models.Workflow, error) {
 	query := fmt.Sprintf(
 		`SELECT %s FROM workflow WHERE
-			json_extract(schedule, '$.trigger') = $1;
+			%s = $1;
 		`,
 		models.WorkflowCols(),
+		jsonText(DB, "schedule", "trigger"),
 	)
 	args := []interface{}{trigger}
 
@@ -83,12 +84,24 @@ func (*workflowReader) GetByScheduleTrigger(
 }
 
 func (*workflowReader) GetTargets(ctx context.Context, ID uuid.UUID, DB database.Database) ([]uuid.UUID, error) {
-	query := `
+	sourceIDsQuery := `SELECT 1 FROM json_each(schedule, '$.source_ids') WHERE json_each.value = $2`
+	if isPostgres(DB) {
+		sourceIDsQuery = `SELECT 1 FROM jsonb_array_elements_text(schedule->'source_ids') AS source_id WHERE source_id = $2`
+	}
+
+	query := fmt.Sprintf(`
 		SELECT id FROM workflow
 		WHERE
-			json_extract(schedule, '$.trigger') = $1
-			AND json_extract(schedule, '$.source_id') = $2
-		;`
+			%s = $1
+			AND (
+				%s = $2
+				OR EXISTS (%s)
+			)
+		;`,
+		jsonText(DB, "schedule", "trigger"),
+		jsonText(DB, "schedule", "source_id"),
+		sourceIDsQuery,
+	)
 	args := []interface{}{shared.CascadingUpdateTrigger, ID}
 
 	var objectIDs []views.ObjectID
@@ -110,7 +123,7 @@ func (*workflowReader) GetLastRunByEngine(
 	engine shared.EngineType,
 	DB database.Database,
 ) ([]views.WorkflowLastRun, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT 
 			workflow.id AS workflow_id, 
 			workflow.schedule, 
@@ -137,7 +150,9 @@ func (*workflowReader) GetLastRunByEngine(
 			AND workflow_dag.id = workflow_dag_result.workflow_dag_id 
 			AND workflow.id = workflow_latest_run.id 
 			AND workflow_dag_result.created_at = workflow_latest_run.created_at
-			AND json_extract(workflow_dag.engine_config, '$.type') = $1;`
+			AND %s = $1;`,
+		jsonText(DB, "workflow_dag.engine_config", "type"),
+	)
 
 	var lastRuns []views.WorkflowLastRun
 	args := []interface{}{engine}
@@ -157,7 +172,7 @@ func (*workflowReader) GetLatestStatusesByOrg(ctx context.Context, orgID string,
 	// is no match with a row in the right table. If there is no match, the columns of the right table
 	// are NULL.
 	// This means that `last_run_at` and `status` in the query output can be NULL.
-	query := `
+	query := fmt.Sprintf(`
 		WITH workflow_results AS
 		(
 			SELECT 
@@ -165,7 +180,7 @@ func (*workflowReader) GetLatestStatusesByOrg(ctx context.Context, orgID string,
 		 		wf.description AS description, wf.created_at AS created_at,
 		 		wfdr.created_at AS run_at, wfdr.status as status,
 				wfdr.id as result_id, wfd.id AS dag_id,
-				json_extract(wfd.engine_config, '$.type') as engine
+				%s as engine
 			FROM 
 				workflow AS wf
 				INNER JOIN app_user ON wf.user_id = app_user.id
@@ -207,7 +222,9 @@ func (*workflowReader) GetLatestStatusesByOrg(ctx context.Context, orgID string,
 				)
 			)
 		ORDER BY 
-			created_at DESC;`
+			created_at DESC;`,
+		jsonText(DB, "wfd.engine_config", "type"),
+	)
 	args := []interface{}{orgID}
 
 	var latestWorkflowResponse []views.LatestWorkflowStatus
@@ -252,6 +269,7 @@ func (*workflowWriter) Create(
 	schedule *shared.Schedule,
 	retentionPolicy *shared.RetentionPolicy,
 	notificationSettings *shared.NotificationSettings,
+	maxParallelOperators int,
 	DB database.Database,
 ) (*models.Workflow, error) {
 	cols := []string{
@@ -263,6 +281,7 @@ func (*workflowWriter) Create(
 		models.WorkflowCreatedAt,
 		models.WorkflowRetentionPolicy,
 		models.WorkflowNotificationSettings,
+		models.WorkflowMaxParallelOperators,
 	}
 	query := DB.PrepareInsertWithReturnAllStmt(models.WorkflowTable, cols, models.WorkflowCols())
 
@@ -271,7 +290,7 @@ func (*workflowWriter) Create(
 		return nil, err
 	}
 
-	args := []interface{}{ID, userID, name, description, schedule, time.Now(), retentionPolicy, notificationSettings}
+	args := []interface{}{ID, userID, name, description, schedule, time.Now(), retentionPolicy, notificationSettings, maxParallelOperators}
 	return getWorkflow(ctx, DB, query, args...)
 }
 
@@ -301,7 +320,30 @@ func (*workflowWriter) Update(
 	return &workflow, err
 }
 
+func (*workflowWriter) Lock(ctx context.Context, ID uuid.UUID, DB database.Database) error {
+	// SQLite transactions are IMMEDIATE, so they already hold the write lock of the database.
+	if !isPostgres(DB) {
+		return nil
+	}
+
+	query := `SELECT id FROM workflow WHERE id = $1 FOR UPDATE;`
+	args := []interface{}{ID}
+	return DB.Execute(ctx, query, args...)
+}
+
 func (*workflowWriter) RemoveNotificationFromSettings(ctx context.Context, notificationResourceID uuid.UUID, DB database.Database) error {
+	if isPostgres(DB) {
+		query := `
+		UPDATE workflow
+		SET
+			notification_settings = notification_settings #- $1::text[]
+		WHERE
+			notification_settings IS NOT NULL
+			AND notification_settings #> $1::text[] IS NOT NULL;`
+		jsonPath := fmt.Sprintf("{settings,%s}", notificationResourceID)
+		return DB.Execute(ctx, query, jsonPath)
+	}
+
 	query := `
 	UPDATE workflow
 	SET
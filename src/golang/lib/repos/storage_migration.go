@@ -5,6 +5,7 @@ import (
 
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/google/uuid"
 )
 
@@ -14,6 +15,13 @@ type StorageMigration interface {
 }
 
 type storageMigrationReader interface {
+	// Get returns the storage migration entry with the given ID.
+	Get(
+		ctx context.Context,
+		id uuid.UUID,
+		DB database.Database,
+	) (*models.StorageMigration, error)
+
 	// List returns all the storage migration entries.
 	// The returned list is expected to be ordered in reverse chronological order (latest migrations first).
 	List(
@@ -21,6 +29,13 @@ type storageMigrationReader interface {
 		DB database.Database,
 	) ([]models.StorageMigration, error)
 
+	// GetObjects returns the objects that the storage migration with the given ID copied and verified.
+	GetObjects(
+		ctx context.Context,
+		id uuid.UUID,
+		DB database.Database,
+	) ([]models.StorageMigrationObject, error)
+
 	// Current returns the one storage migration entry marked `current`. Returns an ErrNoRows if it does not exist yet.
 	Current(
 		ctx context.Context,
@@ -31,12 +46,25 @@ type storageMigrationReader interface {
 type storageMigrationWriter interface {
 	// Create inserts a new storage migration entry with all the starter fields.
 	// A nil resource id refers to the local filesystem.
+	// The storage configs are recorded so that the migration can be resumed or rolled back.
 	Create(
 		ctx context.Context,
 		destResourceID *uuid.UUID,
+		prevStorageConfig *shared.StorageConfig,
+		destStorageConfig *shared.StorageConfig,
+		dryRun bool,
 		DB database.Database,
 	) (*models.StorageMigration, error)
 
+	// CreateObjects records the objects that the storage migration with the given ID copied and verified.
+	// `checksums` maps the key of every object to its checksum.
+	CreateObjects(
+		ctx context.Context,
+		id uuid.UUID,
+		checksums map[string]string,
+		DB database.Database,
+	) error
+
 	// Update updates the storage migration entry with the given ID.
 	Update(
 		ctx context.Context,
@@ -68,29 +68,57 @@ func (ts *TestSuite) TestArtifactResult_GetByArtifactAndWorkflow() {
 }
 
 func (ts *TestSuite) TestArtifactResult_GetByArtifactAndDAGResult() {
-	expectedArtifactResults, artifact, dag, _ := ts.seedArtifactResult(1)
+	expectedArtifactResults, artifact, dagResult, _ := ts.seedArtifactResult(1)
 	expectedArtifactResult := expectedArtifactResults[0]
 
-	actualArtifactResult, err := ts.artifactResult.GetByArtifactAndDAGResult(ts.ctx, artifact.ID, dag.ID, ts.DB)
+	actualArtifactResult, err := ts.artifactResult.GetByArtifactAndDAGResult(ts.ctx, artifact.ID, dagResult.ID, ts.DB)
 
 	require.Nil(ts.T(), err)
 	requireDeepEqual(ts.T(), expectedArtifactResult, *actualArtifactResult)
 }
 
 func (ts *TestSuite) TestArtifactResult_GetByDAGResults() {
-	expectedArtifactResults, _, dag, _ := ts.seedArtifactResult(3)
+	expectedArtifactResults, _, dagResult, _ := ts.seedArtifactResult(3)
 
-	actualArtifactResults, err := ts.artifactResult.GetByDAGResults(ts.ctx, []uuid.UUID{dag.ID}, ts.DB)
+	actualArtifactResults, err := ts.artifactResult.GetByDAGResults(ts.ctx, []uuid.UUID{dagResult.ID}, ts.DB)
 	require.Nil(ts.T(), err)
 	require.Equal(ts.T(), 3, len(actualArtifactResults))
 	requireDeepEqualArtifactResults(ts.T(), expectedArtifactResults, actualArtifactResults)
 }
 
+func (ts *TestSuite) TestArtifactResult_GetByContentPaths() {
+	artifactResults, _, _, _ := ts.seedArtifactResult(3)
+
+	actualArtifactResults, err := ts.artifactResult.GetByContentPaths(
+		ts.ctx,
+		[]string{artifactResults[0].ContentPath, artifactResults[1].ContentPath, randString(10)},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	requireDeepEqualArtifactResults(ts.T(), artifactResults[:2], actualArtifactResults)
+}
+
+func (ts *TestSuite) TestArtifactResult_GetContentPaths() {
+	artifactResults, _, _, _ := ts.seedArtifactResult(3)
+
+	expectedContentPaths := make([]string, 0, len(artifactResults))
+	for _, artifactResult := range artifactResults {
+		expectedContentPaths = append(expectedContentPaths, artifactResult.ContentPath)
+	}
+
+	actualContentPaths, err := ts.artifactResult.GetContentPaths(ts.ctx, ts.DB)
+	require.Nil(ts.T(), err)
+	require.ElementsMatch(ts.T(), expectedContentPaths, actualContentPaths)
+}
+
 func (ts *TestSuite) TestArtifactResult_Create() {
+	artifact, dag, _, _ := ts.seedArtifactInWorkflow()
+	dagResult := ts.seedDAGResultWithDAG(1, []uuid.UUID{dag.ID})[0]
+
 	tmpTime := time.Now()
 	expectedArtifactResult := &models.ArtifactResult{
-		DAGResultID: uuid.New(),
-		ArtifactID:  uuid.New(),
+		DAGResultID: dagResult.ID,
+		ArtifactID:  artifact.ID,
 		ContentPath: randString(10),
 		Status:      shared.PendingExecutionStatus,
 		ExecState: shared.NullExecutionState{
@@ -119,6 +147,9 @@ func (ts *TestSuite) TestArtifactResult_Create() {
 }
 
 func (ts *TestSuite) TestArtifactResult_CreateWithExecStateAndMetadata() {
+	artifact, dag, _, _ := ts.seedArtifactInWorkflow()
+	dagResult := ts.seedDAGResultWithDAG(1, []uuid.UUID{dag.ID})[0]
+
 	schema := make([]map[string]string, 1)
 	schema[0] = make(map[string]string)
 	schema[0][randString(10)] = randString(10)
@@ -127,8 +158,8 @@ func (ts *TestSuite) TestArtifactResult_CreateWithExecStateAndMetadata() {
 	systemMetrics[randString(10)] = randString(10)
 
 	expectedArtifactResult := &models.ArtifactResult{
-		DAGResultID: uuid.New(),
-		ArtifactID:  uuid.New(),
+		DAGResultID: dagResult.ID,
+		ArtifactID:  artifact.ID,
 		ContentPath: randString(10),
 		Status:      shared.CanceledExecutionStatus,
 		ExecState: shared.NullExecutionState{
@@ -181,7 +212,7 @@ func (ts *TestSuite) TestArtifactResult_DeleteBatch() {
 	artifactResults, _, _, _ := ts.seedArtifactResult(3)
 	IDs := []uuid.UUID{artifactResults[0].ID, artifactResults[1].ID, artifactResults[2].ID}
 
-	err := ts.dag.DeleteBatch(ts.ctx, IDs, ts.DB)
+	err := ts.artifactResult.DeleteBatch(ts.ctx, IDs, ts.DB)
 	require.Nil(ts.T(), err)
 }
 
@@ -3,6 +3,8 @@ package tests
 import (
 	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/views"
@@ -45,6 +47,108 @@ func (ts *TestSuite) TestDAGResult_GetByWorkflow() {
 	requireDeepEqualDAGResults(ts.T(), expectedDAGResults, actualDAGResults)
 }
 
+func (ts *TestSuite) TestDAGResult_GetInProgressByWorkflow() {
+	dags := ts.seedDAG(1)
+	dag := dags[0]
+
+	dagResults := ts.seedDAGResultWithDAG(3, []uuid.UUID{dag.ID, dag.ID, dag.ID})
+
+	// The DAGResult in the middle is no longer in progress.
+	_, err := ts.dagResult.Update(
+		ts.ctx,
+		dagResults[1].ID,
+		map[string]interface{}{
+			models.DAGResultStatus: shared.SucceededExecutionStatus,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	actualDAGResults, err := ts.dagResult.GetInProgressByWorkflow(ts.ctx, dag.WorkflowID, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), 2, len(actualDAGResults))
+	require.Equal(ts.T(), dagResults[0].ID, actualDAGResults[0].ID)
+	require.Equal(ts.T(), dagResults[2].ID, actualDAGResults[1].ID)
+}
+
+func (ts *TestSuite) TestDAGResult_GetAbandoned() {
+	workflows := ts.seedWorkflow(3)
+	dags := ts.seedDAGWithWorkflow(3, []uuid.UUID{workflows[0].ID, workflows[1].ID, workflows[2].ID})
+	dagResults := ts.seedDAGResultWithDAG(4, []uuid.UUID{dags[0].ID, dags[1].ID, dags[2].ID, dags[2].ID})
+
+	// claim claims a new run request of the workflow with a lease of leaseDuration,
+	// and records dagResultID as the run it created if it is set.
+	claim := func(workflowID uuid.UUID, leaseDuration time.Duration, dagResultID uuid.UUID) {
+		ts.seedRunRequest(1, workflowID)
+		runRequest, err := ts.runRequest.Claim(ts.ctx, "worker", leaseDuration, ts.DB)
+		require.Nil(ts.T(), err)
+
+		if dagResultID != uuid.Nil {
+			_, err = ts.runRequest.Update(
+				ts.ctx,
+				runRequest.ID,
+				map[string]interface{}{
+					models.RunRequestDAGResultID: dagResultID,
+				},
+				ts.DB,
+			)
+			require.Nil(ts.T(), err)
+		}
+	}
+
+	// The run request of the first DAGResult is executed by a worker, while the worker of the
+	// second DAGResult's run request stopped renewing its lease. The third DAGResult is not
+	// executed by the live run request of its workflow.
+	claim(dags[0].WorkflowID, time.Minute, dagResults[0].ID)
+	claim(dags[1].WorkflowID, -time.Minute, dagResults[1].ID)
+	claim(dags[2].WorkflowID, time.Minute, uuid.Nil)
+
+	// Finished DAGResults are never abandoned.
+	_, err := ts.dagResult.Update(
+		ts.ctx,
+		dagResults[3].ID,
+		map[string]interface{}{
+			models.DAGResultStatus: shared.SucceededExecutionStatus,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	abandoned, err := ts.dagResult.GetAbandoned(ts.ctx, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), 2, len(abandoned))
+	require.Equal(ts.T(), dagResults[1].ID, abandoned[0].ID)
+	require.Equal(ts.T(), dagResults[2].ID, abandoned[1].ID)
+}
+
+func (ts *TestSuite) TestDAGResult_GetLatestSucceededByWorkflow() {
+	dags := ts.seedDAG(1)
+	dag := dags[0]
+
+	dagResults := ts.seedDAGResultWithDAG(3, []uuid.UUID{dag.ID, dag.ID, dag.ID})
+
+	// Only the first two DAGResults succeeded.
+	for _, dagResult := range dagResults[:2] {
+		_, err := ts.dagResult.Update(
+			ts.ctx,
+			dagResult.ID,
+			map[string]interface{}{
+				models.DAGResultStatus: shared.SucceededExecutionStatus,
+			},
+			ts.DB,
+		)
+		require.Nil(ts.T(), err)
+	}
+
+	actualDAGResult, err := ts.dagResult.GetLatestSucceededByWorkflow(ts.ctx, dag.WorkflowID, time.Time{}, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), dagResults[1].ID, actualDAGResult.ID)
+
+	// No DAGResult succeeded after the second one was created.
+	_, err = ts.dagResult.GetLatestSucceededByWorkflow(ts.ctx, dag.WorkflowID, dagResults[1].CreatedAt, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+}
+
 func (ts *TestSuite) TestDAGResult_GetKOffsetByWorkflow() {
 	dags := ts.seedDAG(1)
 	dag := dags[0]
@@ -177,6 +281,54 @@ func (ts *TestSuite) TestDAGResult_Update() {
 	require.Nil(ts.T(), err)
 
 	requireDeepEqual(ts.T(), newExecState, newDAGResult.ExecState.ExecutionState)
+	require.False(ts.T(), newDAGResult.Pinned)
+
+	newDAGResult, err = ts.dagResult.Update(
+		ts.ctx,
+		dagResult.ID,
+		map[string]interface{}{models.DAGResultPinned: true},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	require.True(ts.T(), newDAGResult.Pinned)
+}
+
+func (ts *TestSuite) TestDAGResult_UpdateInProgress() {
+	dagResults := ts.seedDAGResult(1)
+	dagResult := dagResults[0]
+
+	canceledState := shared.ExecutionState{
+		Status:     shared.CanceledExecutionStatus,
+		Timestamps: dagResult.ExecState.Timestamps,
+	}
+
+	newDAGResult, err := ts.dagResult.UpdateInProgress(
+		ts.ctx,
+		dagResult.ID,
+		map[string]interface{}{
+			models.DAGResultStatus:    shared.CanceledExecutionStatus,
+			models.DAGResultExecState: &canceledState,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), shared.CanceledExecutionStatus, newDAGResult.Status)
+	requireDeepEqual(ts.T(), canceledState, newDAGResult.ExecState.ExecutionState)
+
+	// A run that has finished is not updated again.
+	_, err = ts.dagResult.UpdateInProgress(
+		ts.ctx,
+		dagResult.ID,
+		map[string]interface{}{
+			models.DAGResultStatus: shared.SucceededExecutionStatus,
+		},
+		ts.DB,
+	)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+
+	actualDAGResult, err := ts.dagResult.Get(ts.ctx, dagResult.ID, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), shared.CanceledExecutionStatus, actualDAGResult.Status)
 }
 
 func (ts *TestSuite) TestDAGResult_UpdateBatchStatusByStatus() {
@@ -4,10 +4,21 @@ import (
 	"flag"
 	"testing"
 
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/stretchr/testify/suite"
 )
 
-var runTests = flag.Bool("database", false, "If this flag is set, the database resource tests will be run.")
+var (
+	runTests = flag.Bool("database", false, "If this flag is set, the database resource tests will be run.")
+	dbType   = flag.String("dbType", "sqlite", "The database dialect to run the tests against: sqlite or postgres.")
+
+	// Postgres config, only used when dbType is postgres.
+	host     = flag.String("host", "localhost", "The host of the Postgres server to connect to.")
+	port     = flag.String("port", "5432", "The port number to connect to the Postgres database.")
+	user     = flag.String("username", "postgres", "The username for connecting to the Postgres database.")
+	password = flag.String("password", "", "The password for connecting to the Postgres database.")
+	dbName   = flag.String("dbName", "aqueduct_test", "The Postgres database to run the tests against.")
+)
 
 // TestDatabaseSuite is the entrypoint for all database resource tests
 // in this package.
@@ -17,5 +28,16 @@ func TestDatabaseSuite(t *testing.T) {
 		t.Skip("Skipping database resource tests.")
 	}
 
-	suite.Run(t, new(TestSuite))
+	suite.Run(t, &TestSuite{
+		dbConfig: &database.DatabaseConfig{
+			Type: database.Type(*dbType),
+			Postgres: &database.PostgresConfig{
+				Address:  *host,
+				Port:     *port,
+				UserName: *user,
+				Password: *password,
+				Database: *dbName,
+			},
+		},
+	})
 }
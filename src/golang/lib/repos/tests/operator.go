@@ -4,6 +4,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/check"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/connector"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/function"
 	"github.com/aqueducthq/aqueduct/lib/models/utils"
@@ -237,6 +238,26 @@ func (ts *TestSuite) TestOperator_ValidateOrg() {
 	require.False(ts.T(), invalid)
 }
 
+func (ts *TestSuite) TestOperator_GetFunctionStoragePaths() {
+	// Operators without a storage path are not returned.
+	ts.seedOperator(2)
+
+	specs := []*operator.Spec{
+		operator.NewSpecFromFunction(function.Function{StoragePath: "function-path"}),
+		operator.NewSpecFromCheck(check.Check{Function: function.Function{StoragePath: "check-path"}}),
+		// Operators can share the same code.
+		operator.NewSpecFromFunction(function.Function{StoragePath: "function-path"}),
+	}
+	for _, spec := range specs {
+		_, err := ts.operator.Create(ts.ctx, randString(10), randString(15), spec, nil, ts.DB)
+		require.Nil(ts.T(), err)
+	}
+
+	storagePaths, err := ts.operator.GetFunctionStoragePaths(ts.ctx, ts.DB)
+	require.Nil(ts.T(), err)
+	require.ElementsMatch(ts.T(), []string{"function-path", "check-path"}, storagePaths)
+}
+
 func (ts *TestSuite) TestOperator_GetUnusedCondaEnvNames() {
 	artifactID := uuid.New()
 	users := ts.seedUser(1)
@@ -3,6 +3,8 @@ package tests
 import (
 	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
@@ -13,7 +15,7 @@ import (
 )
 
 func (ts *TestSuite) TestOperatorResult_Get() {
-	operatorResults := ts.seedOperatorResultForDAGAndOperator(1, uuid.New(), uuid.New())
+	operatorResults := ts.seedOperatorResultForDAGAndOperator(1, ts.seedDAGResult(1)[0].ID, ts.seedOperator(1)[0].ID)
 	expectedOperatorResult := operatorResults[0]
 
 	actualOperatorResult, err := ts.operatorResult.Get(ts.ctx, expectedOperatorResult.ID, ts.DB)
@@ -22,7 +24,7 @@ func (ts *TestSuite) TestOperatorResult_Get() {
 }
 
 func (ts *TestSuite) TestOperatorResult_GetBatch() {
-	expectedOperatorResults := ts.seedOperatorResultForDAGAndOperator(3, uuid.New(), uuid.New())
+	expectedOperatorResults := ts.seedOperatorResultForDAGAndOperator(3, ts.seedDAGResult(1)[0].ID, ts.seedOperator(1)[0].ID)
 
 	IDs := make([]uuid.UUID, 0, len(expectedOperatorResults))
 	for _, expectedOperatorResult := range expectedOperatorResults {
@@ -35,7 +37,7 @@ func (ts *TestSuite) TestOperatorResult_GetBatch() {
 }
 
 func (ts *TestSuite) TestOperatorResult_GetByDAGResultAndOperator() {
-	operatorResults := ts.seedOperatorResultForDAGAndOperator(1, uuid.New(), uuid.New())
+	operatorResults := ts.seedOperatorResultForDAGAndOperator(1, ts.seedDAGResult(1)[0].ID, ts.seedOperator(1)[0].ID)
 	expectedOperatorResult := operatorResults[0]
 
 	actualOperatorResult, err := ts.operatorResult.GetByDAGResultAndOperator(ts.ctx, expectedOperatorResult.DAGResultID, expectedOperatorResult.OperatorID, ts.DB)
@@ -47,16 +49,121 @@ func (ts *TestSuite) TestOperatorResult_GetByDAGResultAndOperator() {
 }
 
 func (ts *TestSuite) TestOperatorResult_GetByDAGResultBatch() {
-	operatorId := uuid.New()
-	expectedOperatorResultsA := ts.seedOperatorResultForDAGAndOperator(3, uuid.New(), operatorId)
-	expectedOperatorResultsB := ts.seedOperatorResultForDAGAndOperator(3, uuid.New(), operatorId)
-	_ = ts.seedOperatorResultForDAGAndOperator(3, uuid.New(), operatorId)
+	operatorId := ts.seedOperator(1)[0].ID
+	dagResults := ts.seedDAGResult(3)
+	expectedOperatorResultsA := ts.seedOperatorResultForDAGAndOperator(3, dagResults[0].ID, operatorId)
+	expectedOperatorResultsB := ts.seedOperatorResultForDAGAndOperator(3, dagResults[1].ID, operatorId)
+	_ = ts.seedOperatorResultForDAGAndOperator(3, dagResults[2].ID, operatorId)
 
 	actualOperatorResults, err := ts.operatorResult.GetByDAGResultBatch(ts.ctx, []uuid.UUID{expectedOperatorResultsA[0].DAGResultID, expectedOperatorResultsB[0].DAGResultID}, ts.DB)
 	require.Nil(ts.T(), err)
 	requireDeepEqualOperatorResults(ts.T(), append(expectedOperatorResultsA, expectedOperatorResultsB...), actualOperatorResults)
 }
 
+func (ts *TestSuite) TestOperatorResult_GetLatestSucceededByResultCacheKey() {
+	dagResults := ts.seedDAGResult(3)
+	operatorID := ts.seedOperator(1)[0].ID
+	cacheKey := randString(10)
+
+	// The first two runs succeeded with the same cache key, and the last one has a different key.
+	cacheKeys := []string{cacheKey, cacheKey, randString(10)}
+	operatorResults := make([]models.OperatorResult, 0, len(dagResults))
+	for i, dagResult := range dagResults {
+		operatorResult := ts.seedOperatorResultForDAGAndOperator(1, dagResult.ID, operatorID)[0]
+
+		execState := shared.NullExecutionState{
+			ExecutionState: shared.ExecutionState{
+				Status:         shared.SucceededExecutionStatus,
+				ResultCacheKey: cacheKeys[i],
+			},
+		}
+		changes := map[string]interface{}{
+			models.OperatorResultStatus:    shared.SucceededExecutionStatus,
+			models.OperatorResultExecState: &execState,
+		}
+		updatedOperatorResult, err := ts.operatorResult.Update(ts.ctx, operatorResult.ID, changes, ts.DB)
+		require.Nil(ts.T(), err)
+
+		operatorResults = append(operatorResults, *updatedOperatorResult)
+	}
+
+	expectedOperatorResult := operatorResults[0]
+	if dagResults[1].CreatedAt.After(dagResults[0].CreatedAt) {
+		expectedOperatorResult = operatorResults[1]
+	}
+
+	actualOperatorResult, err := ts.operatorResult.GetLatestSucceededByResultCacheKey(
+		ts.ctx,
+		operatorID,
+		cacheKey,
+		time.Now().Add(-time.Hour),
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	requireDeepEqual(ts.T(), &expectedOperatorResult, actualOperatorResult)
+
+	// Results of runs created before `since` are not returned.
+	_, err = ts.operatorResult.GetLatestSucceededByResultCacheKey(
+		ts.ctx,
+		operatorID,
+		cacheKey,
+		time.Now().Add(time.Hour),
+		ts.DB,
+	)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+}
+
+func (ts *TestSuite) TestOperatorResult_GetLatestSucceededByResultCacheKey_Reused() {
+	operatorID := ts.seedOperator(1)[0].ID
+	cacheKey := randString(10)
+	dagID := ts.seedDAG(1)[0].ID
+
+	// setSucceeded marks a new result of the operator as succeeded with the cache key.
+	setSucceeded := func(dagResultID uuid.UUID, cachedFromDAGResultID *uuid.UUID) models.OperatorResult {
+		operatorResult := ts.seedOperatorResultForDAGAndOperator(1, dagResultID, operatorID)[0]
+		execState := shared.NullExecutionState{
+			ExecutionState: shared.ExecutionState{
+				Status:                shared.SucceededExecutionStatus,
+				ResultCacheKey:        cacheKey,
+				CachedFromDAGResultID: cachedFromDAGResultID,
+			},
+		}
+		changes := map[string]interface{}{
+			models.OperatorResultStatus:    shared.SucceededExecutionStatus,
+			models.OperatorResultExecState: &execState,
+		}
+		updatedOperatorResult, err := ts.operatorResult.Update(ts.ctx, operatorResult.ID, changes, ts.DB)
+		require.Nil(ts.T(), err)
+		return *updatedOperatorResult
+	}
+
+	computedDAGResult := ts.seedDAGResultWithDAG(1, []uuid.UUID{dagID})[0]
+	computedOperatorResult := setSucceeded(computedDAGResult.ID, nil)
+
+	time.Sleep(10 * time.Millisecond)
+	expiredAt := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	// Two later runs reused the computed result, and both are within the TTL.
+	for _, dagResult := range ts.seedDAGResultWithDAG(2, []uuid.UUID{dagID, dagID}) {
+		setSucceeded(dagResult.ID, &computedDAGResult.ID)
+	}
+
+	// The TTL counts from the run that computed the result, so the operator must be recomputed.
+	_, err := ts.operatorResult.GetLatestSucceededByResultCacheKey(ts.ctx, operatorID, cacheKey, expiredAt, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+
+	actualOperatorResult, err := ts.operatorResult.GetLatestSucceededByResultCacheKey(
+		ts.ctx,
+		operatorID,
+		cacheKey,
+		computedDAGResult.CreatedAt.Add(-time.Second),
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	requireDeepEqual(ts.T(), &computedOperatorResult, actualOperatorResult)
+}
+
 func (ts *TestSuite) TestOperatorResult_GetCheckStatusByArtifactBatch() {
 	expectedOperatorResults, operator, artifactID := ts.seedOperatorResult(3, operator.CheckType)
 
@@ -83,15 +190,18 @@ func (ts *TestSuite) TestOperatorResult_GetStatusByDAGResultAndArtifactBatch() {
 	artifactIDA := uuid.New()
 	artifactIDB := uuid.New()
 
-	dagResultIDA := uuid.New()
-	dagResultIDB := uuid.New()
+	dags := ts.seedDAG(2)
+	dagResults := ts.seedDAGResultWithDAG(2, []uuid.UUID{dags[0].ID, dags[1].ID})
+
+	dagResultIDA := dagResults[0].ID
+	dagResultIDB := dagResults[1].ID
 	dagResultIDC := dagResultIDB
 	dagResultIDD := dagResultIDB
 
-	operatorA := ts.seedOperatorAndDAGOperatorToArtifact(artifactIDA, dagResultIDA, operator.FunctionType)
-	operatorB := ts.seedOperatorAndDAGOperatorToArtifact(artifactIDB, dagResultIDB, operator.FunctionType)
-	operatorC := ts.seedOperatorAndDAGOperatorToArtifact(artifactIDA, dagResultIDC, operator.FunctionType)
-	operatorD := ts.seedOperatorAndDAGOperatorToArtifact(uuid.New(), dagResultIDD, operator.FunctionType)
+	operatorA := ts.seedOperatorAndDAGOperatorToArtifact(artifactIDA, dags[0].ID, operator.FunctionType)
+	operatorB := ts.seedOperatorAndDAGOperatorToArtifact(artifactIDB, dags[1].ID, operator.FunctionType)
+	operatorC := ts.seedOperatorAndDAGOperatorToArtifact(artifactIDA, dags[1].ID, operator.FunctionType)
+	operatorD := ts.seedOperatorAndDAGOperatorToArtifact(uuid.New(), dags[1].ID, operator.FunctionType)
 
 	expectedOperatorResultsA := ts.seedOperatorResultForDAGAndOperator(3, dagResultIDA, operatorA.ID)
 	expectedOperatorResultsB := ts.seedOperatorResultForDAGAndOperator(3, dagResultIDB, operatorB.ID)
@@ -144,8 +254,8 @@ func (ts *TestSuite) TestOperatorResult_GetStatusByDAGResultAndArtifactBatch() {
 }
 
 func (ts *TestSuite) TestOperatorResult_Create() {
-	dagResultID := uuid.New()
-	operatorID := uuid.New()
+	dagResultID := ts.seedDAGResult(1)[0].ID
+	operatorID := ts.seedOperator(1)[0].ID
 	now := time.Now()
 	execState := shared.ExecutionState{
 		Status: shared.PendingExecutionStatus,
@@ -178,14 +288,14 @@ func (ts *TestSuite) TestOperatorResult_Create() {
 }
 
 func (ts *TestSuite) TestOperatorResult_Delete() {
-	operatorResults := ts.seedOperatorResultForDAGAndOperator(1, uuid.New(), uuid.New())
+	operatorResults := ts.seedOperatorResultForDAGAndOperator(1, ts.seedDAGResult(1)[0].ID, ts.seedOperator(1)[0].ID)
 
 	err := ts.operatorResult.Delete(ts.ctx, operatorResults[0].ID, ts.DB)
 	require.Nil(ts.T(), err)
 }
 
 func (ts *TestSuite) TestOperatorResult_DeleteBatch() {
-	operatorResults := ts.seedOperatorResultForDAGAndOperator(3, uuid.New(), uuid.New())
+	operatorResults := ts.seedOperatorResultForDAGAndOperator(3, ts.seedDAGResult(1)[0].ID, ts.seedOperator(1)[0].ID)
 
 	IDs := make([]uuid.UUID, 0, len(operatorResults))
 	for _, operatorResult := range operatorResults {
@@ -197,7 +307,7 @@ func (ts *TestSuite) TestOperatorResult_DeleteBatch() {
 }
 
 func (ts *TestSuite) TestOperatorResult_Update() {
-	operatorResults := ts.seedOperatorResultForDAGAndOperator(1, uuid.New(), uuid.New())
+	operatorResults := ts.seedOperatorResultForDAGAndOperator(1, ts.seedDAGResult(1)[0].ID, ts.seedOperator(1)[0].ID)
 	expectedOperatorResult := operatorResults[0]
 
 	execState := shared.NullExecutionState{
@@ -224,7 +334,7 @@ func (ts *TestSuite) TestOperatorResult_Update() {
 }
 
 func (ts *TestSuite) TestOperatorResult_UpdateBatchStatusByStatus() {
-	operatorResults := ts.seedOperatorResultForDAGAndOperator(2, uuid.New(), uuid.New())
+	operatorResults := ts.seedOperatorResultForDAGAndOperator(2, ts.seedDAGResult(1)[0].ID, ts.seedOperator(1)[0].ID)
 	succeededOperatorResult := operatorResults[0]
 	pendingOperatorResult := operatorResults[1]
 
@@ -130,7 +130,7 @@ func (ts *TestSuite) TestResource_Create() {
 
 func (ts *TestSuite) TestResource_CreateForUser() {
 	userID := utils.NullUUID{
-		UUID:   uuid.New(),
+		UUID:   ts.seedUser(1)[0].ID,
 		IsNull: false,
 	}
 	name := randString(10)
@@ -0,0 +1,238 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func (ts *TestSuite) TestRunRequest_Create() {
+	workflows := ts.seedWorkflow(1)
+	resumedDAGResultID := uuid.New()
+
+	runRequest, err := ts.runRequest.Create(
+		ts.ctx,
+		workflows[0].ID,
+		nil, /* parameters */
+		&resumedDAGResultID,
+		"Waiting for a worker.",
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), workflows[0].ID, runRequest.WorkflowID)
+	require.Equal(ts.T(), shared.QueuedRunRequestStatus, runRequest.Status)
+	require.Equal(ts.T(), shared.RunParameters{}, runRequest.Parameters)
+	require.False(ts.T(), runRequest.ResumedDAGResultID.IsNull)
+	require.Equal(ts.T(), resumedDAGResultID, runRequest.ResumedDAGResultID.UUID)
+	require.Equal(ts.T(), "Waiting for a worker.", runRequest.WaitReason)
+	require.True(ts.T(), runRequest.LeaseExpiresAt.IsNull)
+	require.Equal(ts.T(), 0, runRequest.Attempts)
+
+	actualRunRequest, err := ts.runRequest.Get(ts.ctx, runRequest.ID, ts.DB)
+	require.Nil(ts.T(), err)
+	requireDeepEqual(ts.T(), *runRequest, *actualRunRequest)
+}
+
+func (ts *TestSuite) TestRunRequest_ListUnfinished() {
+	workflows := ts.seedWorkflow(1)
+	runRequests := ts.seedRunRequest(3, workflows[0].ID)
+
+	_, err := ts.runRequest.Update(
+		ts.ctx,
+		runRequests[1].ID,
+		map[string]interface{}{
+			models.RunRequestStatus: shared.DoneRunRequestStatus,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	actualRunRequests, err := ts.runRequest.ListUnfinished(ts.ctx, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), 2, len(actualRunRequests))
+	require.Equal(ts.T(), runRequests[0].ID, actualRunRequests[0].ID)
+	require.Equal(ts.T(), runRequests[2].ID, actualRunRequests[1].ID)
+}
+
+func (ts *TestSuite) TestRunRequest_Claim() {
+	workflows := ts.seedWorkflow(1)
+	runRequests := ts.seedRunRequest(2, workflows[0].ID)
+
+	// The oldest request is claimed first.
+	for _, runRequest := range runRequests {
+		claimed, err := ts.runRequest.Claim(ts.ctx, "worker", time.Minute, ts.DB)
+		require.Nil(ts.T(), err)
+		require.Equal(ts.T(), runRequest.ID, claimed.ID)
+		require.Equal(ts.T(), shared.ClaimedRunRequestStatus, claimed.Status)
+		require.Equal(ts.T(), "worker", claimed.ClaimedBy)
+		require.Equal(ts.T(), "", claimed.WaitReason)
+		require.Equal(ts.T(), 1, claimed.Attempts)
+		require.False(ts.T(), claimed.LeaseExpiresAt.IsNull)
+		require.WithinDuration(ts.T(), time.Now().Add(time.Minute), claimed.LeaseExpiresAt.Time, 5*time.Second)
+		require.Equal(ts.T(), runRequest.Parameters, claimed.Parameters)
+	}
+
+	_, err := ts.runRequest.Claim(ts.ctx, "worker", time.Minute, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+}
+
+func (ts *TestSuite) TestRunRequest_RenewLease() {
+	workflows := ts.seedWorkflow(1)
+	ts.seedRunRequest(1, workflows[0].ID)
+
+	claimed, err := ts.runRequest.Claim(ts.ctx, "worker", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+
+	renewed, err := ts.runRequest.RenewLease(ts.ctx, claimed.ID, "worker", time.Hour, ts.DB)
+	require.Nil(ts.T(), err)
+	require.WithinDuration(ts.T(), time.Now().Add(time.Hour), renewed.LeaseExpiresAt.Time, 5*time.Second)
+
+	// Only the worker holding the lease can renew it.
+	_, err = ts.runRequest.RenewLease(ts.ctx, claimed.ID, "other-worker", time.Hour, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+
+	_, err = ts.runRequest.Update(
+		ts.ctx,
+		claimed.ID,
+		map[string]interface{}{
+			models.RunRequestStatus: shared.DoneRunRequestStatus,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	_, err = ts.runRequest.RenewLease(ts.ctx, claimed.ID, "worker", time.Hour, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+}
+
+func (ts *TestSuite) TestRunRequest_Expired() {
+	workflows := ts.seedWorkflow(1)
+	runRequests := ts.seedRunRequest(3, workflows[0].ID)
+
+	// The first request is running and the second one is claimed, both with an expired lease.
+	// The lease of the third request has not expired yet.
+	running, err := ts.runRequest.Claim(ts.ctx, "worker", -time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+	_, err = ts.runRequest.Update(
+		ts.ctx,
+		running.ID,
+		map[string]interface{}{
+			models.RunRequestStatus: shared.RunningRunRequestStatus,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	_, err = ts.runRequest.Claim(ts.ctx, "worker", -time.Second, ts.DB)
+	require.Nil(ts.T(), err)
+	_, err = ts.runRequest.Claim(ts.ctx, "worker", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+
+	requeued, err := ts.runRequest.RequeueExpired(ts.ctx, "Requeued.", ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), 1, len(requeued))
+	require.Equal(ts.T(), runRequests[1].ID, requeued[0].ID)
+	require.Equal(ts.T(), shared.QueuedRunRequestStatus, requeued[0].Status)
+	require.Equal(ts.T(), "Requeued.", requeued[0].WaitReason)
+	require.Equal(ts.T(), "", requeued[0].ClaimedBy)
+	require.True(ts.T(), requeued[0].LeaseExpiresAt.IsNull)
+
+	finished, err := ts.runRequest.FinishExpired(ts.ctx, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), 1, len(finished))
+	require.Equal(ts.T(), runRequests[0].ID, finished[0].ID)
+	require.Equal(ts.T(), shared.DoneRunRequestStatus, finished[0].Status)
+
+	// The requeued request is claimed again.
+	claimed, err := ts.runRequest.Claim(ts.ctx, "other-worker", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), runRequests[1].ID, claimed.ID)
+	require.Equal(ts.T(), 2, claimed.Attempts)
+}
+
+func (ts *TestSuite) TestRunRequest_ClaimQueueOverlapPolicy() {
+	workflows := ts.seedWorkflow(2)
+	queuedWorkflow := workflows[0]
+	schedule := queuedWorkflow.Schedule
+	schedule.OverlapPolicy = shared.QueueOverlapPolicy
+	_, err := ts.workflow.Update(
+		ts.ctx,
+		queuedWorkflow.ID,
+		map[string]interface{}{models.WorkflowSchedule: &schedule},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	queuedRequests := ts.seedRunRequest(2, queuedWorkflow.ID)
+	otherRequests := ts.seedRunRequest(1, workflows[1].ID)
+
+	// The second request of the workflow with a queue overlap policy waits for the first one.
+	for _, expected := range []models.RunRequest{queuedRequests[0], otherRequests[0]} {
+		claimed, err := ts.runRequest.Claim(ts.ctx, "worker", time.Minute, ts.DB)
+		require.Nil(ts.T(), err)
+		require.Equal(ts.T(), expected.ID, claimed.ID)
+	}
+
+	_, err = ts.runRequest.Claim(ts.ctx, "worker", time.Minute, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+
+	waiting, err := ts.runRequest.MarkWaitingForPreviousRun(ts.ctx, "Waiting for the previous run.", ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), 1, len(waiting))
+	require.Equal(ts.T(), queuedRequests[1].ID, waiting[0].ID)
+	require.Equal(ts.T(), "Waiting for the previous run.", waiting[0].WaitReason)
+
+	// The wait reason is only updated once.
+	waiting, err = ts.runRequest.MarkWaitingForPreviousRun(ts.ctx, "Waiting for the previous run.", ts.DB)
+	require.Nil(ts.T(), err)
+	require.Empty(ts.T(), waiting)
+
+	// The second request is claimed once the first one is done.
+	_, err = ts.runRequest.Update(
+		ts.ctx,
+		queuedRequests[0].ID,
+		map[string]interface{}{
+			models.RunRequestStatus: shared.DoneRunRequestStatus,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	claimed, err := ts.runRequest.Claim(ts.ctx, "worker", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), queuedRequests[1].ID, claimed.ID)
+	require.Equal(ts.T(), "", claimed.WaitReason)
+}
+
+func (ts *TestSuite) TestRunRequest_DeleteDoneBefore() {
+	workflows := ts.seedWorkflow(1)
+	runRequests := ts.seedRunRequest(2, workflows[0].ID)
+
+	_, err := ts.runRequest.Update(
+		ts.ctx,
+		runRequests[0].ID,
+		map[string]interface{}{
+			models.RunRequestStatus: shared.DoneRunRequestStatus,
+		},
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+
+	// Requests created after `before` are kept.
+	require.Nil(ts.T(), ts.runRequest.DeleteDoneBefore(ts.ctx, runRequests[0].CreatedAt.Add(-time.Second), ts.DB))
+	_, err = ts.runRequest.Get(ts.ctx, runRequests[0].ID, ts.DB)
+	require.Nil(ts.T(), err)
+
+	// Only the requests that are done are deleted.
+	require.Nil(ts.T(), ts.runRequest.DeleteDoneBefore(ts.ctx, time.Now().Add(time.Second), ts.DB))
+	_, err = ts.runRequest.Get(ts.ctx, runRequests[0].ID, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+
+	_, err = ts.runRequest.Get(ts.ctx, runRequests[1].ID, ts.DB)
+	require.Nil(ts.T(), err)
+}
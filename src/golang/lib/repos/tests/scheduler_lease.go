@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func (ts *TestSuite) TestSchedulerLease_Acquire() {
+	// The lease expires by the clock of the database, which is allowed to differ slightly from the local clock.
+	before := time.Now()
+	lease, err := ts.schedulerLease.Acquire(ts.ctx, "scheduler", "replica-1", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), "scheduler", lease.Name)
+	require.Equal(ts.T(), "replica-1", lease.Holder)
+	require.WithinDuration(ts.T(), before.Add(time.Minute), lease.ExpiresAt, 5*time.Second)
+
+	// Another replica cannot acquire the lease before it expires.
+	_, err = ts.schedulerLease.Acquire(ts.ctx, "scheduler", "replica-2", time.Minute, ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+
+	// The holder renews the lease.
+	renewedLease, err := ts.schedulerLease.Acquire(ts.ctx, "scheduler", "replica-1", 2*time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+	require.True(ts.T(), renewedLease.ExpiresAt.After(lease.ExpiresAt))
+
+	// Another replica takes over the lease once it expires.
+	_, err = ts.schedulerLease.Acquire(ts.ctx, "scheduler", "replica-1", 10*time.Millisecond, ts.DB)
+	require.Nil(ts.T(), err)
+	time.Sleep(50 * time.Millisecond)
+
+	lease, err = ts.schedulerLease.Acquire(ts.ctx, "scheduler", "replica-2", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), "replica-2", lease.Holder)
+
+	actualLease, err := ts.schedulerLease.Get(ts.ctx, "scheduler", ts.DB)
+	require.Nil(ts.T(), err)
+	requireDeepEqual(ts.T(), *lease, *actualLease)
+
+	// Leases with different names are independent.
+	_, err = ts.schedulerLease.Acquire(ts.ctx, "other", "replica-1", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+}
+
+func (ts *TestSuite) TestSchedulerLease_Release() {
+	_, err := ts.schedulerLease.Acquire(ts.ctx, "scheduler", "replica-1", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+
+	// Only the holder releases the lease.
+	require.Nil(ts.T(), ts.schedulerLease.Release(ts.ctx, "scheduler", "replica-2", ts.DB))
+	_, err = ts.schedulerLease.Get(ts.ctx, "scheduler", ts.DB)
+	require.Nil(ts.T(), err)
+
+	require.Nil(ts.T(), ts.schedulerLease.Release(ts.ctx, "scheduler", "replica-1", ts.DB))
+	_, err = ts.schedulerLease.Get(ts.ctx, "scheduler", ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+
+	_, err = ts.schedulerLease.Acquire(ts.ctx, "scheduler", "replica-2", time.Minute, ts.DB)
+	require.Nil(ts.T(), err)
+}
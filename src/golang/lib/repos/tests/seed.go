@@ -28,16 +28,23 @@ const (
 // real destination IDs and nil ones. Also updates each entry to have a complete set of timestamps.
 func (ts *TestSuite) seedStorageMigration() []models.StorageMigration {
 	count := 5
+	resources := ts.seedResource(count)
 	storageMigrations := make([]models.StorageMigration, count)
 	for i := 0; i < count; i++ {
 		var destResourceID *uuid.UUID
 		var err error
 		if i%2 == 0 {
-			rawID, err := uuid.NewUUID()
-			destResourceID = &rawID
-			require.Nil(ts.T(), err)
+			destResourceID = &resources[i].ID
 		}
-		entry, err := ts.storageMigration.Create(ts.ctx, destResourceID, ts.DB)
+		prevStorageConfig := &shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: randString(10)},
+		}
+		destStorageConfig := &shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: randString(10)},
+		}
+		entry, err := ts.storageMigration.Create(ts.ctx, destResourceID, prevStorageConfig, destStorageConfig, false, ts.DB)
 		require.Nil(ts.T(), err)
 
 		now := time.Now()
@@ -227,6 +234,7 @@ func (ts *TestSuite) seedWorkflowWithUser(count int, userIDs []uuid.UUID) []mode
 			schedule,
 			retentionPolicy,
 			&shared.NotificationSettings{},
+			0, /* maxParallelOperators */
 			ts.DB,
 		)
 		require.Nil(ts.T(), err)
@@ -393,7 +401,7 @@ func (ts *TestSuite) seedOperator(count int) []models.Operator {
 }
 
 // seedOperatorResultForDAGAndOperator creates count OperatorResult records.
-// It does not create any other records and only creates OperatorResults for the specified DAG and operator.
+// It does not create any other records and only creates OperatorResults for the specified DAG result and operator.
 func (ts *TestSuite) seedOperatorResultForDAGAndOperator(count int, dagResultID uuid.UUID, operatorID uuid.UUID) []models.OperatorResult {
 	operatorResults := make([]models.OperatorResult, 0, count)
 
@@ -421,7 +429,7 @@ func (ts *TestSuite) seedOperatorResultForDAGAndOperator(count int, dagResultID
 }
 
 // seedOperatorResult creates count OperatorResult records.
-// It creates DAGEdges, Operators, OperatorResults.
+// It creates DAGEdges, Operators, a DAGResult and OperatorResults.
 func (ts *TestSuite) seedOperatorResult(count int, opType operator.Type) ([]models.OperatorResult, *models.Operator, uuid.UUID) {
 	artifactID := uuid.New()
 	users := ts.seedUser(1)
@@ -431,8 +439,9 @@ func (ts *TestSuite) seedOperatorResult(count int, opType operator.Type) ([]mode
 	dags := ts.seedDAGWithWorkflow(1, workflowIDs)
 	dag := dags[0]
 	operator := ts.seedOperatorAndDAG(artifactID, dag.ID, users[0].ID, opType)
+	dagResult := ts.seedDAGResultWithDAG(1, []uuid.UUID{dag.ID})[0]
 
-	return ts.seedOperatorResultForDAGAndOperator(count, dag.ID, operator.ID), operator, artifactID
+	return ts.seedOperatorResultForDAGAndOperator(count, dagResult.ID, operator.ID), operator, artifactID
 }
 
 // seedOperatorWithDAG creates count Operator records of Type opType.
@@ -619,22 +628,23 @@ func (ts *TestSuite) seedWatcher() *models.Watcher {
 	return watcher
 }
 
-// seedArtifactResult creates a workflow with 1 DAG and count artifact_result records
-// belonging to the same workflow DAG.
-func (ts *TestSuite) seedArtifactResult(count int) ([]models.ArtifactResult, models.Artifact, models.DAG, models.Workflow) {
+// seedArtifactResult creates a workflow with 1 DAG and 1 DAG result, and count artifact_result
+// records belonging to the same DAG result.
+func (ts *TestSuite) seedArtifactResult(count int) ([]models.ArtifactResult, models.Artifact, models.DAGResult, models.Workflow) {
 	artifactResults := make([]models.ArtifactResult, 0, count)
 
 	artifact, dag, workflow, _ := ts.seedArtifactInWorkflow()
+	dagResult := ts.seedDAGResultWithDAG(1, []uuid.UUID{dag.ID})[0]
 
 	for i := 0; i < count; i++ {
 		contentPath := randString(10)
-		artifactResult, err := ts.artifactResult.Create(ts.ctx, dag.ID, artifact.ID, contentPath, ts.DB)
+		artifactResult, err := ts.artifactResult.Create(ts.ctx, dagResult.ID, artifact.ID, contentPath, ts.DB)
 		require.Nil(ts.T(), err)
 
 		artifactResults = append(artifactResults, *artifactResult)
 	}
 
-	return artifactResults, artifact, dag, workflow
+	return artifactResults, artifact, dagResult, workflow
 }
 
 // seedSchemaVersion creates count schema versions versioned from CurrentSchemaVersion + 1  to CurrentSchemaVersion + count.
@@ -904,3 +914,25 @@ func (ts *TestSuite) seedComplexWorkflow() (
 
 	return dag, operators, artifacts, operatorNodes, artifactNodes
 }
+
+// seedRunRequest creates count queued RunRequest records for the workflow workflowID,
+// in the order of the returned list.
+func (ts *TestSuite) seedRunRequest(count int, workflowID uuid.UUID) []models.RunRequest {
+	runRequests := make([]models.RunRequest, 0, count)
+	for i := 0; i < count; i++ {
+		runRequest, err := ts.runRequest.Create(
+			ts.ctx,
+			workflowID,
+			shared.RunParameters{
+				"param": {Val: randString(10), SerializationType: string(shared.StringSerialization)},
+			},
+			nil, /* resumedDAGResultID */
+			"Waiting for a worker.",
+			ts.DB,
+		)
+		require.Nil(ts.T(), err)
+
+		runRequests = append(runRequests, *runRequest)
+	}
+	return runRequests
+}
@@ -3,6 +3,9 @@ package tests
 import (
 	"github.com/aqueducthq/aqueduct/lib/database"
 	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,3 +28,51 @@ func (ts *TestSuite) TestStorageMigrationCurrent() {
 	require.Nil(ts.T(), err)
 	requireDeepEqual(ts.T(), migrations[0], *current)
 }
+
+func (ts *TestSuite) TestStorageMigrationGet() {
+	migrations := ts.seedStorageMigration()
+
+	actualMigration, err := ts.storageMigration.Get(ts.ctx, migrations[1].ID, ts.DB)
+	require.Nil(ts.T(), err)
+	requireDeepEqual(ts.T(), migrations[1], *actualMigration)
+	require.NotNil(ts.T(), actualMigration.PrevStorageConfig)
+	require.NotNil(ts.T(), actualMigration.DestStorageConfig)
+
+	_, err = ts.storageMigration.Get(ts.ctx, uuid.New(), ts.DB)
+	require.True(ts.T(), aq_errors.Is(err, database.ErrNoRows()))
+}
+
+func (ts *TestSuite) TestStorageMigrationUpdateProgress() {
+	migrations := ts.seedStorageMigration()
+
+	progress := &shared.StorageMigrationProgress{
+		TotalObjects:  10,
+		CopiedObjects: 2,
+		CopiedBytes:   100,
+	}
+	actualMigration, err := ts.storageMigration.Update(ts.ctx, migrations[0].ID, map[string]interface{}{
+		models.StorageMigrationProgress: progress,
+	}, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Equal(ts.T(), *progress, *actualMigration.Progress)
+	require.False(ts.T(), actualMigration.DryRun)
+}
+
+func (ts *TestSuite) TestStorageMigrationObjects() {
+	migrations := ts.seedStorageMigration()
+
+	objects, err := ts.storageMigration.GetObjects(ts.ctx, migrations[0].ID, ts.DB)
+	require.Nil(ts.T(), err)
+	require.Empty(ts.T(), objects)
+
+	require.Nil(ts.T(), ts.storageMigration.CreateObjects(ts.ctx, migrations[0].ID, map[string]string{"a": "checksum-a"}, ts.DB))
+	require.Nil(ts.T(), ts.storageMigration.CreateObjects(ts.ctx, migrations[0].ID, map[string]string{"b": "checksum-b"}, ts.DB))
+	require.Nil(ts.T(), ts.storageMigration.CreateObjects(ts.ctx, migrations[1].ID, map[string]string{"a": "checksum-c"}, ts.DB))
+
+	objects, err = ts.storageMigration.GetObjects(ts.ctx, migrations[0].ID, ts.DB)
+	require.Nil(ts.T(), err)
+	require.ElementsMatch(ts.T(), []models.StorageMigrationObject{
+		{StorageMigrationID: migrations[0].ID, Key: "a", Checksum: "checksum-a"},
+		{StorageMigrationID: migrations[0].ID, Key: "b", Checksum: "checksum-b"},
+	}, objects)
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/repos"
-	"github.com/aqueducthq/aqueduct/lib/repos/sqlite"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 )
@@ -19,6 +19,9 @@ type TestSuite struct {
 	suite.Suite
 	ctx context.Context
 
+	// dbConfig specifies the database that the tests run against.
+	dbConfig *database.DatabaseConfig
+
 	// List of all repos
 	artifact             repos.Artifact
 	artifactResult       repos.ArtifactResult
@@ -30,6 +33,8 @@ type TestSuite struct {
 	notification         repos.Notification
 	operator             repos.Operator
 	operatorResult       repos.OperatorResult
+	runRequest           repos.RunRequest
+	schedulerLease       repos.SchedulerLease
 	schemaVersion        repos.SchemaVersion
 	storageMigration     repos.StorageMigration
 	user                 repos.User
@@ -43,30 +48,32 @@ type TestSuite struct {
 // connection and creates the repos. It initializes the database schema
 // to the latest version.
 func (ts *TestSuite) SetupSuite() {
-	DB, err := database.NewSqliteInMemoryDatabase(&database.SqliteConfig{})
+	DB, err := newTestDatabase(ts.dbConfig)
 	if err != nil {
-		ts.T().Errorf("Unable to create SQLite client: %v", err)
+		ts.T().Fatalf("Unable to create %s client: %v", ts.dbConfig.Type, err)
 	}
 
 	ts.ctx = context.Background()
 	ts.DB = DB
 
 	// Initialize repos
-	ts.artifact = sqlite.NewArtifactRepo()
-	ts.artifactResult = sqlite.NewArtifactResultRepo()
-	ts.dag = sqlite.NewDAGRepo()
-	ts.dagEdge = sqlite.NewDAGEdgeRepo()
-	ts.dagResult = sqlite.NewDAGResultRepo()
-	ts.executionEnvironment = sqlite.NewExecutionEnvironmentRepo()
-	ts.resource = sqlite.NewResourceRepo()
-	ts.notification = sqlite.NewNotificationRepo()
-	ts.operator = sqlite.NewOperatorRepo()
-	ts.operatorResult = sqlite.NewOperatorResultRepo()
-	ts.schemaVersion = sqlite.NewSchemaVersionRepo()
-	ts.storageMigration = sqlite.NewStorageMigrationRepo()
-	ts.user = sqlite.NewUserRepo()
-	ts.watcher = sqlite.NewWatcherRepo()
-	ts.workflow = sqlite.NewWorklowRepo()
+	ts.artifact = sql.NewArtifactRepo()
+	ts.artifactResult = sql.NewArtifactResultRepo()
+	ts.dag = sql.NewDAGRepo()
+	ts.dagEdge = sql.NewDAGEdgeRepo()
+	ts.dagResult = sql.NewDAGResultRepo()
+	ts.executionEnvironment = sql.NewExecutionEnvironmentRepo()
+	ts.resource = sql.NewResourceRepo()
+	ts.notification = sql.NewNotificationRepo()
+	ts.operator = sql.NewOperatorRepo()
+	ts.operatorResult = sql.NewOperatorResultRepo()
+	ts.runRequest = sql.NewRunRequestRepo()
+	ts.schedulerLease = sql.NewSchedulerLeaseRepo()
+	ts.schemaVersion = sql.NewSchemaVersionRepo()
+	ts.storageMigration = sql.NewStorageMigrationRepo()
+	ts.user = sql.NewUserRepo()
+	ts.watcher = sql.NewWatcherRepo()
+	ts.workflow = sql.NewWorklowRepo()
 
 	// Init database schema
 	if err := initDBSchema(DB); err != nil {
@@ -75,6 +82,19 @@ func (ts *TestSuite) SetupSuite() {
 	}
 }
 
+// newTestDatabase creates a database client for conf.
+// SQLite tests always run against an in-memory database.
+func newTestDatabase(conf *database.DatabaseConfig) (database.Database, error) {
+	switch conf.Type {
+	case database.SqliteType:
+		return database.NewSqliteInMemoryDatabase(&database.SqliteConfig{})
+	case database.PostgresType:
+		return database.NewPostgresDatabaseWithPort(conf.Postgres)
+	default:
+		return nil, database.ErrUnsupportedDbType()
+	}
+}
+
 func initDBSchema(DB database.Database) error {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -102,22 +122,27 @@ func initDBSchema(DB database.Database) error {
 
 // TearDownTest is run after each test finishes.
 func (ts *TestSuite) TearDownTest() {
-	// Clear all of the tables
+	// Clear all of the tables. The referencing tables are cleared first,
+	// so that the foreign keys hold on Postgres.
 	query := `
-	DELETE FROM app_user;
-	DELETE FROM artifact;
 	DELETE FROM artifact_result;
+	DELETE FROM operator_result;
+	DELETE FROM workflow_dag_result;
+	DELETE FROM workflow_dag_edge;
+	DELETE FROM workflow_dag;
+	DELETE FROM workflow_watcher;
+	DELETE FROM run_request;
+	DELETE FROM workflow;
+	DELETE FROM artifact;
+	DELETE FROM operator;
 	DELETE FROM execution_environment;
-	DELETE FROM resource;
 	DELETE FROM notification;
-	DELETE FROM operator;
-	DELETE FROM operator_result;
-	DELETE FROM schema_version;
+	DELETE FROM storage_migration_object;
 	DELETE FROM storage_migration;
-	DELETE FROM workflow;
-	DELETE FROM workflow_dag;
-	DELETE FROM workflow_dag_edge;
-	DELETE FROM workflow_dag_result;
+	DELETE FROM resource;
+	DELETE FROM scheduler_lease;
+	DELETE FROM schema_version;
+	DELETE FROM app_user;
 	;
 	`
 	if err := ts.DB.Execute(ts.ctx, query); err != nil {
@@ -3,6 +3,7 @@ package tests
 import (
 	"github.com/aqueducthq/aqueduct/lib/models"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -60,6 +61,7 @@ func (ts *TestSuite) TestWorkflow_GetByScheduleTrigger() {
 				KLatestRuns: 5,
 			},
 			&shared.NotificationSettings{},
+			0, /* maxParallelOperators */
 			ts.DB,
 		)
 		require.Nil(ts.T(), err)
@@ -92,6 +94,7 @@ func (ts *TestSuite) TestWorkflow_GetTargets() {
 				KLatestRuns: 5,
 			},
 			&shared.NotificationSettings{},
+			0, /* maxParallelOperators */
 			ts.DB,
 		)
 		require.Nil(ts.T(), err)
@@ -99,6 +102,27 @@ func (ts *TestSuite) TestWorkflow_GetTargets() {
 		expectedIDs = append(expectedIDs, workflow.ID)
 	}
 
+	// Create a Workflow with multiple sources, one of which is `triggerWorkflow`
+	workflow, err := ts.workflow.Create(
+		ts.ctx,
+		triggerWorkflow.UserID,
+		randString(10),
+		randString(15),
+		&shared.Schedule{
+			Trigger:     shared.CascadingUpdateTrigger,
+			SourceIDs:   []uuid.UUID{uuid.New(), triggerWorkflow.ID},
+			CascadeMode: shared.AllCascadeMode,
+		},
+		&shared.RetentionPolicy{
+			KLatestRuns: 5,
+		},
+		&shared.NotificationSettings{},
+		0, /* maxParallelOperators */
+		ts.DB,
+	)
+	require.Nil(ts.T(), err)
+	expectedIDs = append(expectedIDs, workflow.ID)
+
 	actualIDs, err := ts.workflow.GetTargets(ts.ctx, triggerWorkflow.ID, ts.DB)
 	require.Nil(ts.T(), err)
 	require.ElementsMatch(ts.T(), expectedIDs, actualIDs)
@@ -151,6 +175,8 @@ func (ts *TestSuite) TestWorkflow_Create() {
 				notificationResourceID: shared.ErrorNotificationLevel,
 			},
 		},
+		MaxParallelOperators: 4,
+		CascadeTriggeredAt:   utils.NullTime{IsNull: true},
 	}
 
 	actualWorkflow, err := ts.workflow.Create(
@@ -161,6 +187,7 @@ func (ts *TestSuite) TestWorkflow_Create() {
 		&expectedWorkflow.Schedule,
 		&expectedWorkflow.RetentionPolicy,
 		&expectedWorkflow.NotificationSettings,
+		expectedWorkflow.MaxParallelOperators,
 		ts.DB,
 	)
 	require.Nil(ts.T(), err)
@@ -203,6 +230,7 @@ func (ts *TestSuite) TestWorkflow_Update() {
 		models.WorkflowName:                 newName,
 		models.WorkflowSchedule:             &newSchedule,
 		models.WorkflowNotificationSettings: &newNotificationSettings,
+		models.WorkflowMaxParallelOperators: 2,
 	}
 
 	newWorkflow, err := ts.workflow.Update(ts.ctx, oldWorkflow.ID, changes, ts.DB)
@@ -211,6 +239,7 @@ func (ts *TestSuite) TestWorkflow_Update() {
 	requireDeepEqual(ts.T(), newSchedule, newWorkflow.Schedule)
 	require.Equal(ts.T(), newName, newWorkflow.Name)
 	requireDeepEqual(ts.T(), newWorkflow.NotificationSettings, newNotificationSettings)
+	require.Equal(ts.T(), 2, newWorkflow.MaxParallelOperators)
 }
 
 func (ts *TestSuite) TestWorkflow_RemoveNotificationFromSettings() {
@@ -237,6 +266,7 @@ func (ts *TestSuite) TestWorkflow_RemoveNotificationFromSettings() {
 		&workflow.Schedule,
 		&workflow.RetentionPolicy,
 		&workflow.NotificationSettings,
+		workflow.MaxParallelOperators,
 		ts.DB,
 	)
 	require.Nil(ts.T(), err)
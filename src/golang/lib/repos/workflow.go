@@ -35,8 +35,8 @@ type workflowReader interface {
 	// GetByScheduleTrigger returns all Workflows where Schedule.Trigger is equal to trigger.
 	GetByScheduleTrigger(ctx context.Context, trigger shared.UpdateTrigger, DB database.Database) ([]models.Workflow, error)
 
-	// GetTargets returns the ID of each Workflow where Schedule.SourceID
-	// is equal to ID.
+	// GetTargets returns the ID of each Workflow with a cascading trigger
+	// where ID is one of the source Workflows of its Schedule.
 	GetTargets(ctx context.Context, ID uuid.UUID, DB database.Database) ([]uuid.UUID, error)
 
 	// GetLastRunByEngine returns a WorkflowLastRun for each Workflow where the latest
@@ -63,6 +63,7 @@ type workflowWriter interface {
 		schedule *shared.Schedule,
 		retentionPolicy *shared.RetentionPolicy,
 		notificationSettings *shared.NotificationSettings,
+		maxParallelOperators int,
 		DB database.Database,
 	) (*models.Workflow, error)
 
@@ -72,6 +73,10 @@ type workflowWriter interface {
 	// Update applies changes to the Workflow with ID. It returns the updated Workflow.
 	Update(ctx context.Context, ID uuid.UUID, changes map[string]interface{}, DB database.Database) (*models.Workflow, error)
 
+	// Lock locks the Workflow with ID until the transaction DB ends, so that concurrent
+	// transactions that lock the same Workflow are executed one at a time.
+	Lock(ctx context.Context, ID uuid.UUID, DB database.Database) error
+
 	// RemoveNotificationFromSettings removes `notificationResourceID` from notification_settings
 	// field when possible.
 	// If the ID does not appear in any notification_settings field,
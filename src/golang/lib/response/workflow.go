@@ -56,6 +56,7 @@ type DAGResult struct {
 	ID        uuid.UUID              `json:"id"`
 	DagID     uuid.UUID              `json:"dag_id"`
 	ExecState *shared.ExecutionState `json:"exec_state"`
+	Pinned    bool                   `json:"pinned"`
 }
 
 func NewDAGResultFromDBObject(dbDAGResult *models.DAGResult) *DAGResult {
@@ -68,6 +69,7 @@ func NewDAGResultFromDBObject(dbDAGResult *models.DAGResult) *DAGResult {
 		ID:        dbDAGResult.ID,
 		DagID:     dbDAGResult.DagID,
 		ExecState: execStatePtr,
+		Pinned:    dbDAGResult.Pinned,
 	}
 }
 
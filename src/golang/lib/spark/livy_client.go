@@ -194,3 +194,25 @@ func (c *LivyClient) GetStatement(sessionID int, statementID int) (*Statement, e
 
 	return &s, nil
 }
+
+// CancelStatement cancels a statement that is waiting or running
+func (c *LivyClient) CancelStatement(sessionID int, statementID int) error {
+	u := fmt.Sprintf("%s/sessions/%d/statements/%d/cancel", c.LivyServerURL, sessionID, statementID)
+
+	req, err := http.NewRequest("POST", u, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error creating cancel statement request.")
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error sending cancel statement request.")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Newf("Error cancelling statement: %v", resp.Status)
+	}
+
+	return nil
+}
@@ -128,6 +128,27 @@ func TestGetStatement(t *testing.T) {
 	assert.Equal(t, expectedStatement, statement)
 }
 
+func TestCancelStatement(t *testing.T) {
+	cleanup := setup()
+	defer cleanup()
+
+	mux.HandleFunc("/sessions/1/statements/1/cancel", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		jsonResp := `{"msg": "canceled"}`
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(jsonResp))
+	})
+
+	// Call CancelStatement with session ID 1 and statement ID 1
+	err := client.CancelStatement(1, 1)
+	assert.NoError(t, err)
+
+	// Cancelling a statement that does not exist should fail.
+	err = client.CancelStatement(1, 2)
+	assert.Error(t, err)
+}
+
 func TestGetSessions(t *testing.T) {
 	cleanup := setup()
 	defer cleanup()
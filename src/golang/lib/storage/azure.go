@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+)
+
+type azureStorage struct {
+	azureConfig *shared.AzureConfig
+}
+
+func newAzureStorage(azureConfig *shared.AzureConfig) *azureStorage {
+	return &azureStorage{
+		azureConfig: azureConfig,
+	}
+}
+
+// newClient returns a Blob service client authenticated with the credentials of the config.
+func (a *azureStorage) newClient() (*azblob.Client, error) {
+	azureConfig := a.azureConfig
+	if azureConfig.ConnectionString != "" {
+		return azblob.NewClientFromConnectionString(azureConfig.ConnectionString, nil)
+	}
+
+	accountURL := azureConfig.AccountURL
+	if accountURL == "" {
+		if azureConfig.AccountName == "" {
+			return nil, errors.New("Either the account URL or the account name of the Azure storage account must be set.")
+		}
+		accountURL = fmt.Sprintf("https://%s.blob.core.windows.net", azureConfig.AccountName)
+	}
+
+	if azureConfig.AccountKey != "" {
+		credential, err := azblob.NewSharedKeyCredential(azureConfig.AccountName, azureConfig.AccountKey)
+		if err != nil {
+			return nil, err
+		}
+		return azblob.NewClientWithSharedKeyCredential(accountURL, credential, nil)
+	}
+
+	if azureConfig.SASToken != "" {
+		sasURL := fmt.Sprintf("%s?%s", strings.TrimSuffix(accountURL, "/"), strings.TrimPrefix(azureConfig.SASToken, "?"))
+		return azblob.NewClientWithNoCredential(sasURL, nil)
+	}
+
+	return nil, errors.New("No credentials were provided for the Azure storage account.")
+}
+
+// fullKey returns the name of the blob of key, under the prefix of the config.
+func (a *azureStorage) fullKey(key string) string {
+	return a.azureConfig.Prefix + key
+}
+
+func isAzureNotFound(err error) bool {
+	return bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound)
+}
+
+func (a *azureStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	reader, err := a.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func (a *azureStorage) Put(ctx context.Context, key string, value []byte) error {
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.UploadBuffer(ctx, a.azureConfig.Container, a.fullKey(key), value, nil)
+	return err
+}
+
+func (a *azureStorage) Delete(ctx context.Context, key string) error {
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteBlob(ctx, a.azureConfig.Container, a.fullKey(key), nil)
+	return err
+}
+
+func (a *azureStorage) Exists(ctx context.Context, key string) bool {
+	_, err := a.Stat(ctx, key)
+	// TODO: ENG-2428 we should explicitly surface other error types to the caller
+	// instead of just returning `false` for non ErrObjectDoesNotExist errors.
+	return err == nil
+}
+
+func (a *azureStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []ObjectInfo{}
+	fullPrefix := a.fullKey(prefix)
+	pager := client.NewListBlobsFlatPager(a.azureConfig.Container, &azblob.ListBlobsFlatOptions{
+		Prefix: &fullPrefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			info := ObjectInfo{
+				Key: strings.TrimPrefix(*item.Name, a.azureConfig.Prefix),
+			}
+			if item.Properties != nil {
+				if item.Properties.ContentLength != nil {
+					info.Size = *item.Properties.ContentLength
+				}
+				if item.Properties.LastModified != nil {
+					info.LastModified = *item.Properties.LastModified
+				}
+			}
+			objects = append(objects, info)
+		}
+	}
+
+	return objects, nil
+}
+
+func (a *azureStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := client.ServiceClient().
+		NewContainerClient(a.azureConfig.Container).
+		NewBlobClient(a.fullKey(key)).
+		GetProperties(ctx, nil)
+	if err != nil {
+		if isAzureNotFound(err) {
+			return nil, errors.Wrapf(ErrObjectDoesNotExist(), "Unable to fetch blob `%s` from container `%s`.", a.fullKey(key), a.azureConfig.Container)
+		}
+		return nil, err
+	}
+
+	info := &ObjectInfo{Key: key}
+	if properties.ContentLength != nil {
+		info.Size = *properties.ContentLength
+	}
+	if properties.LastModified != nil {
+		info.LastModified = *properties.LastModified
+	}
+
+	return info, nil
+}
+
+func (a *azureStorage) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	return a.NewRangeReader(ctx, key, 0, -1)
+}
+
+func (a *azureStorage) NewRangeReader(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		// A zero count requests the whole blob, so an empty range is not requested at all.
+		if _, err := a.Stat(ctx, key); err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	blobRange := blob.HTTPRange{Offset: offset}
+	if length > 0 {
+		blobRange.Count = length
+	}
+
+	result, err := client.DownloadStream(ctx, a.azureConfig.Container, a.fullKey(key), &azblob.DownloadStreamOptions{
+		Range: blobRange,
+	})
+	if err != nil {
+		if isAzureNotFound(err) {
+			return nil, errors.Wrapf(ErrObjectDoesNotExist(), "Unable to fetch blob `%s` from container `%s`.", a.fullKey(key), a.azureConfig.Container)
+		}
+		return nil, err
+	}
+
+	return result.Body, nil
+}
+
+func (a *azureStorage) NewWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// The upload reads the content from the pipe in blocks, so it is never held in memory as a whole.
+	pipeReader, pipeWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.UploadStream(ctx, a.azureConfig.Container, a.fullKey(key), pipeReader, nil)
+		// Unblock the writer if the upload failed before the whole content was read.
+		pipeReader.CloseWithError(err)
+		done <- err
+	}()
+
+	return &uploadWriter{PipeWriter: pipeWriter, done: done}, nil
+}
+
+// ValidateAzureConfig checks that the container of azureConfig is accessible with its credentials.
+func ValidateAzureConfig(ctx context.Context, azureConfig *shared.AzureConfig) error {
+	if azureConfig.Container == "" {
+		return errors.New("The container of the Azure storage account must be set.")
+	}
+
+	store := newAzureStorage(azureConfig)
+	client, err := store.newClient()
+	if err != nil {
+		return err
+	}
+
+	// Listing a single blob requires the same permissions as reading artifacts.
+	maxResults := int32(1)
+	pager := client.NewListBlobsFlatPager(azureConfig.Container, &azblob.ListBlobsFlatOptions{
+		Prefix:     &azureConfig.Prefix,
+		MaxResults: &maxResults,
+	})
+	_, err = pager.NextPage(ctx)
+	return err
+}
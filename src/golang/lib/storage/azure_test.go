@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"flag"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// The account name and key of the Azurite emulator are the same for every installation.
+const (
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+var (
+	runAzurite = flag.Bool("azurite", false, "If this flag is set, the Azure storage tests will be run against the Azurite emulator.")
+	azuriteURL = flag.String("azuriteURL", "http://127.0.0.1:10000/devstoreaccount1", "The Blob service endpoint of the Azurite emulator.")
+)
+
+func TestAzureStorageCredentials(t *testing.T) {
+	store := newAzureStorage(&shared.AzureConfig{Container: "container"})
+	_, err := store.newClient()
+	require.NotNil(t, err)
+
+	store = newAzureStorage(&shared.AzureConfig{AccountName: "account", Container: "container"})
+	_, err = store.newClient()
+	require.NotNil(t, err)
+
+	store = newAzureStorage(&shared.AzureConfig{AccountName: "account", SASToken: "?sv=2021-08-06", Container: "container"})
+	client, err := store.newClient()
+	require.Nil(t, err)
+	require.Equal(t, "https://account.blob.core.windows.net?sv=2021-08-06", client.URL())
+}
+
+func TestAzureStorage(t *testing.T) {
+	if !*runAzurite {
+		t.Skip("Skipping Azure storage tests, since the -azurite flag is not set.")
+	}
+
+	ctx := context.Background()
+	azureConfig := &shared.AzureConfig{
+		AccountURL:  *azuriteURL,
+		Container:   "aqueduct-test",
+		Prefix:      uuid.NewString() + "/",
+		AccountName: azuriteAccountName,
+		AccountKey:  azuriteAccountKey,
+	}
+	store := newAzureStorage(azureConfig)
+
+	client, err := store.newClient()
+	require.Nil(t, err)
+	_, err = client.CreateContainer(ctx, azureConfig.Container, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		require.Nil(t, err)
+	}
+
+	require.Nil(t, ValidateAzureConfig(ctx, azureConfig))
+
+	require.Nil(t, store.Put(ctx, "a", []byte("0123456789")))
+	require.True(t, store.Exists(ctx, "a"))
+	require.False(t, store.Exists(ctx, "missing"))
+
+	content, err := store.Get(ctx, "a")
+	require.Nil(t, err)
+	require.Equal(t, "0123456789", string(content))
+
+	_, err = store.Get(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+
+	info, err := store.Stat(ctx, "a")
+	require.Nil(t, err)
+	require.Equal(t, int64(10), info.Size)
+
+	reader, err := store.NewRangeReader(ctx, "a", 2, 3)
+	require.Nil(t, err)
+	content, err = io.ReadAll(reader)
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+	require.Equal(t, "234", string(content))
+
+	writer, err := store.NewWriter(ctx, "dir/b")
+	require.Nil(t, err)
+	_, err = io.WriteString(writer, "streamed")
+	require.Nil(t, err)
+	require.Nil(t, writer.Close())
+
+	objects, err := store.List(ctx, "")
+	require.Nil(t, err)
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	sort.Strings(keys)
+	require.Equal(t, []string{"a", "dir/b"}, keys)
+
+	require.Nil(t, store.Delete(ctx, "a"))
+	require.Nil(t, store.Delete(ctx, "dir/b"))
+	require.False(t, store.Exists(ctx, "a"))
+}
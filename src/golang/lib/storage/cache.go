@@ -0,0 +1,461 @@
+package storage
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// globalCache is the cache of remote storage objects of this process. It is nil if caching is disabled.
+var globalCache *Cache
+
+// uncachedPrefixes are the prefixes of the keys of objects that are never cached. The data keys are small
+// and are rewritten when the master key is rotated.
+var uncachedPrefixes = []string{EncryptionKeysDir}
+
+// Cache is a size-bounded LRU cache of storage objects on the local disk. It caches the objects
+// as they are stored, so compressed and encrypted objects are not decompressed or decrypted on disk.
+//
+// Objects are invalidated by writes of this process. Since other processes may overwrite objects as well,
+// a cached object is only served if the size and modification time of the object in the underlying storage
+// still match, which costs a Stat but no download. Objects under `uncachedPrefixes` are never cached.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu   sync.Mutex
+	size int64
+	// entries holds the *cacheEntry of all objects in the cache, from the most to the least recently used.
+	entries *list.List
+	byKey   map[string]*list.Element
+	// fills counts the reads of each key that add the object to the cache once they complete.
+	// If the object is invalidated in the meantime, the key is marked as stale, so that the fills are dropped.
+	fills map[string]int
+	stale map[string]bool
+
+	hits   int64
+	misses int64
+}
+
+type cacheEntry struct {
+	key          string
+	size         int64
+	lastModified time.Time
+}
+
+// CacheStats describes the usage of a Cache.
+type CacheStats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Objects int   `json:"objects"`
+	Size    int64 `json:"size"`
+	MaxSize int64 `json:"max_size"`
+}
+
+// EnableCache caches the objects of remote storage in dir, up to maxSize bytes,
+// for all storage constructed by this process afterwards. Any content of dir is deleted,
+// since the objects may have changed while the cache was not in use.
+func EnableCache(dir string, maxSize int64) error {
+	cache, err := NewCache(dir, maxSize)
+	if err != nil {
+		return err
+	}
+
+	globalCache = cache
+	return nil
+}
+
+// GetCache returns the cache enabled by `EnableCache`, or nil if caching is disabled.
+func GetCache() *Cache {
+	return globalCache
+}
+
+func NewCache(dir string, maxSize int64) (*Cache, error) {
+	if maxSize <= 0 {
+		return nil, errors.Newf("The maximum size of the storage cache must be positive, but is %d.", maxSize)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, errors.Wrap(err, "Unable to clear the storage cache directory.")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "Unable to create the storage cache directory.")
+	}
+
+	return &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: list.New(),
+		byKey:   map[string]*list.Element{},
+		fills:   map[string]int{},
+		stale:   map[string]bool{},
+	}, nil
+}
+
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    atomic.LoadInt64(&c.hits),
+		Misses:  atomic.LoadInt64(&c.misses),
+		Objects: c.entries.Len(),
+		Size:    c.size,
+		MaxSize: c.maxSize,
+	}
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// lookup returns the entry of key and marks it as recently used.
+func (c *Cache) lookup(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.byKey[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.entries.MoveToFront(elem)
+	entry := *elem.Value.(*cacheEntry)
+	return &entry, true
+}
+
+// open returns a reader of the cached object of key, if it has the size and modification time of info.
+// Otherwise, the object is invalidated. The file of an object that is evicted while it is read remains
+// readable until it is closed. It counts a hit or a miss.
+func (c *Cache) open(key string, info *ObjectInfo) (*os.File, bool) {
+	entry, ok := c.lookup(key)
+	if ok && (entry.size != info.Size || !entry.lastModified.Equal(info.LastModified)) {
+		c.remove(key)
+		ok = false
+	}
+
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	file, err := os.Open(c.path(key))
+	if err != nil {
+		// The object was evicted in between.
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+	return file, true
+}
+
+// beginFill registers a read of key that adds the object to the cache with `endFill`.
+func (c *Cache) beginFill(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fills[key]++
+}
+
+// endFill moves the temporary file at tmpPath into the cache as the object of key, unless the
+// object was invalidated since `beginFill`. The least recently used objects are evicted if the
+// cache is full. If tmpPath is empty, nothing is added.
+func (c *Cache) endFill(key string, tmpPath string, size int64, lastModified time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stale := c.stale[key]
+	c.fills[key]--
+	if c.fills[key] == 0 {
+		delete(c.fills, key)
+		delete(c.stale, key)
+	}
+
+	if tmpPath == "" {
+		return
+	}
+
+	if stale || size > c.maxSize {
+		os.Remove(tmpPath)
+		return
+	}
+
+	if err := os.Rename(tmpPath, c.path(key)); err != nil {
+		log.Errorf("Unable to add object %s to the storage cache: %v", key, err)
+		os.Remove(tmpPath)
+		return
+	}
+
+	if elem, ok := c.byKey[key]; ok {
+		c.size -= elem.Value.(*cacheEntry).size
+		c.entries.Remove(elem)
+	}
+
+	c.byKey[key] = c.entries.PushFront(&cacheEntry{
+		key:          key,
+		size:         size,
+		lastModified: lastModified,
+	})
+	c.size += size
+
+	for c.size > c.maxSize {
+		c.removeLocked(c.entries.Back().Value.(*cacheEntry).key)
+	}
+}
+
+// remove invalidates the object of key.
+func (c *Cache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeLocked(key)
+}
+
+func (c *Cache) removeLocked(key string) {
+	if c.fills[key] > 0 {
+		c.stale[key] = true
+	}
+
+	elem, ok := c.byKey[key]
+	if !ok {
+		return
+	}
+
+	c.size -= elem.Value.(*cacheEntry).size
+	c.entries.Remove(elem)
+	delete(c.byKey, key)
+	if err := os.Remove(c.path(key)); err != nil {
+		log.Errorf("Unable to remove object %s from the storage cache: %v", key, err)
+	}
+}
+
+// cachedStorage reads objects of the underlying Storage through a Cache.
+// Objects are added to the cache when they are read as a whole, and invalidated when they are written.
+type cachedStorage struct {
+	Storage
+	cache *Cache
+	// namespace separates the objects of different storage in the cache.
+	namespace string
+}
+
+func newCachedStorage(store Storage, cache *Cache, namespace string) *cachedStorage {
+	return &cachedStorage{
+		Storage:   store,
+		cache:     cache,
+		namespace: namespace,
+	}
+}
+
+func (c *cachedStorage) cacheKey(key string) string {
+	return c.namespace + "/" + key
+}
+
+func (c *cachedStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	reader, err := c.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// Put invalidates the object after it is written as well, since it may have been read in the meantime.
+func (c *cachedStorage) Put(ctx context.Context, key string, value []byte) error {
+	c.cache.remove(c.cacheKey(key))
+	defer c.cache.remove(c.cacheKey(key))
+	return c.Storage.Put(ctx, key, value)
+}
+
+func (c *cachedStorage) Delete(ctx context.Context, key string) error {
+	c.cache.remove(c.cacheKey(key))
+	defer c.cache.remove(c.cacheKey(key))
+	return c.Storage.Delete(ctx, key)
+}
+
+func (c *cachedStorage) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !isCached(key) {
+		return c.Storage.NewReader(ctx, key)
+	}
+
+	cacheKey := c.cacheKey(key)
+	info, err := c.Storage.Stat(ctx, key)
+	if err != nil {
+		c.cache.remove(cacheKey)
+		return nil, err
+	}
+
+	if file, ok := c.cache.open(cacheKey, info); ok {
+		return file, nil
+	}
+
+	// The fill begins before the object is read, so that it is dropped if the object is written in between.
+	// If it is written before, the modification time of the cached object is older than that of the
+	// written object, so the cached object is never served.
+	c.cache.beginFill(cacheKey)
+	reader, err := c.Storage.NewReader(ctx, key)
+	if err != nil {
+		c.cache.endFill(cacheKey, "", 0, time.Time{})
+		return nil, err
+	}
+
+	if info.Size > c.cache.maxSize {
+		c.cache.endFill(cacheKey, "", 0, time.Time{})
+		return reader, nil
+	}
+
+	file, err := os.CreateTemp(c.cache.dir, "tmp-")
+	if err != nil {
+		log.Errorf("Unable to add object %s to the storage cache: %v", key, err)
+		c.cache.endFill(cacheKey, "", 0, time.Time{})
+		return reader, nil
+	}
+
+	return &cacheFillReader{
+		reader:       reader,
+		file:         file,
+		cache:        c.cache,
+		key:          cacheKey,
+		lastModified: info.LastModified,
+	}, nil
+}
+
+// NewRangeReader only reads from the cache, since partial objects are not cached.
+func (c *cachedStorage) NewRangeReader(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if !isCached(key) {
+		return c.Storage.NewRangeReader(ctx, key, offset, length)
+	}
+
+	info, err := c.Storage.Stat(ctx, key)
+	if err != nil {
+		c.cache.remove(c.cacheKey(key))
+		return nil, err
+	}
+
+	file, ok := c.cache.open(c.cacheKey(key), info)
+	if !ok {
+		return c.Storage.NewRangeReader(ctx, key, offset, length)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if length < 0 {
+		return file, nil
+	}
+
+	return &readCloser{
+		Reader:  io.LimitReader(file, length),
+		closers: []io.Closer{file},
+	}, nil
+}
+
+func (c *cachedStorage) NewWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	c.cache.remove(c.cacheKey(key))
+	writer, err := c.Storage.NewWriter(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invalidatingWriter{
+		WriteCloser: writer,
+		cache:       c.cache,
+		key:         c.cacheKey(key),
+	}, nil
+}
+
+// invalidatingWriter invalidates the object once it is written, since it may
+// have been read in the meantime.
+type invalidatingWriter struct {
+	io.WriteCloser
+	cache *Cache
+	key   string
+}
+
+func (w *invalidatingWriter) Close() error {
+	err := w.WriteCloser.Close()
+	w.cache.remove(w.key)
+	return err
+}
+
+// cacheFillReader copies the object read from reader to file, and adds it to the cache
+// once it is read completely.
+type cacheFillReader struct {
+	reader       io.ReadCloser
+	file         *os.File
+	cache        *Cache
+	key          string
+	lastModified time.Time
+
+	size int64
+	// failed is set if the content cannot be added to the cache.
+	failed   bool
+	complete bool
+}
+
+func (r *cacheFillReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 && !r.failed {
+		if _, writeErr := r.file.Write(p[:n]); writeErr != nil {
+			log.Errorf("Unable to add object %s to the storage cache: %v", r.key, writeErr)
+			r.failed = true
+		}
+		r.size += int64(n)
+	}
+
+	if err == io.EOF {
+		r.complete = true
+	}
+	return n, err
+}
+
+func (r *cacheFillReader) Close() error {
+	err := r.reader.Close()
+	if closeErr := r.file.Close(); closeErr != nil {
+		r.failed = true
+	}
+
+	if r.complete && !r.failed && err == nil {
+		r.cache.endFill(r.key, r.file.Name(), r.size, r.lastModified)
+	} else {
+		os.Remove(r.file.Name())
+		r.cache.endFill(r.key, "", 0, time.Time{})
+	}
+	return err
+}
+
+// isCached returns whether the object of key may be cached.
+func isCached(key string) bool {
+	for _, prefix := range uncachedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// newCachedStorageIfEnabled reads the objects of remote storage with config through the cache
+// of this process, if it is enabled.
+func newCachedStorageIfEnabled(store Storage, config *shared.StorageConfig) Storage {
+	if globalCache == nil || config.Type == shared.FileStorageType {
+		return store
+	}
+	return newCachedStorage(store, globalCache, keyringID(config))
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCachedStorage(t *testing.T, maxSize int64) (*cachedStorage, *fileStorage) {
+	cache, err := NewCache(t.TempDir(), maxSize)
+	require.Nil(t, err)
+
+	inner := newFileStorage(&shared.FileConfig{Directory: t.TempDir()})
+	return newCachedStorage(inner, cache, "test"), inner
+}
+
+func TestCachedStorage(t *testing.T) {
+	ctx := context.Background()
+	store, inner := newTestCachedStorage(t, 1024)
+
+	require.Nil(t, store.Put(ctx, "key", []byte("value")))
+
+	content, err := store.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, "value", string(content))
+	require.Equal(t, CacheStats{Misses: 1, Objects: 1, Size: 5, MaxSize: 1024}, store.cache.Stats())
+
+	reader, err := store.NewRangeReader(ctx, "key", 1, 3)
+	require.Nil(t, err)
+	content, err = io.ReadAll(reader)
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+	require.Equal(t, "alu", string(content))
+
+	// Stat and Exists always ask the underlying storage, so they do not count as hits.
+	info, err := store.Stat(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, int64(5), info.Size)
+	require.True(t, store.Exists(ctx, "key"))
+	require.Equal(t, int64(1), store.cache.Stats().Hits)
+
+	// The object is read again if another process overwrites it.
+	time.Sleep(10 * time.Millisecond)
+	require.Nil(t, inner.Put(ctx, "key", []byte("other")))
+	content, err = store.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, "other", string(content))
+	require.Equal(t, CacheStats{Hits: 1, Misses: 2, Objects: 1, Size: 5, MaxSize: 1024}, store.cache.Stats())
+
+	// The object is dropped from the cache if another process deletes it.
+	require.Nil(t, inner.Delete(ctx, "key"))
+	_, err = store.Get(ctx, "key")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+	require.Equal(t, 0, store.cache.Stats().Objects)
+
+	// Writes invalidate the object.
+	require.Nil(t, store.Put(ctx, "key", []byte("new value")))
+	content, err = store.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, "new value", string(content))
+
+	writer, err := store.NewWriter(ctx, "key")
+	require.Nil(t, err)
+	_, err = writer.Write([]byte("written"))
+	require.Nil(t, err)
+	require.Nil(t, writer.Close())
+	content, err = store.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, "written", string(content))
+
+	require.Nil(t, store.Delete(ctx, "key"))
+	require.False(t, store.Exists(ctx, "key"))
+	_, err = store.Get(ctx, "key")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+	require.Equal(t, 0, store.cache.Stats().Objects)
+}
+
+func TestCachedStorageEviction(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestCachedStorage(t, 25)
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.Nil(t, store.Put(ctx, key, bytes.Repeat([]byte(key), 10)))
+		_, err := store.Get(ctx, key)
+		require.Nil(t, err)
+
+		// a is the most recently used object before c is added, so b is evicted.
+		if key == "b" {
+			_, err = store.Get(ctx, "a")
+			require.Nil(t, err)
+		}
+	}
+
+	stats := store.cache.Stats()
+	require.Equal(t, 2, stats.Objects)
+	require.Equal(t, int64(20), stats.Size)
+
+	hits := stats.Hits
+	for _, key := range []string{"a", "c"} {
+		_, err := store.Get(ctx, key)
+		require.Nil(t, err)
+	}
+	require.Equal(t, hits+2, store.cache.Stats().Hits)
+
+	_, err := store.Get(ctx, "b")
+	require.Nil(t, err)
+	require.Equal(t, hits+2, store.cache.Stats().Hits)
+
+	// Objects larger than the cache are not cached.
+	require.Nil(t, store.Put(ctx, "large", bytes.Repeat([]byte("l"), 30)))
+	content, err := store.Get(ctx, "large")
+	require.Nil(t, err)
+	require.Len(t, content, 30)
+	require.False(t, store.cache.Stats().Size > 25)
+	_, ok := store.cache.lookup(store.cacheKey("large"))
+	require.False(t, ok)
+}
+
+func TestCachedStorageUncachedPrefixes(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestCachedStorage(t, 1024)
+
+	key := EncryptionKeysDir + "default/key"
+	require.Nil(t, store.Put(ctx, key, []byte("data key")))
+	content, err := store.Get(ctx, key)
+	require.Nil(t, err)
+	require.Equal(t, "data key", string(content))
+	require.Equal(t, CacheStats{MaxSize: 1024}, store.cache.Stats())
+}
+
+func TestCachedStoragePartialReads(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestCachedStorage(t, 1024)
+	require.Nil(t, store.Put(ctx, "key", []byte("value")))
+
+	// Objects that are not read completely are not cached.
+	reader, err := store.NewReader(ctx, "key")
+	require.Nil(t, err)
+	_, err = reader.Read(make([]byte, 2))
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+
+	reader, err = store.NewRangeReader(ctx, "key", 0, 2)
+	require.Nil(t, err)
+	_, err = io.ReadAll(reader)
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+	require.Equal(t, 0, store.cache.Stats().Objects)
+
+	// Objects that are written while they are read are not cached.
+	reader, err = store.NewReader(ctx, "key")
+	require.Nil(t, err)
+	require.Nil(t, store.Put(ctx, "key", []byte("new value")))
+	_, err = io.ReadAll(reader)
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+	require.Equal(t, 0, store.cache.Stats().Objects)
+
+	content, err := store.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, "new value", string(content))
+	require.Equal(t, 1, store.cache.Stats().Objects)
+}
@@ -0,0 +1,421 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"io"
+	"log"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/klauspost/compress/zstd"
+)
+
+// An object written by compressedStorage is framed as follows:
+//
+//	| magic (4 bytes) | algorithm (1 byte) | payload | uncompressed size (8 bytes) | SHA-256 (32 bytes) |
+//
+// The payload is the content compressed with the algorithm, and the trailer describes the
+// uncompressed content. The Python executor reads and writes the same format.
+const (
+	frameMagic       = "\x89AQS"
+	frameHeaderSize  = len(frameMagic) + 1
+	frameTrailerSize = 8 + sha256.Size
+)
+
+// The algorithms are identified by a single byte in the header of the frame.
+var frameAlgorithms = map[shared.CompressionAlgorithm]byte{
+	shared.NoCompression:   0,
+	shared.GzipCompression: 1,
+	shared.ZstdCompression: 2,
+}
+
+// compressedStorage compresses and checksums objects before they are written to the underlying
+// Storage, and decompresses and verifies them on every read. Objects that are not framed, e.g. because
+// they were written before compression was enabled, are read as is.
+// List reports the stored sizes of objects, while Stat reports their uncompressed sizes.
+type compressedStorage struct {
+	Storage
+	algorithm byte
+}
+
+func newCompressedStorage(store Storage, algorithm shared.CompressionAlgorithm) *compressedStorage {
+	algorithmByte, ok := frameAlgorithms[algorithm]
+	if !ok {
+		log.Fatalf("Unsupported compression algorithm: %s", algorithm)
+	}
+
+	return &compressedStorage{
+		Storage:   store,
+		algorithm: algorithmByte,
+	}
+}
+
+func (c *compressedStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	reader, err := c.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to read object `%s`.", key)
+	}
+
+	return content, nil
+}
+
+func (c *compressedStorage) Put(ctx context.Context, key string, value []byte) error {
+	var buf bytes.Buffer
+	writer, err := newFrameWriter(nopWriteCloser{&buf}, c.algorithm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(value); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return c.Storage.Put(ctx, key, buf.Bytes())
+}
+
+func (c *compressedStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := c.Storage.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size < int64(frameHeaderSize+frameTrailerSize) {
+		return info, nil
+	}
+
+	header, err := readRange(ctx, c.Storage, key, 0, int64(frameHeaderSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if string(header[:len(frameMagic)]) != frameMagic {
+		return info, nil
+	}
+
+	trailer, err := readRange(ctx, c.Storage, key, info.Size-int64(frameTrailerSize), int64(frameTrailerSize))
+	if err != nil {
+		return nil, err
+	}
+
+	info.Size = int64(binary.BigEndian.Uint64(trailer))
+	return info, nil
+}
+
+func (c *compressedStorage) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := c.Storage.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	frameReader, err := newFrameReader(reader)
+	if err != nil {
+		reader.Close()
+		return nil, errors.Wrapf(err, "Unable to read object `%s`.", key)
+	}
+
+	return frameReader, nil
+}
+
+// NewRangeReader decompresses the object from the start and skips the content before offset.
+// The content is only verified if the range extends to the end of the object.
+// Since the compressed frames are not indexed, every range read costs O(offset), so serving
+// many ranges of a large compressed object reads it from the start each time.
+func (c *compressedStorage) NewRangeReader(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	reader, err := c.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	rangeReader, err := newRangeReadCloser(reader, offset, length)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to read object `%s`.", key)
+	}
+
+	return rangeReader, nil
+}
+
+// NewWriter compresses the content as it is written. If writing fails, the object is left
+// without a valid trailer, so it is detected as corrupted when it is read.
+func (c *compressedStorage) NewWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	writer, err := c.Storage.NewWriter(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	frameWriter, err := newFrameWriter(writer, c.algorithm)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+
+	return frameWriter, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// frameWriter frames the content written to it and writes the frame to w.
+type frameWriter struct {
+	w          io.WriteCloser
+	compressor io.WriteCloser
+	hash       hash.Hash
+	size       uint64
+	err        error
+}
+
+func newFrameWriter(w io.WriteCloser, algorithm byte) (*frameWriter, error) {
+	if _, err := io.WriteString(w, frameMagic+string([]byte{algorithm})); err != nil {
+		return nil, err
+	}
+
+	var compressor io.WriteCloser
+	switch algorithm {
+	case frameAlgorithms[shared.NoCompression]:
+		compressor = nopWriteCloser{w}
+	case frameAlgorithms[shared.GzipCompression]:
+		compressor = gzip.NewWriter(w)
+	case frameAlgorithms[shared.ZstdCompression]:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		compressor = encoder
+	default:
+		return nil, errors.Newf("Unsupported compression algorithm %d.", algorithm)
+	}
+
+	return &frameWriter{
+		w:          w,
+		compressor: compressor,
+		hash:       sha256.New(),
+	}, nil
+}
+
+func (fw *frameWriter) Write(p []byte) (int, error) {
+	if fw.err != nil {
+		return 0, fw.err
+	}
+
+	n, err := fw.compressor.Write(p)
+	fw.hash.Write(p[:n])
+	fw.size += uint64(n)
+	if err != nil {
+		fw.err = err
+	}
+	return n, err
+}
+
+func (fw *frameWriter) Close() error {
+	if fw.err == nil {
+		fw.err = fw.compressor.Close()
+	}
+
+	if fw.err == nil {
+		trailer := make([]byte, 8, frameTrailerSize)
+		binary.BigEndian.PutUint64(trailer, fw.size)
+		trailer = fw.hash.Sum(trailer)
+		_, fw.err = fw.w.Write(trailer)
+	}
+
+	if err := fw.w.Close(); err != nil && fw.err == nil {
+		fw.err = err
+	}
+	return fw.err
+}
+
+// newFrameReader returns a reader of the content of the object read from r. If the object is framed,
+// the content is decompressed and the returned reader fails with `ErrObjectCorrupted` once it reaches
+// the end of the content, if the content does not match the trailer. Otherwise, the object is read as is.
+func newFrameReader(r io.ReadCloser) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(frameHeaderSize)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if len(header) < frameHeaderSize || string(header[:len(frameMagic)]) != frameMagic {
+		return &readCloser{
+			Reader:  buffered,
+			closers: []io.Closer{r},
+		}, nil
+	}
+
+	if _, err := buffered.Discard(frameHeaderSize); err != nil {
+		return nil, err
+	}
+
+	payload := &trailerReader{
+		r:   buffered,
+		buf: make([]byte, 0, 32*1024+frameTrailerSize),
+	}
+	fr := &frameReader{
+		payload: payload,
+		hash:    sha256.New(),
+		closers: []io.Closer{r},
+	}
+
+	switch header[len(frameMagic)] {
+	case frameAlgorithms[shared.NoCompression]:
+		fr.content = payload
+	case frameAlgorithms[shared.GzipCompression]:
+		decompressor, err := gzip.NewReader(payload)
+		if err != nil {
+			return nil, fr.corrupted(err)
+		}
+		fr.content = decompressor
+		fr.closers = append([]io.Closer{decompressor}, fr.closers...)
+	case frameAlgorithms[shared.ZstdCompression]:
+		decoder, err := zstd.NewReader(payload, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		fr.content = decoder
+		fr.closers = append([]io.Closer{decoder.IOReadCloser()}, fr.closers...)
+	default:
+		return nil, errors.Wrapf(ErrObjectCorrupted(), "Unknown compression algorithm %d.", header[len(frameMagic)])
+	}
+
+	return fr, nil
+}
+
+// frameReader reads the decompressed content of a frame and verifies it against the trailer of the frame.
+type frameReader struct {
+	payload *trailerReader
+	content io.Reader
+	hash    hash.Hash
+	size    uint64
+	closers []io.Closer
+}
+
+func (fr *frameReader) Read(p []byte) (int, error) {
+	n, err := fr.content.Read(p)
+	fr.hash.Write(p[:n])
+	fr.size += uint64(n)
+
+	switch {
+	case err == io.EOF:
+		if verifyErr := fr.verify(); verifyErr != nil {
+			return n, verifyErr
+		}
+	case err != nil:
+		return n, fr.corrupted(err)
+	}
+	return n, err
+}
+
+func (fr *frameReader) verify() error {
+	// The decompressor may stop before the end of the payload, which must not have any content left.
+	extra, err := io.Copy(io.Discard, fr.payload)
+	if err != nil {
+		return err
+	}
+	if extra > 0 {
+		return errors.Wrapf(ErrObjectCorrupted(), "Unexpected %d bytes after the compressed content.", extra)
+	}
+
+	trailer := fr.payload.buf
+	if size := binary.BigEndian.Uint64(trailer); size != fr.size {
+		return errors.Wrapf(ErrObjectCorrupted(), "Expected %d bytes of content but read %d.", size, fr.size)
+	}
+
+	if !bytes.Equal(trailer[8:], fr.hash.Sum(nil)) {
+		return errors.Wrap(ErrObjectCorrupted(), "The checksum of the content does not match.")
+	}
+
+	return nil
+}
+
+// corrupted returns err as `ErrObjectCorrupted` if it is caused by invalid compressed content
+// rather than by reading the underlying object.
+func (fr *frameReader) corrupted(err error) error {
+	if fr.payload.err != nil {
+		return err
+	}
+	return errors.Wrapf(ErrObjectCorrupted(), "Unable to decompress content: %v", err)
+}
+
+func (fr *frameReader) Close() error {
+	return closeAll(fr.closers)
+}
+
+// trailerReader reads the payload of a frame from r, withholding the trailer at the end of r.
+// Once it returns `io.EOF`, buf holds the trailer.
+type trailerReader struct {
+	r   io.Reader
+	buf []byte
+	eof bool
+	// err is the last error returned by r, other than `io.EOF`.
+	err error
+}
+
+func (t *trailerReader) Read(p []byte) (int, error) {
+	for !t.eof && len(t.buf) <= frameTrailerSize {
+		n, err := t.r.Read(t.buf[len(t.buf):cap(t.buf)])
+		t.buf = t.buf[:len(t.buf)+n]
+		if err == io.EOF {
+			t.eof = true
+		} else if err != nil {
+			t.err = err
+			return 0, err
+		}
+	}
+
+	available := len(t.buf) - frameTrailerSize
+	if available <= 0 {
+		if available < 0 {
+			return 0, errors.Wrap(ErrObjectCorrupted(), "The object is truncated.")
+		}
+		return 0, io.EOF
+	}
+
+	n := copy(p, t.buf[:available])
+	t.buf = t.buf[:copy(t.buf, t.buf[n:])]
+	return n, nil
+}
+
+// NewChecksumReader returns a reader of r, which fails with `ErrObjectCorrupted` once it reaches
+// the end of r if the content does not match checksum, the hex-encoded SHA-256 of the content.
+func NewChecksumReader(r io.ReadCloser, checksum string) io.ReadCloser {
+	return &checksumReader{
+		ReadCloser: r,
+		checksum:   checksum,
+		hash:       sha256.New(),
+	}
+}
+
+type checksumReader struct {
+	io.ReadCloser
+	checksum string
+	hash     hash.Hash
+}
+
+func (cr *checksumReader) Read(p []byte) (int, error) {
+	n, err := cr.ReadCloser.Read(p)
+	cr.hash.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(cr.hash.Sum(nil)) != cr.checksum {
+		return n, errors.Wrap(ErrObjectCorrupted(), "The checksum of the content does not match.")
+	}
+	return n, err
+}
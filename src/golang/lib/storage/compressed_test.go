@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressedStorage(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+
+	for _, algorithm := range []shared.CompressionAlgorithm{
+		shared.NoCompression,
+		shared.GzipCompression,
+		shared.ZstdCompression,
+	} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			inner := newFileStorage(&shared.FileConfig{Directory: t.TempDir()})
+			store := newCompressedStorage(inner, algorithm)
+
+			require.Nil(t, store.Put(ctx, "put", content))
+
+			writer, err := store.NewWriter(ctx, "writer")
+			require.Nil(t, err)
+			_, err = io.Copy(writer, bytes.NewReader(content))
+			require.Nil(t, err)
+			require.Nil(t, writer.Close())
+
+			for _, key := range []string{"put", "writer"} {
+				stored, err := inner.Get(ctx, key)
+				require.Nil(t, err)
+				require.Equal(t, frameMagic, string(stored[:len(frameMagic)]))
+				if algorithm != shared.NoCompression {
+					require.Less(t, len(stored), len(content))
+				}
+
+				value, err := store.Get(ctx, key)
+				require.Nil(t, err)
+				require.Equal(t, content, value)
+
+				info, err := store.Stat(ctx, key)
+				require.Nil(t, err)
+				require.Equal(t, int64(len(content)), info.Size)
+
+				reader, err := store.NewRangeReader(ctx, key, 12, 5)
+				require.Nil(t, err)
+				value, err = io.ReadAll(reader)
+				require.Nil(t, err)
+				require.Nil(t, reader.Close())
+				require.Equal(t, "23456", string(value))
+			}
+		})
+	}
+}
+
+func TestCompressedStorageUnframed(t *testing.T) {
+	ctx := context.Background()
+	inner := newFileStorage(&shared.FileConfig{Directory: t.TempDir()})
+	store := newCompressedStorage(inner, shared.ZstdCompression)
+
+	// Objects written before compression was enabled are read as is.
+	for key, value := range map[string]string{"legacy": "legacy content", "short": "ab", "empty": ""} {
+		require.Nil(t, inner.Put(ctx, key, []byte(value)))
+
+		content, err := store.Get(ctx, key)
+		require.Nil(t, err)
+		require.Equal(t, value, string(content))
+
+		info, err := store.Stat(ctx, key)
+		require.Nil(t, err)
+		require.Equal(t, int64(len(value)), info.Size)
+	}
+
+	_, err := store.Get(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+}
+
+func TestCompressedStorageCorrupted(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+
+	for _, algorithm := range []shared.CompressionAlgorithm{
+		shared.NoCompression,
+		shared.GzipCompression,
+		shared.ZstdCompression,
+	} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			inner := newFileStorage(&shared.FileConfig{Directory: t.TempDir()})
+			store := newCompressedStorage(inner, algorithm)
+
+			require.Nil(t, store.Put(ctx, "key", content))
+			stored, err := inner.Get(ctx, "key")
+			require.Nil(t, err)
+
+			corruptions := map[string][]byte{
+				"payload":   flipByte(stored, frameHeaderSize+len(stored)/2),
+				"size":      flipByte(stored, len(stored)-frameTrailerSize),
+				"checksum":  flipByte(stored, len(stored)-1),
+				"truncated": stored[:len(stored)-10],
+				"trailer":   stored[:len(stored)-frameTrailerSize],
+				"extra":     append(append([]byte{}, stored...), 0),
+			}
+			for name, corrupted := range corruptions {
+				require.Nil(t, inner.Put(ctx, "key", corrupted))
+
+				_, err := store.Get(ctx, "key")
+				require.True(t, errors.Is(err, ErrObjectCorrupted()), "%s: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestChecksumReader(t *testing.T) {
+	sum := sha256.Sum256([]byte("content"))
+	checksum := hex.EncodeToString(sum[:])
+
+	content, err := io.ReadAll(NewChecksumReader(io.NopCloser(bytes.NewReader([]byte("content"))), checksum))
+	require.Nil(t, err)
+	require.Equal(t, "content", string(content))
+
+	_, err = io.ReadAll(NewChecksumReader(io.NopCloser(bytes.NewReader([]byte("contents"))), checksum))
+	require.True(t, errors.Is(err, ErrObjectCorrupted()))
+}
+
+func flipByte(content []byte, i int) []byte {
+	flipped := append([]byte{}, content...)
+	flipped[i] ^= 0xff
+	return flipped
+}
@@ -26,6 +26,11 @@ func convertS3ResourcetoStorageConfig(c *shared.S3ResourceConfig) (*shared.Stora
 			Bucket:  fmt.Sprintf("s3://%s", c.Bucket),
 			Region:  c.Region,
 			RootDir: c.RootDir,
+			S3EndpointConfig: shared.S3EndpointConfig{
+				EndpointURL:    c.EndpointURL,
+				ForcePathStyle: bool(c.ForcePathStyle),
+				SkipTLSVerify:  bool(c.SkipTLSVerify),
+			},
 		},
 	}
 	switch c.Type {
@@ -99,6 +104,14 @@ func convertGCSResourcetoStorageConfig(c *shared.GCSResourceConfig) *shared.Stor
 	}
 }
 
+func convertAzureBlobResourcetoStorageConfig(c *shared.AzureBlobResourceConfig) *shared.StorageConfig {
+	azureConfig := c.AzureConfig
+	return &shared.StorageConfig{
+		Type:        shared.AzureStorageType,
+		AzureConfig: &azureConfig,
+	}
+}
+
 func ConvertResourceConfigToStorageConfig(
 	svc shared.Service,
 	confData []byte,
@@ -118,6 +131,13 @@ func ConvertResourceConfigToStorageConfig(
 		}
 
 		return convertGCSResourcetoStorageConfig(&c), nil
+	case shared.AzureBlob:
+		var c shared.AzureBlobResourceConfig
+		if err := json.Unmarshal(confData, &c); err != nil {
+			return nil, err
+		}
+
+		return convertAzureBlobResourcetoStorageConfig(&c), nil
 	default:
 		return nil, errors.Newf("%v cannot be used as the storage layer", svc)
 	}
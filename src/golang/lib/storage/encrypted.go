@@ -0,0 +1,729 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	aq_config "github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// An object written by encryptedStorage is framed as follows:
+//
+//	| magic (4 bytes) | version (1 byte) | key ID length (2 bytes) | key ID | nonce (12 bytes) | chunks |
+//
+// The content is split into chunks of `encryptionChunkSize` bytes, which are sealed with AES-256-GCM
+// using the data key with the key ID. The nonce of a chunk is the nonce of the object XORed with the
+// index of the chunk, and the last chunk is authenticated as such, so chunks cannot be reordered or dropped.
+// An empty object has a single empty chunk. The Python executor reads and writes the same format.
+//
+// The data keys are stored in the underlying storage under `EncryptionKeysDir`, wrapped by the master key,
+// which never leaves the server. Python jobs are given the unwrapped data keys they need, see `JobDataKeys`.
+// Rotating the master key only re-wraps the data keys, see `RotateMasterKey`.
+const (
+	// EncryptionKeysDir is the directory of the storage that holds the wrapped data keys.
+	EncryptionKeysDir = "encryption/keys/"
+
+	encryptionMagic        = "\x89AQE"
+	encryptionVersion      = 1
+	encryptionNonceSize    = 12
+	encryptionTagSize      = 16
+	encryptionChunkSize    = 64 * 1024
+	encryptionSealedSize   = encryptionChunkSize + encryptionTagSize
+	encryptionPrefixSize   = len(encryptionMagic) + 1 + 2
+	defaultEncryptionScope = "default"
+	dataKeySize            = 32
+	encryptionLastChunk    = 1
+	encryptionNotLastChunk = 0
+)
+
+var (
+	// dataKeys caches the unwrapped data keys by the ID of the keyring and the key ID,
+	// since a storage is constructed for almost every access.
+	dataKeys sync.Map
+	// writeDataKeys caches the key ID of the data key that new objects are encrypted with,
+	// by the ID of the keyring and the key scope.
+	writeDataKeys sync.Map
+)
+
+// encryptedStorage encrypts objects before they are written to the underlying Storage,
+// and decrypts them when they are read. Objects that are not encrypted, e.g. because they were
+// written before encryption was enabled, are read as is.
+// List reports the stored sizes of objects, while Stat reports their decrypted sizes.
+type encryptedStorage struct {
+	Storage
+	masterKey string
+	scope     string
+	// keyringID identifies the data keys of the underlying storage in the caches.
+	keyringID string
+}
+
+func newEncryptedStorage(store Storage, keyringID string, masterKey string, scope string) *encryptedStorage {
+	if scope == "" {
+		scope = defaultEncryptionScope
+	}
+
+	return &encryptedStorage{
+		Storage:   store,
+		masterKey: masterKey,
+		scope:     scope,
+		keyringID: keyringID,
+	}
+}
+
+// keyringID returns the ID of the data keys stored in the storage with config.
+func keyringID(config *shared.StorageConfig) string {
+	base := *config
+	base.Compression = nil
+	base.Encryption = nil
+	serialized, err := json.Marshal(base)
+	if err != nil {
+		// This never happens, since the config only consists of strings and bools.
+		return string(config.Type)
+	}
+
+	sum := sha256.Sum256(serialized)
+	return hex.EncodeToString(sum[:])
+}
+
+func (e *encryptedStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	reader, err := e.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to read object `%s`.", key)
+	}
+
+	return content, nil
+}
+
+func (e *encryptedStorage) Put(ctx context.Context, key string, value []byte) error {
+	var buf bytes.Buffer
+	writer, err := e.newChunkWriter(ctx, nopWriteCloser{&buf})
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(value); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return e.Storage.Put(ctx, key, buf.Bytes())
+}
+
+func (e *encryptedStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := e.Storage.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	header, encrypted, err := e.readHeaderAt(ctx, key, info.Size)
+	if err != nil || !encrypted {
+		return info, err
+	}
+
+	body := info.Size - int64(header.size())
+	chunks := (body + encryptionSealedSize - 1) / encryptionSealedSize
+	if chunks == 0 || body-chunks*encryptionTagSize < 0 {
+		return nil, errors.Wrapf(ErrObjectCorrupted(), "Object `%s` is truncated.", key)
+	}
+
+	info.Size = body - chunks*encryptionTagSize
+	return info, nil
+}
+
+func (e *encryptedStorage) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	return e.NewRangeReader(ctx, key, 0, -1)
+}
+
+// NewRangeReader only reads and decrypts the chunks of the object that overlap the range.
+func (e *encryptedStorage) NewRangeReader(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if offset == 0 {
+		return e.newReader(ctx, key, length)
+	}
+
+	info, err := e.Storage.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	header, encrypted, err := e.readHeaderAt(ctx, key, info.Size)
+	if err != nil {
+		return nil, err
+	}
+	if !encrypted {
+		return e.Storage.NewRangeReader(ctx, key, offset, length)
+	}
+
+	aead, err := e.dataKey(ctx, header.keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	chunk := offset / encryptionChunkSize
+	reader, err := e.Storage.NewRangeReader(ctx, key, int64(header.size())+chunk*encryptionSealedSize, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	rangeReader, err := newRangeReadCloser(newChunkReader(reader, aead, header.nonce, uint64(chunk)), offset%encryptionChunkSize, length)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to read object `%s`.", key)
+	}
+
+	return rangeReader, nil
+}
+
+func (e *encryptedStorage) newReader(ctx context.Context, key string, length int64) (io.ReadCloser, error) {
+	reader, err := e.Storage.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(reader)
+	header, encrypted, err := readEncryptionHeader(buffered)
+	if err != nil {
+		reader.Close()
+		return nil, errors.Wrapf(err, "Unable to read object `%s`.", key)
+	}
+
+	var content io.ReadCloser = &readCloser{Reader: buffered, closers: []io.Closer{reader}}
+	if encrypted {
+		aead, err := e.dataKey(ctx, header.keyID)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+		content = newChunkReader(content, aead, header.nonce, 0)
+	}
+
+	return newRangeReadCloser(content, 0, length)
+}
+
+func (e *encryptedStorage) NewWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	writer, err := e.Storage.NewWriter(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkWriter, err := e.newChunkWriter(ctx, writer)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+
+	return chunkWriter, nil
+}
+
+func (e *encryptedStorage) newChunkWriter(ctx context.Context, w io.WriteCloser) (*chunkWriter, error) {
+	keyID, err := e.writeDataKeyID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := e.dataKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &encryptionHeader{
+		keyID: keyID,
+		nonce: make([]byte, encryptionNonceSize),
+	}
+	if _, err := io.ReadFull(rand.Reader, header.nonce); err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(header.bytes()); err != nil {
+		return nil, err
+	}
+
+	return &chunkWriter{
+		w:     w,
+		aead:  aead,
+		nonce: header.nonce,
+		buf:   make([]byte, 0, encryptionChunkSize),
+	}, nil
+}
+
+// readHeaderAt reads the encryption header of the object at key, whose stored size is size.
+// The returned bool is whether the object is encrypted.
+func (e *encryptedStorage) readHeaderAt(ctx context.Context, key string, size int64) (*encryptionHeader, bool, error) {
+	if size < int64(encryptionPrefixSize) {
+		return nil, false, nil
+	}
+
+	prefix, err := readRange(ctx, e.Storage, key, 0, int64(encryptionPrefixSize))
+	if err != nil {
+		return nil, false, err
+	}
+	if string(prefix[:len(encryptionMagic)]) != encryptionMagic {
+		return nil, false, nil
+	}
+
+	headerSize := int64(encryptionPrefixSize) + int64(binary.BigEndian.Uint16(prefix[len(encryptionMagic)+1:])) + encryptionNonceSize
+	if headerSize > size {
+		return nil, false, errors.Wrapf(ErrObjectCorrupted(), "Object `%s` is truncated.", key)
+	}
+
+	header, err := readRange(ctx, e.Storage, key, 0, headerSize)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return readEncryptionHeader(bufio.NewReader(bytes.NewReader(header)))
+}
+
+func readRange(ctx context.Context, store Storage, key string, offset int64, length int64) ([]byte, error) {
+	reader, err := store.NewRangeReader(ctx, key, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(reader, content); err != nil {
+		return nil, errors.Wrapf(err, "Unable to read object `%s`.", key)
+	}
+
+	return content, nil
+}
+
+// writeDataKeyID returns the ID of the data key that new objects are encrypted with. This is the oldest
+// data key of the scope, so that every process uses the same one. A data key is only created if the scope
+// has none yet. If processes race to create it, each key they create is valid, and later processes
+// converge on the oldest one.
+func (e *encryptedStorage) writeDataKeyID(ctx context.Context) (string, error) {
+	cacheKey := e.keyringID + "/" + e.scope
+	if keyID, ok := writeDataKeys.Load(cacheKey); ok {
+		return keyID.(string), nil
+	}
+
+	keyIDs, err := e.dataKeyIDs(ctx, e.scope)
+	if err != nil {
+		return "", err
+	}
+
+	var keyID string
+	if len(keyIDs) > 0 {
+		keyID = keyIDs[0]
+	} else {
+		keyID, err = e.createDataKey(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	actual, _ := writeDataKeys.LoadOrStore(cacheKey, keyID)
+	return actual.(string), nil
+}
+
+// dataKeyIDs returns the IDs of the data keys of scope, from the oldest to the newest.
+func (e *encryptedStorage) dataKeyIDs(ctx context.Context, scope string) ([]string, error) {
+	objects, err := e.Storage.List(ctx, EncryptionKeysDir+scope+"/")
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list data keys.")
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if !objects[i].LastModified.Equal(objects[j].LastModified) {
+			return objects[i].LastModified.Before(objects[j].LastModified)
+		}
+		return objects[i].Key < objects[j].Key
+	})
+
+	keyIDs := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keyIDs = append(keyIDs, strings.TrimPrefix(object.Key, EncryptionKeysDir))
+	}
+
+	return keyIDs, nil
+}
+
+// createDataKey creates a data key of the scope and returns its ID.
+func (e *encryptedStorage) createDataKey(ctx context.Context) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+
+	keyID := e.scope + "/" + uuid.NewString()
+	wrapped, err := wrapDataKey(dataKey, keyID, e.masterKey)
+	if err != nil {
+		return "", err
+	}
+
+	if err := e.Storage.Put(ctx, EncryptionKeysDir+keyID, wrapped); err != nil {
+		return "", errors.Wrap(err, "Unable to store data key.")
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	dataKeys.Store(e.keyringID+"/"+keyID, aead)
+	return keyID, nil
+}
+
+// dataKey returns the cipher of the data key with keyID.
+func (e *encryptedStorage) dataKey(ctx context.Context, keyID string) (cipher.AEAD, error) {
+	cacheKey := e.keyringID + "/" + keyID
+	if aead, ok := dataKeys.Load(cacheKey); ok {
+		return aead.(cipher.AEAD), nil
+	}
+
+	dataKey, err := e.readDataKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKeys.Store(cacheKey, aead)
+	return aead, nil
+}
+
+// readDataKey reads the data key with keyID from the storage and unwraps it.
+func (e *encryptedStorage) readDataKey(ctx context.Context, keyID string) ([]byte, error) {
+	wrapped, err := e.Storage.Get(ctx, EncryptionKeysDir+keyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to read data key `%s`.", keyID)
+	}
+
+	dataKey, err := unwrapDataKey(wrapped, keyID, e.masterKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to unwrap data key `%s`.", keyID)
+	}
+
+	return dataKey, nil
+}
+
+// JobDataKeys returns the unwrapped data keys by key ID that a Python job needs to access the encrypted
+// storage with config, and the ID of the data key it encrypts new objects with. These are the data keys of
+// the key scope of config and of the default scope, so that the job never has access to the master key
+// or to the data keys of other workflows.
+func JobDataKeys(ctx context.Context, config *shared.StorageConfig) (map[string][]byte, string, error) {
+	if config.Encryption == nil {
+		return nil, "", errors.New("The storage is not encrypted.")
+	}
+
+	e := newEncryptedStorage(newBaseStorage(config), keyringID(config), aq_config.StorageMasterKey(), config.Encryption.KeyScope)
+	return e.jobDataKeys(ctx)
+}
+
+func (e *encryptedStorage) jobDataKeys(ctx context.Context) (map[string][]byte, string, error) {
+	writeKeyID, err := e.writeDataKeyID(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	scopes := []string{e.scope}
+	if e.scope != defaultEncryptionScope {
+		scopes = append(scopes, defaultEncryptionScope)
+	}
+
+	keys := map[string][]byte{}
+	for _, scope := range scopes {
+		keyIDs, err := e.dataKeyIDs(ctx, scope)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, keyID := range keyIDs {
+			dataKey, err := e.readDataKey(ctx, keyID)
+			if err != nil {
+				return nil, "", err
+			}
+			keys[keyID] = dataKey
+		}
+	}
+
+	return keys, writeKeyID, nil
+}
+
+// RotateMasterKey re-wraps all data keys of the storage with config, which are wrapped by oldMasterKey,
+// with newMasterKey. The objects themselves are not rewritten, since they are encrypted with the data keys.
+// Data keys that are already wrapped by newMasterKey are skipped, so an interrupted rotation can be retried.
+func RotateMasterKey(ctx context.Context, config *shared.StorageConfig, oldMasterKey string, newMasterKey string) error {
+	if _, err := newAEAD([]byte(newMasterKey)); err != nil {
+		return errors.Wrap(err, "The new master key is invalid.")
+	}
+
+	store := newBaseStorage(config)
+	objects, err := store.List(ctx, EncryptionKeysDir)
+	if err != nil {
+		return errors.Wrap(err, "Unable to list data keys.")
+	}
+
+	for _, object := range objects {
+		keyID := strings.TrimPrefix(object.Key, EncryptionKeysDir)
+		wrapped, err := store.Get(ctx, object.Key)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to read data key `%s`.", keyID)
+		}
+
+		if _, err := unwrapDataKey(wrapped, keyID, newMasterKey); err == nil {
+			continue
+		}
+
+		dataKey, err := unwrapDataKey(wrapped, keyID, oldMasterKey)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to unwrap data key `%s`.", keyID)
+		}
+
+		rewrapped, err := wrapDataKey(dataKey, keyID, newMasterKey)
+		if err != nil {
+			return err
+		}
+
+		if err := store.Put(ctx, object.Key, rewrapped); err != nil {
+			return errors.Wrapf(err, "Unable to store data key `%s`.", keyID)
+		}
+	}
+
+	return nil
+}
+
+// wrapDataKey encrypts dataKey with masterKey. The key ID is authenticated as well,
+// so a wrapped data key cannot be swapped with another.
+func wrapDataKey(dataKey []byte, keyID string, masterKey string) ([]byte, error) {
+	aead, err := newAEAD([]byte(masterKey))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, dataKey, []byte(keyID)), nil
+}
+
+func unwrapDataKey(wrapped []byte, keyID string, masterKey string) ([]byte, error) {
+	aead, err := newAEAD([]byte(masterKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("The wrapped data key is truncated.")
+	}
+
+	nonce, ciphertext := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(keyID))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+type encryptionHeader struct {
+	keyID string
+	nonce []byte
+}
+
+func (h *encryptionHeader) size() int {
+	return encryptionPrefixSize + len(h.keyID) + encryptionNonceSize
+}
+
+func (h *encryptionHeader) bytes() []byte {
+	header := make([]byte, 0, h.size())
+	header = append(header, encryptionMagic...)
+	header = append(header, encryptionVersion)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(h.keyID)))
+	header = append(header, h.keyID...)
+	return append(header, h.nonce...)
+}
+
+// readEncryptionHeader reads the encryption header from r. The returned bool is whether the object
+// is encrypted. If it is not, nothing is read from r.
+func readEncryptionHeader(r *bufio.Reader) (*encryptionHeader, bool, error) {
+	prefix, err := r.Peek(encryptionPrefixSize)
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+
+	if len(prefix) < encryptionPrefixSize || string(prefix[:len(encryptionMagic)]) != encryptionMagic {
+		return nil, false, nil
+	}
+
+	if version := prefix[len(encryptionMagic)]; version != encryptionVersion {
+		return nil, false, errors.Newf("Unsupported encryption version %d.", version)
+	}
+
+	header := make([]byte, encryptionPrefixSize+int(binary.BigEndian.Uint16(prefix[len(encryptionMagic)+1:]))+encryptionNonceSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, false, errors.Wrap(ErrObjectCorrupted(), "The encryption header is truncated.")
+	}
+
+	return &encryptionHeader{
+		keyID: string(header[encryptionPrefixSize : len(header)-encryptionNonceSize]),
+		nonce: header[len(header)-encryptionNonceSize:],
+	}, true, nil
+}
+
+// chunkNonce returns the nonce of the chunk with index.
+func chunkNonce(nonce []byte, index uint64) []byte {
+	chunk := make([]byte, encryptionNonceSize)
+	copy(chunk, nonce)
+	counter := binary.BigEndian.Uint64(chunk[encryptionNonceSize-8:])
+	binary.BigEndian.PutUint64(chunk[encryptionNonceSize-8:], counter^index)
+	return chunk
+}
+
+// chunkWriter encrypts the content written to it in chunks and writes them to w.
+type chunkWriter struct {
+	w     io.WriteCloser
+	aead  cipher.AEAD
+	nonce []byte
+	index uint64
+	buf   []byte
+	err   error
+}
+
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if cw.err != nil {
+			return written, cw.err
+		}
+
+		// A full chunk is only sealed once more content follows, since the last chunk is sealed differently.
+		if len(cw.buf) == encryptionChunkSize {
+			cw.err = cw.seal(encryptionNotLastChunk)
+			continue
+		}
+
+		n := copy(cw.buf[len(cw.buf):encryptionChunkSize], p)
+		cw.buf = cw.buf[:len(cw.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (cw *chunkWriter) seal(last byte) error {
+	sealed := cw.aead.Seal(nil, chunkNonce(cw.nonce, cw.index), cw.buf, []byte{last})
+	cw.index++
+	cw.buf = cw.buf[:0]
+	_, err := cw.w.Write(sealed)
+	return err
+}
+
+func (cw *chunkWriter) Close() error {
+	if cw.err == nil {
+		cw.err = cw.seal(encryptionLastChunk)
+	}
+
+	if err := cw.w.Close(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.err
+}
+
+// chunkReader decrypts the chunks read from r, starting with the chunk with index.
+type chunkReader struct {
+	r       *bufio.Reader
+	closer  io.Closer
+	aead    cipher.AEAD
+	nonce   []byte
+	index   uint64
+	sealed  []byte
+	content []byte
+	done    bool
+}
+
+func newChunkReader(r io.ReadCloser, aead cipher.AEAD, nonce []byte, index uint64) *chunkReader {
+	return &chunkReader{
+		r:      bufio.NewReader(r),
+		closer: r,
+		aead:   aead,
+		nonce:  nonce,
+		index:  index,
+		sealed: make([]byte, encryptionSealedSize),
+	}
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for len(cr.content) == 0 {
+		if cr.done {
+			return 0, io.EOF
+		}
+		if err := cr.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, cr.content)
+	cr.content = cr.content[n:]
+	return n, nil
+}
+
+// open reads and decrypts the next chunk.
+func (cr *chunkReader) open() error {
+	n, err := io.ReadFull(cr.r, cr.sealed)
+	last := false
+	switch {
+	case err == io.ErrUnexpectedEOF || err == io.EOF:
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := cr.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	aad := []byte{encryptionNotLastChunk}
+	if last {
+		aad[0] = encryptionLastChunk
+	}
+
+	content, err := cr.aead.Open(cr.sealed[:0], chunkNonce(cr.nonce, cr.index), cr.sealed[:n], aad)
+	if err != nil {
+		return errors.Wrapf(ErrObjectCorrupted(), "Unable to decrypt chunk %d.", cr.index)
+	}
+
+	cr.index++
+	cr.content = content
+	cr.done = last
+	return nil
+}
+
+func (cr *chunkReader) Close() error {
+	return cr.closer.Close()
+}
@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMasterKey    = "00000000000000000000000000000000"
+	testNewMasterKey = "11111111111111111111111111111111"
+)
+
+func newTestEncryptedStorage(t *testing.T, scope string) (*encryptedStorage, *fileStorage, *shared.StorageConfig) {
+	config := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+	inner := newFileStorage(config.FileConfig)
+	return newEncryptedStorage(inner, keyringID(config), testMasterKey, scope), inner, config
+}
+
+func TestEncryptedStorage(t *testing.T) {
+	ctx := context.Background()
+
+	for _, size := range []int{0, 10, encryptionChunkSize, 3*encryptionChunkSize + 17} {
+		store, inner, _ := newTestEncryptedStorage(t, "")
+		content := make([]byte, size)
+		for i := range content {
+			content[i] = byte(i % 251)
+		}
+
+		require.Nil(t, store.Put(ctx, "put", content))
+
+		writer, err := store.NewWriter(ctx, "writer")
+		require.Nil(t, err)
+		_, err = io.Copy(writer, bytes.NewReader(content))
+		require.Nil(t, err)
+		require.Nil(t, writer.Close())
+
+		for _, key := range []string{"put", "writer"} {
+			stored, err := inner.Get(ctx, key)
+			require.Nil(t, err)
+			require.Equal(t, encryptionMagic, string(stored[:len(encryptionMagic)]))
+			if size > 0 {
+				require.False(t, bytes.Contains(stored, content[:10]))
+			}
+
+			value, err := store.Get(ctx, key)
+			require.Nil(t, err)
+			require.Equal(t, content, value)
+
+			info, err := store.Stat(ctx, key)
+			require.Nil(t, err)
+			require.Equal(t, int64(size), info.Size)
+
+			if size > encryptionChunkSize {
+				offset := int64(encryptionChunkSize + 5)
+				reader, err := store.NewRangeReader(ctx, key, offset, 100)
+				require.Nil(t, err)
+				value, err = io.ReadAll(reader)
+				require.Nil(t, err)
+				require.Nil(t, reader.Close())
+				require.Equal(t, content[offset:offset+100], value)
+
+				reader, err = store.NewRangeReader(ctx, key, offset, -1)
+				require.Nil(t, err)
+				value, err = io.ReadAll(reader)
+				require.Nil(t, err)
+				require.Nil(t, reader.Close())
+				require.Equal(t, content[offset:], value)
+			}
+		}
+	}
+}
+
+func TestEncryptedStorageWithCompression(t *testing.T) {
+	ctx := context.Background()
+	encrypted, _, _ := newTestEncryptedStorage(t, "")
+	store := newCompressedStorage(encrypted, shared.ZstdCompression)
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+
+	require.Nil(t, store.Put(ctx, "key", content))
+
+	value, err := store.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, content, value)
+
+	info, err := store.Stat(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, int64(len(content)), info.Size)
+}
+
+func TestEncryptedStorageUnencrypted(t *testing.T) {
+	ctx := context.Background()
+	store, inner, _ := newTestEncryptedStorage(t, "")
+
+	// Objects written before encryption was enabled are read as is.
+	require.Nil(t, inner.Put(ctx, "legacy", []byte("legacy content")))
+
+	content, err := store.Get(ctx, "legacy")
+	require.Nil(t, err)
+	require.Equal(t, "legacy content", string(content))
+
+	reader, err := store.NewRangeReader(ctx, "legacy", 7, -1)
+	require.Nil(t, err)
+	content, err = io.ReadAll(reader)
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+	require.Equal(t, "content", string(content))
+
+	_, err = store.Get(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+}
+
+func TestEncryptedStorageCorrupted(t *testing.T) {
+	ctx := context.Background()
+	store, inner, _ := newTestEncryptedStorage(t, "")
+	content := bytes.Repeat([]byte("0123456789"), encryptionChunkSize/5)
+
+	require.Nil(t, store.Put(ctx, "key", content))
+	stored, err := inner.Get(ctx, "key")
+	require.Nil(t, err)
+	headerSize := len(stored) - len(content) - 2*encryptionTagSize
+
+	corruptions := map[string][]byte{
+		"chunk":     flipByte(stored, headerSize+10),
+		"nonce":     flipByte(stored, headerSize-1),
+		"truncated": stored[:len(stored)-10],
+		// Dropping the last chunk leaves a chunk that was not sealed as the last one.
+		"dropped": stored[:headerSize+encryptionSealedSize],
+	}
+	for name, corrupted := range corruptions {
+		require.Nil(t, inner.Put(ctx, "key", corrupted))
+
+		_, err := store.Get(ctx, "key")
+		require.True(t, errors.Is(err, ErrObjectCorrupted()), "%s: %v", name, err)
+	}
+}
+
+func TestEncryptedStorageDataKeys(t *testing.T) {
+	ctx := context.Background()
+	workflowID := uuid.NewString()
+	store, inner, config := newTestEncryptedStorage(t, workflowID)
+
+	require.Nil(t, store.Put(ctx, "a", []byte("a")))
+	require.Nil(t, store.Put(ctx, "b", []byte("b")))
+
+	// The data key is created once per scope, and is stored under the scope.
+	keys, err := inner.List(ctx, EncryptionKeysDir)
+	require.Nil(t, err)
+	require.Len(t, keys, 1)
+	require.Contains(t, keys[0].Key, EncryptionKeysDir+workflowID+"/")
+
+	// Other processes reuse the data key of the scope instead of creating their own.
+	writeDataKeys.Delete(store.keyringID + "/" + workflowID)
+	require.Nil(t, store.Put(ctx, "c", []byte("c")))
+	keys, err = inner.List(ctx, EncryptionKeysDir)
+	require.Nil(t, err)
+	require.Len(t, keys, 1)
+
+	// Objects can be read with any scope, since they refer to their data key.
+	otherStore := newEncryptedStorage(inner, store.keyringID, testMasterKey, "")
+	content, err := otherStore.Get(ctx, "a")
+	require.Nil(t, err)
+	require.Equal(t, "a", string(content))
+
+	// Rotating the master key only re-wraps the data keys.
+	stored, err := inner.Get(ctx, "a")
+	require.Nil(t, err)
+	require.Nil(t, RotateMasterKey(ctx, config, testMasterKey, testNewMasterKey))
+
+	restored, err := inner.Get(ctx, "a")
+	require.Nil(t, err)
+	require.Equal(t, stored, restored)
+
+	wrapped, err := inner.Get(ctx, keys[0].Key)
+	require.Nil(t, err)
+	keyID := keys[0].Key[len(EncryptionKeysDir):]
+	_, err = unwrapDataKey(wrapped, keyID, testMasterKey)
+	require.NotNil(t, err)
+	_, err = unwrapDataKey(wrapped, keyID, testNewMasterKey)
+	require.Nil(t, err)
+
+	// A new process only has the new master key, so nothing can be served from the caches.
+	dataKeys.Delete(store.keyringID + "/" + keyID)
+	newStore := newEncryptedStorage(inner, store.keyringID, testNewMasterKey, "")
+	content, err = newStore.Get(ctx, "b")
+	require.Nil(t, err)
+	require.Equal(t, "b", string(content))
+}
+
+func TestEncryptedStorageJobDataKeys(t *testing.T) {
+	ctx := context.Background()
+	workflowID := uuid.NewString()
+	store, inner, _ := newTestEncryptedStorage(t, workflowID)
+	sharedStore := newEncryptedStorage(inner, store.keyringID, testMasterKey, "")
+	otherStore := newEncryptedStorage(inner, store.keyringID, testMasterKey, uuid.NewString())
+
+	require.Nil(t, sharedStore.Put(ctx, "shared", []byte("shared")))
+	require.Nil(t, otherStore.Put(ctx, "other", []byte("other")))
+
+	// A job gets the data keys of its workflow and the shared data keys, but not those of other workflows.
+	keys, writeKeyID, err := store.jobDataKeys(ctx)
+	require.Nil(t, err)
+	require.Len(t, keys, 2)
+	require.Contains(t, writeKeyID, workflowID+"/")
+	require.Contains(t, keys, writeKeyID)
+
+	sharedKeyID, err := sharedStore.writeDataKeyID(ctx)
+	require.Nil(t, err)
+	wrapped, err := inner.Get(ctx, EncryptionKeysDir+sharedKeyID)
+	require.Nil(t, err)
+	sharedKey, err := unwrapDataKey(wrapped, sharedKeyID, testMasterKey)
+	require.Nil(t, err)
+	require.Equal(t, sharedKey, keys[sharedKeyID])
+
+	// The same data key keeps being used for the workflow.
+	_, sameWriteKeyID, err := store.jobDataKeys(ctx)
+	require.Nil(t, err)
+	require.Equal(t, writeKeyID, sameWriteKeyID)
+}
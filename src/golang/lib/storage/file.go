@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 )
@@ -61,6 +65,133 @@ func (f *fileStorage) Exists(ctx context.Context, key string) bool {
 	return !errors.Is(err, os.ErrNotExist)
 }
 
+func (f *fileStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	root := f.fileConfig.Directory
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return []ObjectInfo{}, nil
+	}
+
+	objects := []ObjectInfo{}
+	err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relPath)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (f *fileStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := os.Stat(f.getFullPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectDoesNotExist()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (f *fileStorage) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	return f.NewRangeReader(ctx, key, 0, -1)
+}
+
+func (f *fileStorage) NewRangeReader(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	file, err := os.Open(f.getFullPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectDoesNotExist()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if length < 0 {
+		return file, nil
+	}
+
+	return &readCloser{
+		Reader:  io.LimitReader(file, length),
+		closers: []io.Closer{file},
+	}, nil
+}
+
+func (f *fileStorage) NewWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	filePath := f.getFullPath(key)
+	dir := path.Dir(filePath)
+	if err := os.MkdirAll(dir, dirPermissionCode); err != nil {
+		return nil, err
+	}
+
+	// The content is written to a temporary file, which replaces the object once it is complete,
+	// so that readers never observe a partially written object.
+	tmpFile, err := os.CreateTemp(dir, fmt.Sprintf(".%s-*", path.Base(filePath)))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tmpFile.Chmod(filePermissionCode); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return nil, err
+	}
+
+	return &fileWriter{File: tmpFile, path: filePath}, nil
+}
+
+// fileWriter writes to a temporary file, which is moved to path when it is closed.
+type fileWriter struct {
+	*os.File
+	path string
+}
+
+func (w *fileWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.File.Name())
+		return err
+	}
+
+	return os.Rename(w.File.Name(), w.path)
+}
+
 func (f *fileStorage) getFullPath(key string) string {
 	return fmt.Sprintf("%s/%s", f.fileConfig.Directory, key)
 }
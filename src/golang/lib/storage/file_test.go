@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStorageList(t *testing.T) {
+	ctx := context.Background()
+	store := newFileStorage(&shared.FileConfig{Directory: t.TempDir()})
+
+	for _, key := range []string{"a", "preview/b", "preview/c"} {
+		require.Nil(t, store.Put(ctx, key, []byte(key)))
+	}
+
+	listKeys := func(prefix string) []string {
+		objects, err := store.List(ctx, prefix)
+		require.Nil(t, err)
+
+		keys := make([]string, 0, len(objects))
+		for _, object := range objects {
+			require.Equal(t, int64(len(object.Key)), object.Size)
+			keys = append(keys, object.Key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	require.Equal(t, []string{"a", "preview/b", "preview/c"}, listKeys(""))
+	require.Equal(t, []string{"preview/b", "preview/c"}, listKeys("preview/"))
+	require.Empty(t, listKeys("missing"))
+
+	// Listing a storage directory that was never written to returns no objects.
+	emptyStore := newFileStorage(&shared.FileConfig{Directory: t.TempDir() + "/missing"})
+	objects, err := emptyStore.List(ctx, "")
+	require.Nil(t, err)
+	require.Empty(t, objects)
+}
+
+func TestFileStorageStream(t *testing.T) {
+	ctx := context.Background()
+	store := newFileStorage(&shared.FileConfig{Directory: t.TempDir()})
+
+	writer, err := store.NewWriter(ctx, "dir/key")
+	require.Nil(t, err)
+
+	_, err = io.WriteString(writer, "0123456789")
+	require.Nil(t, err)
+
+	// The object is only visible once the writer is closed.
+	_, err = store.Stat(ctx, "dir/key")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+
+	require.Nil(t, writer.Close())
+
+	info, err := store.Stat(ctx, "dir/key")
+	require.Nil(t, err)
+	require.Equal(t, int64(10), info.Size)
+
+	readAll := func(reader io.ReadCloser, err error) string {
+		require.Nil(t, err)
+		defer reader.Close()
+
+		content, err := io.ReadAll(reader)
+		require.Nil(t, err)
+		return string(content)
+	}
+
+	require.Equal(t, "0123456789", readAll(store.NewReader(ctx, "dir/key")))
+	require.Equal(t, "234", readAll(store.NewRangeReader(ctx, "dir/key", 2, 3)))
+	require.Equal(t, "789", readAll(store.NewRangeReader(ctx, "dir/key", 7, -1)))
+	require.Equal(t, "", readAll(store.NewRangeReader(ctx, "dir/key", 2, 0)))
+
+	_, err = store.NewReader(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+}
+
+func TestReadSeekerServeContent(t *testing.T) {
+	ctx := context.Background()
+	store := newFileStorage(&shared.FileConfig{Directory: t.TempDir()})
+	require.Nil(t, store.Put(ctx, "key", []byte("0123456789")))
+
+	serve := func(rangeHeader string) *httptest.ResponseRecorder {
+		reader := NewReadSeeker(ctx, store, "key", 10)
+		defer reader.Close()
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if rangeHeader != "" {
+			request.Header.Set("Range", rangeHeader)
+		}
+
+		recorder := httptest.NewRecorder()
+		http.ServeContent(recorder, request, "", time.Time{}, reader)
+		return recorder
+	}
+
+	recorder := serve("")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "0123456789", recorder.Body.String())
+
+	recorder = serve("bytes=3-5")
+	require.Equal(t, http.StatusPartialContent, recorder.Code)
+	require.Equal(t, "345", recorder.Body.String())
+
+	recorder = serve("bytes=-2")
+	require.Equal(t, http.StatusPartialContent, recorder.Code)
+	require.Equal(t, "89", recorder.Body.String())
+
+	recorder = serve("bytes=20-")
+	require.Equal(t, http.StatusRequestedRangeNotSatisfiable, recorder.Code)
+}
@@ -9,6 +9,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -120,6 +121,108 @@ func (g *gcsStorage) Exists(ctx context.Context, key string) bool {
 	return err != storage.ErrObjectNotExist
 }
 
+func (g *gcsStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	client, err := g.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	bucket, rootDir := g.parseBucketAndKey("")
+	if rootDir != "" {
+		rootDir += "/"
+	}
+
+	objects := []ObjectInfo{}
+	it := client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: rootDir + prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, ObjectInfo{
+			Key:          strings.TrimPrefix(attrs.Name, rootDir),
+			Size:         attrs.Size,
+			LastModified: attrs.Updated,
+		})
+	}
+
+	return objects, nil
+}
+
+func (g *gcsStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	client, err := g.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	bucket, fullKey := g.parseBucketAndKey(key)
+
+	attrs, err := client.Bucket(bucket).Object(fullKey).Attrs(ctx)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, ErrObjectDoesNotExist()
+		}
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         attrs.Size,
+		LastModified: attrs.Updated,
+	}, nil
+}
+
+func (g *gcsStorage) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	return g.NewRangeReader(ctx, key, 0, -1)
+}
+
+func (g *gcsStorage) NewRangeReader(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	client, err := g.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, key := g.parseBucketAndKey(key)
+
+	reader, err := client.Bucket(bucket).Object(key).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		client.Close()
+		if err == storage.ErrObjectNotExist {
+			return nil, ErrObjectDoesNotExist()
+		}
+		return nil, err
+	}
+
+	// The client must stay open until the reader is closed.
+	return &readCloser{
+		Reader:  reader,
+		closers: []io.Closer{reader, client},
+	}, nil
+}
+
+func (g *gcsStorage) NewWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	client, err := g.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, key := g.parseBucketAndKey(key)
+
+	writer := client.Bucket(bucket).Object(key).NewWriter(ctx)
+
+	// The client must stay open until the writer is closed.
+	return &writeCloser{
+		Writer:  writer,
+		closers: []io.Closer{writer, client},
+	}, nil
+}
+
 // newClient returns a GCS client for this storage object.
 // The caller must call `defer client.Close()` on the returned storage client.
 func (g *gcsStorage) newClient(ctx context.Context) (*storage.Client, error) {
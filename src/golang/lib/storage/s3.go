@@ -3,7 +3,9 @@ package storage
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"io"
+	"net/http"
 	"net/url"
 	"path"
 	"strings"
@@ -14,9 +16,13 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/dropbox/godropbox/errors"
 )
 
+// s3ErrCodeNotFound is the error code of a HeadObject request for a missing object.
+const s3ErrCodeNotFound = "NotFound"
+
 type s3Storage struct {
 	s3Config *shared.S3Config
 }
@@ -158,14 +164,169 @@ func (s *s3Storage) Exists(ctx context.Context, key string) bool {
 	return true
 }
 
+func (s *s3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	sess, err := CreateS3Session(s.s3Config)
+	if err != nil {
+		return nil, err
+	}
+
+	s3Client := s3.New(sess)
+
+	bucket, rootDir, err := s.parseBucketAndKey("")
+	if err != nil {
+		return nil, err
+	}
+
+	if rootDir != "" {
+		rootDir += "/"
+	}
+
+	objects := []ObjectInfo{}
+	err = s3Client.ListObjectsV2PagesWithContext(
+		ctx,
+		&s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(rootDir + prefix),
+		},
+		func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+			for _, object := range page.Contents {
+				objects = append(objects, ObjectInfo{
+					Key:          strings.TrimPrefix(aws.StringValue(object.Key), rootDir),
+					Size:         aws.Int64Value(object.Size),
+					LastModified: aws.TimeValue(object.LastModified),
+				})
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (s *s3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	sess, err := CreateS3Session(s.s3Config)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, fullKey, err := s.parseBucketAndKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s3.New(sess).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fullKey),
+	})
+	if err != nil {
+		// HeadObject responses have no body, so a missing object is reported as `NotFound`.
+		if aerr, ok := err.(awserr.Error); ok && (aerr.Code() == s3ErrCodeNotFound || aerr.Code() == s3.ErrCodeNoSuchKey) {
+			return nil, errors.Wrapf(ErrObjectDoesNotExist(), "Unable to fetch key `%s` from bucket `%s`.", fullKey, bucket)
+		}
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(result.ContentLength),
+		LastModified: aws.TimeValue(result.LastModified),
+	}, nil
+}
+
+func (s *s3Storage) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.NewRangeReader(ctx, key, 0, -1)
+}
+
+func (s *s3Storage) NewRangeReader(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		// An empty range cannot be requested from S3.
+		if _, err := s.Stat(ctx, key); err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	sess, err := CreateS3Session(s.s3Config)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, fullKey, err := s.parseBucketAndKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fullKey),
+	}
+	if offset > 0 || length > 0 {
+		input.Range = aws.String(httpRange(offset, length))
+	}
+
+	result, err := s3.New(sess).GetObjectWithContext(ctx, input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, errors.Wrapf(ErrObjectDoesNotExist(), "Unable to fetch key `%s` from bucket `%s`.", fullKey, bucket)
+		}
+		return nil, err
+	}
+
+	return result.Body, nil
+}
+
+func (s *s3Storage) NewWriter(ctx context.Context, key string) (io.WriteCloser, error) {
+	sess, err := CreateS3Session(s.s3Config)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, fullKey, err := s.parseBucketAndKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// The uploader reads the content from the pipe in parts, so it is never held in memory as a whole.
+	pipeReader, pipeWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := s3manager.NewUploader(sess).UploadWithContext(ctx, &s3manager.UploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(fullKey),
+			Body:   pipeReader,
+		})
+		// Unblock the writer if the upload failed before the whole content was read.
+		pipeReader.CloseWithError(err)
+		done <- err
+	}()
+
+	return &uploadWriter{PipeWriter: pipeWriter, done: done}, nil
+}
+
 func CreateS3Session(s3Config *shared.S3Config) (*session.Session, error) {
-	sess, err := session.NewSession(&aws.Config{
+	awsConfig := &aws.Config{
 		Region: aws.String(s3Config.Region),
 		Credentials: credentials.NewSharedCredentials(
 			s3Config.CredentialsPath,
 			s3Config.CredentialsProfile,
 		),
-	})
+	}
+
+	if s3Config.EndpointURL != "" {
+		awsConfig.Endpoint = aws.String(s3Config.EndpointURL)
+	}
+	awsConfig.S3ForcePathStyle = aws.Bool(s3Config.ForcePathStyle)
+
+	if s3Config.SkipTLSVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		awsConfig.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	sess, err := session.NewSession(awsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -1,12 +1,31 @@
 package storage
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 
+	"github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
+var (
+	runMinIO       = flag.Bool("minio", false, "If this flag is set, the S3 storage tests will be run against a MinIO server.")
+	minIOURL       = flag.String("minioURL", "http://localhost:9000", "The endpoint of the MinIO server.")
+	minIOAccessKey = flag.String("minioAccessKey", "minioadmin", "The access key of the MinIO server.")
+	minIOSecretKey = flag.String("minioSecretKey", "minioadmin", "The secret key of the MinIO server.")
+)
+
 func TestParseBucketAndKey(t *testing.T) {
 	type test struct {
 		inputBucket    string
@@ -43,3 +62,99 @@ func TestParseBucketAndKey(t *testing.T) {
 		require.Equal(t, tc.expectedKey, key)
 	}
 }
+
+func TestCreateS3SessionEndpoint(t *testing.T) {
+	sess, err := CreateS3Session(&shared.S3Config{Region: "us-east-2"})
+	require.Nil(t, err)
+	require.Nil(t, sess.Config.Endpoint)
+	require.False(t, aws.BoolValue(sess.Config.S3ForcePathStyle))
+
+	sess, err = CreateS3Session(&shared.S3Config{
+		Region: "us-east-2",
+		S3EndpointConfig: shared.S3EndpointConfig{
+			EndpointURL:    "https://minio.internal:9000",
+			ForcePathStyle: true,
+			SkipTLSVerify:  true,
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "https://minio.internal:9000", aws.StringValue(sess.Config.Endpoint))
+	require.True(t, aws.BoolValue(sess.Config.S3ForcePathStyle))
+	require.NotNil(t, sess.Config.HTTPClient.Transport)
+}
+
+func TestS3Storage(t *testing.T) {
+	if !*runMinIO {
+		t.Skip("Skipping S3 storage tests, since the -minio flag is not set.")
+	}
+
+	ctx := context.Background()
+
+	credentialsPath := filepath.Join(t.TempDir(), "credentials")
+	require.Nil(t, os.WriteFile(
+		credentialsPath,
+		[]byte(fmt.Sprintf("[default]\naws_access_key_id=%s\naws_secret_access_key=%s\n", *minIOAccessKey, *minIOSecretKey)),
+		0o600,
+	))
+
+	s3Config := &shared.S3Config{
+		Region:             "us-east-1",
+		Bucket:             "s3://aqueduct-test",
+		RootDir:            uuid.NewString() + "/",
+		CredentialsPath:    credentialsPath,
+		CredentialsProfile: "default",
+		S3EndpointConfig: shared.S3EndpointConfig{
+			EndpointURL:    *minIOURL,
+			ForcePathStyle: true,
+		},
+	}
+	store := newS3Storage(s3Config)
+
+	sess, err := CreateS3Session(s3Config)
+	require.Nil(t, err)
+	_, err = s3.New(sess).CreateBucketWithContext(ctx, &s3.CreateBucketInput{Bucket: aws.String("aqueduct-test")})
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != s3.ErrCodeBucketAlreadyOwnedByYou {
+		require.Nil(t, err)
+	}
+
+	require.Nil(t, store.Put(ctx, "a", []byte("0123456789")))
+	require.True(t, store.Exists(ctx, "a"))
+	require.False(t, store.Exists(ctx, "missing"))
+
+	content, err := store.Get(ctx, "a")
+	require.Nil(t, err)
+	require.Equal(t, "0123456789", string(content))
+
+	_, err = store.Get(ctx, "missing")
+	require.True(t, errors.Is(err, ErrObjectDoesNotExist()))
+
+	info, err := store.Stat(ctx, "a")
+	require.Nil(t, err)
+	require.Equal(t, int64(10), info.Size)
+
+	reader, err := store.NewRangeReader(ctx, "a", 2, 3)
+	require.Nil(t, err)
+	content, err = io.ReadAll(reader)
+	require.Nil(t, err)
+	require.Nil(t, reader.Close())
+	require.Equal(t, "234", string(content))
+
+	writer, err := store.NewWriter(ctx, "dir/b")
+	require.Nil(t, err)
+	_, err = io.WriteString(writer, "streamed")
+	require.Nil(t, err)
+	require.Nil(t, writer.Close())
+
+	objects, err := store.List(ctx, "")
+	require.Nil(t, err)
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	sort.Strings(keys)
+	require.Equal(t, []string{"a", "dir/b"}, keys)
+
+	require.Nil(t, store.Delete(ctx, "a"))
+	require.Nil(t, store.Delete(ctx, "dir/b"))
+	require.False(t, store.Exists(ctx, "a"))
+}
@@ -2,8 +2,11 @@ package storage
 
 import (
 	"context"
+	"io"
 	"log"
+	"time"
 
+	aq_config "github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/errors"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 )
@@ -14,12 +17,43 @@ func ErrObjectDoesNotExist() error {
 	return errors.New("Object does not exist in storage.")
 }
 
+// ErrObjectCorrupted is thrown when the content read from storage does not match its checksum.
+// NOTE: Callers need to wrap this error with the path that is being accessed.
+func ErrObjectCorrupted() error {
+	return errors.New("Object in storage is corrupted.")
+}
+
+// ObjectInfo describes an object in storage.
+type ObjectInfo struct {
+	// Key is the key of the object, relative to the root of the storage.
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
 type Storage interface {
 	// Throws `ErrObjectDoesNotExist` if the path does not exist.
 	Get(ctx context.Context, key string) ([]byte, error)
 	Put(ctx context.Context, key string, value []byte) error
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) bool
+	// List returns all objects whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+
+	// The following methods stream the object instead of holding it in memory.
+	// Throws `ErrObjectDoesNotExist` if the path does not exist.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// The caller must close the returned reader.
+	// Throws `ErrObjectDoesNotExist` if the path does not exist.
+	NewReader(ctx context.Context, key string) (io.ReadCloser, error)
+	// NewRangeReader reads length bytes of the object starting at offset.
+	// If length is negative, it reads until the end of the object.
+	// If the storage is compressed, the object is read from the start, so the cost is O(offset).
+	// The caller must close the returned reader.
+	// Throws `ErrObjectDoesNotExist` if the path does not exist.
+	NewRangeReader(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error)
+	// The object is only written once the returned writer is closed without error.
+	NewWriter(ctx context.Context, key string) (io.WriteCloser, error)
 }
 
 func NewStorage(config *shared.StorageConfig) Storage {
@@ -27,6 +61,32 @@ func NewStorage(config *shared.StorageConfig) Storage {
 		log.Fatalf("Nil storage config.")
 	}
 
+	// The cache holds objects as they are stored, so they are never decrypted on disk.
+	store := newCachedStorageIfEnabled(newBaseStorage(config), config)
+	if config.Encryption != nil {
+		store = newEncryptedStorage(store, keyringID(config), aq_config.StorageMasterKey(), config.Encryption.KeyScope)
+	}
+
+	// Objects are compressed before they are encrypted, since encrypted content does not compress.
+	if config.Compression != nil {
+		store = newCompressedStorage(store, config.Compression.Algorithm)
+	}
+
+	return store
+}
+
+// NewUncachedStorage returns the storage of config without the local cache, for objects that other
+// processes overwrite in place, e.g. the secrets of the vault.
+func NewUncachedStorage(config *shared.StorageConfig) Storage {
+	if config == nil {
+		log.Fatalf("Nil storage config.")
+	}
+
+	return newBaseStorage(config)
+}
+
+// newBaseStorage returns the storage of config, without compression or encryption.
+func newBaseStorage(config *shared.StorageConfig) Storage {
 	switch config.Type {
 	case shared.S3StorageType:
 		return newS3Storage(config.S3Config)
@@ -34,6 +94,8 @@ func NewStorage(config *shared.StorageConfig) Storage {
 		return newFileStorage(config.FileConfig)
 	case shared.GCSStorageType:
 		return newGCSStorage(config.GCSConfig)
+	case shared.AzureStorageType:
+		return newAzureStorage(config.AzureConfig)
 	default:
 		log.Fatalf("Unsupported storage type: %s", config.Type)
 		return nil
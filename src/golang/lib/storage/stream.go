@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dropbox/godropbox/errors"
+)
+
+// readCloser reads from Reader and closes all of its closers in order when it is closed.
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (rc *readCloser) Close() error {
+	return closeAll(rc.closers)
+}
+
+// writeCloser writes to Writer and closes all of its closers in order when it is closed.
+type writeCloser struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (wc *writeCloser) Close() error {
+	return closeAll(wc.closers)
+}
+
+// uploadWriter writes to the pipe read by an upload, which sends its result to done.
+// Closing the writer waits for the upload to complete.
+type uploadWriter struct {
+	*io.PipeWriter
+	done chan error
+}
+
+func (w *uploadWriter) Close() error {
+	if err := w.PipeWriter.Close(); err != nil {
+		return err
+	}
+	return <-w.done
+}
+
+func closeAll(closers []io.Closer) error {
+	var firstErr error
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// newRangeReadCloser skips offset bytes of r and limits it to length bytes, unless length is negative.
+func newRangeReadCloser(r io.ReadCloser, offset int64, length int64) (io.ReadCloser, error) {
+	if offset > 0 {
+		if _, err := io.CopyN(io.Discard, r, offset); err != nil && err != io.EOF {
+			r.Close()
+			return nil, err
+		}
+	}
+
+	if length < 0 {
+		return r, nil
+	}
+
+	return &readCloser{
+		Reader:  io.LimitReader(r, length),
+		closers: []io.Closer{r},
+	}, nil
+}
+
+// httpRange returns the value of the HTTP `Range` header that requests length bytes
+// starting at offset. If length is negative, it requests all bytes until the end.
+func httpRange(offset int64, length int64) string {
+	if length < 0 {
+		return fmt.Sprintf("bytes=%d-", offset)
+	}
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+// objectReadSeeker reads an object of a known size from storage. Seeking is supported
+// by opening a new range reader at the requested offset on the next read.
+type objectReadSeeker struct {
+	ctx   context.Context
+	store Storage
+	key   string
+	size  int64
+
+	offset int64
+	reader io.ReadCloser
+}
+
+// NewReadSeeker returns a reader of the object at key of store, which supports seeking.
+// size must be the size of the object. It allows serving objects with `http.ServeContent`,
+// which handles HTTP `Range` requests, without holding the object in memory.
+func NewReadSeeker(ctx context.Context, store Storage, key string, size int64) io.ReadSeekCloser {
+	return &objectReadSeeker{
+		ctx:   ctx,
+		store: store,
+		key:   key,
+		size:  size,
+	}
+}
+
+func (rs *objectReadSeeker) Read(p []byte) (int, error) {
+	if rs.offset >= rs.size {
+		return 0, io.EOF
+	}
+
+	if rs.reader == nil {
+		reader, err := rs.store.NewRangeReader(rs.ctx, rs.key, rs.offset, -1)
+		if err != nil {
+			return 0, err
+		}
+		rs.reader = reader
+	}
+
+	n, err := rs.reader.Read(p)
+	rs.offset += int64(n)
+	return n, err
+}
+
+func (rs *objectReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = rs.offset + offset
+	case io.SeekEnd:
+		newOffset = rs.size + offset
+	default:
+		return 0, errors.Newf("Invalid whence %d.", whence)
+	}
+
+	if newOffset < 0 {
+		return 0, errors.New("Cannot seek to a negative offset.")
+	}
+
+	if newOffset != rs.offset && rs.reader != nil {
+		if err := rs.reader.Close(); err != nil {
+			return 0, err
+		}
+		rs.reader = nil
+	}
+
+	rs.offset = newOffset
+	return newOffset, nil
+}
+
+func (rs *objectReadSeeker) Close() error {
+	if rs.reader == nil {
+		return nil
+	}
+
+	err := rs.reader.Close()
+	rs.reader = nil
+	return err
+}
@@ -0,0 +1,120 @@
+package storage_gc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// operatorFilePrefix is the prefix of the keys of the operator code that is uploaded with a workflow.
+const operatorFilePrefix = "operator-"
+
+// isCollectable returns whether key has the form of a key that Aqueduct writes for the outputs of runs
+// or for operator code. These are bare UUIDs at the root of the storage, e.g. the content and metadata
+// of artifact results and the code of GitHub operators, and "operator-" followed by a UUID for uploaded
+// operator code. Everything else, e.g. the vault, the data keys of encrypted objects, preview outputs,
+// and objects of other applications sharing the bucket, is never collected.
+func isCollectable(key string) bool {
+	key = strings.TrimPrefix(key, operatorFilePrefix)
+	_, err := uuid.Parse(key)
+	// uuid.Parse also accepts the URN and braced forms, which Aqueduct never writes.
+	return err == nil && len(key) == 36
+}
+
+// Report summarizes a garbage collection of the storage.
+type Report struct {
+	DryRun bool
+	// Orphans are the objects that are not referenced by the metadata database.
+	Orphans []storage.ObjectInfo
+	// ReclaimableBytes is the total size of the Orphans.
+	ReclaimableBytes int64
+	// FailedKeys are the keys of the Orphans that could not be deleted.
+	FailedKeys []string
+}
+
+// Collect deletes the objects in store that Aqueduct wrote for runs or operators, see isCollectable, but
+// that are neither the content of an artifact result nor the code of an operator, and that were last
+// modified more than gracePeriod ago. The grace period protects
+// objects of runs that are still in progress, which are not referenced by the metadata database yet.
+// If dryRun is set, the orphaned objects are only reported.
+func Collect(
+	ctx context.Context,
+	store storage.Storage,
+	gracePeriod time.Duration,
+	dryRun bool,
+	artifactResultRepo repos.ArtifactResult,
+	operatorRepo repos.Operator,
+	DB database.Database,
+) (*Report, error) {
+	// The storage must be listed before the references are read, so that an object
+	// that is written and referenced in between is never considered orphaned.
+	cutoff := time.Now().Add(-gracePeriod)
+	objects, err := store.List(ctx, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list storage objects.")
+	}
+
+	contentPaths, err := artifactResultRepo.GetContentPaths(ctx, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read artifact result content paths.")
+	}
+
+	functionPaths, err := operatorRepo.GetFunctionStoragePaths(ctx, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to read operator storage paths.")
+	}
+
+	referenced := make(map[string]bool, len(contentPaths)+len(functionPaths))
+	for _, key := range contentPaths {
+		referenced[key] = true
+	}
+	for _, key := range functionPaths {
+		referenced[key] = true
+	}
+
+	report := &Report{
+		DryRun:  dryRun,
+		Orphans: orphanedObjects(objects, referenced, cutoff),
+	}
+	for _, object := range report.Orphans {
+		report.ReclaimableBytes += object.Size
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	for _, object := range report.Orphans {
+		if err := store.Delete(ctx, object.Key); err != nil {
+			log.Errorf("Unable to delete orphaned storage object %s: %v", object.Key, err)
+			report.FailedKeys = append(report.FailedKeys, object.Key)
+		}
+	}
+
+	return report, nil
+}
+
+// orphanedObjects returns the objects that are not referenced and were last modified before cutoff.
+func orphanedObjects(
+	objects []storage.ObjectInfo,
+	referenced map[string]bool,
+	cutoff time.Time,
+) []storage.ObjectInfo {
+	orphans := make([]storage.ObjectInfo, 0, len(objects))
+	for _, object := range objects {
+		if !isCollectable(object.Key) || referenced[object.Key] || !object.LastModified.Before(cutoff) {
+			continue
+		}
+
+		orphans = append(orphans, object)
+	}
+
+	return orphans
+}
@@ -0,0 +1,41 @@
+package storage_gc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/databricks"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrphanedObjects(t *testing.T) {
+	now := time.Now()
+	cutoff := now.Add(-time.Hour)
+	newObject := func(key string, age time.Duration) storage.ObjectInfo {
+		return storage.ObjectInfo{Key: key, Size: 10, LastModified: now.Add(-age)}
+	}
+
+	orphan := uuid.New().String()
+	content := uuid.New().String()
+	objects := []storage.ObjectInfo{
+		newObject(orphan, 2*time.Hour),
+		newObject(content, 2*time.Hour),
+		newObject(uuid.New().String(), time.Minute),
+		newObject("operator-"+uuid.New().String(), 2*time.Hour),
+		newObject("vault/secret", 2*time.Hour),
+		newObject("preview/"+uuid.New().String(), 2*time.Hour),
+		newObject(databricks.DatabricksFunctionScript, 2*time.Hour),
+		newObject(storage.EncryptionKeysDir+"default/key", 2*time.Hour),
+		newObject("other-app/"+uuid.New().String(), 2*time.Hour),
+		newObject("report.csv", 2*time.Hour),
+		newObject("urn:uuid:"+uuid.New().String(), 2*time.Hour),
+	}
+
+	require.Equal(
+		t,
+		[]storage.ObjectInfo{objects[0], objects[3]},
+		orphanedObjects(objects, map[string]bool{content: true}, cutoff),
+	)
+}
@@ -3,6 +3,8 @@ package storage_migration
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sync/atomic"
 	"time"
 
 	"github.com/aqueducthq/aqueduct/config"
@@ -19,158 +21,412 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	localFilesystemName = "Local Filesystem"
+
+	// The progress of a migration is saved after this many objects are copied, or after this much time.
+	progressSaveObjects  = 100
+	progressSaveInterval = 10 * time.Second
+)
+
+// ErrInvalidStorageMigration is the root of the errors returned when a storage migration cannot be started,
+// resumed or rolled back in the current state. Any other error returned by them is unexpected.
+func ErrInvalidStorageMigration() error {
+	return errors.New("The storage migration is invalid.")
+}
+
+// inProgress is set while a storage migration of this server is running, since the
+// migrations would otherwise overwrite each other's storage config.
+var inProgress atomic.Bool
+
+// Repos contains the repos needed by storage migrations.
+type Repos struct {
+	ArtifactRepo         repos.Artifact
+	ArtifactResultRepo   repos.ArtifactResult
+	DAGRepo              repos.DAG
+	ResourceRepo         repos.Resource
+	OperatorRepo         repos.Operator
+	StorageMigrationRepo repos.StorageMigration
+	WorkflowRepo         repos.Workflow
+}
+
 // Perform starts and manages the storage migration process through its entire lifecycle.
 // The migration logic is performed asynchronously, and it's process is tracked as a new entry in the
-// `storage_migration` table. This method does not block on the migration process.
+// `storage_migration` table, which is returned. This method does not block on the migration process.
 // This method also pauses the server, until the migration completes or errors, wherein the server will restart.
+//
+// If `dryRun` is set, the server is not paused and nothing is copied. The migration only estimates
+// the number of objects and bytes to copy, which are recorded in its progress.
 func Perform(
 	ctx context.Context,
 	orgID string,
 	destResourceObj *models.Resource,
 	newStorageConfig *shared.StorageConfig,
+	dryRun bool,
 	pauseServer func(),
 	restartServer func(),
-	artifactRepo repos.Artifact,
-	artifactResultRepo repos.ArtifactResult,
-	DAGRepo repos.DAG,
-	resourceRepo repos.Resource,
-	operatorRepo repos.Operator,
-	storageMigrationRepo repos.StorageMigration,
+	repos *Repos,
 	DB database.Database,
-) error {
-	destResourceName := "Local Filesystem"
+) (*models.StorageMigration, error) {
 	var destResourceID *uuid.UUID
 	if destResourceObj != nil {
-		destResourceName = destResourceObj.Name
 		destResourceID = &destResourceObj.ID
 	}
 
+	currentStorageConfig := config.Storage()
+	// Keep compressing and encrypting objects in the new storage, since it is not configured by the destination resource.
+	if newStorageConfig.Compression == nil {
+		newStorageConfig.Compression = currentStorageConfig.Compression
+	}
+	if newStorageConfig.Encryption == nil {
+		newStorageConfig.Encryption = currentStorageConfig.Encryption
+	}
+
+	return start(
+		ctx,
+		orgID,
+		destResourceID,
+		&currentStorageConfig,
+		newStorageConfig,
+		dryRun,
+		pauseServer,
+		restartServer,
+		repos,
+		DB,
+	)
+}
+
+// Resume continues the failed storage migration with ID `storageMigrationID` asynchronously.
+// The objects that were copied and verified before the migration failed are not copied again.
+// A migration that was interrupted by a restart of the server can be resumed as well.
+func Resume(
+	ctx context.Context,
+	orgID string,
+	storageMigrationID uuid.UUID,
+	pauseServer func(),
+	restartServer func(),
+	repos *Repos,
+	DB database.Database,
+) (*models.StorageMigration, error) {
+	if !inProgress.CompareAndSwap(false, true) {
+		return nil, errors.Wrap(ErrInvalidStorageMigration(), "Another storage migration is in progress.")
+	}
+
+	storageMigrationObj, err := repos.StorageMigrationRepo.Get(ctx, storageMigrationID, DB)
+	if err != nil {
+		inProgress.Store(false)
+		if aq_errors.Is(err, database.ErrNoRows()) {
+			return nil, errors.Wrap(ErrInvalidStorageMigration(), "The storage migration does not exist.")
+		}
+		return nil, errors.Wrap(err, "Unable to retrieve storage migration.")
+	}
+
+	if err := validateResume(storageMigrationObj); err != nil {
+		inProgress.Store(false)
+		return nil, err
+	}
+
+	go run(storageMigrationObj, orgID, pauseServer, restartServer, repos, DB)
+	return storageMigrationObj, nil
+}
+
+func validateResume(storageMigrationObj *models.StorageMigration) error {
+	if storageMigrationObj.DryRun {
+		return errors.Wrap(ErrInvalidStorageMigration(), "A dry run cannot be resumed.")
+	}
+
+	if storageMigrationObj.ExecState.Status == shared.SucceededExecutionStatus {
+		return errors.Wrap(ErrInvalidStorageMigration(), "The storage migration has already succeeded.")
+	}
+
+	if storageMigrationObj.PrevStorageConfig == nil || storageMigrationObj.DestStorageConfig == nil {
+		return errors.Wrap(ErrInvalidStorageMigration(), "The storage migration was started by an older version of Aqueduct and cannot be resumed.")
+	}
+
+	if currentStorageConfig := config.Storage(); !reflect.DeepEqual(&currentStorageConfig, storageMigrationObj.PrevStorageConfig) {
+		return errors.Wrap(ErrInvalidStorageMigration(), "The storage layer has changed since the storage migration started, so it cannot be resumed.")
+	}
+
+	return nil
+}
+
+// Rollback migrates the storage layer back to the storage config before the current storage migration.
+// The migration is performed asynchronously, like `Perform`, and its entry is returned.
+func Rollback(
+	ctx context.Context,
+	orgID string,
+	pauseServer func(),
+	restartServer func(),
+	repos *Repos,
+	DB database.Database,
+) (*models.StorageMigration, error) {
+	currentObj, err := repos.StorageMigrationRepo.Current(ctx, DB)
+	if err != nil {
+		if aq_errors.Is(err, database.ErrNoRows()) {
+			return nil, errors.Wrap(ErrInvalidStorageMigration(), "There is no storage migration to roll back.")
+		}
+		return nil, errors.Wrap(err, "Unable to retrieve the current storage migration.")
+	}
+
+	if currentObj.PrevStorageConfig == nil {
+		return nil, errors.Wrap(ErrInvalidStorageMigration(), "The current storage migration was performed by an older version of Aqueduct and cannot be rolled back.")
+	}
+
+	migrations, err := repos.StorageMigrationRepo.List(ctx, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to list storage migrations.")
+	}
+
+	// The previous storage layer is the destination of the last successful migration before the current one.
+	// If there is none, it is the local filesystem.
+	var destResourceID *uuid.UUID
+	foundCurrent := false
+	for _, migration := range migrations {
+		if migration.ID == currentObj.ID {
+			foundCurrent = true
+			continue
+		}
+
+		if foundCurrent && !migration.DryRun && migration.ExecState.Status == shared.SucceededExecutionStatus {
+			if migration.DestResourceID != uuid.Nil {
+				id := migration.DestResourceID
+				destResourceID = &id
+			}
+			break
+		}
+	}
+
+	currentStorageConfig := config.Storage()
+	return start(
+		ctx,
+		orgID,
+		destResourceID,
+		&currentStorageConfig,
+		currentObj.PrevStorageConfig,
+		false, /* dryRun */
+		pauseServer,
+		restartServer,
+		repos,
+		DB,
+	)
+}
+
+// start records a new storage migration from `prevStorageConfig` to `newStorageConfig`, and runs it asynchronously.
+func start(
+	ctx context.Context,
+	orgID string,
+	destResourceID *uuid.UUID,
+	prevStorageConfig *shared.StorageConfig,
+	newStorageConfig *shared.StorageConfig,
+	dryRun bool,
+	pauseServer func(),
+	restartServer func(),
+	repos *Repos,
+	DB database.Database,
+) (*models.StorageMigration, error) {
+	if !inProgress.CompareAndSwap(false, true) {
+		return nil, errors.Wrap(ErrInvalidStorageMigration(), "Another storage migration is in progress.")
+	}
+
 	// Begin recording the storage migration lifecycle.
-	storageMigrationObj, err := storageMigrationRepo.Create(
+	storageMigrationObj, err := repos.StorageMigrationRepo.Create(
 		ctx,
 		destResourceID,
+		prevStorageConfig,
+		newStorageConfig,
+		dryRun,
 		DB,
 	)
 	if err != nil {
-		return errors.Wrap(err, "Unable to migrate storage.")
+		inProgress.Store(false)
+		return nil, errors.Wrap(err, "Unable to migrate storage.")
 	}
 
-	// If the migration is successful, the new entry is given a success execution status, along with `current=True`.
-	// If the migration is unsuccessful, the error is recorded on the new entry in `storage_migration`.
-	go func() {
-		// Shadows the context in the outer scope on purpose.
-		ctx := context.Background()
+	go run(storageMigrationObj, orgID, pauseServer, restartServer, repos, DB)
+	return storageMigrationObj, nil
+}
 
+// run performs the storage migration `storageMigrationObj`.
+// If the migration is successful, the entry is given a success execution status, along with `current=True`.
+// If the migration is unsuccessful, the error is recorded on the entry, along with the progress so far.
+func run(
+	storageMigrationObj *models.StorageMigration,
+	orgID string,
+	pauseServer func(),
+	restartServer func(),
+	repos *Repos,
+	DB database.Database,
+) {
+	defer inProgress.Store(false)
+
+	ctx := context.Background()
+	destResourceName := getDestResourceName(ctx, storageMigrationObj, repos.ResourceRepo, DB)
+
+	if storageMigrationObj.Progress == nil {
+		storageMigrationObj.Progress = &shared.StorageMigrationProgress{}
+	}
+
+	if storageMigrationObj.DryRun {
+		log.Info("Starting storage migration dry run...")
+	} else {
 		log.Info("Starting storage migration process...")
 		// Wait until the server is paused
 		pauseServer()
 		// Makes sure that the server is restarted
 		defer restartServer()
+	}
 
-		execState := storageMigrationObj.ExecState
-
-		var err error
-		defer func() {
-			if err != nil {
-				execState.UpdateWithFailure(
-					// This can be a system error too. But no one cares right now.
-					shared.UserFatalFailure,
-					&shared.Error{
-						Tip:     fmt.Sprintf("Failure occurred when migrating to the new storage resource `%s`.", destResourceName),
-						Context: err.Error(),
-					},
-				)
-				err = updateStorageMigrationExecState(ctx, storageMigrationObj.ID, &execState, storageMigrationRepo, DB)
-				if err != nil {
-					log.Errorf("Unexpected error when updating the storage migration entry to FAILED: %v", err)
-					return
-				}
+	execState := storageMigrationObj.ExecState
 
+	var err error
+	defer func() {
+		if err != nil {
+			execState.UpdateWithFailure(
+				// This can be a system error too. But no one cares right now.
+				shared.UserFatalFailure,
+				&shared.Error{
+					Tip:     fmt.Sprintf("Failure occurred when migrating to the new storage resource `%s`.", destResourceName),
+					Context: err.Error(),
+				},
+			)
+			// Record the progress, so that the migration can be resumed where it failed.
+			if err := saveProgress(ctx, storageMigrationObj, nil /* checksums */, repos.StorageMigrationRepo, DB); err != nil {
+				log.Errorf("Unexpected error when updating the storage migration progress: %v", err)
 			}
-		}()
 
-		// Mark the migration explicitly as RUNNING.
-		runningAt := time.Now()
-		execState.Timestamps.RunningAt = &runningAt
-		err = updateStorageMigrationExecState(ctx, storageMigrationObj.ID, &execState, storageMigrationRepo, DB)
-		if err != nil {
-			log.Errorf("Unexpected error when updating the storage migration entry to RUNNING: %v", err)
-			return
+			err = updateStorageMigrationExecState(ctx, storageMigrationObj, &execState, repos.StorageMigrationRepo, DB)
+			if err != nil {
+				log.Errorf("Unexpected error when updating the storage migration entry to FAILED: %v", err)
+				return
+			}
 		}
+	}()
 
-		// Actually perform the storage migration.
-		// Wait until there are no more workflow runs in progress
-		lock := utils.NewExecutionLock()
-		if err = lock.Lock(); err != nil {
-			err = errors.Wrap(err, "Unexpected error when acquiring workflow execution lock.")
-			return
-		}
-		defer func() {
-			if lockErr := lock.Unlock(); lockErr != nil {
-				log.Errorf("Unexpected error when unlocking workflow execution lock: %v", lockErr)
-			}
-		}()
-
-		// Migrate all storage content to the new storage config
-		currentStorageConfig := config.Storage()
-		storageCleanupConfig, err := MigrateStorageAndVault(
-			context.Background(),
-			&currentStorageConfig,
-			newStorageConfig,
-			orgID,
-			DAGRepo,
-			artifactRepo,
-			artifactResultRepo,
-			operatorRepo,
-			resourceRepo,
-			DB,
-		)
-		// We let the defer() handle the failure case appropriately.
+	// Mark the migration explicitly as RUNNING. A resumed migration was marked as FAILED before.
+	runningAt := time.Now()
+	execState.Status = shared.RunningExecutionStatus
+	execState.FailureType = nil
+	execState.Error = nil
+	execState.Timestamps.RunningAt = &runningAt
+	execState.Timestamps.FinishedAt = nil
+	err = updateStorageMigrationExecState(ctx, storageMigrationObj, &execState, repos.StorageMigrationRepo, DB)
+	if err != nil {
+		log.Errorf("Unexpected error when updating the storage migration entry to RUNNING: %v", err)
+		return
+	}
+
+	if storageMigrationObj.DryRun {
+		err = estimateStorage(ctx, storageMigrationObj, repos, DB)
 		if err != nil {
 			return
 		}
 
-		log.Info("Successfully migrated the storage layer!")
+		log.Infof("Estimated the storage migration: %+v", *storageMigrationObj.Progress)
 		finishedAt := time.Now()
 		execState.Timestamps.FinishedAt = &finishedAt
 		execState.Status = shared.SucceededExecutionStatus
 
-		// The update of the storage config and storage migration entry should happen together.
-		// While we don't enforce this atomically, we can make the two update together to minimize the risk.
-		err = updateStorageMigrationExecState(ctx, storageMigrationObj.ID, &execState, storageMigrationRepo, DB)
+		err = saveProgress(ctx, storageMigrationObj, nil /* checksums */, repos.StorageMigrationRepo, DB)
 		if err != nil {
-			log.Errorf("Unexpected error when updating the storage migration entry to SUCCESS: %v", err)
 			return
 		}
 
-		err = config.UpdateStorage(newStorageConfig)
+		err = updateStorageMigrationExecState(ctx, storageMigrationObj, &execState, repos.StorageMigrationRepo, DB)
 		if err != nil {
-			log.Errorf("Unexpected error when updating the global storage layer config: %v", err)
-			return
-		} else {
-			log.Info("Successfully updated the global storage layer config!")
+			log.Errorf("Unexpected error when updating the storage migration entry to SUCCESS: %v", err)
 		}
+		return
+	}
 
-		// We only perform best-effort deletion the old storage layer files here, after everything else has succeede.
-		for _, key := range storageCleanupConfig.StoreKeys {
-			if err := storageCleanupConfig.Store.Delete(ctx, key); err != nil {
-				log.Errorf("Unexpected error when deleting the old storage file %s: %v", key, err)
-			}
+	// Actually perform the storage migration.
+	// Wait until there are no more workflow runs in progress
+	lock := utils.NewExecutionLock()
+	if err = lock.Lock(); err != nil {
+		err = errors.Wrap(err, "Unexpected error when acquiring workflow execution lock.")
+		return
+	}
+	defer func() {
+		if lockErr := lock.Unlock(); lockErr != nil {
+			log.Errorf("Unexpected error when unlocking workflow execution lock: %v", lockErr)
 		}
+	}()
 
-		for _, key := range storageCleanupConfig.VaultKeys {
-			if err := storageCleanupConfig.Vault.Delete(ctx, key); err != nil {
-				log.Errorf("Unexpected error when deleting the old vault file %s: %v", key, err)
-			}
+	// Migrate all storage content to the new storage config
+	storageCleanupConfig, err := MigrateStorageAndVault(
+		ctx,
+		storageMigrationObj,
+		orgID,
+		repos,
+		DB,
+	)
+	// We let the defer() handle the failure case appropriately.
+	if err != nil {
+		return
+	}
+
+	log.Info("Successfully migrated the storage layer!")
+	finishedAt := time.Now()
+	execState.Timestamps.FinishedAt = &finishedAt
+	execState.Status = shared.SucceededExecutionStatus
+
+	// The update of the storage config and storage migration entry should happen together.
+	// While we don't enforce this atomically, we can make the two update together to minimize the risk.
+	err = saveProgress(ctx, storageMigrationObj, nil /* checksums */, repos.StorageMigrationRepo, DB)
+	if err != nil {
+		return
+	}
+
+	err = updateStorageMigrationExecState(ctx, storageMigrationObj, &execState, repos.StorageMigrationRepo, DB)
+	if err != nil {
+		log.Errorf("Unexpected error when updating the storage migration entry to SUCCESS: %v", err)
+		return
+	}
+
+	err = config.UpdateStorage(storageMigrationObj.DestStorageConfig)
+	if err != nil {
+		log.Errorf("Unexpected error when updating the global storage layer config: %v", err)
+		return
+	} else {
+		log.Info("Successfully updated the global storage layer config!")
+	}
+
+	// We only perform best-effort deletion the old storage layer files here, after everything else has succeede.
+	for _, key := range storageCleanupConfig.StoreKeys {
+		if err := storageCleanupConfig.Store.Delete(ctx, key); err != nil {
+			log.Errorf("Unexpected error when deleting the old storage file %s: %v", key, err)
 		}
-	}()
-	return nil
+	}
+
+	for _, key := range storageCleanupConfig.VaultKeys {
+		if err := storageCleanupConfig.Vault.Delete(ctx, key); err != nil {
+			log.Errorf("Unexpected error when deleting the old vault file %s: %v", key, err)
+		}
+	}
+}
+
+// getDestResourceName returns the name of the destination resource of `storageMigrationObj`, for error messages.
+func getDestResourceName(
+	ctx context.Context,
+	storageMigrationObj *models.StorageMigration,
+	resourceRepo repos.Resource,
+	DB database.Database,
+) string {
+	if storageMigrationObj.DestResourceID == uuid.Nil {
+		return localFilesystemName
+	}
+
+	resourceObj, err := resourceRepo.Get(ctx, storageMigrationObj.DestResourceID, DB)
+	if err != nil {
+		log.Errorf("Unable to retrieve the destination resource of the storage migration: %v", err)
+		return storageMigrationObj.DestResourceID.String()
+	}
+	return resourceObj.Name
 }
 
-// Also updates `current=True` if the execution state is marked as SUCCESS!
+// Also updates `current=True` if the execution state is marked as SUCCESS, unless the migration is a dry run!
 func updateStorageMigrationExecState(
 	ctx context.Context,
-	storageMigrationID uuid.UUID,
+	storageMigrationObj *models.StorageMigration,
 	execState *shared.ExecutionState,
 	storageMigrationRepo repos.StorageMigration,
 	db database.Database,
@@ -189,7 +445,7 @@ func updateStorageMigrationExecState(
 	}
 
 	// This is updated to a transaction if we also need to mark an old entry as current=False.
-	if execState.Status == shared.SucceededExecutionStatus {
+	if execState.Status == shared.SucceededExecutionStatus && !storageMigrationObj.DryRun {
 		updates[models.StorageMigrationCurrent] = true
 
 		// If there was a previous storage migration, update that entry to be `current=False`.
@@ -216,7 +472,7 @@ func updateStorageMigrationExecState(
 	// Perform the actual intended execution state update.
 	_, err = storageMigrationRepo.Update(
 		ctx,
-		storageMigrationID,
+		storageMigrationObj.ID,
 		updates,
 		txn,
 	)
@@ -227,6 +483,40 @@ func updateStorageMigrationExecState(
 	return txn.Commit(ctx)
 }
 
+// saveProgress records the progress of `storageMigrationObj`, along with the objects in `checksums`
+// that were copied and verified since the progress was last saved.
+func saveProgress(
+	ctx context.Context,
+	storageMigrationObj *models.StorageMigration,
+	checksums map[string]string,
+	storageMigrationRepo repos.StorageMigration,
+	DB database.Database,
+) error {
+	txn, err := DB.BeginTx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Unable to start transaction for updating the storage migration progress.")
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	if err := storageMigrationRepo.CreateObjects(ctx, storageMigrationObj.ID, checksums, txn); err != nil {
+		return errors.Wrap(err, "Unable to record the migrated objects.")
+	}
+
+	_, err = storageMigrationRepo.Update(
+		ctx,
+		storageMigrationObj.ID,
+		map[string]interface{}{
+			models.StorageMigrationProgress: storageMigrationObj.Progress,
+		},
+		txn,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to update the storage migration progress.")
+	}
+
+	return txn.Commit(ctx)
+}
+
 // StorageCleanupConfig contains the fields necessary to cleanup the old storage layer.
 // Callers of `MigrateStorageAndVault` can use this config struct to perform best-effort cleanup
 // after the migration completes.
@@ -238,154 +528,122 @@ type StorageCleanupConfig struct {
 	Vault vault.Vault
 }
 
-// MigrateStorageAndVault copies all storage (and vault) content from `oldConf` to `newConf`.
-// This includes:
+// MigrateStorageAndVault copies all storage (and vault) content of the storage migration `storageMigrationObj`
+// from its previous storage config to its destination storage config. This includes:
 //   - artifact result content
 //   - operator (function, check) code
-//   - vault content (resource credentials)
+//   - vault content (resource credentials and webhook secrets), unless the vault is external
+//
+// Every object is verified against the checksum of the old object once it is copied, and recorded as
+// an object of `storageMigrationObj`. Objects that were already migrated by a previous attempt are skipped.
 //
 // The keys to all the contents that were copied are also returned, so that the caller can perform best-effort
 // cleanup the old storage layer.
 func MigrateStorageAndVault(
 	ctx context.Context,
-	oldConf *shared.StorageConfig,
-	newConf *shared.StorageConfig,
+	storageMigrationObj *models.StorageMigration,
 	orgID string,
-	dagRepo repos.DAG,
-	artifactRepo repos.Artifact,
-	artifactResultRepo repos.ArtifactResult,
-	operatorRepo repos.Operator,
-	resourceRepo repos.Resource,
+	repos *Repos,
 	DB database.Database,
 ) (*StorageCleanupConfig, error) {
+	oldConf := storageMigrationObj.PrevStorageConfig
+	newConf := storageMigrationObj.DestStorageConfig
 	log.Infof("Migrating from %v to %v", *oldConf, *newConf)
 
 	oldStore := storage.NewStorage(oldConf)
-	newStore := storage.NewStorage(newConf)
 
-	oldVault, err := vault.NewVault(oldConf, config.EncryptionKey())
+	dagsObjects, err := listObjects(ctx, repos, DB)
 	if err != nil {
 		return nil, err
 	}
 
-	newVault, err := vault.NewVault(newConf, config.EncryptionKey())
+	migratedObjects, err := repos.StorageMigrationRepo.GetObjects(ctx, storageMigrationObj.ID, DB)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Unable to retrieve the migrated objects.")
 	}
 
-	txn, err := DB.BeginTx(ctx)
-	if err != nil {
-		return nil, err
+	migrated := make(map[string]bool, len(migratedObjects))
+	for _, object := range migratedObjects {
+		migrated[object.Key] = true
 	}
-	defer database.TxnRollbackIgnoreErr(ctx, txn)
 
-	dags, err := dagRepo.List(ctx, txn)
-	if err != nil {
-		return nil, err
+	progress := storageMigrationObj.Progress
+	progress.TotalObjects = 0
+	progress.SkippedObjects = 0
+	for _, dagObjects := range dagsObjects {
+		progress.TotalObjects += len(dagObjects.objects)
 	}
 
 	toDelete := []string{}
+	// unsaved holds the checksums of the objects that were copied since the progress was last saved.
+	unsaved := map[string]string{}
+	lastSaved := time.Now()
 
-	log.Infof("There are %v DAGs to migrate", len(dags))
-
-	for _, dag := range dags {
+	for _, dagObjects := range dagsObjects {
+		dag := dagObjects.dag
 		log.Infof("Starting migration for DAG %v", dag.ID)
 
-		if dag.EngineConfig.Type == shared.AirflowEngineType {
-			// We cannot migrate content for Airflow workflows
-			log.Info("This DAG's engine is Airflow, so its migration will be skipped.")
-			continue
-		}
+		// The data keys of the new storage may be scoped per workflow.
+		dagStorageConfig := newConf.ForWorkflow(dag.WorkflowID)
+		newStore := storage.NewStorage(&dagStorageConfig)
 
-		// Migrate all of the artifact result content for this DAG
-		artifacts, err := artifactRepo.GetByDAG(ctx, dag.ID, txn)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Infof("There are %v artifacts to migrate for DAG %v", len(artifacts), dag.ID)
+		for _, object := range dagObjects.objects {
+			toDelete = append(toDelete, object.key)
 
-		for _, artifact := range artifacts {
-			log.Infof("Starting migration for artifact %v of DAG %v", artifact.ID, dag.ID)
+			if migrated[object.key] {
+				// The object was copied before the migration was resumed.
+				continue
+			}
 
-			artifactResults, err := artifactResultRepo.GetByArtifact(ctx, artifact.ID, txn)
+			checksum, size, err := copyObject(ctx, oldStore, newStore, object.key)
 			if err != nil {
+				if !object.required && aq_errors.Is(err, storage.ErrObjectDoesNotExist()) {
+					progress.SkippedObjects++
+					continue
+				}
+
+				log.Errorf("Unable to migrate %s: %v", object.description, err)
+				// Record the objects that were copied so far, so that a resumed migration does not copy them again.
+				if saveErr := saveProgress(ctx, storageMigrationObj, unsaved, repos.StorageMigrationRepo, DB); saveErr != nil {
+					log.Errorf("Unexpected error when updating the storage migration progress: %v", saveErr)
+				}
 				return nil, err
 			}
 
-			log.Infof("There are %v artifact results to migrate for artifact %v", len(artifactResults), artifact.ID)
-
-			// For each artifact result, move the content from `oldStore` to `newStore`
-			for _, artifactResult := range artifactResults {
-				log.Infof("Starting migration for artifact result %v of artifact %v", artifactResult.ID, artifact.ID)
+			migrated[object.key] = true
+			unsaved[object.key] = checksum
+			progress.CopiedObjects++
+			progress.CopiedBytes += size
 
-				val, err := oldStore.Get(ctx, artifactResult.ContentPath)
-				if err != nil &&
-					!artifactResult.ExecState.IsNull &&
-					artifactResult.ExecState.Status == shared.SucceededExecutionStatus {
-					// Return an error because the artifact result is successful,
-					// but not found in current storage.
-					log.Errorf("Unable to get artifact result %v from old store: %v", artifactResult.ID, err)
+			if len(unsaved) >= progressSaveObjects || time.Since(lastSaved) >= progressSaveInterval {
+				if err := saveProgress(ctx, storageMigrationObj, unsaved, repos.StorageMigrationRepo, DB); err != nil {
 					return nil, err
 				}
-
-				if err == nil {
-					// Only try to migrate artifact result if there was no issue reading
-					// it from the `oldStore`
-					if err := newStore.Put(ctx, artifactResult.ContentPath, val); err != nil {
-						log.Errorf("Unable to write artifact result %v to new store: %v", artifactResult.ID, err)
-						return nil, err
-					}
-				}
-
-				toDelete = append(toDelete, artifactResult.ContentPath)
+				unsaved = map[string]string{}
+				lastSaved = time.Now()
 			}
 		}
+	}
 
-		// Migrate all operator code for this DAG
-		operators, err := operatorRepo.GetByDAG(ctx, dag.ID, txn)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Infof("There are %v operators to migrate for DAG %v", len(operators), dag.ID)
-
-		for _, operator := range operators {
-			log.Infof("Starting migration for operator %v of DAG %v", operator.ID, dag.ID)
-
-			var operatorCodePath string
-			switch {
-			case operator.Spec.IsFunction():
-				operatorCodePath = operator.Spec.Function().StoragePath
-			case operator.Spec.IsCheck():
-				operatorCodePath = operator.Spec.Check().Function.StoragePath
-			case operator.Spec.IsMetric():
-				operatorCodePath = operator.Spec.Metric().Function.StoragePath
-			default:
-				// There is no operator code to migrate for this operator
-				continue
-			}
-
-			val, err := oldStore.Get(ctx, operatorCodePath)
-			if err != nil {
-				log.Errorf("Unable to get operator code %v from old store: %v", operator.ID, err)
-				return nil, err
-			}
-
-			if err := newStore.Put(ctx, operatorCodePath, val); err != nil {
-				log.Errorf("Unable to write operator code %v to new store: %v", operator.ID, err)
-				return nil, err
-			}
+	if err := saveProgress(ctx, storageMigrationObj, unsaved, repos.StorageMigrationRepo, DB); err != nil {
+		return nil, err
+	}
 
-			toDelete = append(toDelete, operatorCodePath)
-		}
+	txn, err := DB.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
 
-		// Update the storage config for the DAG
-		if _, err := dagRepo.Update(
+	// Update the storage config of the DAGs, once all of their content is in the new storage.
+	for _, dagObjects := range dagsObjects {
+		dag := dagObjects.dag
+		dagStorageConfig := newConf.ForWorkflow(dag.WorkflowID)
+		if _, err := repos.DAGRepo.Update(
 			ctx,
 			dag.ID,
 			map[string]interface{}{
-				models.DagStorageConfig: newConf,
+				models.DagStorageConfig: &dagStorageConfig,
 			},
 			txn,
 		); err != nil {
@@ -393,27 +651,82 @@ func MigrateStorageAndVault(
 		}
 	}
 
-	// Migrate the vault portion of storage
-	toDeleteFromVault, err := utils.MigrateVault(
-		ctx,
-		oldVault,
-		newVault,
-		orgID,
-		resourceRepo,
-		txn,
-	)
-	if err != nil {
-		return nil, err
+	cleanupConfig := &StorageCleanupConfig{
+		StoreKeys: toDelete,
+		Store:     oldStore,
+	}
+
+	// Migrate the vault portion of storage, unless the secrets are stored outside of the storage layer.
+	if !vault.IsExternal() {
+		oldVault, err := vault.NewVault(oldConf, config.EncryptionKey())
+		if err != nil {
+			return nil, err
+		}
+
+		newVault, err := vault.NewVault(newConf, config.EncryptionKey())
+		if err != nil {
+			return nil, err
+		}
+
+		toDeleteFromVault, err := utils.MigrateVault(
+			ctx,
+			oldVault,
+			newVault,
+			orgID,
+			repos.ResourceRepo,
+			repos.WorkflowRepo,
+			txn,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		cleanupConfig.VaultKeys = toDeleteFromVault
+		cleanupConfig.Vault = oldVault
 	}
 
 	if err := txn.Commit(ctx); err != nil {
 		return nil, err
 	}
 
-	return &StorageCleanupConfig{
-		StoreKeys: toDelete,
-		VaultKeys: toDeleteFromVault,
-		Store:     oldStore,
-		Vault:     oldVault,
-	}, nil
+	return cleanupConfig, nil
+}
+
+// estimateStorage records the number and size of the objects that the storage migration
+// `storageMigrationObj` would copy in its progress, without copying them.
+func estimateStorage(
+	ctx context.Context,
+	storageMigrationObj *models.StorageMigration,
+	repos *Repos,
+	DB database.Database,
+) error {
+	oldStore := storage.NewStorage(storageMigrationObj.PrevStorageConfig)
+
+	dagsObjects, err := listObjects(ctx, repos, DB)
+	if err != nil {
+		return err
+	}
+
+	progress := &shared.StorageMigrationProgress{}
+	for _, dagObjects := range dagsObjects {
+		for _, object := range dagObjects.objects {
+			info, err := oldStore.Stat(ctx, object.key)
+			if err != nil {
+				if !object.required && aq_errors.Is(err, storage.ErrObjectDoesNotExist()) {
+					progress.SkippedObjects++
+					continue
+				}
+
+				// The migration would fail on this object as well.
+				log.Errorf("Unable to stat %s: %v", object.description, err)
+				return err
+			}
+
+			progress.TotalObjects++
+			progress.TotalBytes += info.Size
+		}
+	}
+
+	storageMigrationObj.Progress = progress
+	return nil
 }
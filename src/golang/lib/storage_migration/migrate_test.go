@@ -0,0 +1,270 @@
+package storage_migration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/cmd/migrator/migrator"
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrgID = "aqueduct"
+
+type testEnv struct {
+	ctx       context.Context
+	DB        database.Database
+	repos     *Repos
+	oldConfig *shared.StorageConfig
+	newConfig *shared.StorageConfig
+	oldStore  storage.Storage
+	newStore  storage.Storage
+}
+
+// newTestEnv initializes the server config with a file storage, and a SQLite database with the current schema.
+func newTestEnv(t *testing.T) *testEnv {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "server.yml")
+	oldConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: filepath.Join(dir, "old")},
+	}
+	newConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: filepath.Join(dir, "new")},
+	}
+
+	// Some migrations load the server config themselves, so the config is initialized after them.
+	DB, err := database.NewSqliteInMemoryDatabase(&database.SqliteConfig{})
+	require.Nil(t, err)
+	t.Cleanup(DB.Close)
+	require.Nil(t, migrator.GoTo(context.Background(), models.CurrentSchemaVersion, DB))
+
+	serverConfig := fmt.Sprintf(
+		"aqPath: %s\nencryptionKey: %s\nstorageConfig:\n  type: file\n  fileConfig:\n    directory: %s\n",
+		dir,
+		"00000000000000000000000000000000",
+		oldConfig.FileConfig.Directory,
+	)
+	require.Nil(t, os.WriteFile(configPath, []byte(serverConfig), 0o664))
+	require.Nil(t, config.Init(configPath))
+
+	// The execution lock of storage migrations is a file in the working directory.
+	cwd, err := os.Getwd()
+	require.Nil(t, err)
+	require.Nil(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(cwd) })
+
+	return &testEnv{
+		ctx: context.Background(),
+		DB:  DB,
+		repos: &Repos{
+			ArtifactRepo:         sql.NewArtifactRepo(),
+			ArtifactResultRepo:   sql.NewArtifactResultRepo(),
+			DAGRepo:              sql.NewDAGRepo(),
+			ResourceRepo:         sql.NewResourceRepo(),
+			OperatorRepo:         sql.NewOperatorRepo(),
+			StorageMigrationRepo: sql.NewStorageMigrationRepo(),
+			WorkflowRepo:         sql.NewWorklowRepo(),
+		},
+		oldConfig: oldConfig,
+		newConfig: newConfig,
+		oldStore:  storage.NewStorage(oldConfig),
+		newStore:  storage.NewStorage(newConfig),
+	}
+}
+
+// seedArtifactResults creates a DAG in the old storage with an artifact result for every status in
+// `statuses`, and returns their content paths. The content of succeeded results is written to the old storage.
+func (e *testEnv) seedArtifactResults(t *testing.T, statuses ...shared.ExecutionStatus) []string {
+	user, err := sql.NewUserRepo().Create(e.ctx, testOrgID, uuid.NewString(), e.DB)
+	require.Nil(t, err)
+
+	workflow, err := e.repos.WorkflowRepo.Create(
+		e.ctx,
+		user.ID,
+		"workflow",
+		"",
+		&shared.Schedule{Trigger: shared.ManualUpdateTrigger},
+		&shared.RetentionPolicy{},
+		&shared.NotificationSettings{},
+		0, /* maxParallelOperators */
+		e.DB,
+	)
+	require.Nil(t, err)
+
+	dag, err := e.repos.DAGRepo.Create(
+		e.ctx,
+		workflow.ID,
+		e.oldConfig,
+		&shared.EngineConfig{Type: shared.AqueductEngineType, AqueductConfig: &shared.AqueductConfig{}},
+		e.DB,
+	)
+	require.Nil(t, err)
+
+	artifact, err := e.repos.ArtifactRepo.Create(e.ctx, "artifact", "", shared.StringArtifact, true, e.DB)
+	require.Nil(t, err)
+
+	_, err = sql.NewDAGEdgeRepo().Create(e.ctx, dag.ID, shared.ArtifactToOperatorDAGEdge, artifact.ID, uuid.New(), 0, e.DB)
+	require.Nil(t, err)
+
+	now := time.Now()
+	dagResult, err := sql.NewDAGResultRepo().Create(
+		e.ctx,
+		dag.ID,
+		&shared.ExecutionState{
+			Status:     shared.SucceededExecutionStatus,
+			Timestamps: &shared.ExecutionTimestamps{PendingAt: &now},
+		},
+		e.DB,
+	)
+	require.Nil(t, err)
+
+	contentPaths := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		contentPath := uuid.NewString()
+		artifactResult, err := e.repos.ArtifactResultRepo.Create(e.ctx, dagResult.ID, artifact.ID, contentPath, e.DB)
+		require.Nil(t, err)
+
+		_, err = e.repos.ArtifactResultRepo.Update(e.ctx, artifactResult.ID, map[string]interface{}{
+			models.ArtifactResultExecState: &shared.ExecutionState{Status: status},
+		}, e.DB)
+		require.Nil(t, err)
+
+		if status == shared.SucceededExecutionStatus {
+			require.Nil(t, e.oldStore.Put(e.ctx, contentPath, []byte(contentPath)))
+		}
+		contentPaths = append(contentPaths, contentPath)
+	}
+
+	return contentPaths
+}
+
+// waitForMigration waits until the storage migration with ID `id` that is run in the background is done.
+func (e *testEnv) waitForMigration(t *testing.T, id uuid.UUID) *models.StorageMigration {
+	require.Eventually(t, func() bool { return !inProgress.Load() }, 10*time.Second, 10*time.Millisecond)
+
+	storageMigrationObj, err := e.repos.StorageMigrationRepo.Get(e.ctx, id, e.DB)
+	require.Nil(t, err)
+	return storageMigrationObj
+}
+
+func noop() {}
+
+func TestEstimateStorage(t *testing.T) {
+	env := newTestEnv(t)
+	contentPaths := env.seedArtifactResults(t, shared.SucceededExecutionStatus, shared.FailedExecutionStatus)
+
+	storageMigrationObj, err := env.repos.StorageMigrationRepo.Create(env.ctx, nil, env.oldConfig, env.newConfig, true /* dryRun */, env.DB)
+	require.Nil(t, err)
+
+	require.Nil(t, estimateStorage(env.ctx, storageMigrationObj, env.repos, env.DB))
+	require.Equal(t, shared.StorageMigrationProgress{
+		TotalObjects:   1,
+		TotalBytes:     int64(len(contentPaths[0])),
+		SkippedObjects: 1,
+	}, *storageMigrationObj.Progress)
+
+	// Nothing is copied by a dry run.
+	require.False(t, env.newStore.Exists(env.ctx, contentPaths[0]))
+
+	// The content of a succeeded result must exist, since the migration would fail on it.
+	require.Nil(t, env.oldStore.Delete(env.ctx, contentPaths[0]))
+	require.NotNil(t, estimateStorage(env.ctx, storageMigrationObj, env.repos, env.DB))
+}
+
+func TestResume(t *testing.T) {
+	env := newTestEnv(t)
+	contentPaths := env.seedArtifactResults(t, shared.SucceededExecutionStatus, shared.SucceededExecutionStatus)
+
+	_, err := Resume(env.ctx, testOrgID, uuid.New(), noop, noop, env.repos, env.DB)
+	require.True(t, errors.Is(err, ErrInvalidStorageMigration()))
+
+	dryRunObj, err := env.repos.StorageMigrationRepo.Create(env.ctx, nil, env.oldConfig, env.newConfig, true /* dryRun */, env.DB)
+	require.Nil(t, err)
+	_, err = Resume(env.ctx, testOrgID, dryRunObj.ID, noop, noop, env.repos, env.DB)
+	require.True(t, errors.Is(err, ErrInvalidStorageMigration()))
+
+	// The first object was copied before the migration failed, so it is not copied again,
+	// even though it no longer exists in the old storage.
+	storageMigrationObj, err := env.repos.StorageMigrationRepo.Create(env.ctx, nil, env.oldConfig, env.newConfig, false /* dryRun */, env.DB)
+	require.Nil(t, err)
+	require.Nil(t, copyAndRecord(env, storageMigrationObj.ID, contentPaths[0]))
+	require.Nil(t, env.oldStore.Delete(env.ctx, contentPaths[0]))
+
+	_, err = Resume(env.ctx, testOrgID, storageMigrationObj.ID, noop, noop, env.repos, env.DB)
+	require.Nil(t, err)
+	storageMigrationObj = env.waitForMigration(t, storageMigrationObj.ID)
+
+	require.Equal(t, shared.SucceededExecutionStatus, storageMigrationObj.ExecState.Status)
+	require.True(t, storageMigrationObj.Current)
+	require.Equal(t, 1, storageMigrationObj.Progress.CopiedObjects)
+	require.Equal(t, *env.newConfig, config.Storage())
+
+	for _, contentPath := range contentPaths {
+		content, err := env.newStore.Get(env.ctx, contentPath)
+		require.Nil(t, err)
+		require.Equal(t, contentPath, string(content))
+	}
+
+	objects, err := env.repos.StorageMigrationRepo.GetObjects(env.ctx, storageMigrationObj.ID, env.DB)
+	require.Nil(t, err)
+	require.Len(t, objects, 2)
+
+	// A migration that succeeded cannot be resumed.
+	_, err = Resume(env.ctx, testOrgID, storageMigrationObj.ID, noop, noop, env.repos, env.DB)
+	require.True(t, errors.Is(err, ErrInvalidStorageMigration()))
+}
+
+func TestRollback(t *testing.T) {
+	env := newTestEnv(t)
+	contentPaths := env.seedArtifactResults(t, shared.SucceededExecutionStatus)
+
+	_, err := Rollback(env.ctx, testOrgID, noop, noop, env.repos, env.DB)
+	require.True(t, errors.Is(err, ErrInvalidStorageMigration()))
+
+	storageMigrationObj, err := Perform(env.ctx, testOrgID, nil, env.newConfig, false /* dryRun */, noop, noop, env.repos, env.DB)
+	require.Nil(t, err)
+	storageMigrationObj = env.waitForMigration(t, storageMigrationObj.ID)
+	require.Equal(t, shared.SucceededExecutionStatus, storageMigrationObj.ExecState.Status)
+	require.Equal(t, *env.newConfig, config.Storage())
+	require.False(t, env.oldStore.Exists(env.ctx, contentPaths[0]))
+
+	rollbackObj, err := Rollback(env.ctx, testOrgID, noop, noop, env.repos, env.DB)
+	require.Nil(t, err)
+	rollbackObj = env.waitForMigration(t, rollbackObj.ID)
+
+	require.Equal(t, shared.SucceededExecutionStatus, rollbackObj.ExecState.Status)
+	require.True(t, rollbackObj.Current)
+	require.Equal(t, uuid.Nil, rollbackObj.DestResourceID)
+	require.Equal(t, *env.oldConfig, config.Storage())
+
+	content, err := env.oldStore.Get(env.ctx, contentPaths[0])
+	require.Nil(t, err)
+	require.Equal(t, contentPaths[0], string(content))
+
+	storageMigrationObj, err = env.repos.StorageMigrationRepo.Get(env.ctx, storageMigrationObj.ID, env.DB)
+	require.Nil(t, err)
+	require.False(t, storageMigrationObj.Current)
+}
+
+// copyAndRecord copies the object at key to the new storage, as a storage migration with ID `id` does.
+func copyAndRecord(env *testEnv, id uuid.UUID, key string) error {
+	checksum, _, err := copyObject(env.ctx, env.oldStore, env.newStore, key)
+	if err != nil {
+		return err
+	}
+
+	return env.repos.StorageMigrationRepo.CreateObjects(env.ctx, id, map[string]string{key: checksum}, env.DB)
+}
@@ -0,0 +1,161 @@
+package storage_migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/dropbox/godropbox/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// migrationObject is an object in storage that is copied by a storage migration.
+type migrationObject struct {
+	key string
+	// description identifies the object in logs.
+	description string
+	// required is set if the migration fails when the object does not exist in the old storage.
+	required bool
+}
+
+// dagObjects are the objects of a DAG, which are copied to the storage of the DAG's workflow.
+type dagObjects struct {
+	dag     models.DAG
+	objects []migrationObject
+}
+
+// listObjects returns the objects in storage of all DAGs that can be migrated.
+func listObjects(ctx context.Context, repos *Repos, DB database.Database) ([]dagObjects, error) {
+	dags, err := repos.DAGRepo.List(ctx, DB)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("There are %v DAGs to migrate", len(dags))
+
+	dagsObjects := make([]dagObjects, 0, len(dags))
+	for _, dag := range dags {
+		if dag.EngineConfig.Type == shared.AirflowEngineType {
+			// We cannot migrate content for Airflow workflows
+			log.Infof("The engine of DAG %v is Airflow, so its migration will be skipped.", dag.ID)
+			continue
+		}
+
+		objects := []migrationObject{}
+
+		// Migrate all of the artifact result content for this DAG
+		artifacts, err := repos.ArtifactRepo.GetByDAG(ctx, dag.ID, DB)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, artifact := range artifacts {
+			artifactResults, err := repos.ArtifactResultRepo.GetByArtifact(ctx, artifact.ID, DB)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, artifactResult := range artifactResults {
+				objects = append(objects, migrationObject{
+					key:         artifactResult.ContentPath,
+					description: fmt.Sprintf("artifact result %v of artifact %v", artifactResult.ID, artifact.ID),
+					// The content of an artifact result that did not succeed may not exist.
+					required: !artifactResult.ExecState.IsNull &&
+						artifactResult.ExecState.Status == shared.SucceededExecutionStatus,
+				})
+			}
+		}
+
+		// Migrate all operator code for this DAG
+		operators, err := repos.OperatorRepo.GetByDAG(ctx, dag.ID, DB)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, operator := range operators {
+			var operatorCodePath string
+			switch {
+			case operator.Spec.IsFunction():
+				operatorCodePath = operator.Spec.Function().StoragePath
+			case operator.Spec.IsCheck():
+				operatorCodePath = operator.Spec.Check().Function.StoragePath
+			case operator.Spec.IsMetric():
+				operatorCodePath = operator.Spec.Metric().Function.StoragePath
+			default:
+				// There is no operator code to migrate for this operator
+				continue
+			}
+
+			objects = append(objects, migrationObject{
+				key:         operatorCodePath,
+				description: fmt.Sprintf("operator code %v", operator.ID),
+				required:    true,
+			})
+		}
+
+		log.Infof("There are %v objects to migrate for DAG %v", len(objects), dag.ID)
+		dagsObjects = append(dagsObjects, dagObjects{
+			dag:     dag,
+			objects: objects,
+		})
+	}
+
+	return dagsObjects, nil
+}
+
+// copyObject copies the object at `key` from `oldStore` to `newStore`, and verifies that the object
+// read back from `newStore` has the same checksum. It returns the checksum and the size of the object.
+func copyObject(ctx context.Context, oldStore storage.Storage, newStore storage.Storage, key string) (string, int64, error) {
+	reader, err := oldStore.NewReader(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	defer reader.Close()
+
+	writer, err := newStore.NewWriter(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(writer, io.TeeReader(reader, hash))
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "Unable to copy object %s.", key)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if err := verifyObject(ctx, newStore, key, checksum); err != nil {
+		return "", 0, err
+	}
+
+	return checksum, size, nil
+}
+
+// verifyObject checks that the object at `key` in `store` has the sha256 checksum `checksum`.
+func verifyObject(ctx context.Context, store storage.Storage, key string, checksum string) error {
+	reader, err := store.NewReader(ctx, key)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to read object %s to verify it.", key)
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return errors.Wrapf(err, "Unable to read object %s to verify it.", key)
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != checksum {
+		return errors.Newf("Object %s has checksum %s after it was copied, but expected %s.", key, actual, checksum)
+	}
+
+	return nil
+}
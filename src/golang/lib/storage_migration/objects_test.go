@@ -0,0 +1,58 @@
+package storage_migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorage(t *testing.T) storage.Storage {
+	return storage.NewStorage(&shared.StorageConfig{
+		Type:        shared.FileStorageType,
+		FileConfig:  &shared.FileConfig{Directory: t.TempDir()},
+		Compression: &shared.CompressionConfig{Algorithm: shared.GzipCompression},
+	})
+}
+
+func TestCopyObject(t *testing.T) {
+	ctx := context.Background()
+	oldStore := newTestStorage(t)
+	newStore := newTestStorage(t)
+
+	content := []byte("artifact content")
+	require.Nil(t, oldStore.Put(ctx, "key", content))
+
+	checksum, size, err := copyObject(ctx, oldStore, newStore, "key")
+	require.Nil(t, err)
+	sum := sha256.Sum256(content)
+	require.Equal(t, hex.EncodeToString(sum[:]), checksum)
+	require.Equal(t, int64(len(content)), size)
+
+	copied, err := newStore.Get(ctx, "key")
+	require.Nil(t, err)
+	require.Equal(t, content, copied)
+
+	_, _, err = copyObject(ctx, oldStore, newStore, "missing")
+	require.True(t, errors.Is(err, storage.ErrObjectDoesNotExist()))
+}
+
+func TestVerifyObject(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	content := []byte("artifact content")
+	require.Nil(t, store.Put(ctx, "key", content))
+	sum := sha256.Sum256(content)
+
+	require.Nil(t, verifyObject(ctx, store, "key", hex.EncodeToString(sum[:])))
+
+	otherSum := sha256.Sum256([]byte("other content"))
+	require.NotNil(t, verifyObject(ctx, store, "key", hex.EncodeToString(otherSum[:])))
+	require.NotNil(t, verifyObject(ctx, store, "missing", hex.EncodeToString(sum[:])))
+}
@@ -0,0 +1,26 @@
+package vault
+
+import (
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+)
+
+const (
+	azureVaultDir = "vault"
+)
+
+func newAzureVault(azureStoreConf shared.AzureConfig, key string) Vault {
+	// The Azure vault stores secrets under the [prefix]/vault path
+	// NOTE: The existing prefix is expected to always end with a slash.
+	azureStoreConf.Prefix += azureVaultDir + "/"
+
+	store := storage.NewUncachedStorage(&shared.StorageConfig{
+		Type:        shared.AzureStorageType,
+		AzureConfig: &azureStoreConf,
+	})
+
+	return &vault{
+		store: store,
+		key:   key,
+	}
+}
@@ -15,7 +15,7 @@ func newFileVault(fileStoreConf shared.FileConfig, key string) Vault {
 	// The file vault stores secrets under the ../vault subdirectory
 	fileStoreConf.Directory = filepath.Join(fileStoreConf.Directory, FileVaultDir)
 
-	store := storage.NewStorage(&shared.StorageConfig{
+	store := storage.NewUncachedStorage(&shared.StorageConfig{
 		Type:       shared.FileStorageType,
 		FileConfig: &fileStoreConf,
 	})
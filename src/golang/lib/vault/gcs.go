@@ -15,7 +15,7 @@ func newGCSVault(gcsStoreConf shared.GCSConfig, key string) Vault {
 	// The GCS vault stores secrets under the ../vault path
 	gcsStoreConf.Bucket = path.Join(gcsStoreConf.Bucket, gcsVaultDir)
 
-	store := storage.NewStorage(&shared.StorageConfig{
+	store := storage.NewUncachedStorage(&shared.StorageConfig{
 		Type:      shared.GCSStorageType,
 		GCSConfig: &gcsStoreConf,
 	})
@@ -0,0 +1,293 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/dropbox/godropbox/errors"
+)
+
+const (
+	defaultHashiCorpMount        = "secret"
+	defaultHashiCorpAppRoleMount = "approle"
+
+	hashiCorpTokenHeader     = "X-Vault-Token"
+	hashiCorpNamespaceHeader = "X-Vault-Namespace"
+	hashiCorpTokenEnvVar     = "VAULT_TOKEN"
+	hashiCorpSecretIDEnvVar  = "VAULT_SECRET_ID"
+
+	hashiCorpRequestTimeout = 30 * time.Second
+	// An AppRole token is renewed by logging in again once it expires within this margin.
+	hashiCorpTokenRenewMargin = 30 * time.Second
+)
+
+// hashiCorpVaults holds a hashiCorpVault per config, so that the AppRole login is not repeated
+// every time a vault is constructed.
+var hashiCorpVaults sync.Map
+
+// hashiCorpVault stores secrets in a KV v2 secrets engine of HashiCorp Vault.
+// Each secret is a separate entry under the path prefix of the config.
+type hashiCorpVault struct {
+	conf   config.HashiCorpVaultConfig
+	client *http.Client
+
+	mu    sync.Mutex
+	token string
+	// tokenExpiresAt is zero if the token does not expire.
+	tokenExpiresAt time.Time
+}
+
+func newHashiCorpVault(conf *config.HashiCorpVaultConfig) (Vault, error) {
+	if conf == nil || conf.Address == "" {
+		return nil, errors.New("The address of the HashiCorp Vault server is not configured.")
+	}
+
+	switch conf.AuthMethod {
+	case "", config.HashiCorpTokenAuth, config.HashiCorpAppRoleAuth:
+	default:
+		return nil, errors.Newf("Unsupported HashiCorp Vault auth method: %v", conf.AuthMethod)
+	}
+
+	serialized, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := hashiCorpVaults.Load(string(serialized)); ok {
+		return v.(*hashiCorpVault), nil
+	}
+
+	client := &http.Client{Timeout: hashiCorpRequestTimeout}
+	if conf.CACertPath != "" {
+		caCert, err := os.ReadFile(conf.CACertPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to read the CA certificate of the HashiCorp Vault server.")
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("The CA certificate of the HashiCorp Vault server is malformed.")
+		}
+
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: certPool},
+		}
+	}
+
+	v, _ := hashiCorpVaults.LoadOrStore(string(serialized), &hashiCorpVault{
+		conf:   *conf,
+		client: client,
+	})
+	return v.(*hashiCorpVault), nil
+}
+
+func (v *hashiCorpVault) Put(ctx context.Context, name string, secrets map[string]string) error {
+	body := map[string]interface{}{
+		"data": secrets,
+	}
+	return v.do(ctx, http.MethodPost, v.secretPath("data", name), body, nil)
+}
+
+// Get throws `storage.ErrObjectDoesNotExist` if the secret does not exist, like the storage vault.
+func (v *hashiCorpVault) Get(ctx context.Context, name string) (map[string]string, error) {
+	var resp struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, v.secretPath("data", name), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data.Data, nil
+}
+
+// Delete removes all versions of the secret.
+func (v *hashiCorpVault) Delete(ctx context.Context, name string) error {
+	return v.do(ctx, http.MethodDelete, v.secretPath("metadata", name), nil, nil)
+}
+
+// secretPath returns the API path of the secret `name` under the KV v2 endpoint `endpoint`.
+func (v *hashiCorpVault) secretPath(endpoint string, name string) string {
+	mount := v.conf.Mount
+	if mount == "" {
+		mount = defaultHashiCorpMount
+	}
+
+	return strings.Trim(mount, "/") + "/" + endpoint + "/" + v.conf.PathPrefix + name
+}
+
+// do sends a request to the Vault API, and decodes the response into `result` unless it is nil.
+// If an AppRole token is rejected, e.g. because it was revoked, the request is retried once with a new token.
+func (v *hashiCorpVault) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	for attempt := 0; ; attempt++ {
+		token, err := v.getToken(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = v.send(ctx, method, path, token, body, result)
+		if err == errHashiCorpForbidden &&
+			attempt == 0 &&
+			v.conf.AuthMethod == config.HashiCorpAppRoleAuth {
+			v.invalidateToken(token)
+			continue
+		}
+		return err
+	}
+}
+
+// errHashiCorpForbidden is returned by `send` if the token is rejected.
+var errHashiCorpForbidden = errors.New("The HashiCorp Vault token is not permitted to access the secret.")
+
+func (v *hashiCorpVault) send(
+	ctx context.Context,
+	method string,
+	path string,
+	token string,
+	body interface{},
+	result interface{},
+) error {
+	var reqBody io.Reader
+	if body != nil {
+		serialized, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(serialized)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(v.conf.Address, "/")+"/v1/"+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if token != "" {
+		req.Header.Set(hashiCorpTokenHeader, token)
+	}
+	if v.conf.Namespace != "" {
+		req.Header.Set(hashiCorpNamespaceHeader, v.conf.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Unable to send request to HashiCorp Vault.")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "Unable to read response from HashiCorp Vault.")
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return storage.ErrObjectDoesNotExist()
+	case resp.StatusCode == http.StatusForbidden:
+		return errHashiCorpForbidden
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(respBody, &errResp)
+		return errors.Newf(
+			"HashiCorp Vault request %s %s failed with status %d: %s",
+			method,
+			path,
+			resp.StatusCode,
+			strings.Join(errResp.Errors, "; "),
+		)
+	}
+
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return errors.Wrap(err, "Unable to parse response from HashiCorp Vault.")
+	}
+	return nil
+}
+
+// getToken returns the token to authenticate requests with. AppRole tokens are cached until they expire.
+func (v *hashiCorpVault) getToken(ctx context.Context) (string, error) {
+	if v.conf.AuthMethod != config.HashiCorpAppRoleAuth {
+		if v.conf.Token != "" {
+			return v.conf.Token, nil
+		}
+		return os.Getenv(hashiCorpTokenEnvVar), nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.token != "" && (v.tokenExpiresAt.IsZero() || time.Until(v.tokenExpiresAt) > hashiCorpTokenRenewMargin) {
+		return v.token, nil
+	}
+
+	secretID := v.conf.SecretID
+	if secretID == "" {
+		secretID = os.Getenv(hashiCorpSecretIDEnvVar)
+	}
+
+	mount := v.conf.AppRoleMount
+	if mount == "" {
+		mount = defaultHashiCorpAppRoleMount
+	}
+
+	var resp struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	err := v.send(
+		ctx,
+		http.MethodPost,
+		"auth/"+strings.Trim(mount, "/")+"/login",
+		"", /* token */
+		map[string]string{
+			"role_id":   v.conf.RoleID,
+			"secret_id": secretID,
+		},
+		&resp,
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "Unable to log in to HashiCorp Vault with AppRole.")
+	}
+
+	if resp.Auth.ClientToken == "" {
+		return "", errors.New("HashiCorp Vault did not return a token for the AppRole login.")
+	}
+
+	v.token = resp.Auth.ClientToken
+	v.tokenExpiresAt = time.Time{}
+	if resp.Auth.LeaseDuration > 0 {
+		v.tokenExpiresAt = time.Now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second)
+	}
+	return v.token, nil
+}
+
+// invalidateToken forces a new login, unless the token was already replaced.
+func (v *hashiCorpVault) invalidateToken(token string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.token == token {
+		v.token = ""
+	}
+}
@@ -0,0 +1,189 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/config"
+	"github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRoleID   = "test-role-id"
+	testSecretID = "test-secret-id"
+)
+
+// fakeHashiCorpVault implements the subset of the HashiCorp Vault API used by hashiCorpVault:
+// a KV v2 secrets engine mounted at `secret` and the AppRole auth method mounted at `approle`.
+type fakeHashiCorpVault struct {
+	mu      sync.Mutex
+	secrets map[string]map[string]string
+	tokens  map[string]bool
+	logins  int
+}
+
+func newFakeHashiCorpVault(tokens ...string) *fakeHashiCorpVault {
+	f := &fakeHashiCorpVault{
+		secrets: map[string]map[string]string{},
+		tokens:  map[string]bool{},
+	}
+	for _, token := range tokens {
+		f.tokens[token] = true
+	}
+	return f
+}
+
+func (f *fakeHashiCorpVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil ||
+			body["role_id"] != testRoleID ||
+			body["secret_id"] != testSecretID {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+
+		f.logins++
+		token := "approle-token-" + string(rune('0'+f.logins))
+		f.tokens[token] = true
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{
+				"client_token":   token,
+				"lease_duration": 3600,
+			},
+		})
+		return
+	}
+
+	if !f.tokens[r.Header.Get(hashiCorpTokenHeader)] {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":["permission denied"]}`))
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		name := strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")
+		switch r.Method {
+		case http.MethodPost:
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			f.secrets[name] = body.Data
+			w.Write([]byte(`{"data":{"version":1}}`))
+		case http.MethodGet:
+			secrets, ok := f.secrets[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors":[]}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"data": secrets},
+			})
+		}
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/") && r.Method == http.MethodDelete:
+		delete(f.secrets, strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestHashiCorpVault(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeHashiCorpVault("root-token")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	v, err := newHashiCorpVault(&config.HashiCorpVaultConfig{
+		Address:    server.URL,
+		PathPrefix: "aqueduct/",
+		Token:      "root-token",
+	})
+	require.Nil(t, err)
+
+	secrets := map[string]string{"username": "user", "password": "password"}
+	require.Nil(t, v.Put(ctx, "resource", secrets))
+	require.Equal(t, secrets, fake.secrets["aqueduct/resource"])
+
+	actualSecrets, err := v.Get(ctx, "resource")
+	require.Nil(t, err)
+	require.Equal(t, secrets, actualSecrets)
+
+	require.Nil(t, v.Delete(ctx, "resource"))
+	_, err = v.Get(ctx, "resource")
+	require.True(t, errors.Is(err, storage.ErrObjectDoesNotExist()))
+
+	// A token that is not permitted fails instead of being retried.
+	v, err = newHashiCorpVault(&config.HashiCorpVaultConfig{
+		Address: server.URL,
+		Token:   "other-token",
+	})
+	require.Nil(t, err)
+	_, err = v.Get(ctx, "resource")
+	require.NotNil(t, err)
+}
+
+func TestHashiCorpVaultAppRole(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeHashiCorpVault()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	conf := &config.HashiCorpVaultConfig{
+		Address:    server.URL,
+		AuthMethod: config.HashiCorpAppRoleAuth,
+		RoleID:     testRoleID,
+		SecretID:   testSecretID,
+	}
+	v, err := newHashiCorpVault(conf)
+	require.Nil(t, err)
+
+	secrets := map[string]string{"token": "secret"}
+	require.Nil(t, v.Put(ctx, "resource", secrets))
+	_, err = v.Get(ctx, "resource")
+	require.Nil(t, err)
+	require.Equal(t, 1, fake.logins)
+
+	// The vault is reused for the same config, so the token is not requested again.
+	sameVault, err := newHashiCorpVault(conf)
+	require.Nil(t, err)
+	require.True(t, sameVault == v)
+
+	// A revoked token is replaced by logging in again.
+	fake.mu.Lock()
+	fake.tokens = map[string]bool{}
+	fake.mu.Unlock()
+
+	actualSecrets, err := v.Get(ctx, "resource")
+	require.Nil(t, err)
+	require.Equal(t, secrets, actualSecrets)
+	require.Equal(t, 2, fake.logins)
+
+	// A login with the wrong secret ID fails.
+	v, err = newHashiCorpVault(&config.HashiCorpVaultConfig{
+		Address:    server.URL,
+		AuthMethod: config.HashiCorpAppRoleAuth,
+		RoleID:     testRoleID,
+		SecretID:   "wrong-secret-id",
+	})
+	require.Nil(t, err)
+	_, err = v.Get(ctx, "resource")
+	require.NotNil(t, err)
+}
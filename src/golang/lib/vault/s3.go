@@ -12,9 +12,10 @@ const (
 func newS3Vault(s3StoreConf shared.S3Config, key string) Vault {
 	// The S3 vault stores secrets under the [root_dir]/vault path
 	// NOTE: The existing root directory is expected to always end with a slash.
+	// The endpoint config is kept as is, so S3-compatible services store the vault as well.
 	s3StoreConf.RootDir += s3VaultDir + "/"
 
-	store := storage.NewStorage(&shared.StorageConfig{
+	store := storage.NewUncachedStorage(&shared.StorageConfig{
 		Type:     shared.S3StorageType,
 		S3Config: &s3StoreConf,
 	})
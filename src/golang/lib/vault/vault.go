@@ -3,6 +3,7 @@ package vault
 import (
 	"context"
 
+	"github.com/aqueducthq/aqueduct/config"
 	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/dropbox/godropbox/errors"
@@ -14,8 +15,26 @@ type Vault interface {
 	Delete(ctx context.Context, name string) error
 }
 
-// NewVault constructs a Vault from the storage config and encryption key provided.
+// NewVault constructs the Vault of the server config. By default, it stores the secrets in the
+// storage layer with the storage config and encryption key provided. If the server config selects
+// an external vault, the storage config and key are ignored.
 func NewVault(storageConf *shared.StorageConfig, key string) (Vault, error) {
+	if IsExternal() {
+		return newHashiCorpVault(config.Vault().HashiCorp)
+	}
+
+	return NewStorageVault(storageConf, key)
+}
+
+// IsExternal returns whether the secrets are stored outside of the storage layer, so they
+// do not have to be migrated along with it.
+func IsExternal() bool {
+	return config.Vault().Type == config.HashiCorpVaultType
+}
+
+// NewStorageVault constructs a Vault that stores the secrets in the storage layer,
+// regardless of the server config.
+func NewStorageVault(storageConf *shared.StorageConfig, key string) (Vault, error) {
 	switch storageConf.Type {
 	case shared.FileStorageType:
 		return newFileVault(*storageConf.FileConfig, key), nil
@@ -23,6 +42,8 @@ func NewVault(storageConf *shared.StorageConfig, key string) (Vault, error) {
 		return newS3Vault(*storageConf.S3Config, key), nil
 	case shared.GCSStorageType:
 		return newGCSVault(*storageConf.GCSConfig, key), nil
+	case shared.AzureStorageType:
+		return newAzureVault(*storageConf.AzureConfig, key), nil
 	default:
 		return nil, errors.Newf("Unsupported vault type: %v", storageConf.Type)
 	}
@@ -1,8 +1,9 @@
 package artifact
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"io"
 	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
@@ -33,6 +34,13 @@ type Artifact interface {
 	// by the caller.
 	DeleteContent(ctx context.Context) error
 
+	// Reuse points this artifact to `result`, which was computed by a previous run,
+	// so that it does not have to be computed again.
+	Reuse(ctx context.Context, result *models.ArtifactResult) error
+
+	// GetResult returns the result of this artifact in the workflow run `dagResultID`.
+	GetResult(ctx context.Context, dagResultID uuid.UUID) (*models.ArtifactResult, error)
+
 	// InitializeResult initializes the artifact in the database.
 	InitializeResult(ctx context.Context, dagResultID uuid.UUID) error
 
@@ -58,6 +66,11 @@ type Artifact interface {
 	// Errors if the artifact has not yet been computed.
 	GetContent(ctx context.Context) ([]byte, error)
 
+	// GetContentReader works similar to GetContent but streams the content
+	// from storage instead of loading it into memory.
+	// The caller is responsible for closing the returned reader.
+	GetContentReader(ctx context.Context) (io.ReadCloser, error)
+
 	// SampleContent works similar to GetContent but takes only
 	// a sample of data if it's too large to fit client.
 	//
@@ -65,6 +78,11 @@ type Artifact interface {
 	// the number of rows sent to client.
 	SampleContent(ctx context.Context) ([]byte, bool, error)
 
+	// SampleContentReader works similar to SampleContent but streams the content.
+	// The returned reader is nil if there is no content to send to client.
+	// Otherwise, the caller is responsible for closing it.
+	SampleContentReader(ctx context.Context) (io.ReadCloser, bool, error)
+
 	// SetExecState updates the execution state of the artifact.
 	// For now, it doesn't tries to 'merge' the incoming state with the current state
 	// e.g. if current exec state has a 'pending' status with 'PendingAt' timestamp,
@@ -216,6 +234,34 @@ func (a *ArtifactImpl) ShouldPersistContent() bool {
 	return a.shouldPersistContent
 }
 
+func (a *ArtifactImpl) Reuse(ctx context.Context, result *models.ArtifactResult) error {
+	if a.resultsPersisted {
+		return errors.Newf("Artifact %s cannot be reused after its result was persisted.", a.Name())
+	}
+
+	if result.Metadata.IsNull {
+		return errors.Newf("Artifact %s cannot be reused, since the previous result has no metadata.", a.Name())
+	}
+
+	// The metadata file of the previous run is not kept, so it is written again for the operators
+	// that consume this artifact.
+	err := utils.WriteToStorage(ctx, a.storageConfig, a.execPaths.ArtifactMetadataPath, &result.Metadata.ArtifactResultMetadata)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to write metadata of artifact %s.", a.Name())
+	}
+
+	a.execPaths.ArtifactContentPath = result.ContentPath
+	return nil
+}
+
+func (a *ArtifactImpl) GetResult(ctx context.Context, dagResultID uuid.UUID) (*models.ArtifactResult, error) {
+	if a.resultRepo == nil {
+		return nil, errors.New("Artifact's result reader cannot be nil.")
+	}
+
+	return a.resultRepo.GetByArtifactAndDAGResult(ctx, a.ID(), dagResultID, a.db)
+}
+
 func (a *ArtifactImpl) InitializeResult(ctx context.Context, dagResultID uuid.UUID) error {
 	if a.resultRepo == nil {
 		return errors.New("Artifact's result writer cannot be nil.")
@@ -241,6 +287,8 @@ func (a *ArtifactImpl) updateArtifactResultAfterComputation(ctx context.Context)
 		models.ArtifactResultMetadata:  nil,
 		models.ArtifactResultStatus:    a.execState.Status,
 		models.ArtifactResultExecState: a.execState,
+		// The content path changes if the artifact reused the result of a previous run.
+		models.ArtifactResultContentPath: a.execPaths.ArtifactContentPath,
 	}
 
 	metadataExists := utils.ObjectExistsInStorage(ctx, a.storageConfig, a.execPaths.ArtifactMetadataPath)
@@ -374,8 +422,12 @@ func (a *ArtifactImpl) Finish(ctx context.Context) {
 		return
 	}
 
-	// Do not update the cache or clean anything up if the artifact result was persisted.
+	// Once the artifact result was persisted, its metadata is stored in the artifact_result's
+	// table, so the metadata file is no longer needed. The content is kept for the result.
 	if a.resultsPersisted {
+		if a.execPaths.ArtifactMetadataPath != "" {
+			utils.CleanupStorageFile(ctx, a.storageConfig, a.execPaths.ArtifactMetadataPath)
+		}
 		return
 	}
 
@@ -407,18 +459,57 @@ func (a *ArtifactImpl) GetMetadata(ctx context.Context) (*shared.ArtifactResultM
 }
 
 func (a *ArtifactImpl) GetContent(ctx context.Context) ([]byte, error) {
-	content, err := storage.NewStorage(a.storageConfig).Get(ctx, a.execPaths.ArtifactContentPath)
+	reader, err := a.GetContentReader(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to read content of artifact %s.", a.Name())
+	}
 	return content, nil
 }
 
+func (a *ArtifactImpl) GetContentReader(ctx context.Context) (io.ReadCloser, error) {
+	metadata, err := a.GetMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := storage.NewStorage(a.storageConfig).NewReader(ctx, a.execPaths.ArtifactContentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Artifacts written before checksums were recorded are not verified.
+	if metadata != nil && metadata.Checksum != "" {
+		return storage.NewChecksumReader(reader, metadata.Checksum), nil
+	}
+	return reader, nil
+}
+
 func (a *ArtifactImpl) SetExecState(execState shared.ExecutionState) {
 	a.execState = &execState
 }
 
 func (a *ArtifactImpl) SampleContent(ctx context.Context) ([]byte, bool, error) {
+	reader, isDownsampled, err := a.SampleContentReader(ctx)
+	if err != nil || reader == nil {
+		return nil, false, err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return content, isDownsampled, nil
+}
+
+func (a *ArtifactImpl) SampleContentReader(ctx context.Context) (io.ReadCloser, bool, error) {
 	metadata, err := a.GetMetadata(ctx)
 	if err != nil {
 		return nil, false, err
@@ -434,62 +525,28 @@ func (a *ArtifactImpl) SampleContent(ctx context.Context) ([]byte, bool, error)
 		return nil, false, nil
 	}
 
-	content, err := a.GetContent(ctx)
+	reader, err := a.GetContentReader(ctx)
 	if err != nil {
 		return nil, false, err
 	}
 
-	// For table types, we returns a down-sampled table when possible
-	if metadata.SerializationType == shared.TableSerialization {
-		// The over-simplified type for table orient.
-		type table struct {
-			Schema map[string]interface{} `json:"schema"`
-			Data   []interface{}          `json:"data"`
-		}
-
-		var t table
-
-		err := json.Unmarshal(content, &t)
-		if err != nil {
-			return nil, false, err
-		}
-
-		if len(t.Data) <= sampleTableRow {
-			// If the table is small, return the original content
-			return content, false, nil
-		}
-
-		t.Data = t.Data[:sampleTableRow]
-		downsampledContent, err := json.Marshal(t)
-		if err != nil {
-			return nil, false, err
-		}
-
-		return downsampledContent, true, nil
+	// For table types, we returns a down-sampled table when possible.
+	// Only the sampled rows are decoded, so the sample is small enough to be held in memory.
+	var sample func(io.Reader) ([]byte, bool, error)
+	switch metadata.SerializationType {
+	case shared.TableSerialization:
+		sample = sampleTable
+	case shared.BsonTableSerialization:
+		sample = sampleRecords
+	default:
+		return reader, false, nil
 	}
 
-	if metadata.SerializationType == shared.BsonTableSerialization {
-		// The over-simplified type for record orient.
-		var t []interface{}
-
-		err := json.Unmarshal(content, &t)
-		if err != nil {
-			return nil, false, err
-		}
-
-		if len(t) <= sampleTableRow {
-			// If the table is small, return the original content
-			return content, false, nil
-		}
-
-		t = t[:sampleTableRow]
-		downsampledContent, err := json.Marshal(t)
-		if err != nil {
-			return nil, false, err
-		}
-
-		return downsampledContent, true, nil
+	defer reader.Close()
+	content, isDownsampled, err := sample(reader)
+	if err != nil {
+		return nil, false, err
 	}
 
-	return content, false, nil
+	return io.NopCloser(bytes.NewReader(content)), isDownsampled, nil
 }
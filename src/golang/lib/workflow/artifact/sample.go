@@ -0,0 +1,139 @@
+package artifact
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/dropbox/godropbox/errors"
+)
+
+// tableDataKey is the key of the rows of a table serialized with the table orient.
+const tableDataKey = "data"
+
+// sampleTable reads a table serialized with the table orient, i.e. `{"schema": ..., "data": [...]}`,
+// from r and returns it with at most `sampleTableRow` rows. The returned bool is whether any rows were dropped.
+// The rows are decoded one by one, so the full table is never held in memory, and r is only read
+// until the end of the data if the schema is already known.
+func sampleTable(r io.Reader) ([]byte, bool, error) {
+	decoder := json.NewDecoder(r)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, false, err
+	}
+
+	fields := map[string]interface{}{}
+	isDownsampled := false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false, err
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return nil, false, errors.Newf("Unexpected table key %v.", token)
+		}
+
+		if key != tableDataKey {
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return nil, false, err
+			}
+			fields[key] = value
+			continue
+		}
+
+		rows, more, err := sampleArray(decoder)
+		if err != nil {
+			return nil, false, err
+		}
+		fields[key] = rows
+
+		if more {
+			isDownsampled = true
+			if len(fields) > 1 {
+				// The schema has already been read, so the remaining rows can be ignored.
+				break
+			}
+
+			if err := skipArray(decoder); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return content, isDownsampled, nil
+}
+
+// sampleRecords reads a table serialized with the record orient, i.e. `[...]`, from r and returns it
+// with at most `sampleTableRow` rows. The returned bool is whether any rows were dropped.
+func sampleRecords(r io.Reader) ([]byte, bool, error) {
+	rows, more, err := sampleArray(json.NewDecoder(r))
+	if err != nil {
+		return nil, false, err
+	}
+
+	content, err := json.Marshal(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return content, more, nil
+}
+
+// sampleArray decodes the opening delimiter and the first `sampleTableRow` elements of an array.
+// The returned bool is whether the array has more elements. If it does not, the closing delimiter
+// is decoded as well.
+func sampleArray(decoder *json.Decoder) ([]json.RawMessage, bool, error) {
+	if err := expectDelim(decoder, '['); err != nil {
+		return nil, false, err
+	}
+
+	rows := []json.RawMessage{}
+	for decoder.More() {
+		if len(rows) == sampleTableRow {
+			return rows, true, nil
+		}
+
+		var row json.RawMessage
+		if err := decoder.Decode(&row); err != nil {
+			return nil, false, err
+		}
+		rows = append(rows, row)
+	}
+
+	if err := expectDelim(decoder, ']'); err != nil {
+		return nil, false, err
+	}
+
+	return rows, false, nil
+}
+
+// skipArray decodes and drops the remaining elements and the closing delimiter of an array.
+func skipArray(decoder *json.Decoder) error {
+	for decoder.More() {
+		var row json.RawMessage
+		if err := decoder.Decode(&row); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, ']')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return errors.Newf("Expected %v but got %v.", delim, token)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package artifact
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleTable(t *testing.T) {
+	rows := func(n int) string {
+		values := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			values = append(values, fmt.Sprintf(`{"a":%d}`, i))
+		}
+		return "[" + strings.Join(values, ",") + "]"
+	}
+
+	type table struct {
+		Schema map[string]interface{} `json:"schema"`
+		Data   []interface{}          `json:"data"`
+	}
+
+	for _, tc := range []struct {
+		name          string
+		content       string
+		numRows       int
+		isDownsampled bool
+	}{
+		{"small", fmt.Sprintf(`{"schema":{"fields":[]},"data":%s}`, rows(3)), 3, false},
+		{"large", fmt.Sprintf(`{"schema":{"fields":[]},"data":%s}`, rows(sampleTableRow+10)), sampleTableRow, true},
+		{"data first", fmt.Sprintf(`{"data":%s,"schema":{"fields":[]}}`, rows(sampleTableRow+10)), sampleTableRow, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			content, isDownsampled, err := sampleTable(strings.NewReader(tc.content))
+			require.Nil(t, err)
+			require.Equal(t, tc.isDownsampled, isDownsampled)
+
+			var sampled table
+			require.Nil(t, json.Unmarshal(content, &sampled))
+			require.Len(t, sampled.Data, tc.numRows)
+			require.NotNil(t, sampled.Schema)
+		})
+	}
+
+	_, _, err := sampleTable(strings.NewReader(`[]`))
+	require.NotNil(t, err)
+}
+
+func TestSampleRecords(t *testing.T) {
+	content, isDownsampled, err := sampleRecords(strings.NewReader(`[{"a":1}, {"a":2}]`))
+	require.Nil(t, err)
+	require.False(t, isDownsampled)
+	require.JSONEq(t, `[{"a":1},{"a":2}]`, string(content))
+
+	records := strings.Repeat(`{"a":1},`, sampleTableRow) + `{"a":2}`
+	content, isDownsampled, err = sampleRecords(strings.NewReader("[" + records + "]"))
+	require.Nil(t, err)
+	require.True(t, isDownsampled)
+
+	var sampled []interface{}
+	require.Nil(t, json.Unmarshal(content, &sampled))
+	require.Len(t, sampled, sampleTableRow)
+}
@@ -0,0 +1,146 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// CascadeTargets returns the IDs of the Workflows to trigger after a run of the Workflow sourceID
+// succeeded. Targets with the `any` cascade mode are always triggered. Targets with the `all`
+// cascade mode are only triggered once every one of their sources has succeeded since they were
+// last triggered or ran, and the time they are triggered at is recorded.
+func CascadeTargets(
+	ctx context.Context,
+	sourceID uuid.UUID,
+	workflowRepo repos.Workflow,
+	dagResultRepo repos.DAGResult,
+	DB database.Database,
+) ([]uuid.UUID, error) {
+	targetIDs, err := workflowRepo.GetTargets(ctx, sourceID, DB)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to retrieve target Workflows.")
+	}
+
+	toTrigger := make([]uuid.UUID, 0, len(targetIDs))
+	for _, targetID := range targetIDs {
+		target, err := workflowRepo.Get(ctx, targetID, DB)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to retrieve target Workflow.")
+		}
+
+		if target.Schedule.CascadeMode != shared.AllCascadeMode {
+			toTrigger = append(toTrigger, targetID)
+			continue
+		}
+
+		ready, err := recordCascadeIfReady(ctx, targetID, sourceID, workflowRepo, dagResultRepo, DB)
+		if err != nil {
+			return nil, err
+		}
+
+		if ready {
+			toTrigger = append(toTrigger, targetID)
+		}
+	}
+
+	return toTrigger, nil
+}
+
+// recordCascadeIfReady returns whether the target Workflow with targetID should be triggered,
+// because every one of its sources, other than sourceID which just succeeded, has succeeded since
+// it was last triggered or ran. If so, the time it is triggered at is recorded. The target is
+// locked while this is decided, so that sources that succeed at the same time trigger it only once,
+// and a source that succeeds again before the target ran does not trigger it again.
+func recordCascadeIfReady(
+	ctx context.Context,
+	targetID uuid.UUID,
+	sourceID uuid.UUID,
+	workflowRepo repos.Workflow,
+	dagResultRepo repos.DAGResult,
+	DB database.Database,
+) (bool, error) {
+	txn, err := DB.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer database.TxnRollbackIgnoreErr(ctx, txn)
+
+	if err := workflowRepo.Lock(ctx, targetID, txn); err != nil {
+		return false, errors.Wrap(err, "Unable to lock the target Workflow.")
+	}
+
+	// The target is read again once it is locked, since another source may have just triggered it.
+	target, err := workflowRepo.Get(ctx, targetID, txn)
+	if err != nil {
+		return false, errors.Wrap(err, "Unable to retrieve target Workflow.")
+	}
+
+	ready, err := allSourcesSucceededSinceLastRun(ctx, target, sourceID, dagResultRepo, txn)
+	if err != nil || !ready {
+		return false, err
+	}
+
+	changes := map[string]interface{}{
+		models.WorkflowCascadeTriggeredAt: time.Now(),
+	}
+	if _, err := workflowRepo.Update(ctx, targetID, changes, txn); err != nil {
+		return false, errors.Wrap(err, "Unable to record the cascade of the target Workflow.")
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// allSourcesSucceededSinceLastRun returns whether every source of target, other than sourceID
+// which just succeeded, has a successful run that was created after the last run of target,
+// and after target was last triggered by its sources.
+func allSourcesSucceededSinceLastRun(
+	ctx context.Context,
+	target *models.Workflow,
+	sourceID uuid.UUID,
+	dagResultRepo repos.DAGResult,
+	DB database.Database,
+) (bool, error) {
+	lastRuns, err := dagResultRepo.GetByWorkflow(ctx, target.ID, models.DAGResultCreatedAt, 1, true /* orderDescending */, DB)
+	if err != nil {
+		return false, errors.Wrap(err, "Unable to retrieve the last run of the target Workflow.")
+	}
+
+	// If the target never ran, any successful run of its sources counts.
+	var since time.Time
+	if len(lastRuns) > 0 {
+		since = lastRuns[0].CreatedAt
+	}
+
+	// The run that the sources last triggered may still be queued.
+	if !target.CascadeTriggeredAt.IsNull && target.CascadeTriggeredAt.Time.After(since) {
+		since = target.CascadeTriggeredAt.Time
+	}
+
+	for _, otherSourceID := range target.Schedule.Sources() {
+		if otherSourceID == sourceID {
+			continue
+		}
+
+		_, err := dagResultRepo.GetLatestSucceededByWorkflow(ctx, otherSourceID, since, DB)
+		if err != nil {
+			if aq_errors.Is(err, database.ErrNoRows()) {
+				return false, nil
+			}
+			return false, errors.Wrap(err, "Unable to retrieve the runs of a source Workflow.")
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,114 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/cmd/migrator/migrator"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/repos/sql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCascadeTargetsAllSources(t *testing.T) {
+	ctx := context.Background()
+	DB, err := database.NewSqliteInMemoryDatabase(&database.SqliteConfig{})
+	require.Nil(t, err)
+	t.Cleanup(DB.Close)
+	require.Nil(t, migrator.GoTo(ctx, models.CurrentSchemaVersion, DB))
+
+	workflowRepo := sql.NewWorklowRepo()
+	dagRepo := sql.NewDAGRepo()
+	dagResultRepo := sql.NewDAGResultRepo()
+
+	user, err := sql.NewUserRepo().Create(ctx, "aqueduct", uuid.NewString(), DB)
+	require.Nil(t, err)
+
+	createWorkflow := func(schedule *shared.Schedule) *models.DAG {
+		workflow, err := workflowRepo.Create(
+			ctx,
+			user.ID,
+			uuid.NewString(),
+			"",
+			schedule,
+			&shared.RetentionPolicy{},
+			&shared.NotificationSettings{},
+			0, /* maxParallelOperators */
+			DB,
+		)
+		require.Nil(t, err)
+
+		dag, err := dagRepo.Create(
+			ctx,
+			workflow.ID,
+			&shared.StorageConfig{Type: shared.FileStorageType, FileConfig: &shared.FileConfig{Directory: t.TempDir()}},
+			&shared.EngineConfig{Type: shared.AqueductEngineType, AqueductConfig: &shared.AqueductConfig{}},
+			DB,
+		)
+		require.Nil(t, err)
+		return dag
+	}
+
+	succeed := func(dag *models.DAG) {
+		now := time.Now()
+		_, err := dagResultRepo.Create(
+			ctx,
+			dag.ID,
+			&shared.ExecutionState{
+				Status:     shared.SucceededExecutionStatus,
+				Timestamps: &shared.ExecutionTimestamps{PendingAt: &now},
+			},
+			DB,
+		)
+		require.Nil(t, err)
+	}
+
+	sourceA := createWorkflow(&shared.Schedule{Trigger: shared.ManualUpdateTrigger})
+	sourceB := createWorkflow(&shared.Schedule{Trigger: shared.ManualUpdateTrigger})
+	target := createWorkflow(&shared.Schedule{
+		Trigger:     shared.CascadingUpdateTrigger,
+		SourceIDs:   []uuid.UUID{sourceA.WorkflowID, sourceB.WorkflowID},
+		CascadeMode: shared.AllCascadeMode,
+	})
+
+	// Both sources finish at the same time, but the target is only triggered once.
+	succeed(sourceA)
+	succeed(sourceB)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var triggered []uuid.UUID
+	for _, source := range []*models.DAG{sourceA, sourceB} {
+		wg.Add(1)
+		go func(sourceID uuid.UUID) {
+			defer wg.Done()
+			targetIDs, err := CascadeTargets(ctx, sourceID, workflowRepo, dagResultRepo, DB)
+			require.Nil(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			triggered = append(triggered, targetIDs...)
+		}(source.WorkflowID)
+	}
+	wg.Wait()
+	require.Equal(t, []uuid.UUID{target.WorkflowID}, triggered)
+
+	// A source that succeeds again while the triggered run of the target is still queued
+	// does not trigger it again.
+	time.Sleep(10 * time.Millisecond)
+	succeed(sourceA)
+	targetIDs, err := CascadeTargets(ctx, sourceA.WorkflowID, workflowRepo, dagResultRepo, DB)
+	require.Nil(t, err)
+	require.Empty(t, targetIDs)
+
+	// The target is triggered again once the other source succeeded as well.
+	succeed(sourceB)
+	targetIDs, err = CascadeTargets(ctx, sourceB.WorkflowID, workflowRepo, dagResultRepo, DB)
+	require.Nil(t, err)
+	require.Equal(t, []uuid.UUID{target.WorkflowID}, targetIDs)
+}
@@ -0,0 +1,74 @@
+package workflow
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/gorhill/cronexpr"
+)
+
+const (
+	// ScheduledTimeParamName is the built-in parameter that is set to the time a cron run
+	// was scheduled at, in RFC 3339 format in UTC. It only has an effect on workflows that
+	// have a parameter with this name.
+	ScheduledTimeParamName = "aqueduct_scheduled_time"
+
+	// MaxCatchUpRuns is the maximum number of runs that are caught up for a workflow
+	// with an AllCatchUpPolicy, so that a frequent schedule does not launch an unbounded
+	// number of runs after a long outage.
+	MaxCatchUpRuns = 100
+)
+
+// MissedRunTimes returns the times of the cron runs of schedule that were missed between
+// lastRunAt and now, which should be caught up according to the schedule's CatchUpPolicy.
+// It also returns the times of the missed runs that are not caught up since there were
+// more than MaxCatchUpRuns, so that they can be recorded as skipped. Both are in ascending order.
+func MissedRunTimes(schedule *shared.Schedule, lastRunAt time.Time, now time.Time) ([]time.Time, []time.Time, error) {
+	if schedule.CatchUpPolicy == shared.NoneCatchUpPolicy {
+		return nil, nil, nil
+	}
+
+	location, err := schedule.Location()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Invalid timezone %s.", schedule.Timezone)
+	}
+
+	expr, err := cronexpr.Parse(string(schedule.CronSchedule))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Invalid cron schedule %s.", schedule.CronSchedule)
+	}
+
+	// The cron schedule is evaluated in the timezone of the schedule.
+	missedTimes := []time.Time{}
+	for t := expr.Next(lastRunAt.In(location)); !t.IsZero() && !t.After(now); t = expr.Next(t) {
+		missedTimes = append(missedTimes, t)
+	}
+
+	if schedule.CatchUpPolicy != shared.AllCatchUpPolicy {
+		// Only the latest missed run is caught up, and the earlier ones are superseded by it.
+		if len(missedTimes) == 0 {
+			return missedTimes, nil, nil
+		}
+		return missedTimes[len(missedTimes)-1:], nil, nil
+	}
+
+	// Only the latest missed runs are caught up.
+	if len(missedTimes) <= MaxCatchUpRuns {
+		return missedTimes, nil, nil
+	}
+	numDropped := len(missedTimes) - MaxCatchUpRuns
+	return missedTimes[numDropped:], missedTimes[:numDropped], nil
+}
+
+// ScheduledTimeParams returns the built-in parameters of a cron run scheduled at scheduledAt.
+func ScheduledTimeParams(scheduledAt time.Time) map[string]param.Param {
+	return map[string]param.Param{
+		ScheduledTimeParamName: {
+			Val:               base64.StdEncoding.EncodeToString([]byte(scheduledAt.UTC().Format(time.RFC3339))),
+			SerializationType: string(shared.StringSerialization),
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissedRunTimes(t *testing.T) {
+	lastRunAt := time.Date(2022, time.November, 1, 18, 0, 0, 0, time.UTC)
+	now := time.Date(2022, time.November, 2, 6, 30, 0, 0, time.UTC)
+	schedule := &shared.Schedule{
+		Trigger:      shared.PeriodicUpdateTrigger,
+		CronSchedule: "0 * * * *",
+	}
+
+	// An empty policy only catches up the latest missed run.
+	runTimes, droppedTimes, err := MissedRunTimes(schedule, lastRunAt, now)
+	require.Nil(t, err)
+	require.Equal(t, []time.Time{time.Date(2022, time.November, 2, 6, 0, 0, 0, time.UTC)}, runTimes)
+	require.Empty(t, droppedTimes)
+
+	schedule.CatchUpPolicy = shared.NoneCatchUpPolicy
+	runTimes, _, err = MissedRunTimes(schedule, lastRunAt, now)
+	require.Nil(t, err)
+	require.Empty(t, runTimes)
+
+	// Every hour from 7pm to 6am is caught up.
+	schedule.CatchUpPolicy = shared.AllCatchUpPolicy
+	runTimes, droppedTimes, err = MissedRunTimes(schedule, lastRunAt, now)
+	require.Nil(t, err)
+	require.Equal(t, 12, len(runTimes))
+	require.Empty(t, droppedTimes)
+	for i, runTime := range runTimes {
+		require.True(t, lastRunAt.Add(time.Duration(i+1)*time.Hour).Equal(runTime))
+	}
+
+	// Only the latest runs are caught up after a long outage.
+	// The earlier runs are returned separately, so that they can be recorded as skipped.
+	runTimes, droppedTimes, err = MissedRunTimes(schedule, lastRunAt.Add(-30*24*time.Hour), now)
+	require.Nil(t, err)
+	require.Equal(t, MaxCatchUpRuns, len(runTimes))
+	require.Equal(t, 30*24+12-MaxCatchUpRuns, len(droppedTimes))
+	require.True(t, runTimes[MaxCatchUpRuns-1].Equal(time.Date(2022, time.November, 2, 6, 0, 0, 0, time.UTC)))
+	require.True(t, droppedTimes[0].Equal(lastRunAt.Add(-30*24*time.Hour+time.Hour)))
+	require.True(t, droppedTimes[len(droppedTimes)-1].Add(time.Hour).Equal(runTimes[0]))
+
+	// There is no missed run if the server was down for less than an interval.
+	runTimes, droppedTimes, err = MissedRunTimes(schedule, now.Add(-20*time.Minute), now)
+	require.Nil(t, err)
+	require.Empty(t, runTimes)
+	require.Empty(t, droppedTimes)
+}
+
+func TestMissedRunTimesTimezone(t *testing.T) {
+	// 9am in New York is 1pm in UTC during daylight saving time, which ends on November 6th,
+	// and 2pm in UTC afterwards.
+	schedule := &shared.Schedule{
+		Trigger:       shared.PeriodicUpdateTrigger,
+		CronSchedule:  "0 9 * * *",
+		Timezone:      "America/New_York",
+		CatchUpPolicy: shared.AllCatchUpPolicy,
+	}
+
+	runTimes, _, err := MissedRunTimes(
+		schedule,
+		time.Date(2022, time.November, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, time.November, 7, 0, 0, 0, 0, time.UTC),
+	)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(runTimes))
+	require.True(t, time.Date(2022, time.November, 5, 13, 0, 0, 0, time.UTC).Equal(runTimes[0]))
+	require.True(t, time.Date(2022, time.November, 6, 14, 0, 0, 0, time.UTC).Equal(runTimes[1]))
+
+	schedule.Timezone = "America/Atlantis"
+	_, _, err = MissedRunTimes(schedule, time.Now().Add(-time.Hour), time.Now())
+	require.NotNil(t, err)
+}
+
+func TestScheduledTimeParams(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.Nil(t, err)
+
+	scheduledAt := time.Date(2022, time.November, 2, 2, 0, 0, 0, newYork)
+	params := ScheduledTimeParams(scheduledAt)
+
+	p, ok := params[ScheduledTimeParamName]
+	require.True(t, ok)
+	require.Equal(t, string(shared.StringSerialization), p.SerializationType)
+
+	val, err := base64.StdEncoding.DecodeString(p.Val)
+	require.Nil(t, err)
+	require.Equal(t, "2022-11-02T06:00:00Z", string(val))
+}
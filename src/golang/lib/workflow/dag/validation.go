@@ -16,6 +16,11 @@ var (
 	ErrUnreachableArtifact     = errors.New("The DAG has an unreachable artifact")
 	ErrUnDefinedArtifact       = errors.New("The DAG's operator edge contains an undefined artifact.")
 	ErrUnexecutableOperator    = errors.New("The DAG contains an operator whose dependencies will never be met.")
+	ErrInvalidRetryPolicy      = errors.New("The DAG contains an operator with an invalid retry policy.")
+	ErrInvalidOperatorTimeout  = errors.New("The DAG contains an operator with a negative timeout.")
+	ErrInvalidMaxParallelOps   = errors.New("The workflow's max parallel operators cannot be negative.")
+	ErrInvalidCachePolicy      = errors.New("The DAG contains an operator with an invalid cache policy.")
+	ErrInvalidRetentionPolicy  = errors.New("The workflow's retention policy is invalid.")
 
 	ValidationErrors = map[error]bool{
 		ErrNoOperator:              true,
@@ -23,6 +28,11 @@ var (
 		ErrUnreachableArtifact:     true,
 		ErrUnDefinedArtifact:       true,
 		ErrUnexecutableOperator:    true,
+		ErrInvalidRetryPolicy:      true,
+		ErrInvalidOperatorTimeout:  true,
+		ErrInvalidMaxParallelOps:   true,
+		ErrInvalidCachePolicy:      true,
+		ErrInvalidRetentionPolicy:  true,
 	}
 )
 
@@ -33,6 +43,14 @@ func Validate(
 		return ErrNoOperator
 	}
 
+	if dag.Metadata != nil && dag.Metadata.MaxParallelOperators < 0 {
+		return ErrInvalidMaxParallelOps
+	}
+
+	if dag.Metadata != nil && dag.Metadata.RetentionPolicy.Validate() != nil {
+		return ErrInvalidRetentionPolicy
+	}
+
 	// In this map, the keys are all artifact IDs that appear in the
 	// dag's edge definition, and the value is a boolean indicating
 	// whether each artifact is defined in `dag.Artifacts`.
@@ -44,6 +62,23 @@ func Validate(
 	artifactParents := make(map[uuid.UUID]bool)
 
 	for _, op := range dag.Operators {
+		if retry := op.Spec.Retry(); retry != nil {
+			if err := retry.Validate(); err != nil {
+				return ErrInvalidRetryPolicy
+			}
+		}
+
+		if op.Spec.Timeout() < 0 {
+			return ErrInvalidOperatorTimeout
+		}
+
+		if cache := op.Spec.Cache(); cache != nil {
+			// Loads have side effects, so they must run every time.
+			if op.Spec.IsLoad() || cache.Validate() != nil {
+				return ErrInvalidCachePolicy
+			}
+		}
+
 		for _, inputArtifactId := range op.Inputs {
 			artifactIdsInEdges[inputArtifactId] = false
 		}
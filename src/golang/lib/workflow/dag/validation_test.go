@@ -2,8 +2,13 @@ package dag
 
 import (
 	"testing"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/connector"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/function"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -231,4 +236,64 @@ func TestValidate(t *testing.T) {
 		undefinedArtifactDag,
 	)
 	require.Equal(t, err, ErrUnDefinedArtifact)
+
+	invalidRetryPolicyDag := generateBasicDag(t)
+	for id, op := range invalidRetryPolicyDag.Operators {
+		op.Spec = *operator.NewSpecFromFunction(function.Function{}).SetRetry(&operator.RetryConfig{MaxAttempts: 0})
+		invalidRetryPolicyDag.Operators[id] = op
+		break
+	}
+	err = Validate(
+		invalidRetryPolicyDag,
+	)
+	require.Equal(t, err, ErrInvalidRetryPolicy)
+
+	invalidTimeoutDag := generateBasicDag(t)
+	for id, op := range invalidTimeoutDag.Operators {
+		op.Spec = *operator.NewSpecFromFunction(function.Function{}).SetTimeout(-time.Second)
+		invalidTimeoutDag.Operators[id] = op
+		break
+	}
+	err = Validate(
+		invalidTimeoutDag,
+	)
+	require.Equal(t, err, ErrInvalidOperatorTimeout)
+
+	invalidMaxParallelOpsDag := generateBasicDag(t)
+	invalidMaxParallelOpsDag.Metadata = &models.Workflow{MaxParallelOperators: -1}
+	err = Validate(
+		invalidMaxParallelOpsDag,
+	)
+	require.Equal(t, err, ErrInvalidMaxParallelOps)
+
+	invalidRetentionPolicyDag := generateBasicDag(t)
+	invalidRetentionPolicyDag.Metadata = &models.Workflow{
+		RetentionPolicy: shared.RetentionPolicy{KLatestRuns: -1, MaxAgeMillisec: -1},
+	}
+	err = Validate(
+		invalidRetentionPolicyDag,
+	)
+	require.Equal(t, err, ErrInvalidRetentionPolicy)
+
+	invalidCachePolicyDag := generateBasicDag(t)
+	for id, op := range invalidCachePolicyDag.Operators {
+		op.Spec = *operator.NewSpecFromFunction(function.Function{}).SetCache(&operator.CacheConfig{TTLMillisec: 0})
+		invalidCachePolicyDag.Operators[id] = op
+		break
+	}
+	err = Validate(
+		invalidCachePolicyDag,
+	)
+	require.Equal(t, err, ErrInvalidCachePolicy)
+
+	cachedLoadDag := generateBasicDag(t)
+	for id, op := range cachedLoadDag.Operators {
+		op.Spec = *operator.NewSpecFromLoad(connector.Load{}).SetCache(&operator.CacheConfig{TTLMillisec: 1000})
+		cachedLoadDag.Operators[id] = op
+		break
+	}
+	err = Validate(
+		cachedLoadDag,
+	)
+	require.Equal(t, err, ErrInvalidCachePolicy)
 }
@@ -27,6 +27,9 @@ type WorkflowDag interface {
 	ResultID() uuid.UUID
 	Name() string
 	NotificationSettings() shared.NotificationSettings
+	// MaxParallelOperators is the maximum number of operators of this dag that are executed
+	// at the same time. There is no limit if it is 0.
+	MaxParallelOperators() int
 
 	Link() string
 	ResultLink() string
@@ -121,6 +124,14 @@ func (dag *workflowDagImpl) NotificationSettings() shared.NotificationSettings {
 	return dag.dbDAG.Metadata.NotificationSettings
 }
 
+func (dag *workflowDagImpl) MaxParallelOperators() int {
+	if dag.dbDAG.Metadata == nil {
+		return 0
+	}
+
+	return dag.dbDAG.Metadata.MaxParallelOperators
+}
+
 func (dag *workflowDagImpl) Link() string {
 	return fmt.Sprintf("%s/workflow/%s", dag.displayIP, dag.ID())
 }
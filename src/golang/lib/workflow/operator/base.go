@@ -57,6 +57,18 @@ type baseOperator struct {
 
 	// Used for dynamic resource.
 	dynamicProperties *dynamicProperties
+
+	// These fields are only set if the operator was retried.
+	// initialJobName is the job name of the first attempt, which the job names of retries are based on.
+	initialJobName string
+	// attempts are the execution states of the previous failed attempts.
+	attempts []shared.ExecutionState
+	// retryAt is the earliest time the next attempt can be launched.
+	retryAt time.Time
+
+	// resultCacheKey is only set if the operator has a cache policy. It is persisted with the
+	// result, so that later runs with the same key can reuse it.
+	resultCacheKey string
 }
 
 func (bo *baseOperator) Type() operator.Type {
@@ -135,6 +147,21 @@ func (bo *baseOperator) launch(ctx context.Context, spec job.Spec) error {
 		return errors.Newf("Cannot launch operator with state %s", bo.execState.Status)
 	}
 
+	if time.Now().Before(bo.retryAt) {
+		// The operator is still waiting for the backoff before its next attempt.
+		return nil
+	}
+
+	// Check if this operator can reuse the result of a previous published run instead of computing it again.
+	if bo.usesResultCache() {
+		reused, err := bo.reuseCachedResult(ctx)
+		if err != nil {
+			log.Errorf("Unable to check the result cache of operator %s: %v", bo.Name(), err)
+		} else if reused {
+			return nil
+		}
+	}
+
 	bo.UpdateExecState(&shared.ExecutionState{Status: shared.RunningExecutionStatus})
 
 	// Check if this operator can use previously cached results instead of computing for scratch.
@@ -352,9 +379,14 @@ func (bo *baseOperator) PersistResult(ctx context.Context) error {
 	}
 
 	// Best effort writes after this point.
+	opExecState := *execState
+	opExecState.Attempts = bo.attempts
+	if bo.resultCacheKey != "" {
+		opExecState.ResultCacheKey = bo.resultCacheKey
+	}
 	updateOperatorResultAfterComputation(
 		ctx,
-		execState,
+		&opExecState,
 		bo.resultRepo,
 		bo.resultID,
 		bo.db,
@@ -394,7 +426,133 @@ func (bo *baseOperator) Finish(ctx context.Context) {
 	}
 }
 
-func (bo *baseOperator) Cancel() {
+func (bo *baseOperator) ScheduleRetry(ctx context.Context) bool {
+	retry := bo.dbOperator.Spec.Retry()
+	if retry == nil || bo.execState.Status != shared.FailedExecutionStatus || bo.execState.FailureType == nil {
+		return false
+	}
+
+	attempt := len(bo.attempts) + 1
+	if !retry.ShouldRetry(attempt, *bo.execState.FailureType) {
+		return false
+	}
+
+	// The timestamps are shared with the next attempt's execution state, so they must be copied.
+	failedAttempt := bo.execState
+	if bo.execState.Timestamps != nil {
+		timestamps := *bo.execState.Timestamps
+		failedAttempt.Timestamps = &timestamps
+	}
+	bo.attempts = append(bo.attempts, failedAttempt)
+
+	// The metadata of the failed attempt must be removed, otherwise `Poll()` would
+	// treat the next attempt as already completed.
+	utils.CleanupStorageFile(ctx, bo.storageConfig, bo.metadataPath)
+
+	if bo.initialJobName == "" {
+		bo.initialJobName = bo.jobName
+	}
+	bo.jobName = fmt.Sprintf("%s-%d", bo.initialJobName, attempt+1)
+
+	backoff := retry.Backoff(attempt)
+	bo.retryAt = time.Now().Add(backoff)
+
+	log.Infof(
+		"Retrying operator %s in %v after failed attempt %d of %d.",
+		bo.Name(),
+		backoff,
+		attempt,
+		retry.MaxAttempts,
+	)
+
+	now := time.Now()
+	bo.execState = shared.ExecutionState{
+		Status: shared.PendingExecutionStatus,
+		Timestamps: &shared.ExecutionTimestamps{
+			PendingAt: &now,
+		},
+	}
+
+	return true
+}
+
+func (bo *baseOperator) Reuse(
+	ctx context.Context,
+	result *models.OperatorResult,
+	artifactResults map[uuid.UUID]models.ArtifactResult,
+) error {
+	if bo.execState.Status != shared.PendingExecutionStatus {
+		return errors.Newf("Cannot reuse operator with state %s", bo.execState.Status)
+	}
+
+	if result.ExecState.IsNull {
+		return errors.Newf("Operator %s cannot be reused, since the previous result has no execution state.", bo.Name())
+	}
+
+	for _, outputArtifact := range bo.outputs {
+		artifactResult, ok := artifactResults[outputArtifact.ID()]
+		if !ok {
+			return errors.Newf("Operator %s cannot be reused, since artifact %s has no previous result.", bo.Name(), outputArtifact.Name())
+		}
+
+		if err := outputArtifact.Reuse(ctx, &artifactResult); err != nil {
+			return err
+		}
+	}
+
+	// The operator is terminated, so `Poll()` returns this state without checking the job manager.
+	execState := result.ExecState.ExecutionState
+	bo.attempts = execState.Attempts
+	execState.Attempts = nil
+	bo.execState = execState
+	return nil
+}
+
+// cancelJob stops the job of this operator if it is running. This is best effort, since the job
+// may have just completed, or it may not have been launched through the job manager if its
+// results were cached.
+func (bo *baseOperator) cancelJob(ctx context.Context) {
+	if bo.execState.Status != shared.RunningExecutionStatus || bo.jobManager == nil || bo.jobName == "" {
+		return
+	}
+
+	err := bo.jobManager.Cancel(ctx, bo.jobName)
+	if err != nil && err.Code() != job.JobMissing && err.Code() != job.Noop {
+		log.Errorf("Unable to cancel job %s of operator %s: %v", bo.jobName, bo.Name(), err)
+	}
+}
+
+func (bo *baseOperator) EnforceTimeout(ctx context.Context) bool {
+	timeout := bo.dbOperator.Spec.Timeout()
+	if timeout <= 0 || bo.execState.Status != shared.RunningExecutionStatus {
+		return false
+	}
+
+	if bo.execState.Timestamps == nil || bo.execState.Timestamps.RunningAt == nil {
+		return false
+	}
+
+	if time.Since(*bo.execState.Timestamps.RunningAt) <= timeout {
+		return false
+	}
+
+	log.Infof("Operator %s exceeded its timeout of %v.", bo.Name(), timeout)
+	bo.cancelJob(ctx)
+
+	failureType := shared.UserFatalFailure
+	bo.UpdateExecState(&shared.ExecutionState{
+		Status:      shared.FailedExecutionStatus,
+		FailureType: &failureType,
+		Error: &shared.Error{
+			Context: fmt.Sprintf("Operator %s did not finish within its timeout of %v.", bo.Name(), timeout),
+			Tip:     shared.TipOperatorTimeout,
+		},
+	})
+	return true
+}
+
+func (bo *baseOperator) Cancel(ctx context.Context) {
+	bo.cancelJob(ctx)
 	bo.UpdateExecState(&shared.ExecutionState{
 		Status: shared.CanceledExecutionStatus,
 	})
@@ -46,8 +46,27 @@ type Operator interface {
 	Poll(ctx context.Context) (*shared.ExecutionState, error)
 
 	// Cancel updates the status of this operator execution if the result of the
-	// execution will not be generated. This does not persist the exec state to DB.
-	Cancel()
+	// execution will not be generated. If the operator is running, its job is stopped.
+	// This does not persist the exec state to DB.
+	Cancel(ctx context.Context)
+
+	// EnforceTimeout stops the job of this operator and marks the operator as failed if it has been
+	// running for longer than the timeout in its spec. Returns whether the operator timed out.
+	// This does not persist the exec state to DB.
+	EnforceTimeout(ctx context.Context) bool
+
+	// ScheduleRetry checks whether this operator should be run again after it failed, based on
+	// the retry policy in its spec. If so, the failed attempt is recorded, and the operator is reset
+	// to pending with a new job name. `Launch()` does not start the next attempt until the backoff
+	// of the retry policy has elapsed. Returns whether a retry was scheduled.
+	// This does not persist anything to DB.
+	ScheduleRetry(ctx context.Context) bool
+
+	// Reuse marks this operator as completed with `result` from a previous run, so that it is not
+	// executed again. `artifactResults` are the results of the same run, keyed by artifact ID, which
+	// the output artifacts of this operator are pointed to.
+	// This does not persist anything to DB.
+	Reuse(ctx context.Context, result *models.OperatorResult, artifactResults map[uuid.UUID]models.ArtifactResult) error
 
 	// Finish is an end-of-lifecycle hook meant to do any final cleanup work.
 	// Also calls Finish() on all the operator's output artifacts.
@@ -0,0 +1,140 @@
+package operator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	aq_errors "github.com/aqueducthq/aqueduct/lib/errors"
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// usesResultCache returns whether this operator can reuse the results of a previous run
+// instead of being executed. This only applies to the first attempt of published operators
+// with a cache policy.
+func (bo *baseOperator) usesResultCache() bool {
+	return bo.execMode == Publish &&
+		bo.dbOperator.Spec.Cache() != nil &&
+		bo.resultRepo != nil &&
+		len(bo.attempts) == 0
+}
+
+// computeResultCacheKey hashes everything that determines the results of this operator: the fields
+// of its spec that affect its results, the code of its function, the signatures of its output artifacts,
+// which capture the parameters and structure of the upstream DAG, and the content of its inputs.
+// It must be called once the inputs have been computed.
+func (bo *baseOperator) computeResultCacheKey(ctx context.Context) (string, error) {
+	hash := sha256.New()
+
+	rawSpec, err := json.Marshal(bo.dbOperator.Spec.ResultCacheSpec())
+	if err != nil {
+		return "", errors.Wrap(err, "Unable to serialize operator spec.")
+	}
+	hash.Write(rawSpec)
+
+	if fn := bo.dbOperator.Spec.Function(); fn != nil && fn.StoragePath != "" {
+		code, err := storage.NewStorage(bo.storageConfig).Get(ctx, fn.StoragePath)
+		if err != nil {
+			return "", errors.Wrap(err, "Unable to read operator code.")
+		}
+		hash.Write(code)
+	}
+
+	for _, output := range bo.outputs {
+		hash.Write([]byte(output.Signature().String()))
+	}
+
+	for _, input := range bo.inputs {
+		// Each input is hashed separately, so that the boundaries between inputs are preserved.
+		contentHash, err := hashContent(ctx, input)
+		if err != nil {
+			return "", errors.Wrapf(err, "Unable to read content of input %s.", input.Name())
+		}
+		hash.Write(contentHash)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashContent returns the SHA-256 hash of the content of input. The content is streamed from storage,
+// so large inputs are never held in memory.
+func hashContent(ctx context.Context, input artifact.Artifact) ([]byte, error) {
+	reader, err := input.GetContentReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return nil, err
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// reuseCachedResult looks for a succeeded result of this operator computed within the TTL of its cache
+// policy, whose cache key matches this run. Reused results are never reused again, so the TTL always
+// counts from the run that actually executed the operator. If there is one, and the content of all of its output artifacts
+// is still available, the operator is marked as completed with that result. Returns whether a cached
+// result was reused. The cache key is recorded either way, so that it is persisted with the result.
+func (bo *baseOperator) reuseCachedResult(ctx context.Context) (bool, error) {
+	for _, output := range bo.outputs {
+		if !output.ShouldPersistContent() {
+			// The content of the previous run was not kept, so there is nothing to reuse.
+			return false, nil
+		}
+	}
+
+	cacheKey, err := bo.computeResultCacheKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	bo.resultCacheKey = cacheKey
+
+	since := time.Now().Add(-bo.dbOperator.Spec.Cache().TTL())
+	opResult, err := bo.resultRepo.GetLatestSucceededByResultCacheKey(ctx, bo.ID(), cacheKey, since, bo.db)
+	if err != nil {
+		if aq_errors.Is(err, database.ErrNoRows()) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "Unable to read cached operator result.")
+	}
+
+	artifactResults := make(map[uuid.UUID]models.ArtifactResult, len(bo.outputs))
+	for _, output := range bo.outputs {
+		artifactResult, err := output.GetResult(ctx, opResult.DAGResultID)
+		if err != nil {
+			if aq_errors.Is(err, database.ErrNoRows()) {
+				return false, nil
+			}
+			return false, errors.Wrapf(err, "Unable to read cached result of artifact %s.", output.Name())
+		}
+
+		// The content may have been deleted since, e.g. by the retention policy of the workflow.
+		if artifactResult.Metadata.IsNull ||
+			!utils.ObjectExistsInStorage(ctx, bo.storageConfig, artifactResult.ContentPath) {
+			return false, nil
+		}
+
+		artifactResults[output.ID()] = *artifactResult
+	}
+
+	opResult.ExecState.CachedFromDAGResultID = &opResult.DAGResultID
+	if err := bo.Reuse(ctx, opResult, artifactResults); err != nil {
+		return false, err
+	}
+
+	log.Infof("Operator %s reused its cached result from workflow run %v.", bo.Name(), opResult.DAGResultID)
+	return true, nil
+}
@@ -0,0 +1,44 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+)
+
+// ExpiredDAGResults returns the runs in dagResults that should be removed according to policy at time now.
+// dagResults must be ordered from the most recent to the oldest run. Pinned runs and runs that are still
+// in progress are never removed, but they do count towards the policy's KLatestRuns.
+func ExpiredDAGResults(
+	policy *shared.RetentionPolicy,
+	dagResults []models.DAGResult,
+	now time.Time,
+) []models.DAGResult {
+	expired := make([]models.DAGResult, 0, len(dagResults))
+	numSucceeded := 0
+	for i, dagResult := range dagResults {
+		succeeded := dagResult.Status == shared.SucceededExecutionStatus
+		if succeeded {
+			numSucceeded++
+		}
+
+		if dagResult.Pinned ||
+			dagResult.Status == shared.PendingExecutionStatus ||
+			dagResult.Status == shared.RunningExecutionStatus {
+			continue
+		}
+
+		if succeeded && numSucceeded <= policy.KLatestSuccessfulRuns {
+			continue
+		}
+
+		beyondLatestRuns := policy.KLatestRuns != -1 && i >= policy.KLatestRuns
+		tooOld := policy.MaxAgeMillisec > 0 && now.Sub(dagResult.CreatedAt) > policy.MaxAge()
+		if beyondLatestRuns || tooOld {
+			expired = append(expired, dagResult)
+		}
+	}
+
+	return expired
+}
@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiredDAGResults(t *testing.T) {
+	now := time.Now()
+	newRun := func(age time.Duration, status shared.ExecutionStatus, pinned bool) models.DAGResult {
+		return models.DAGResult{ID: uuid.New(), CreatedAt: now.Add(-age), Status: status, Pinned: pinned}
+	}
+
+	runs := []models.DAGResult{
+		newRun(time.Minute, shared.RunningExecutionStatus, false),
+		newRun(time.Hour, shared.FailedExecutionStatus, false),
+		newRun(2*time.Hour, shared.SucceededExecutionStatus, false),
+		newRun(3*time.Hour, shared.FailedExecutionStatus, true),
+		newRun(4*time.Hour, shared.SucceededExecutionStatus, false),
+		newRun(5*time.Hour, shared.FailedExecutionStatus, false),
+	}
+
+	type test struct {
+		name     string
+		policy   shared.RetentionPolicy
+		expected []models.DAGResult
+	}
+
+	tests := []test{
+		{
+			name:     "keep all runs",
+			policy:   shared.RetentionPolicy{KLatestRuns: -1},
+			expected: []models.DAGResult{},
+		},
+		{
+			name:     "keep latest runs",
+			policy:   shared.RetentionPolicy{KLatestRuns: 2},
+			expected: []models.DAGResult{runs[2], runs[4], runs[5]},
+		},
+		{
+			name:     "remove every run that is not in progress or pinned",
+			policy:   shared.RetentionPolicy{KLatestRuns: 0},
+			expected: []models.DAGResult{runs[1], runs[2], runs[4], runs[5]},
+		},
+		{
+			name:     "max age",
+			policy:   shared.RetentionPolicy{KLatestRuns: -1, MaxAgeMillisec: (150 * time.Minute).Milliseconds()},
+			expected: []models.DAGResult{runs[4], runs[5]},
+		},
+		{
+			name:     "max age and latest runs",
+			policy:   shared.RetentionPolicy{KLatestRuns: 5, MaxAgeMillisec: (90 * time.Minute).Milliseconds()},
+			expected: []models.DAGResult{runs[2], runs[4], runs[5]},
+		},
+		{
+			name:     "keep latest successful runs",
+			policy:   shared.RetentionPolicy{KLatestRuns: 0, KLatestSuccessfulRuns: 1},
+			expected: []models.DAGResult{runs[1], runs[4], runs[5]},
+		},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.expected, ExpiredDAGResults(&tc.policy, runs, now), tc.name)
+	}
+}
@@ -42,6 +42,7 @@ func WriteDAGToDatabase(
 			&dag.Metadata.Schedule,
 			&dag.Metadata.RetentionPolicy,
 			&dag.Metadata.NotificationSettings,
+			dag.Metadata.MaxParallelOperators,
 			DB,
 		)
 		if err != nil {
@@ -347,6 +348,8 @@ func UpdateWorkflowDagToLatest(
 // specified DAGResult. If the workflow run failed or was canceled, it
 // also updates pending and running operator and artifact results to
 // canceled. It also creates the relevant notification(s).
+// The DAGResult is only updated if it is still in progress, so that a run that was
+// canceled is not marked as finished, and vice versa. Otherwise, database.ErrNoRows() is returned.
 func UpdateDAGResultMetadata(
 	ctx context.Context,
 	dagResultID uuid.UUID,
@@ -369,7 +372,7 @@ func UpdateDAGResultMetadata(
 		models.DAGResultExecState: execState,
 	}
 
-	dagResult, err := dagResultRepo.Update(
+	dagResult, err := dagResultRepo.UpdateInProgress(
 		ctx,
 		dagResultID,
 		changes,
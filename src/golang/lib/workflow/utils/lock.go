@@ -1,11 +1,24 @@
 package utils
 
-import "github.com/dropbox/godropbox/sys/filelock"
+import (
+	"fmt"
+
+	"github.com/dropbox/godropbox/sys/filelock"
+)
 
 // executionLock is the name of the shared filelock for blocking workflow run execution
 const executionLock = "ExecutionLock"
 
+// operatorSlotLock is the name prefix of the shared filelocks that bound the number of
+// operators executed at the same time across processes.
+const operatorSlotLock = "OperatorSlot"
+
 // NewExecutionLock returns a new workflow execution mutex
 func NewExecutionLock() *filelock.FileLock {
 	return filelock.New(executionLock)
 }
+
+// NewOperatorSlotLock returns a new mutex for the operator execution slot `slot`.
+func NewOperatorSlotLock(slot int) *filelock.FileLock {
+	return filelock.New(fmt.Sprintf("%s-%d", operatorSlotLock, slot))
+}
@@ -4,14 +4,17 @@ import (
 	"context"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/aqueducthq/aqueduct/lib/repos"
 	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/webhook"
 	log "github.com/sirupsen/logrus"
 )
 
 // MigrateVault migrates all vault content from `oldVault` to `newVault`.
 // This includes:
 //   - resource credentials
+//   - webhook secrets of workflows
 //
 // It also returns the names of all the keys that have been migrated to `newVault`.
 // It is the responsibility of the caller to delete the keys if necessary.
@@ -21,6 +24,7 @@ func MigrateVault(
 	newVault vault.Vault,
 	orgID string,
 	resourceRepo repos.Resource,
+	workflowRepo repos.Workflow,
 	DB database.Database,
 ) ([]string, error) {
 	resources, err := resourceRepo.GetByOrg(ctx, orgID, DB)
@@ -52,5 +56,29 @@ func MigrateVault(
 		keys = append(keys, key)
 	}
 
+	webhookWorkflows, err := workflowRepo.GetByScheduleTrigger(ctx, shared.WebhookUpdateTrigger, DB)
+	if err != nil {
+		return nil, err
+	}
+
+	// For each webhook-triggered workflow, migrate its webhook secret
+	for _, workflowDB := range webhookWorkflows {
+		key := webhook.SecretVaultKey(workflowDB.ID)
+
+		val, err := oldVault.Get(ctx, key)
+		if err != nil {
+			// The secret is only generated once it is requested, so it may not exist.
+			log.Warnf("Unable to get webhook secret of workflow %v from old vault at path %s: %v", workflowDB.ID, key, err)
+			continue
+		}
+
+		if err := newVault.Put(ctx, key, val); err != nil {
+			log.Errorf("Unable to write webhook secret of workflow %v to new vault at path %s: %v", workflowDB.ID, key, err)
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
 	return keys, nil
 }
@@ -46,3 +46,18 @@ func ReadFromStorage(ctx context.Context, storageConfig *shared.StorageConfig, p
 
 	return nil
 }
+
+func WriteToStorage(ctx context.Context, storageConfig *shared.StorageConfig, path string, payload interface{}) error {
+	// Serialize `payload` and write it to storage
+	serializedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "Unable to marshal json payload")
+	}
+
+	err = storage.NewStorage(storageConfig).Put(ctx, path, serializedPayload)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to put object to %s storage at path %s.", storageConfig.Type, path)
+	}
+
+	return nil
+}
@@ -23,11 +23,17 @@ const (
 // If the Workflow exists, its ID should be provided as workflowID; otherwise,
 // workflowID can be ignored.
 // The following conditions are not allowed:
-// 1. Having a CascadingUpdateTrigger where SourceID is for a Workflow that is
+// 1. Having a CascadingUpdateTrigger where a source is a Workflow that is
 // running on a non self-orchestrated engine, such as Airflow. This is not allowed
 // since Aqueduct cannot trigger Workflow runs at the end of execution on an
 // engine that is not self-orchestrated.
 // 2. Having a CascadingUpdateTrigger that creates a cycle amongst the cascading workflows.
+// 3. Having a WebhookUpdateTrigger for a Workflow running on Airflow, or one that maps
+// a parameter to an empty field path.
+// 4. Having a timezone that is not a valid IANA timezone, a blackout window that
+// ends before it starts, or an unsupported catch-up policy. None of these are supported
+// for a Workflow running on Airflow.
+// 5. Having an unsupported overlap policy, or any overlap policy for a Workflow running on Airflow.
 // It returns an HTTP status code and a client-friendly error, if any.
 func ValidateSchedule(
 	ctx context.Context,
@@ -42,37 +48,62 @@ func ValidateSchedule(
 	workflowRepo repos.Workflow,
 	DB database.Database,
 ) (int, error) {
+	if code, err := validateCalendar(schedule, engineType); err != nil {
+		return code, err
+	}
+
+	if code, err := validateOverlapPolicy(schedule, engineType); err != nil {
+		return code, err
+	}
+
+	if schedule.Trigger == shared.WebhookUpdateTrigger {
+		return validateWebhook(schedule, engineType)
+	}
+
 	if schedule.Trigger != shared.CascadingUpdateTrigger {
-		// Only CascadingUpdateTriggers require validation
+		// Only CascadingUpdateTriggers and WebhookUpdateTriggers require validation
 		return http.StatusOK, nil
 	}
 
-	exists, err := workflowRepo.Exists(ctx, schedule.SourceID, DB)
-	if err != nil {
-		return http.StatusInternalServerError, errors.Wrap(err, internalValidationErrMsg)
+	sourceIDs := schedule.Sources()
+	if len(sourceIDs) == 0 {
+		return http.StatusBadRequest, errors.New("A cascading trigger must have at least one source Workflow.")
 	}
 
-	if !exists {
-		return http.StatusBadRequest, errors.New("The specified source Workflow does not exist.")
+	if schedule.CascadeMode != "" &&
+		schedule.CascadeMode != shared.AnyCascadeMode &&
+		schedule.CascadeMode != shared.AllCascadeMode {
+		return http.StatusBadRequest, errors.Newf("Unsupported cascade mode %s.", schedule.CascadeMode)
 	}
 
-	sourceDAG, err := utils.ReadLatestDAGFromDatabase(
-		ctx,
-		schedule.SourceID,
-		workflowRepo,
-		dagRepo,
-		operatorRepo,
-		artifactRepo,
-		dagEdgeRepo,
-		DB,
-	)
-	if err != nil {
-		return http.StatusInternalServerError, errors.Wrap(err, internalValidationErrMsg)
-	}
+	for _, sourceID := range sourceIDs {
+		exists, err := workflowRepo.Exists(ctx, sourceID, DB)
+		if err != nil {
+			return http.StatusInternalServerError, errors.Wrap(err, internalValidationErrMsg)
+		}
+
+		if !exists {
+			return http.StatusBadRequest, errors.New("The specified source Workflow does not exist.")
+		}
 
-	// Condition 1
-	if sourceDAG.EngineConfig.Type == shared.AirflowEngineType {
-		return http.StatusBadRequest, errors.New("Cannot use Workflows running on Airflow for the source.")
+		sourceDAG, err := utils.ReadLatestDAGFromDatabase(
+			ctx,
+			sourceID,
+			workflowRepo,
+			dagRepo,
+			operatorRepo,
+			artifactRepo,
+			dagEdgeRepo,
+			DB,
+		)
+		if err != nil {
+			return http.StatusInternalServerError, errors.Wrap(err, internalValidationErrMsg)
+		}
+
+		// Condition 1
+		if sourceDAG.EngineConfig.Type == shared.AirflowEngineType {
+			return http.StatusBadRequest, errors.New("Cannot use Workflows running on Airflow for the source.")
+		}
 	}
 
 	// Condition 2
@@ -95,28 +126,114 @@ func ValidateSchedule(
 		return http.StatusInternalServerError, errors.Wrap(err, internalValidationErrMsg)
 	}
 
-	if hasCycle := checkForCycle(workflowID, schedule.SourceID, cascadingWorkflows); hasCycle {
+	if hasCycle := checkForCycle(workflowID, sourceIDs, cascadingWorkflows); hasCycle {
 		return http.StatusBadRequest, errors.New("Cannot allow cycles for cascading workflows.")
 	}
 
 	return http.StatusOK, nil
 }
 
-// checkForCycle returns true if setting workflowID's source workflow to sourceID would
+// checkForCycle returns true if setting workflowID's source workflows to sourceIDs would
 // result in a cycle.
-func checkForCycle(workflowID uuid.UUID, sourceID uuid.UUID, targetWorkflows []models.Workflow) bool {
+func checkForCycle(workflowID uuid.UUID, sourceIDs []uuid.UUID, targetWorkflows []models.Workflow) bool {
 	gph := graph.NewDirected()
+	gph.AddNode(workflowID)
+	for _, sourceID := range sourceIDs {
+		gph.AddNode(sourceID)
+	}
+
 	for _, targetWorkflow := range targetWorkflows {
 		gph.AddNode(targetWorkflow.ID)
-		gph.AddNode(targetWorkflow.Schedule.SourceID)
+		for _, targetSourceID := range targetWorkflow.Schedule.Sources() {
+			gph.AddNode(targetSourceID)
+
+			if targetWorkflow.ID != workflowID {
+				// We don't add edges for workflowID to its current source Workflows,
+				// since those will be overwritten by the new sources anyways.
+				gph.AddEdge(targetSourceID, targetWorkflow.ID)
+			}
+		}
+	}
 
-		if targetWorkflow.ID != workflowID {
-			// We don't add an edge for workflowID to its current source Workflow,
-			// since that will be overwritten by the new source anyways.
-			gph.AddEdge(targetWorkflow.Schedule.SourceID, targetWorkflow.ID)
+	// There is a cycle if there exists a path from workflowID to any of sourceIDs
+	for _, sourceID := range sourceIDs {
+		if gph.HasPath(workflowID, sourceID) {
+			return true
 		}
 	}
 
-	// There is a cycle if there exists a path from workflowID to sourceID
-	return gph.HasPath(workflowID, sourceID)
+	return false
+}
+
+// validateWebhook checks condition 3 of ValidateSchedule.
+func validateWebhook(schedule shared.Schedule, engineType shared.EngineType) (int, error) {
+	if engineType == shared.AirflowEngineType {
+		return http.StatusBadRequest, errors.New("Webhook triggers are not supported for Workflows running on Airflow.")
+	}
+
+	if schedule.Webhook == nil {
+		return http.StatusOK, nil
+	}
+
+	for name, path := range schedule.Webhook.ParamFields {
+		if path == "" {
+			return http.StatusBadRequest, errors.Newf("The webhook field of parameter %s cannot be empty.", name)
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// validateCalendar checks condition 4 of ValidateSchedule.
+func validateCalendar(schedule shared.Schedule, engineType shared.EngineType) (int, error) {
+	if schedule.Timezone == "" && len(schedule.BlackoutWindows) == 0 && schedule.CatchUpPolicy == "" {
+		return http.StatusOK, nil
+	}
+
+	if engineType == shared.AirflowEngineType {
+		return http.StatusBadRequest, errors.New(
+			"Timezones, blackout windows and catch-up policies are not supported for Workflows running on Airflow.",
+		)
+	}
+
+	switch schedule.CatchUpPolicy {
+	case "", shared.NoneCatchUpPolicy, shared.LatestCatchUpPolicy, shared.AllCatchUpPolicy:
+	default:
+		return http.StatusBadRequest, errors.Newf("Unsupported catch-up policy %s.", schedule.CatchUpPolicy)
+	}
+
+	if _, err := schedule.Location(); err != nil {
+		return http.StatusBadRequest, errors.Newf("Invalid timezone %s.", schedule.Timezone)
+	}
+
+	for _, window := range schedule.BlackoutWindows {
+		if !window.Start.Before(window.End) {
+			return http.StatusBadRequest, errors.Newf(
+				"The blackout window %s must end after it starts.",
+				window.Name,
+			)
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// validateOverlapPolicy checks condition 5 of ValidateSchedule.
+func validateOverlapPolicy(schedule shared.Schedule, engineType shared.EngineType) (int, error) {
+	switch schedule.OverlapPolicy {
+	case "":
+		return http.StatusOK, nil
+	case shared.AllowOverlapPolicy,
+		shared.SkipOverlapPolicy,
+		shared.QueueOverlapPolicy,
+		shared.CancelPreviousOverlapPolicy:
+	default:
+		return http.StatusBadRequest, errors.Newf("Unsupported overlap policy %s.", schedule.OverlapPolicy)
+	}
+
+	if engineType == shared.AirflowEngineType {
+		return http.StatusBadRequest, errors.New("Overlap policies are not supported for Workflows running on Airflow.")
+	}
+
+	return http.StatusOK, nil
 }
@@ -1,9 +1,12 @@
 package workflow
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/models"
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -46,7 +49,102 @@ func TestCheckForCycle(t *testing.T) {
 		require.Equal(
 			t,
 			tc.formsCycle,
-			checkForCycle(tc.workflowID, tc.sourceID, targetWorkflows),
+			checkForCycle(tc.workflowID, []uuid.UUID{tc.sourceID}, targetWorkflows),
 		)
 	}
+
+	// Workflow C also depends on a new Workflow E, while B gets multiple sources.
+	workflowE := models.Workflow{ID: uuid.New()}
+	workflowC.Schedule.SourceID = uuid.Nil
+	workflowC.Schedule.SourceIDs = []uuid.UUID{workflowA.ID, workflowE.ID}
+	targetWorkflows = []models.Workflow{workflowA, workflowB, workflowC, workflowD, workflowE}
+
+	require.False(t, checkForCycle(workflowB.ID, []uuid.UUID{workflowA.ID, workflowC.ID}, targetWorkflows))
+	require.False(t, checkForCycle(workflowE.ID, []uuid.UUID{workflowA.ID, workflowD.ID}, targetWorkflows))
+	require.True(t, checkForCycle(workflowE.ID, []uuid.UUID{workflowD.ID, workflowC.ID}, targetWorkflows))
+}
+
+func TestValidateWebhook(t *testing.T) {
+	schedule := shared.Schedule{
+		Trigger: shared.WebhookUpdateTrigger,
+		Webhook: &shared.WebhookConfig{
+			ParamFields: map[string]string{"branch": "run.branch"},
+		},
+	}
+
+	statusCode, err := validateWebhook(schedule, shared.AqueductEngineType)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, err = validateWebhook(schedule, shared.AirflowEngineType)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	schedule.Webhook.ParamFields["sha"] = ""
+	statusCode, err = validateWebhook(schedule, shared.AqueductEngineType)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestValidateCalendar(t *testing.T) {
+	start := time.Date(2022, time.December, 25, 0, 0, 0, 0, time.UTC)
+	schedule := shared.Schedule{
+		Trigger:      shared.PeriodicUpdateTrigger,
+		CronSchedule: "0 9 * * *",
+		Timezone:     "America/New_York",
+		BlackoutWindows: []shared.BlackoutWindow{
+			{Name: "christmas", Start: start, End: start.Add(24 * time.Hour)},
+		},
+	}
+
+	statusCode, err := validateCalendar(schedule, shared.AqueductEngineType)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, err = validateCalendar(schedule, shared.AirflowEngineType)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	schedule.Timezone = "America/Atlantis"
+	statusCode, err = validateCalendar(schedule, shared.AqueductEngineType)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	schedule.Timezone = ""
+	schedule.BlackoutWindows[0].End = start
+	statusCode, err = validateCalendar(schedule, shared.AqueductEngineType)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	schedule.BlackoutWindows = nil
+	schedule.CatchUpPolicy = shared.AllCatchUpPolicy
+	statusCode, err = validateCalendar(schedule, shared.AqueductEngineType)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	schedule.CatchUpPolicy = "sometimes"
+	statusCode, err = validateCalendar(schedule, shared.AqueductEngineType)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestValidateOverlapPolicy(t *testing.T) {
+	schedule := shared.Schedule{
+		Trigger:       shared.PeriodicUpdateTrigger,
+		CronSchedule:  "0 * * * *",
+		OverlapPolicy: shared.QueueOverlapPolicy,
+	}
+
+	statusCode, err := validateOverlapPolicy(schedule, shared.AqueductEngineType)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, err = validateOverlapPolicy(schedule, shared.AirflowEngineType)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, statusCode)
+
+	schedule.OverlapPolicy = "sometimes"
+	statusCode, err = validateOverlapPolicy(schedule, shared.AqueductEngineType)
+	require.NotNil(t, err)
+	require.Equal(t, http.StatusBadRequest, statusCode)
 }
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	// SignaturePrefix is prepended to the hex-encoded HMAC-SHA256 of the signed payload
+	// in the signature header of webhook requests.
+	SignaturePrefix = "sha256="
+
+	// TimestampTolerance is how far the timestamp of a webhook request may be from the
+	// current time. Requests outside of it are rejected, so that a captured request
+	// cannot be replayed later.
+	TimestampTolerance = 5 * time.Minute
+
+	secretKey   = "secret"
+	secretBytes = 32
+)
+
+// SecretVaultKey returns the key of the webhook secret of the workflow in the vault.
+func SecretVaultKey(workflowID uuid.UUID) string {
+	return fmt.Sprintf("webhook-%s", workflowID)
+}
+
+// RotateSecret generates a new webhook secret for the workflow and stores it in the vault,
+// replacing the previous one. It returns the new secret.
+func RotateSecret(ctx context.Context, workflowID uuid.UUID, vaultObject vault.Vault) (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "Unable to generate webhook secret.")
+	}
+
+	secret := hex.EncodeToString(buf)
+	if err := vaultObject.Put(ctx, SecretVaultKey(workflowID), map[string]string{secretKey: secret}); err != nil {
+		return "", errors.Wrap(err, "Unable to store webhook secret.")
+	}
+
+	return secret, nil
+}
+
+// ReadSecret returns the webhook secret of the workflow from the vault.
+func ReadSecret(ctx context.Context, workflowID uuid.UUID, vaultObject vault.Vault) (string, error) {
+	secrets, err := vaultObject.Get(ctx, SecretVaultKey(workflowID))
+	if err != nil {
+		return "", errors.Wrap(err, "Unable to read webhook secret.")
+	}
+
+	secret, ok := secrets[secretKey]
+	if !ok || secret == "" {
+		return "", errors.New("The webhook secret is empty.")
+	}
+
+	return secret, nil
+}
+
+// Sign returns the signature of body sent at timestamp with secret, in the format expected
+// in the signature header. The timestamp is the number of seconds since the Unix epoch, as sent
+// in the timestamp header, and the signed payload is the timestamp followed by "." and the body.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature returns whether signature is a valid signature of body sent at timestamp
+// with secret, and whether timestamp is within TimestampTolerance of now.
+func VerifySignature(secret string, timestamp string, body []byte, signature string, now time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	skew := now.Sub(time.Unix(seconds, 0))
+	if skew > TimestampTolerance || skew < -TimestampTolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// ExtractParams maps fields of the JSON body of a webhook request to workflow parameters, as specified
+// by paramFields. Each field is given by its dot-separated path in the body. String fields are passed
+// as string parameters and all other fields as JSON parameters. It errors if a field is missing.
+func ExtractParams(body []byte, paramFields map[string]string) (map[string]param.Param, error) {
+	if len(paramFields) == 0 {
+		return nil, nil
+	}
+
+	var payload interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// Numbers are kept as they were sent, so that large integers are not rounded.
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, errors.Wrap(err, "The webhook body is not valid JSON.")
+	}
+
+	params := make(map[string]param.Param, len(paramFields))
+	for name, path := range paramFields {
+		value, err := lookupField(payload, path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to extract parameter %s.", name)
+		}
+
+		if str, ok := value.(string); ok {
+			params[name] = param.Param{
+				Val:               base64.StdEncoding.EncodeToString([]byte(str)),
+				SerializationType: string(shared.StringSerialization),
+			}
+			continue
+		}
+
+		serialized, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to serialize parameter %s.", name)
+		}
+		params[name] = param.Param{
+			Val:               base64.StdEncoding.EncodeToString(serialized),
+			SerializationType: string(shared.JsonSerialization),
+		}
+	}
+
+	return params, nil
+}
+
+func lookupField(payload interface{}, path string) (interface{}, error) {
+	value := payload
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.Newf("Field %s is not an object.", path)
+		}
+
+		value, ok = object[key]
+		if !ok {
+			return nil, errors.Newf("Field %s is missing from the webhook body.", path)
+		}
+	}
+
+	return value, nil
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/models/shared"
+	"github.com/aqueducthq/aqueduct/lib/models/shared/operator/param"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timestamp := "1700000000"
+	body := []byte(`{"run_id": 12}`)
+	signature := Sign("secret", timestamp, body)
+
+	require.True(t, VerifySignature("secret", timestamp, body, signature, now))
+	require.True(t, VerifySignature("secret", timestamp, body, signature, now.Add(TimestampTolerance)))
+	require.False(t, VerifySignature("other-secret", timestamp, body, signature, now))
+	require.False(t, VerifySignature("secret", timestamp, []byte(`{"run_id": 13}`), signature, now))
+	require.False(t, VerifySignature("secret", timestamp, body, "", now))
+
+	// The timestamp is part of the signed payload, so it cannot be changed to replay a request.
+	require.False(t, VerifySignature("secret", "1700000060", body, signature, now))
+
+	// Requests outside of the tolerance are rejected even if the signature is valid.
+	require.False(t, VerifySignature("secret", timestamp, body, signature, now.Add(TimestampTolerance+time.Second)))
+	require.False(t, VerifySignature("secret", timestamp, body, signature, now.Add(-TimestampTolerance-time.Second)))
+
+	require.False(t, VerifySignature("secret", "", body, Sign("secret", "", body), now))
+	require.False(t, VerifySignature("secret", "yesterday", body, Sign("secret", "yesterday", body), now))
+}
+
+func TestExtractParams(t *testing.T) {
+	body := []byte(`{"run": {"id": 12345678901234567890, "branch": "main"}, "tags": ["a", "b"]}`)
+
+	params, err := ExtractParams(body, map[string]string{
+		"run_id": "run.id",
+		"branch": "run.branch",
+		"tags":   "tags",
+	})
+	require.Nil(t, err)
+
+	encode := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+	require.Equal(t, map[string]param.Param{
+		"run_id": {Val: encode("12345678901234567890"), SerializationType: string(shared.JsonSerialization)},
+		"branch": {Val: encode("main"), SerializationType: string(shared.StringSerialization)},
+		"tags":   {Val: encode(`["a","b"]`), SerializationType: string(shared.JsonSerialization)},
+	}, params)
+
+	_, err = ExtractParams(body, map[string]string{"sha": "run.sha"})
+	require.NotNil(t, err)
+
+	_, err = ExtractParams(body, map[string]string{"branch": "run.branch.name"})
+	require.NotNil(t, err)
+
+	_, err = ExtractParams([]byte(`not json`), map[string]string{"branch": "run.branch"})
+	require.NotNil(t, err)
+
+	// Without any mapped fields, the body does not have to be JSON.
+	params, err = ExtractParams([]byte(`not json`), nil)
+	require.Nil(t, err)
+	require.Empty(t, params)
+}
@@ -61,6 +61,11 @@ class S3Config(models.BaseConfig):
 
     use_as_storage: str = ""
 
+    # These override the AWS endpoint, to connect to S3-compatible services like MinIO.
+    endpoint_url: str = ""
+    force_path_style: str = "false"
+    skip_tls_verify: str = "false"
+
 
 class AthenaConfig(models.BaseConfig):
     # default type to ACCESS_KEY mainly for backward compatibility
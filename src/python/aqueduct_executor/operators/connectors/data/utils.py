@@ -1,9 +1,10 @@
 import os
 import urllib.parse
 import uuid
-from typing import Union
+from typing import Any, Dict, Union
 
 import boto3
+from botocore.config import Config as BotoConfig
 from aqueduct_executor.operators.connectors.data.config import (
     AthenaConfig,
     AWSCredentialType,
@@ -72,5 +73,20 @@ def construct_boto_session(config: Union[S3Config, AthenaConfig]) -> boto3.sessi
         raise Exception("Unsupported resource config type: %s" % config.type)
 
 
+def s3_endpoint_kwargs(config: S3Config) -> Dict[str, Any]:
+    """
+    returns the arguments of a boto S3 client or resource that override the AWS endpoint,
+    to connect to S3-compatible services like MinIO.
+    """
+    kwargs: Dict[str, Any] = {}
+    if config.endpoint_url:
+        kwargs["endpoint_url"] = config.endpoint_url
+    if config.skip_tls_verify == "true":
+        kwargs["verify"] = False
+    if config.force_path_style == "true":
+        kwargs["config"] = BotoConfig(s3={"addressing_style": "path"})
+    return kwargs
+
+
 def url_encode(value: str) -> str:
     return urllib.parse.quote_plus(value)
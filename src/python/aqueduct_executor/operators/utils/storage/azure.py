@@ -0,0 +1,42 @@
+from typing import Any
+
+from aqueduct_executor.operators.utils.storage.config import AzureStorageConfig
+from aqueduct_executor.operators.utils.storage.storage import Storage
+from azure.storage.blob import BlobServiceClient
+
+
+class AzureStorage(Storage):
+    _client: Any  # Azure container client
+    _config: AzureStorageConfig
+
+    def __init__(self, config: AzureStorageConfig):
+        if config.connection_string:
+            service_client = BlobServiceClient.from_connection_string(config.connection_string)
+        else:
+            account_url = config.account_url or f"https://{config.account_name}.blob.core.windows.net"
+            credential: Any = config.sas_token
+            if config.account_key:
+                credential = {"account_name": config.account_name, "account_key": config.account_key}
+            service_client = BlobServiceClient(account_url=account_url, credential=credential)
+
+        self._client = service_client.get_container_client(config.container)
+        self._config = config
+
+    def put(self, key: str, value: bytes) -> None:
+        key = self._resolve_full_key(key)
+        print(f"writing to azure: {key}")
+        self._client.upload_blob(name=key, data=value, overwrite=True)
+
+    def get(self, key: str) -> bytes:
+        key = self._resolve_full_key(key)
+        print(f"reading from azure: {key}")
+        return bytes(self._client.download_blob(key).readall())
+
+    def exists(self, key: str) -> bool:
+        key = self._resolve_full_key(key)
+        print(f"checking if exists in azure: {key}")
+        return bool(self._client.get_blob_client(key).exists())
+
+    def _resolve_full_key(self, key: str) -> str:
+        # The prefix is expected to be in the format "path/to/dir/", or empty.
+        return self._config.prefix + key
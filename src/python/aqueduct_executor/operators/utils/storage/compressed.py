@@ -0,0 +1,103 @@
+import gzip
+import hashlib
+import struct
+
+import zstandard
+from aqueduct_executor.operators.utils.storage.config import CompressionAlgorithm
+from aqueduct_executor.operators.utils.storage.storage import Storage
+
+# An object written by CompressedStorage is framed as follows:
+#
+#   | magic (4 bytes) | algorithm (1 byte) | payload | uncompressed size (8 bytes) | SHA-256 (32 bytes) |
+#
+# The payload is the content compressed with the algorithm, and the trailer describes the
+# uncompressed content. This must be kept in sync with `lib/storage/compressed.go`.
+_FRAME_MAGIC = b"\x89AQS"
+_FRAME_HEADER_SIZE = len(_FRAME_MAGIC) + 1
+_FRAME_TRAILER_SIZE = 8 + hashlib.sha256().digest_size
+
+_FRAME_ALGORITHMS = {
+    CompressionAlgorithm.NONE: 0,
+    CompressionAlgorithm.GZIP: 1,
+    CompressionAlgorithm.ZSTD: 2,
+}
+
+
+class ObjectCorruptedError(Exception):
+    """Raised when the content read from storage does not match its checksum."""
+
+
+class CompressedStorage(Storage):
+    """
+    Compresses and checksums objects before they are written to the underlying storage,
+    and decompresses and verifies them on every read. Objects that are not framed,
+    e.g. because they were written before compression was enabled, are read as is.
+    """
+
+    _storage: Storage
+    _algorithm: CompressionAlgorithm
+
+    def __init__(self, storage: Storage, algorithm: CompressionAlgorithm):
+        self._storage = storage
+        self._algorithm = algorithm
+
+    def put(self, key: str, value: bytes) -> None:
+        self._storage.put(key, frame(value, self._algorithm))
+
+    def get(self, key: str) -> bytes:
+        try:
+            return unframe(self._storage.get(key))
+        except ObjectCorruptedError as e:
+            raise ObjectCorruptedError("Object %s in storage is corrupted: %s" % (key, e))
+
+    def exists(self, key: str) -> bool:
+        return self._storage.exists(key)
+
+
+def frame(content: bytes, algorithm: CompressionAlgorithm) -> bytes:
+    if algorithm == CompressionAlgorithm.GZIP:
+        payload = gzip.compress(content)
+    elif algorithm == CompressionAlgorithm.ZSTD:
+        payload = zstandard.ZstdCompressor().compress(content)
+    else:
+        payload = content
+
+    header = _FRAME_MAGIC + bytes([_FRAME_ALGORITHMS[algorithm]])
+    trailer = struct.pack(">Q", len(content)) + hashlib.sha256(content).digest()
+    return header + payload + trailer
+
+
+def unframe(stored: bytes) -> bytes:
+    if len(stored) < _FRAME_HEADER_SIZE or not stored.startswith(_FRAME_MAGIC):
+        return stored
+
+    if len(stored) < _FRAME_HEADER_SIZE + _FRAME_TRAILER_SIZE:
+        raise ObjectCorruptedError("The object is truncated.")
+
+    algorithm = stored[len(_FRAME_MAGIC)]
+    payload = stored[_FRAME_HEADER_SIZE:-_FRAME_TRAILER_SIZE]
+    trailer = stored[-_FRAME_TRAILER_SIZE:]
+
+    try:
+        if algorithm == _FRAME_ALGORITHMS[CompressionAlgorithm.NONE]:
+            content = payload
+        elif algorithm == _FRAME_ALGORITHMS[CompressionAlgorithm.GZIP]:
+            content = gzip.decompress(payload)
+        elif algorithm == _FRAME_ALGORITHMS[CompressionAlgorithm.ZSTD]:
+            # The content size is not always recorded in the zstd frame, so it is decompressed as a stream.
+            content = zstandard.ZstdDecompressor().decompressobj().decompress(payload)
+        else:
+            raise ObjectCorruptedError("Unknown compression algorithm %d." % algorithm)
+    except (OSError, EOFError, zstandard.ZstdError) as e:
+        raise ObjectCorruptedError("Unable to decompress content: %s" % e)
+
+    (size,) = struct.unpack(">Q", trailer[:8])
+    if size != len(content):
+        raise ObjectCorruptedError(
+            "Expected %d bytes of content but read %d." % (size, len(content))
+        )
+
+    if hashlib.sha256(content).digest() != trailer[8:]:
+        raise ObjectCorruptedError("The checksum of the content does not match.")
+
+    return content
@@ -1,5 +1,5 @@
 from enum import Enum
-from typing import Optional
+from typing import Dict, Optional
 
 from aqueduct_executor.operators.utils.enums import MetaEnum
 from pydantic import BaseModel
@@ -9,6 +9,28 @@ class StorageType(str, Enum, metaclass=MetaEnum):
     S3 = "s3"
     File = "file"
     GCS = "gcs"
+    Azure = "azure"
+
+
+class CompressionAlgorithm(str, Enum, metaclass=MetaEnum):
+    NONE = "none"
+    GZIP = "gzip"
+    ZSTD = "zstd"
+
+
+class CompressionConfig(BaseModel):
+    algorithm: CompressionAlgorithm
+
+
+class EncryptionConfig(BaseModel):
+    per_workflow_keys: bool = False
+    # The scope of the data keys that new objects are encrypted with. If empty, the data keys are shared.
+    key_scope: str = ""
+    # The unwrapped data keys by key ID, base64-encoded. They are set by the server in the job spec,
+    # since the master key that wraps them never leaves the server.
+    data_keys: Dict[str, str] = {}
+    # The ID of the data key that new objects are encrypted with.
+    write_key_id: str
 
 
 class FileStorageConfig(BaseModel):
@@ -27,14 +49,39 @@ class S3StorageConfig(BaseModel):
     aws_access_key_id: str = ""
     aws_secret_access_key: str = ""
 
+    # These override the AWS endpoint, to connect to S3-compatible services like MinIO.
+    endpoint_url: str = ""
+    force_path_style: bool = False
+    skip_tls_verify: bool = False
+
 
 class GCSStorageConfig(BaseModel):
     bucket: str
     service_account_credentials: str
 
 
+class AzureStorageConfig(BaseModel):
+    account_url: str = ""
+    container: str
+
+    # Expected to be in the format "path/to/dir/" (without a leading slash, but with a trailing one).
+    prefix: str = ""
+
+    connection_string: str = ""
+    account_name: str = ""
+    account_key: str = ""
+    sas_token: str = ""
+
+
 class StorageConfig(BaseModel):
     type: StorageType
     file_config: Optional[FileStorageConfig] = None
     s3_config: Optional[S3StorageConfig] = None
     gcs_config: Optional[GCSStorageConfig] = None
+    azure_config: Optional[AzureStorageConfig] = None
+
+    # If set, objects are compressed and checksummed before they are written,
+    # and verified on every read.
+    compression: Optional[CompressionConfig] = None
+    # If set, objects are encrypted before they are written.
+    encryption: Optional[EncryptionConfig] = None
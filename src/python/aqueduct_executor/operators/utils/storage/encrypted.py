@@ -0,0 +1,122 @@
+import os
+import struct
+from typing import Dict
+
+from aqueduct_executor.operators.utils.storage.compressed import ObjectCorruptedError
+from aqueduct_executor.operators.utils.storage.storage import Storage
+from cryptography.exceptions import InvalidTag
+from cryptography.hazmat.primitives.ciphers.aead import AESGCM
+
+# An object written by EncryptedStorage is framed as follows:
+#
+#   | magic (4 bytes) | version (1 byte) | key ID length (2 bytes) | key ID | nonce (12 bytes) | chunks |
+#
+# The content is split into chunks, which are sealed with AES-256-GCM using the data key with the key ID.
+# This must be kept in sync with `lib/storage/encrypted.go`, which documents the format.
+_ENCRYPTION_MAGIC = b"\x89AQE"
+_ENCRYPTION_VERSION = 1
+_ENCRYPTION_PREFIX_SIZE = len(_ENCRYPTION_MAGIC) + 1 + 2
+_ENCRYPTION_NONCE_SIZE = 12
+_ENCRYPTION_TAG_SIZE = 16
+_ENCRYPTION_CHUNK_SIZE = 64 * 1024
+_ENCRYPTION_SEALED_SIZE = _ENCRYPTION_CHUNK_SIZE + _ENCRYPTION_TAG_SIZE
+_LAST_CHUNK = b"\x01"
+_NOT_LAST_CHUNK = b"\x00"
+
+
+class EncryptedStorage(Storage):
+    """
+    Encrypts objects before they are written to the underlying storage, and decrypts them
+    when they are read. The server passes the unwrapped data keys that the job needs, so the
+    master key that wraps them never leaves the server.
+    Objects that are not encrypted, e.g. because they were written before encryption was enabled,
+    are read as is.
+    """
+
+    _storage: Storage
+    _data_keys: Dict[str, AESGCM]
+    _write_key_id: str
+
+    def __init__(self, storage: Storage, data_keys: Dict[str, bytes], write_key_id: str):
+        if write_key_id not in data_keys:
+            raise Exception("The data key %s that new objects are encrypted with is missing." % write_key_id)
+
+        self._storage = storage
+        self._data_keys = {key_id: AESGCM(data_key) for key_id, data_key in data_keys.items()}
+        self._write_key_id = write_key_id
+
+    def put(self, key: str, value: bytes) -> None:
+        key_id = self._write_key_id
+        aead = self._data_keys[key_id]
+        nonce = os.urandom(_ENCRYPTION_NONCE_SIZE)
+
+        encoded_key_id = key_id.encode()
+        parts = [
+            _ENCRYPTION_MAGIC,
+            bytes([_ENCRYPTION_VERSION]),
+            struct.pack(">H", len(encoded_key_id)),
+            encoded_key_id,
+            nonce,
+        ]
+
+        # An empty object has a single empty chunk.
+        num_chunks = max(1, -(-len(value) // _ENCRYPTION_CHUNK_SIZE))
+        for index in range(num_chunks):
+            chunk = value[index * _ENCRYPTION_CHUNK_SIZE : (index + 1) * _ENCRYPTION_CHUNK_SIZE]
+            last = _LAST_CHUNK if index == num_chunks - 1 else _NOT_LAST_CHUNK
+            parts.append(aead.encrypt(_chunk_nonce(nonce, index), chunk, last))
+
+        self._storage.put(key, b"".join(parts))
+
+    def get(self, key: str) -> bytes:
+        stored = self._storage.get(key)
+        if len(stored) < _ENCRYPTION_PREFIX_SIZE or not stored.startswith(_ENCRYPTION_MAGIC):
+            return stored
+
+        version = stored[len(_ENCRYPTION_MAGIC)]
+        if version != _ENCRYPTION_VERSION:
+            raise Exception("Unsupported encryption version %d of object %s." % (version, key))
+
+        (key_id_size,) = struct.unpack(
+            ">H", stored[len(_ENCRYPTION_MAGIC) + 1 : _ENCRYPTION_PREFIX_SIZE]
+        )
+        header_size = _ENCRYPTION_PREFIX_SIZE + key_id_size + _ENCRYPTION_NONCE_SIZE
+        if len(stored) < header_size:
+            raise ObjectCorruptedError("Object %s in storage is corrupted: the header is truncated." % key)
+
+        key_id = stored[_ENCRYPTION_PREFIX_SIZE : _ENCRYPTION_PREFIX_SIZE + key_id_size].decode()
+        nonce = stored[header_size - _ENCRYPTION_NONCE_SIZE : header_size]
+        aead = self._get_data_key(key_id)
+
+        body = stored[header_size:]
+        num_chunks = max(1, -(-len(body) // _ENCRYPTION_SEALED_SIZE))
+        content = []
+        for index in range(num_chunks):
+            sealed = body[index * _ENCRYPTION_SEALED_SIZE : (index + 1) * _ENCRYPTION_SEALED_SIZE]
+            last = _LAST_CHUNK if index == num_chunks - 1 else _NOT_LAST_CHUNK
+            try:
+                content.append(aead.decrypt(_chunk_nonce(nonce, index), sealed, last))
+            except InvalidTag:
+                raise ObjectCorruptedError(
+                    "Object %s in storage is corrupted: unable to decrypt chunk %d." % (key, index)
+                )
+
+        return b"".join(content)
+
+    def exists(self, key: str) -> bool:
+        return self._storage.exists(key)
+
+    def _get_data_key(self, key_id: str) -> AESGCM:
+        if key_id not in self._data_keys:
+            raise Exception(
+                "The data key %s is not available to this job. Only the data keys of the workflow "
+                "and the shared data keys are." % key_id
+            )
+
+        return self._data_keys[key_id]
+
+
+def _chunk_nonce(nonce: bytes, index: int) -> bytes:
+    """The nonce of a chunk is the nonce of the object XORed with the index of the chunk."""
+    (counter,) = struct.unpack(">Q", nonce[-8:])
+    return nonce[:-8] + struct.pack(">Q", counter ^ index)
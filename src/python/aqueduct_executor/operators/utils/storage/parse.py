@@ -1,4 +1,9 @@
+import base64
+
+from aqueduct_executor.operators.utils.storage.azure import AzureStorage
+from aqueduct_executor.operators.utils.storage.compressed import CompressedStorage
 from aqueduct_executor.operators.utils.storage.config import StorageConfig
+from aqueduct_executor.operators.utils.storage.encrypted import EncryptedStorage
 from aqueduct_executor.operators.utils.storage.file import FileStorage
 from aqueduct_executor.operators.utils.storage.gcs import GCSStorage
 from aqueduct_executor.operators.utils.storage.s3 import S3Storage
@@ -6,10 +11,29 @@ from aqueduct_executor.operators.utils.storage.storage import Storage
 
 
 def parse_storage(storage_config: StorageConfig) -> Storage:
+    storage = _parse_base_storage(storage_config)
+    if storage_config.encryption:
+        storage = EncryptedStorage(
+            storage,
+            {
+                key_id: base64.b64decode(data_key)
+                for key_id, data_key in storage_config.encryption.data_keys.items()
+            },
+            storage_config.encryption.write_key_id,
+        )
+    # Objects are compressed before they are encrypted, since encrypted content does not compress.
+    if storage_config.compression:
+        return CompressedStorage(storage, storage_config.compression.algorithm)
+    return storage
+
+
+def _parse_base_storage(storage_config: StorageConfig) -> Storage:
     if storage_config.s3_config:
         return S3Storage(storage_config.s3_config)
     if storage_config.file_config:
         return FileStorage(storage_config.file_config)
     if storage_config.gcs_config:
         return GCSStorage(storage_config.gcs_config)
+    if storage_config.azure_config:
+        return AzureStorage(storage_config.azure_config)
     raise Exception("Unknown storage type")
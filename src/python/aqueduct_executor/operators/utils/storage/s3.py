@@ -1,5 +1,5 @@
 import os
-from typing import Any, Tuple
+from typing import Any, Dict, Tuple
 
 import boto3
 from aqueduct_executor.operators.utils.storage.config import S3StorageConfig
@@ -13,12 +13,22 @@ class S3Storage(Storage):
     _config: S3StorageConfig
 
     def __init__(self, config: S3StorageConfig):
+        # These are only set for S3-compatible services like MinIO.
+        endpoint_kwargs: Dict[str, Any] = {}
+        if config.endpoint_url:
+            endpoint_kwargs["endpoint_url"] = config.endpoint_url
+        if config.skip_tls_verify:
+            endpoint_kwargs["verify"] = False
+        addressing_style = "path" if config.force_path_style else "auto"
+
         if config.aws_access_key_id and config.aws_secret_access_key:
             # The AWS keys are passed in as part of the storage spec for AWS Lambda engines
             self._client = boto3.client(
                 "s3",
                 aws_access_key_id=config.aws_access_key_id,
                 aws_secret_access_key=config.aws_secret_access_key,
+                config=BotoConfig(s3={"addressing_style": addressing_style}),
+                **endpoint_kwargs,
             )
         elif "AWS_ACCESS_KEY_ID" in os.environ and "AWS_SECRET_ACCESS_KEY" in os.environ:
             # The AWS keys are passed in as environment variables for k8s engines
@@ -26,12 +36,21 @@ class S3Storage(Storage):
                 "s3",
                 aws_access_key_id=os.environ["AWS_ACCESS_KEY_ID"],
                 aws_secret_access_key=os.environ["AWS_SECRET_ACCESS_KEY"],
+                config=BotoConfig(s3={"addressing_style": addressing_style}),
+                **endpoint_kwargs,
             )
         else:
             # Boto3 uses an environment variable to determine the credentials filepath and profile
             os.environ["AWS_SHARED_CREDENTIALS_FILE"] = config.credentials_path
             os.environ["AWS_PROFILE"] = config.credentials_profile
-            self._client = boto3.client("s3", config=BotoConfig(region_name=config.region))
+            self._client = boto3.client(
+                "s3",
+                config=BotoConfig(
+                    region_name=config.region,
+                    s3={"addressing_style": addressing_style},
+                ),
+                **endpoint_kwargs,
+            )
 
         self._config = config
 
@@ -1,3 +1,4 @@
+import hashlib
 import io
 import json
 import time
@@ -36,6 +37,7 @@ _METADATA_SYSTEM_METADATA_KEY = "system_metadata"
 _METADATA_ARTIFACT_TYPE_KEY = "artifact_type"
 _METADATA_SERIALIZATION_TYPE_KEY = "serialization_type"
 _METADATA_PYTHON_TYPE_KEY = "python_type"
+_METADATA_CHECKSUM_KEY = "checksum"
 
 # The temporary file name that a Tensorflow keras model will be dumped into before we read/write it from storage.
 # This will be cleaned up within the serialization logic.
@@ -157,6 +159,7 @@ def write_artifact(
     if output_path is not None:
         serialized_val = serialize_val_wrapper(content, serialization_type, derived_from_bson)
         storage.put(output_path, serialized_val)
+        output_metadata[_METADATA_CHECKSUM_KEY] = hashlib.sha256(serialized_val).hexdigest()
 
     output_metadata[_METADATA_SERIALIZATION_TYPE_KEY] = serialization_type
     output_metadata[_METADATA_PYTHON_TYPE_KEY] = type(content).__name__
@@ -47,6 +47,8 @@ export enum ExecutionStatus {
   // Checks can have a warning status.
   Warning = 'warning',
   Deleted = 'deleted',
+  // Scheduled workflow runs can be skipped, e.g. during a blackout window.
+  Skipped = 'skipped',
 }
 
 export const getArtifactResultTableRow = (